@@ -39,10 +39,11 @@ var rootCmd = &cobra.Command{
 	Short: "HAProxy Template Ingress Controller",
 	Long: `HAProxy Template Ingress Controller - Template-driven HAProxy configuration management.
 
-The controller provides two main commands:
+The controller provides the following commands:
 
-  run      - Run the controller (watches CRDs and manages HAProxy)
-  validate - Validate a HAProxyTemplateConfig with embedded tests
+  run       - Run the controller (watches CRDs and manages HAProxy)
+  validate  - Validate a HAProxyTemplateConfig with embedded tests
+  sync-once - Render a HAProxyTemplateConfig and sync it to one Dataplane API
 
 Use "controller [command] --help" for more information about a command.`,
 }
@@ -67,6 +68,7 @@ func init() {
 	// Add subcommands
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(syncOnceCmd)
 }
 
 func main() {
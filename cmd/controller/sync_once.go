@@ -0,0 +1,208 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"haproxy-template-ic/pkg/controller/conversion"
+	"haproxy-template-ic/pkg/controller/testrunner"
+	"haproxy-template-ic/pkg/core/config"
+	"haproxy-template-ic/pkg/dataplane"
+)
+
+var (
+	syncOnceConfigFile   string
+	syncOnceTestName     string
+	syncOnceURL          string
+	syncOnceUsername     string
+	syncOncePasswordFile string
+	syncOnceTimeout      time.Duration
+	syncOnceProfile      bool
+)
+
+// syncOnceCmd represents the sync-once command.
+var syncOnceCmd = &cobra.Command{
+	Use:   "sync-once",
+	Short: "Render a HAProxyTemplateConfig and sync it to one Dataplane API",
+	Long: `Render a HAProxyTemplateConfig once and synchronize the result to a single
+HAProxy Dataplane API endpoint.
+
+The templates are rendered with the fixtures of the validation test selected
+with --test (merged with the _global fixtures), using the production directories
+from the dataplane configuration for auxiliary file paths. This makes it possible
+to reproduce and measure a sync without running the controller in a cluster.
+
+With --profile, a timing breakdown of the sync is printed after it completes:
+template rendering, fetching, parsing and diffing the configuration, auxiliary
+file sync, execution per section, transaction commit and the wait for the
+resulting HAProxy reload.
+
+Example usage:
+  # Sync the rendered output of a test and print the timing breakdown
+  controller sync-once -f config.yaml --test basic-ingress \
+    --url http://localhost:5555 --username admin \
+    --password-file /run/secrets/dataplane-password --profile`,
+	RunE: runSyncOnce,
+}
+
+func init() {
+	syncOnceCmd.Flags().StringVarP(&syncOnceConfigFile, "file", "f", "", "Path to HAProxyTemplateConfig YAML file (required)")
+	syncOnceCmd.Flags().StringVar(&syncOnceTestName, "test", "", "Validation test whose fixtures are rendered (required)")
+	syncOnceCmd.Flags().StringVar(&syncOnceURL, "url", "", "Dataplane API URL (required)")
+	syncOnceCmd.Flags().StringVar(&syncOnceUsername, "username", "admin", "Dataplane API username")
+	syncOnceCmd.Flags().StringVar(&syncOncePasswordFile, "password-file", "", "File containing the Dataplane API password (default: $DATAPLANE_PASSWORD)")
+	syncOnceCmd.Flags().DurationVar(&syncOnceTimeout, "timeout", 2*time.Minute, "Timeout for the sync including the reload wait")
+	syncOnceCmd.Flags().BoolVar(&syncOnceProfile, "profile", false, "Print a timing breakdown of the sync")
+}
+
+func runSyncOnce(cmd *cobra.Command, args []string) error {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	slog.SetDefault(logger)
+
+	if syncOnceConfigFile == "" || syncOnceTestName == "" || syncOnceURL == "" {
+		return fmt.Errorf("--file, --test and --url are required")
+	}
+	password, err := readDataplanePassword(syncOncePasswordFile)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncOnceTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+
+	configSpec, err := loadConfigFromFile(syncOnceConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cfg, err := conversion.ConvertSpec(configSpec)
+	if err != nil {
+		return fmt.Errorf("failed to convert config: %w", err)
+	}
+	config.SetDefaults(cfg)
+
+	engine, err := createTemplateEngine(configSpec, logger)
+	if err != nil {
+		return err
+	}
+
+	client, err := dataplane.NewClient(ctx, &dataplane.Endpoint{
+		URL:      syncOnceURL,
+		Username: syncOnceUsername,
+		Password: password,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Render with production paths so file references match the deployed files
+	paths := dataplane.ResolvePaths(dataplane.PathConfig{
+		MapsDir:    cfg.Dataplane.MapsDir,
+		SSLDir:     cfg.Dataplane.SSLCertsDir,
+		GeneralDir: cfg.Dataplane.GeneralStorageDir,
+		ConfigFile: cfg.Dataplane.ConfigFile,
+	}, client.Capabilities()).ToValidationPaths()
+
+	// haproxy_version() reflects the local HAProxy binary when one is installed
+	var haproxyVersion string
+	if localVersion, err := dataplane.DetectLocalVersion(); err == nil {
+		haproxyVersion = localVersion.Full
+	}
+
+	runner := testrunner.New(cfg, engine, paths, testrunner.Options{
+		Logger:         logger,
+		Capabilities:   client.Capabilities(),
+		HAProxyVersion: haproxyVersion,
+	})
+
+	renderStart := time.Now()
+	haproxyConfig, auxFiles, err := runner.RenderTest(syncOnceTestName, paths)
+	if err != nil {
+		return fmt.Errorf("failed to render test %q: %w", syncOnceTestName, err)
+	}
+	renderDuration := time.Since(renderStart)
+
+	result, err := client.Sync(ctx, haproxyConfig, auxFiles, nil)
+	if err != nil {
+		return err
+	}
+
+	var reloadWait time.Duration
+	if result.ReloadID != "" {
+		waitStart := time.Now()
+		if _, err := client.WaitForReload(ctx, result.ReloadID); err != nil {
+			return err
+		}
+		reloadWait = time.Since(waitStart)
+	}
+
+	fmt.Printf("Sync completed: %s\n", result.String())
+
+	if syncOnceProfile {
+		printSyncProfile(os.Stdout, renderDuration, result, reloadWait, time.Since(startTime))
+	}
+
+	return nil
+}
+
+// printSyncProfile writes the timing breakdown of a sync-once run.
+func printSyncProfile(w io.Writer, render time.Duration, result *dataplane.SyncResult, reloadWait, total time.Duration) {
+	timings := result.Timings
+
+	fmt.Fprintln(w, "\n"+strings.Repeat("=", 40))
+	fmt.Fprintln(w, "SYNC PROFILE")
+	fmt.Fprintln(w, strings.Repeat("=", 40))
+
+	row := func(indent, name string, d time.Duration) {
+		fmt.Fprintf(w, "%s%-*s %12s\n", indent, 26-len(indent), name, d.Round(time.Microsecond))
+	}
+
+	row("", "render", render)
+	row("", "fetch", timings.Fetch)
+	row("", "parse", timings.Parse)
+	row("", "diff", timings.Diff)
+	row("", "auxiliary files", timings.AuxiliaryFiles)
+	row("", "execute", timings.Execute)
+
+	sectionNames := make([]string, 0, len(timings.ExecuteBySection))
+	for section := range timings.ExecuteBySection {
+		sectionNames = append(sectionNames, section)
+	}
+	sort.Strings(sectionNames)
+	for _, section := range sectionNames {
+		row("  ", section, timings.ExecuteBySection[section])
+	}
+
+	row("", "commit", timings.Commit)
+	row("", "reload wait", reloadWait)
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+	row("", "sum of stages", render+timings.Total()+reloadWait)
+	row("", "total", total)
+}
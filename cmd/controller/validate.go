@@ -17,11 +17,16 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/pmezard/go-difflib/difflib"
 	"github.com/spf13/cobra"
 
 	"haproxy-template-ic/pkg/apis/haproxytemplate/v1alpha1"
@@ -45,8 +50,24 @@ var (
 	validateTraceTemplates bool
 	validateDebugFilters   bool
 	validateWorkers        int
+	validateWatchPath      string
+	validateStdin          bool
+	validateURL            string
+	validateUsername       string
+	validatePasswordFile   string
+	validateGoldenFile     string
+	validateUpdateGolden   bool
+	validateDeterminism    bool
+	validateDeterminismRun int
 )
 
+// validateStdinTimeout bounds the diff against a live Dataplane API in --stdin mode.
+const validateStdinTimeout = 2 * time.Minute
+
+// validateWatchDebounce is the quiet period after the last file change before
+// validation re-runs in --watch mode.
+const validateWatchDebounce = 300 * time.Millisecond
+
 // validateCmd represents the validate command.
 var validateCmd = &cobra.Command{
 	Use:   "validate",
@@ -74,7 +95,26 @@ Example usage:
   controller validate -f config.yaml --output json
 
   # Use custom HAProxy binary location
-  controller validate -f config.yaml --haproxy-binary /usr/local/bin/haproxy`,
+  controller validate -f config.yaml --haproxy-binary /usr/local/bin/haproxy
+
+  # Re-run validation whenever the config file changes
+  controller validate --watch config.yaml
+
+  # Compare the rendered haproxy.cfg of a test against a golden file
+  controller validate -f config.yaml --test "test-frontend-routing" --golden haproxy.golden.cfg
+
+  # Regenerate the golden file after an intended change
+  controller validate -f config.yaml --test "test-frontend-routing" --golden haproxy.golden.cfg --update-golden
+
+  # Check that all templates render identically for identical input
+  controller validate -f config.yaml --determinism-check --determinism-runs 10
+
+  # Validate the structure of a rendered haproxy.cfg
+  controller validate --stdin < haproxy.cfg
+
+  # Additionally show what syncing it would change on a live Dataplane API
+  controller validate --stdin --url http://localhost:5555 \
+    --username admin --password-file /run/secrets/dataplane-password < haproxy.cfg`,
 	RunE: runValidate,
 }
 
@@ -88,8 +128,15 @@ func init() {
 	validateCmd.Flags().BoolVar(&validateTraceTemplates, "trace-templates", false, "Show template execution trace")
 	validateCmd.Flags().BoolVar(&validateDebugFilters, "debug-filters", false, "Show filter operation debugging (sort comparisons, etc.)")
 	validateCmd.Flags().IntVar(&validateWorkers, "workers", 0, "Number of parallel test workers (0=auto-detect CPUs, 1=sequential)")
-
-	_ = validateCmd.MarkFlagRequired("file")
+	validateCmd.Flags().StringVar(&validateWatchPath, "watch", "", "Re-run validation whenever the given config file changes (implies --file)")
+	validateCmd.Flags().BoolVar(&validateStdin, "stdin", false, "Validate a rendered haproxy.cfg read from stdin instead of a HAProxyTemplateConfig")
+	validateCmd.Flags().StringVar(&validateURL, "url", "", "Dataplane API URL to diff the --stdin config against (optional)")
+	validateCmd.Flags().StringVar(&validateUsername, "username", "admin", "Dataplane API username (with --url)")
+	validateCmd.Flags().StringVar(&validatePasswordFile, "password-file", "", "File containing the Dataplane API password (with --url, default: $DATAPLANE_PASSWORD)")
+	validateCmd.Flags().StringVar(&validateGoldenFile, "golden", "", "Compare the rendered haproxy.cfg against this golden file (requires a single test)")
+	validateCmd.Flags().BoolVar(&validateUpdateGolden, "update-golden", false, "Write the rendered haproxy.cfg to the --golden file instead of comparing")
+	validateCmd.Flags().BoolVar(&validateDeterminism, "determinism-check", false, "Render each test's fixtures repeatedly and fail if any template output differs, instead of running assertions")
+	validateCmd.Flags().IntVar(&validateDeterminismRun, "determinism-runs", testrunner.DefaultDeterminismRuns, "Number of renders per test with --determinism-check")
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
@@ -101,6 +148,195 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	}))
 	slog.SetDefault(logger)
 
+	if validateUpdateGolden && validateGoldenFile == "" {
+		return fmt.Errorf("--update-golden requires --golden")
+	}
+	if validateDeterminism && validateGoldenFile != "" {
+		return fmt.Errorf("--determinism-check cannot be combined with --golden")
+	}
+
+	if validateStdin {
+		if validateConfigFile != "" || validateWatchPath != "" || validateGoldenFile != "" {
+			return fmt.Errorf("--stdin cannot be combined with --file, --watch or --golden")
+		}
+		ctx, cancel := context.WithTimeout(ctx, validateStdinTimeout)
+		defer cancel()
+
+		var endpoint *dataplane.Endpoint
+		if validateURL != "" {
+			password, err := readDataplanePassword(validatePasswordFile)
+			if err != nil {
+				return err
+			}
+			endpoint = &dataplane.Endpoint{
+				URL:      validateURL,
+				Username: validateUsername,
+				Password: password,
+			}
+		}
+		return validateRenderedConfig(ctx, os.Stdin, os.Stdout, endpoint)
+	}
+
+	// --watch implies the watched file is the config under validation
+	if validateConfigFile == "" {
+		validateConfigFile = validateWatchPath
+	}
+	if validateConfigFile == "" {
+		return fmt.Errorf("either --file or --watch is required")
+	}
+
+	if validateWatchPath != "" {
+		return runValidateWatch(ctx, logger)
+	}
+
+	return validateOnce(ctx, logger)
+}
+
+// validateRenderedConfig validates the structure of a rendered haproxy.cfg read from r
+// and writes the outcome to w.
+//
+// Only the syntax and API schema phases run, so neither a haproxy binary nor the
+// auxiliary files are required. If endpoint is non-nil, the configuration is
+// additionally diffed against that Dataplane API without applying it.
+func validateRenderedConfig(ctx context.Context, r io.Reader, w io.Writer, endpoint *dataplane.Endpoint) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read config from stdin: %w", err)
+	}
+	config := string(data)
+	if strings.TrimSpace(config) == "" {
+		return fmt.Errorf("no config received on stdin")
+	}
+
+	if err := dataplane.ValidateStructure(config, nil); err != nil {
+		fmt.Fprintln(w, "✗ Configuration is invalid")
+		return err
+	}
+	fmt.Fprintln(w, "✓ Configuration is valid")
+
+	if endpoint == nil {
+		return nil
+	}
+
+	client, err := dataplane.NewClient(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	diff, err := client.DryRun(ctx, config)
+	if err != nil {
+		return fmt.Errorf("failed to diff against %s: %w", endpoint.URL, err)
+	}
+
+	fmt.Fprintf(w, "\nDiff against %s:\n%s\n", endpoint.URL, diff.String())
+	return nil
+}
+
+// readDataplanePassword returns the Dataplane API password from path with
+// trailing newlines removed, or from $DATAPLANE_PASSWORD if path is empty.
+// The password is not accepted as a flag since flags show up in process
+// listings and shell history.
+func readDataplanePassword(path string) (string, error) {
+	if path == "" {
+		return os.Getenv("DATAPLANE_PASSWORD"), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read password file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// compareGolden compares the haproxy.cfg rendered by the single test in results
+// against the golden file at path, writing a unified diff to w on mismatch.
+//
+// Both sides are normalized first (see normalizeGolden). With update set, the
+// golden file is (re)written from the rendered config instead.
+func compareGolden(results *testrunner.TestResults, path string, update bool, w io.Writer) error {
+	if len(results.TestResults) != 1 {
+		return fmt.Errorf("--golden requires exactly one test, got %d (select one with --test)", len(results.TestResults))
+	}
+	test := &results.TestResults[0]
+	if test.RenderError != "" {
+		return fmt.Errorf("test %q did not render a config: %s", test.TestName, test.RenderError)
+	}
+	rendered := normalizeGolden(test.RenderedConfig)
+
+	if update {
+		if err := os.WriteFile(path, []byte(rendered), 0o644); err != nil {
+			return fmt.Errorf("failed to write golden file: %w", err)
+		}
+		fmt.Fprintf(w, "✓ Updated golden file %s\n", path)
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read golden file: %w\nHint: Create it with --update-golden", err)
+	}
+	golden := normalizeGolden(string(data))
+
+	if golden == rendered {
+		fmt.Fprintf(w, "✓ Rendered config matches golden file %s\n", path)
+		return nil
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(golden),
+		B:        difflib.SplitLines(rendered),
+		FromFile: path,
+		ToFile:   "rendered (" + test.TestName + ")",
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to diff against golden file: %w", err)
+	}
+
+	fmt.Fprintf(w, "✗ Rendered config does not match golden file %s\n\n%s", path, diff)
+	return fmt.Errorf("rendered config does not match golden file %s", path)
+}
+
+// normalizeGolden normalizes line endings and trailing whitespace so that golden
+// comparisons are not affected by editor settings.
+func normalizeGolden(config string) string {
+	lines := strings.Split(strings.ReplaceAll(config, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimRight(strings.Join(lines, "\n"), "\n") + "\n"
+}
+
+// runValidateWatch validates once and then again after every change of the watched file.
+// Validation failures are printed but do not stop watching; Ctrl+C exits.
+func runValidateWatch(ctx context.Context, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	watcher, err := testrunner.NewFileWatcher(validateWatchPath, validateWatchDebounce)
+	if err != nil {
+		return err
+	}
+
+	revalidate := func() {
+		if err := validateOnce(ctx, logger); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		fmt.Fprintf(os.Stderr, "\nWatching %s for changes (press Ctrl+C to stop)...\n", validateWatchPath)
+	}
+
+	revalidate()
+
+	return watcher.Run(ctx, func() {
+		fmt.Printf("\n%s\n[%s] %s changed, re-running validation\n%s\n",
+			strings.Repeat("=", 80), time.Now().Format(time.TimeOnly), validateWatchPath, strings.Repeat("=", 80))
+		revalidate()
+	})
+}
+
+// validateOnce loads the config, runs its validation tests and prints the results.
+// Returns an error if setup fails or any test fails.
+func validateOnce(ctx context.Context, logger *slog.Logger) error {
 	// Setup validation environment
 	setup, err := setupValidation(logger)
 	if err != nil {
@@ -108,8 +344,16 @@ func runValidate(cmd *cobra.Command, args []string) error {
 	}
 	defer setup.Cleanup()
 
+	if validateDeterminism {
+		results, err := runDeterminismCheck(setup, logger)
+		if err != nil {
+			return err
+		}
+		return reportDeterminism(results, os.Stdout)
+	}
+
 	// Run tests
-	results, err := runValidationTests(ctx, setup.ConfigSpec, setup.Engine, setup.ValidationPaths, setup.Capabilities, logger)
+	results, err := runValidationTests(ctx, setup.ConfigSpec, setup.Engine, setup.ValidationPaths, setup.Capabilities, setup.HAProxyVersion, logger)
 	if err != nil {
 		return err
 	}
@@ -119,6 +363,12 @@ func runValidate(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if validateGoldenFile != "" {
+		if err := compareGolden(results, validateGoldenFile, validateUpdateGolden, os.Stdout); err != nil {
+			return err
+		}
+	}
+
 	// Exit with error code if tests failed
 	if !results.AllPassed() {
 		return fmt.Errorf("validation tests failed: %d/%d tests passed", results.PassedTests, results.TotalTests)
@@ -133,6 +383,7 @@ type ValidationSetup struct {
 	Engine          *templating.TemplateEngine
 	ValidationPaths *dataplane.ValidationPaths
 	Capabilities    dataplane.Capabilities
+	HAProxyVersion  string
 	Cleanup         func()
 }
 
@@ -149,9 +400,17 @@ func setupValidation(logger *slog.Logger) (*ValidationSetup, error) {
 		return nil, fmt.Errorf("no validation tests found in config")
 	}
 
+	// Detect local HAProxy version to determine capabilities
+	// CRT-list storage is only available in HAProxy 3.2+
+	localVersion, err := dataplane.DetectLocalVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect local HAProxy version: %w\nHint: Ensure 'haproxy' is in PATH", err)
+	}
+	capabilities := dataplane.CapabilitiesFromVersion(localVersion)
+
 	// Setup validation paths in temp directory
 	// Pass configSpec so setupValidationPaths can derive subdirectory names from dataplane configuration
-	validationPaths, capabilities, cleanupFunc, err := setupValidationPaths(configSpec)
+	validationPaths, cleanupFunc, err := setupValidationPaths(configSpec, capabilities)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +432,7 @@ func setupValidation(logger *slog.Logger) (*ValidationSetup, error) {
 		Engine:          engine,
 		ValidationPaths: validationPaths,
 		Capabilities:    capabilities,
+		HAProxyVersion:  localVersion.Full,
 		Cleanup:         cleanupFunc,
 	}, nil
 }
@@ -184,6 +444,7 @@ func runValidationTests(
 	engine *templating.TemplateEngine,
 	validationPaths *dataplane.ValidationPaths,
 	capabilities dataplane.Capabilities,
+	haproxyVersion string,
 	logger *slog.Logger,
 ) (*testrunner.TestResults, error) {
 	// Convert CRD spec to internal config format
@@ -198,10 +459,11 @@ func runValidationTests(
 		engine,
 		validationPaths,
 		testrunner.Options{
-			Logger:       logger,
-			Workers:      validateWorkers,
-			DebugFilters: validateDebugFilters,
-			Capabilities: capabilities,
+			Logger:         logger,
+			Workers:        validateWorkers,
+			DebugFilters:   validateDebugFilters,
+			Capabilities:   capabilities,
+			HAProxyVersion: haproxyVersion,
 		},
 	)
 
@@ -218,6 +480,59 @@ func runValidationTests(
 	return results, nil
 }
 
+// runDeterminismCheck renders the fixtures of the validation tests repeatedly
+// and returns which templates produced differing output.
+func runDeterminismCheck(setup *ValidationSetup, logger *slog.Logger) ([]testrunner.DeterminismResult, error) {
+	cfg, err := conversion.ConvertSpec(setup.ConfigSpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert config: %w", err)
+	}
+
+	runner := testrunner.New(cfg, setup.Engine, setup.ValidationPaths, testrunner.Options{
+		Logger:         logger,
+		DebugFilters:   validateDebugFilters,
+		Capabilities:   setup.Capabilities,
+		HAProxyVersion: setup.HAProxyVersion,
+	})
+
+	logger.Info("Checking template determinism",
+		"total_tests", len(cfg.ValidationTests),
+		"runs", validateDeterminismRun,
+		"filter", validateTestName)
+
+	results, err := runner.CheckDeterminism(validateTestName, validateDeterminismRun)
+	if err != nil {
+		return nil, fmt.Errorf("determinism check failed: %w", err)
+	}
+
+	return results, nil
+}
+
+// reportDeterminism writes the outcome of a determinism check to w and returns
+// an error naming the tests with non-deterministic templates.
+func reportDeterminism(results []testrunner.DeterminismResult, w io.Writer) error {
+	var failed []string
+	for i := range results {
+		result := &results[i]
+		switch {
+		case result.RenderError != "":
+			fmt.Fprintf(w, "✗ %s: rendering failed: %s\n", result.TestName, result.RenderError)
+		case len(result.NonDeterministic) > 0:
+			fmt.Fprintf(w, "✗ %s: output differed across %d renders: %s\n",
+				result.TestName, result.Runs, strings.Join(result.NonDeterministic, ", "))
+		default:
+			fmt.Fprintf(w, "✓ %s: output identical across %d renders\n", result.TestName, result.Runs)
+			continue
+		}
+		failed = append(failed, result.TestName)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("determinism check failed for %d/%d tests: %s", len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return nil
+}
+
 // outputResults formats and prints test results, and optionally dumps rendered content and trace.
 func outputResults(results *testrunner.TestResults, engine *templating.TemplateEngine) error {
 	// Format output
@@ -393,34 +708,25 @@ func createTemplateEngine(configSpec *v1alpha1.HAProxyTemplateConfigSpec, logger
 }
 
 // setupValidationPaths creates temporary directories for HAProxy validation.
-// Returns the validation paths, capabilities, and a cleanup function.
+// Returns the validation paths and a cleanup function.
 // IMPORTANT: Subdirectory names are derived from the HAProxyTemplateConfig's dataplane configuration
 // to ensure consistency between production and validation environments.
-func setupValidationPaths(configSpec *v1alpha1.HAProxyTemplateConfigSpec) (
+func setupValidationPaths(configSpec *v1alpha1.HAProxyTemplateConfigSpec, capabilities dataplane.Capabilities) (
 	paths *dataplane.ValidationPaths,
-	capabilities dataplane.Capabilities,
 	cleanup func(),
 	err error,
 ) {
-	// Detect local HAProxy version to determine capabilities
-	// CRT-list storage is only available in HAProxy 3.2+
-	localVersion, err := dataplane.DetectLocalVersion()
-	if err != nil {
-		return nil, dataplane.Capabilities{}, nil, fmt.Errorf("failed to detect local HAProxy version: %w\nHint: Ensure 'haproxy' is in PATH", err)
-	}
-	capabilities = dataplane.CapabilitiesFromVersion(localVersion)
-
 	// Create temporary directory
 	tempDir, err := os.MkdirTemp("", "haproxy-validate-*")
 	if err != nil {
-		return nil, dataplane.Capabilities{}, nil, fmt.Errorf("failed to create temp dir: %w", err)
+		return nil, nil, fmt.Errorf("failed to create temp dir: %w", err)
 	}
 
 	// Convert CRD spec to internal config format to get dataplane configuration with defaults applied
 	cfg, err := conversion.ConvertSpec(configSpec)
 	if err != nil {
 		os.RemoveAll(tempDir)
-		return nil, dataplane.Capabilities{}, nil, fmt.Errorf("failed to convert config spec: %w", err)
+		return nil, nil, fmt.Errorf("failed to convert config spec: %w", err)
 	}
 
 	// Derive subdirectory names from configured dataplane paths using filepath.Base()
@@ -446,7 +752,7 @@ func setupValidationPaths(configSpec *v1alpha1.HAProxyTemplateConfigSpec) (
 	for _, dir := range dirsToCreate {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			os.RemoveAll(tempDir)
-			return nil, dataplane.Capabilities{}, nil, fmt.Errorf("failed to create directory: %w", err)
+			return nil, nil, fmt.Errorf("failed to create directory: %w", err)
 		}
 	}
 
@@ -454,5 +760,5 @@ func setupValidationPaths(configSpec *v1alpha1.HAProxyTemplateConfigSpec) (
 		os.RemoveAll(tempDir)
 	}
 
-	return resolvedPaths.ToValidationPaths(), capabilities, cleanup, nil
+	return resolvedPaths.ToValidationPaths(), cleanup, nil
 }
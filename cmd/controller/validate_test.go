@@ -0,0 +1,154 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/controller/testrunner"
+	"haproxy-template-ic/pkg/dataplane"
+)
+
+const stdinValidConfig = `global
+    daemon
+
+defaults
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+
+backend app
+    server s1 10.0.0.1:80
+`
+
+func TestValidateRenderedConfig_Valid(t *testing.T) {
+	var out bytes.Buffer
+
+	err := validateRenderedConfig(context.Background(), strings.NewReader(stdinValidConfig), &out, nil)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Configuration is valid")
+}
+
+func TestValidateRenderedConfig_Invalid(t *testing.T) {
+	var out bytes.Buffer
+	config := strings.Replace(stdinValidConfig, "10.0.0.1:80", "10.0.0.1:99999", 1)
+
+	err := validateRenderedConfig(context.Background(), strings.NewReader(config), &out, nil)
+	require.Error(t, err)
+
+	var validationErr *dataplane.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "schema", validationErr.Phase)
+	assert.Contains(t, out.String(), "Configuration is invalid")
+}
+
+func TestValidateRenderedConfig_Empty(t *testing.T) {
+	err := validateRenderedConfig(context.Background(), strings.NewReader("\n"), &bytes.Buffer{}, nil)
+	require.Error(t, err)
+}
+
+// goldenResults returns test results for a single test that rendered config.
+func goldenResults(config string) *testrunner.TestResults {
+	return &testrunner.TestResults{
+		TotalTests:  1,
+		TestResults: []testrunner.TestResult{{TestName: "routing", RenderedConfig: config}},
+	}
+}
+
+func TestReadDataplanePassword(t *testing.T) {
+	t.Setenv("DATAPLANE_PASSWORD", "from-env")
+
+	password, err := readDataplanePassword("")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", password)
+
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+	password, err = readDataplanePassword(path)
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", password)
+
+	_, err = readDataplanePassword(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestCompareGolden_Match(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "haproxy.golden.cfg")
+	golden := strings.ReplaceAll(stdinValidConfig, "\n", "  \r\n") + "\n\n"
+	require.NoError(t, os.WriteFile(path, []byte(golden), 0o644))
+
+	var out bytes.Buffer
+	err := compareGolden(goldenResults(stdinValidConfig), path, false, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "matches golden file")
+}
+
+func TestCompareGolden_Mismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "haproxy.golden.cfg")
+	require.NoError(t, os.WriteFile(path, []byte(stdinValidConfig), 0o644))
+	rendered := strings.Replace(stdinValidConfig, "10.0.0.1:80", "10.0.0.2:80", 1)
+
+	var out bytes.Buffer
+	err := compareGolden(goldenResults(rendered), path, false, &out)
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "-    server s1 10.0.0.1:80")
+	assert.Contains(t, out.String(), "+    server s1 10.0.0.2:80")
+}
+
+func TestCompareGolden_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "haproxy.golden.cfg")
+
+	err := compareGolden(goldenResults(stdinValidConfig+"\n\n"), path, true, &bytes.Buffer{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, stdinValidConfig, string(data))
+	require.NoError(t, compareGolden(goldenResults(stdinValidConfig), path, false, &bytes.Buffer{}))
+}
+
+func TestCompareGolden_RequiresSingleTest(t *testing.T) {
+	results := goldenResults(stdinValidConfig)
+	results.TestResults = append(results.TestResults, testrunner.TestResult{TestName: "other"})
+
+	err := compareGolden(results, filepath.Join(t.TempDir(), "golden.cfg"), false, &bytes.Buffer{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--test")
+}
+
+func TestReportDeterminism(t *testing.T) {
+	var out bytes.Buffer
+
+	err := reportDeterminism([]testrunner.DeterminismResult{
+		{TestName: "routing", Runs: 5},
+		{TestName: "labels", Runs: 5, NonDeterministic: []string{"haproxy.cfg", "hosts.map"}},
+	}, &out)
+	require.Error(t, err)
+	assert.Equal(t, "determinism check failed for 1/2 tests: labels", err.Error())
+	assert.Contains(t, out.String(), "✓ routing: output identical across 5 renders")
+	assert.Contains(t, out.String(), "✗ labels: output differed across 5 renders: haproxy.cfg, hosts.map")
+
+	require.NoError(t, reportDeterminism([]testrunner.DeterminismResult{{TestName: "routing", Runs: 5}}, &bytes.Buffer{}))
+}
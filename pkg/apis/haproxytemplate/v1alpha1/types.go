@@ -183,6 +183,14 @@ type ControllerConfig struct {
 	// +optional
 	MetricsPort int `json:"metricsPort,omitempty"`
 
+	// EmitKubernetesEvents records significant sync operations (reloads,
+	// failures, drift corrections) as Kubernetes Events on this resource.
+	//
+	// Events with the same reason are recorded at most every 30s.
+	// Default: false
+	// +optional
+	EmitKubernetesEvents bool `json:"emitKubernetesEvents,omitempty"`
+
 	// LeaderElection configures leader election for high availability.
 	// +optional
 	LeaderElection LeaderElectionConfig `json:"leaderElection,omitempty"`
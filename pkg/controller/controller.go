@@ -54,6 +54,7 @@ import (
 	"haproxy-template-ic/pkg/controller/events"
 	"haproxy-template-ic/pkg/controller/executor"
 	"haproxy-template-ic/pkg/controller/indextracker"
+	"haproxy-template-ic/pkg/controller/k8sevents"
 	leaderelectionctrl "haproxy-template-ic/pkg/controller/leaderelection"
 	"haproxy-template-ic/pkg/controller/metrics"
 	"haproxy-template-ic/pkg/controller/reconciler"
@@ -601,6 +602,7 @@ type reconciliationComponents struct {
 	driftMonitor        *deployer.DriftPreventionMonitor
 	configPublisher     *ctrlconfigpublisher.Component
 	capabilities        dataplane.Capabilities // HAProxy/DataPlane API capabilities
+	haproxyVersion      string                 // Local HAProxy version for haproxy_version()
 }
 
 // leaderOnlyComponents holds components that only the leader should run.
@@ -646,7 +648,7 @@ func createReconciliationComponents(
 		return nil, fmt.Errorf("haproxy-pods store not found (should be auto-injected)")
 	}
 
-	rendererComponent, err := renderer.New(bus, cfg, stores, haproxyPodStore, capabilities, logger)
+	rendererComponent, err := renderer.New(bus, cfg, stores, haproxyPodStore, capabilities, localVersion.Full, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create renderer: %w", err)
 	}
@@ -702,6 +704,7 @@ func createReconciliationComponents(
 		driftMonitor:        driftMonitorComponent,
 		configPublisher:     configPublisherComponent,
 		capabilities:        capabilities,
+		haproxyVersion:      localVersion.Full,
 	}, nil
 }
 
@@ -848,6 +851,7 @@ func setupWebhook(
 	bus *busevents.EventBus,
 	storeManager *resourcestore.Manager,
 	capabilities dataplane.Capabilities,
+	haproxyVersion string,
 	logger *slog.Logger,
 	metricsRecorder webhook.MetricsRecorder,
 	cancel context.CancelFunc,
@@ -905,7 +909,7 @@ func setupWebhook(
 	}
 
 	// Create DryRunValidator
-	dryrunValidator := dryrunvalidator.New(bus, storeManager, cfg, engine, validationPaths, capabilities, logger)
+	dryrunValidator := dryrunvalidator.New(bus, storeManager, cfg, engine, validationPaths, capabilities, haproxyVersion, logger)
 
 	// Start DryRunValidator before webhook
 	go func() {
@@ -981,6 +985,17 @@ func setupReconciliation(
 		return nil, err
 	}
 
+	// Record sync operations as Kubernetes Events on the HAProxyTemplateConfig
+	// Only the leader syncs, so only the leader records events
+	if cfg.Controller.EmitKubernetesEvents && crd != nil {
+		recorder := k8sevents.NewRecorder(iterCtx, k8sClient.Clientset())
+		emitter := k8sevents.NewEmitter(recorder, k8sevents.ConfigReference(crd), k8sevents.DefaultMinInterval)
+		components.deployer.SetSyncObserver(emitter)
+		logger.Info("Kubernetes Events enabled for sync operations",
+			"config_name", crd.Name,
+			"config_namespace", crd.Namespace)
+	}
+
 	// Start all-replica components in background
 	// Leader-only components (Deployer, DeploymentScheduler, DriftMonitor) are NOT started here
 	// Note: Components already subscribed during construction, so they're ready to receive events
@@ -1217,7 +1232,7 @@ func runIteration(
 	// 8. Setup webhook validation if enabled
 	if webhook.HasWebhookEnabled(cfg) {
 		logger.Info("Stage 7: Setting up webhook validation")
-		setupWebhook(setup.IterCtx, cfg, webhookCerts, k8sClient, setup.Bus, setup.StoreManager, reconComponents.capabilities, logger, setup.MetricsComponent.Metrics(), setup.Cancel)
+		setupWebhook(setup.IterCtx, cfg, webhookCerts, k8sClient, setup.Bus, setup.StoreManager, reconComponents.capabilities, reconComponents.haproxyVersion, logger, setup.MetricsComponent.Metrics(), setup.Cancel)
 	}
 
 	// 9. Setup debug and metrics infrastructure
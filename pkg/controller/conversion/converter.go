@@ -58,8 +58,9 @@ func ConvertSpec(spec *v1alpha1.HAProxyTemplateConfigSpec) (*config.Config, erro
 	}
 
 	controllerConfig := config.ControllerConfig{
-		HealthzPort: spec.Controller.HealthzPort,
-		MetricsPort: spec.Controller.MetricsPort,
+		HealthzPort:          spec.Controller.HealthzPort,
+		MetricsPort:          spec.Controller.MetricsPort,
+		EmitKubernetesEvents: spec.Controller.EmitKubernetesEvents,
 		LeaderElection: config.LeaderElectionConfig{
 			Enabled:       leaderElectionEnabled,
 			LeaseName:     spec.Controller.LeaderElection.LeaseName,
@@ -40,6 +40,14 @@ const (
 	EventBufferSize = 50
 )
 
+// SyncObserver receives the outcome of every sync to a single HAProxy instance.
+//
+// ObserveSync is called concurrently for the instances of a deployment, with
+// either the sync result or the error the sync failed with.
+type SyncObserver interface {
+	ObserveSync(instance string, isDriftCheck bool, result *dataplane.SyncResult, err error)
+}
+
 // Component implements the deployer component.
 //
 // It subscribes to DeploymentScheduledEvent and deploys configurations to
@@ -55,6 +63,7 @@ type Component struct {
 	eventChan            <-chan busevents.Event // Event subscription channel (subscribed in constructor)
 	logger               *slog.Logger
 	deploymentInProgress atomic.Bool // Defensive: prevents concurrent deployments if scheduler has bugs
+	syncObserver         SyncObserver
 }
 
 // New creates a new Deployer component.
@@ -73,6 +82,12 @@ func New(eventBus *busevents.EventBus, logger *slog.Logger) *Component {
 	}
 }
 
+// SetSyncObserver sets an observer notified of the outcome of every instance sync.
+// Must be called before Start.
+func (c *Component) SetSyncObserver(observer SyncObserver) {
+	c.syncObserver = observer
+}
+
 // Start begins the deployer's event loop.
 //
 // This method blocks until the context is cancelled or an error occurs.
@@ -225,6 +240,14 @@ func (c *Component) deployToEndpoints(
 			// Determine if this is a drift check based on deployment reason
 			isDriftCheck := reason == "drift_prevention"
 
+			if c.syncObserver != nil {
+				instance := ep.PodName
+				if instance == "" {
+					instance = ep.URL
+				}
+				c.syncObserver.ObserveSync(instance, isDriftCheck, syncResult, err)
+			}
+
 			if err != nil {
 				c.logger.Error("deployment failed for endpoint",
 					"endpoint", ep.URL,
@@ -342,6 +365,7 @@ func (c *Component) deployToSingleEndpoint(
 		"pod", endpoint.PodName,
 		"applied_operations", len(result.AppliedOperations),
 		"reload_triggered", result.ReloadTriggered,
+		"transaction_id", result.TransactionID,
 		"duration", result.Duration)
 
 	return result, nil
@@ -370,6 +394,7 @@ func (c *Component) convertSyncResultToMetadata(result *dataplane.SyncResult) *e
 	return &events.SyncMetadata{
 		ReloadTriggered:        result.ReloadTriggered,
 		ReloadID:               result.ReloadID,
+		TransactionID:          result.TransactionID,
 		SyncDuration:           result.Duration,
 		VersionConflictRetries: result.Retries,
 		FallbackUsed:           result.FallbackToRaw,
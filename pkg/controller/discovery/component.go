@@ -585,12 +585,18 @@ func (c *Component) checkRemoteVersion(endpoint *dataplane.Endpoint) (*dataplane
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
+	credentials, err := endpoint.CredentialsFunc()
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for pod %s: %w", endpoint.PodName, err)
+	}
+
 	// Create client endpoint for version detection
 	clientEndpoint := &client.Endpoint{
-		URL:      endpoint.URL,
-		Username: endpoint.Username,
-		Password: endpoint.Password,
-		PodName:  endpoint.PodName,
+		URL:         endpoint.URL,
+		Username:    endpoint.Username,
+		Password:    endpoint.Password,
+		Credentials: credentials,
+		PodName:     endpoint.PodName,
 	}
 
 	// Call the exported DetectVersion function
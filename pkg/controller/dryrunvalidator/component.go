@@ -29,6 +29,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"os"
 	"strings"
 	"time"
 
@@ -66,6 +67,7 @@ type Component struct {
 	testRunner      *testrunner.Runner
 	logger          *slog.Logger
 	capabilities    dataplane.Capabilities // HAProxy/DataPlane API capabilities
+	haproxyVersion  string                 // Local HAProxy version for haproxy_version()
 }
 
 // New creates a new DryRunValidator component.
@@ -77,6 +79,7 @@ type Component struct {
 //   - engine: Pre-compiled template engine for rendering
 //   - validationPaths: Filesystem paths for HAProxy validation
 //   - capabilities: HAProxy capabilities determined from local version
+//   - haproxyVersion: Local HAProxy version returned by haproxy_version()
 //   - logger: Structured logger
 //
 // Returns:
@@ -88,6 +91,7 @@ func New(
 	engine *templating.TemplateEngine,
 	validationPaths *dataplane.ValidationPaths,
 	capabilities dataplane.Capabilities,
+	haproxyVersion string,
 	logger *slog.Logger,
 ) *Component {
 	// Create test runner for validation tests
@@ -97,9 +101,10 @@ func New(
 		engine,
 		validationPaths,
 		testrunner.Options{
-			Logger:       logger.With("component", "test-runner"),
-			Workers:      1, // Sequential execution in webhook context
-			Capabilities: capabilities,
+			Logger:         logger.With("component", "test-runner"),
+			Workers:        1, // Sequential execution in webhook context
+			Capabilities:   capabilities,
+			HAProxyVersion: haproxyVersion,
 		},
 	)
 
@@ -112,6 +117,7 @@ func New(
 		testRunner:      testRunnerInstance,
 		logger:          logger.With("component", "dryrun-validator"),
 		capabilities:    capabilities,
+		haproxyVersion:  haproxyVersion,
 	}
 }
 
@@ -329,10 +335,12 @@ func (c *Component) buildRenderingContext(stores map[string]types.Store) map[str
 
 	// Build final context
 	return map[string]interface{}{
-		"resources":         resources,
-		"template_snippets": snippetNames,
-		"pathResolver":      pathResolver,
-		"config":            c.config,
+		"resources":                         resources,
+		"template_snippets":                 snippetNames,
+		"pathResolver":                      pathResolver,
+		"config":                            c.config,
+		templating.EnvContextKey:            renderer.BuildTemplateEnvironment(os.LookupEnv),
+		templating.HAProxyVersionContextKey: c.haproxyVersion,
 	}
 }
 
@@ -1199,6 +1199,10 @@ type SyncMetadata struct {
 	// Only populated when ReloadTriggered is true.
 	ReloadID string
 
+	// TransactionID is the ID of the committed Dataplane API transaction.
+	// Empty if the changes were applied without a transaction.
+	TransactionID string
+
 	// SyncDuration is how long the sync operation took.
 	SyncDuration time.Duration
 
@@ -0,0 +1,202 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8sevents records significant sync operations as Kubernetes Events.
+//
+// The Emitter is fed with the SyncResult or SyncError of every instance sync
+// (see deployer.SyncObserver) and records reloads, failures, and drift
+// corrections on the HAProxyTemplateConfig resource, so they show up in
+// `kubectl describe` and in cluster event pipelines.
+package k8sevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"haproxy-template-ic/pkg/apis/haproxytemplate/v1alpha1"
+	"haproxy-template-ic/pkg/dataplane"
+)
+
+// Event reasons recorded by the Emitter.
+const (
+	// ReasonReloaded is recorded when a sync triggered an HAProxy reload.
+	ReasonReloaded = "Reloaded"
+
+	// ReasonFallbackToRaw is recorded when a sync fell back to pushing the raw configuration.
+	ReasonFallbackToRaw = "FallbackToRaw"
+
+	// ReasonDriftCorrected is recorded when a drift prevention sync had to apply changes.
+	ReasonDriftCorrected = "DriftCorrected"
+
+	// ReasonSyncFailed is recorded when a sync failed.
+	ReasonSyncFailed = "SyncFailed"
+)
+
+const (
+	// DefaultMinInterval is the minimum time between two events with the same reason.
+	DefaultMinInterval = 30 * time.Second
+
+	// ComponentName is the event source component.
+	ComponentName = "haproxy-template-ic"
+)
+
+// Emitter records sync operations as Kubernetes Events on a single object.
+//
+// Event frequency is bounded per reason: an event is dropped if an event with
+// the same reason was recorded less than minInterval ago, and the next
+// recorded event mentions how many were dropped. This keeps a deployment to
+// many instances, or a sync failing on every retry, from flooding the API
+// server.
+type Emitter struct {
+	recorder    record.EventRecorder
+	object      runtime.Object
+	minInterval time.Duration
+	now         func() time.Time
+
+	mu         sync.Mutex
+	last       map[string]time.Time
+	suppressed map[string]int
+}
+
+// NewEmitter creates an Emitter recording events on object.
+//
+// A minInterval of 0 uses DefaultMinInterval.
+func NewEmitter(recorder record.EventRecorder, object runtime.Object, minInterval time.Duration) *Emitter {
+	if minInterval <= 0 {
+		minInterval = DefaultMinInterval
+	}
+
+	return &Emitter{
+		recorder:    recorder,
+		object:      object,
+		minInterval: minInterval,
+		now:         time.Now,
+		last:        make(map[string]time.Time),
+		suppressed:  make(map[string]int),
+	}
+}
+
+// NewRecorder creates an EventRecorder writing events to the cluster.
+// The underlying broadcaster is shut down when ctx is cancelled.
+func NewRecorder(ctx context.Context, clientset kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: clientset.CoreV1().Events("")})
+
+	go func() {
+		<-ctx.Done()
+		broadcaster.Shutdown()
+	}()
+
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: ComponentName})
+}
+
+// ConfigReference returns the object reference events are recorded on for a
+// HAProxyTemplateConfig.
+func ConfigReference(config *v1alpha1.HAProxyTemplateConfig) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion:      v1alpha1.SchemeGroupVersion.String(),
+		Kind:            "HAProxyTemplateConfig",
+		Namespace:       config.Namespace,
+		Name:            config.Name,
+		UID:             config.UID,
+		ResourceVersion: config.ResourceVersion,
+	}
+}
+
+// ObserveSync records the outcome of a sync to an instance.
+// It implements deployer.SyncObserver.
+func (e *Emitter) ObserveSync(instance string, isDriftCheck bool, result *dataplane.SyncResult, err error) {
+	if err != nil {
+		e.RecordSyncError(instance, err)
+		return
+	}
+	e.RecordSyncResult(instance, isDriftCheck, result)
+}
+
+// RecordSyncResult records the events for a successful sync: reloads, raw
+// fallbacks, and changes applied by a drift prevention sync.
+func (e *Emitter) RecordSyncResult(instance string, isDriftCheck bool, result *dataplane.SyncResult) {
+	if result == nil {
+		return
+	}
+
+	if result.ReloadTriggered {
+		msg := fmt.Sprintf("Reloaded HAProxy on %s after applying %d operations", instance, len(result.AppliedOperations))
+		if result.ReloadID != "" {
+			msg += fmt.Sprintf(" (reload ID %s)", result.ReloadID)
+		}
+		e.record(corev1.EventTypeNormal, ReasonReloaded, msg)
+	}
+
+	if result.FallbackToRaw {
+		e.record(corev1.EventTypeWarning, ReasonFallbackToRaw,
+			fmt.Sprintf("Pushed the raw configuration to %s after fine-grained sync failed", instance))
+	}
+
+	if isDriftCheck && len(result.AppliedOperations) > 0 {
+		e.record(corev1.EventTypeWarning, ReasonDriftCorrected,
+			fmt.Sprintf("Corrected configuration drift on %s with %d operations", instance, len(result.AppliedOperations)))
+	}
+}
+
+// RecordSyncError records a failed sync. The failing stage is included for
+// SyncErrors.
+func (e *Emitter) RecordSyncError(instance string, err error) {
+	if err == nil {
+		return
+	}
+
+	msg := fmt.Sprintf("Sync to %s failed: %v", instance, err)
+
+	var syncErr *dataplane.SyncError
+	if errors.As(err, &syncErr) {
+		msg = fmt.Sprintf("Sync to %s failed in %s stage: %s", instance, syncErr.Stage, syncErr.Message)
+		if syncErr.Cause != nil {
+			msg += fmt.Sprintf(": %v", syncErr.Cause)
+		}
+	}
+
+	e.record(corev1.EventTypeWarning, ReasonSyncFailed, msg)
+}
+
+// record records an event unless one with the same reason was recorded less
+// than minInterval ago.
+func (e *Emitter) record(eventType, reason, message string) {
+	e.mu.Lock()
+	now := e.now()
+	if last, ok := e.last[reason]; ok && now.Sub(last) < e.minInterval {
+		e.suppressed[reason]++
+		e.mu.Unlock()
+		return
+	}
+	e.last[reason] = now
+	suppressed := e.suppressed[reason]
+	delete(e.suppressed, reason)
+	e.mu.Unlock()
+
+	if suppressed > 0 {
+		message += fmt.Sprintf(" (%d similar events suppressed)", suppressed)
+	}
+	e.recorder.Event(e.object, eventType, reason, message)
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sevents
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	"haproxy-template-ic/pkg/apis/haproxytemplate/v1alpha1"
+	"haproxy-template-ic/pkg/dataplane"
+)
+
+// newTestEmitter creates an Emitter with a fake recorder and a controllable clock.
+func newTestEmitter(t *testing.T) (*Emitter, *record.FakeRecorder, *time.Time) {
+	t.Helper()
+
+	config := &v1alpha1.HAProxyTemplateConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "haproxy-config", Namespace: "default", UID: "uid-1"},
+	}
+	recorder := record.NewFakeRecorder(10)
+	emitter := NewEmitter(recorder, ConfigReference(config), time.Minute)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	emitter.now = func() time.Time { return now }
+
+	return emitter, recorder, &now
+}
+
+// drain returns the events recorded so far.
+func drain(recorder *record.FakeRecorder) []string {
+	var recorded []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			recorded = append(recorded, event)
+		default:
+			return recorded
+		}
+	}
+}
+
+func TestEmitter_ReloadRecordsEvent(t *testing.T) {
+	emitter, recorder, _ := newTestEmitter(t)
+
+	emitter.ObserveSync("haproxy-0", false, &dataplane.SyncResult{
+		Success:           true,
+		ReloadTriggered:   true,
+		ReloadID:          "42",
+		AppliedOperations: make([]dataplane.AppliedOperation, 3),
+	}, nil)
+
+	recorded := drain(recorder)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "Normal Reloaded Reloaded HAProxy on haproxy-0 after applying 3 operations (reload ID 42)", recorded[0])
+}
+
+func TestEmitter_NoEventWithoutReload(t *testing.T) {
+	emitter, recorder, _ := newTestEmitter(t)
+
+	emitter.ObserveSync("haproxy-0", false, &dataplane.SyncResult{
+		Success:           true,
+		AppliedOperations: make([]dataplane.AppliedOperation, 1),
+	}, nil)
+
+	assert.Empty(t, drain(recorder))
+}
+
+func TestEmitter_DriftCorrected(t *testing.T) {
+	emitter, recorder, _ := newTestEmitter(t)
+
+	// Drift check without changes
+	emitter.ObserveSync("haproxy-0", true, &dataplane.SyncResult{Success: true}, nil)
+	assert.Empty(t, drain(recorder))
+
+	// Drift check that had to apply changes
+	emitter.ObserveSync("haproxy-0", true, &dataplane.SyncResult{
+		Success:           true,
+		AppliedOperations: make([]dataplane.AppliedOperation, 2),
+	}, nil)
+
+	recorded := drain(recorder)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "Warning DriftCorrected Corrected configuration drift on haproxy-0 with 2 operations", recorded[0])
+}
+
+func TestEmitter_SyncError(t *testing.T) {
+	emitter, recorder, now := newTestEmitter(t)
+
+	syncErr := &dataplane.SyncError{Stage: "commit", Message: "transaction rejected", Cause: errors.New("409 conflict")}
+	emitter.ObserveSync("haproxy-0", false, nil, fmt.Errorf("sync failed: %w", syncErr))
+
+	*now = now.Add(2 * time.Minute)
+	emitter.ObserveSync("haproxy-1", false, nil, errors.New("connection refused"))
+
+	recorded := drain(recorder)
+	require.Len(t, recorded, 2)
+	assert.Equal(t, "Warning SyncFailed Sync to haproxy-0 failed in commit stage: transaction rejected: 409 conflict", recorded[0])
+	assert.Equal(t, "Warning SyncFailed Sync to haproxy-1 failed: connection refused", recorded[1])
+}
+
+func TestEmitter_RateLimit(t *testing.T) {
+	emitter, recorder, now := newTestEmitter(t)
+	reload := &dataplane.SyncResult{Success: true, ReloadTriggered: true}
+
+	// A deployment reloading three instances records a single event
+	emitter.ObserveSync("haproxy-0", false, reload, nil)
+	emitter.ObserveSync("haproxy-1", false, reload, nil)
+	emitter.ObserveSync("haproxy-2", false, reload, nil)
+
+	// Other reasons are limited independently
+	emitter.ObserveSync("haproxy-0", false, nil, errors.New("timeout"))
+
+	recorded := drain(recorder)
+	require.Len(t, recorded, 2)
+	assert.Contains(t, recorded[0], "Reloaded HAProxy on haproxy-0")
+	assert.Contains(t, recorded[1], "SyncFailed")
+
+	// After the interval, the next event reports the suppressed ones
+	*now = now.Add(time.Minute)
+	emitter.ObserveSync("haproxy-0", false, reload, nil)
+
+	recorded = drain(recorder)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "Normal Reloaded Reloaded HAProxy on haproxy-0 after applying 0 operations (2 similar events suppressed)", recorded[0])
+}
+
+func TestConfigReference(t *testing.T) {
+	ref := ConfigReference(&v1alpha1.HAProxyTemplateConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "haproxy-config", Namespace: "default", UID: "uid-1"},
+	})
+
+	assert.Equal(t, "haproxy-template-ic.github.io/v1alpha1", ref.APIVersion)
+	assert.Equal(t, "HAProxyTemplateConfig", ref.Kind)
+	assert.Equal(t, "default", ref.Namespace)
+	assert.Equal(t, "haproxy-config", ref.Name)
+	assert.Equal(t, "uid-1", string(ref.UID))
+}
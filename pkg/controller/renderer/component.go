@@ -24,8 +24,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"sync"
 	"time"
 
@@ -45,7 +47,7 @@ const (
 
 // Component implements the renderer component.
 //
-// It subscribes to ReconciliationTriggeredEvent and BecameLeaderEvent,
+// It subscribes to ReconciliationTriggeredEvent, BecameLeaderEvent and LostLeadershipEvent,
 // renders all templates using the template engine and resource stores, and publishes the results
 // via TemplateRenderedEvent or TemplateRenderFailedEvent.
 //
@@ -53,8 +55,8 @@ const (
 // 1. Production version with absolute paths for HAProxy pods (/etc/haproxy/*)
 // 2. Validation version with temp directory paths for controller validation
 //
-// The component caches the last rendered output to support state replay during
-// leadership transitions (when new leader-only components start subscribing).
+// The component renders again on leadership transitions so that new leader-only
+// components receive the current state, rendered with is_leader() returning true.
 //
 // CRT-list Fallback:
 // The component determines CRT-list storage capability from the local HAProxy version
@@ -70,20 +72,21 @@ type Component struct {
 	haproxyPodStore types.Store // HAProxy controller pods store for pod-maxconn calculations
 	logger          *slog.Logger
 
-	// State protected by mutex (for leadership transition replay and capabilities)
-	mu                   sync.RWMutex
-	lastHAProxyConfig    string
-	lastValidationConfig string
-	lastValidationPaths  interface{} // dataplane.ValidationPaths
-	lastAuxiliaryFiles   *dataplane.AuxiliaryFiles
-	lastAuxFileCount     int
-	lastRenderDurationMs int64
-	hasRenderedConfig    bool
+	// State protected by mutex (for leadership transitions)
+	mu                sync.RWMutex
+	hasRenderedConfig bool
 
 	// capabilities defines which features are available for the local HAProxy version.
 	// Determined from local HAProxy version at construction time via CapabilitiesFromVersion().
 	// When capabilities.SupportsCrtList is false, CRT-list paths resolve to general files directory.
 	capabilities dataplane.Capabilities
+
+	// haproxyVersion is the local HAProxy version exposed to templates via haproxy_version().
+	haproxyVersion string
+
+	// isLeader is the leader-election state exposed to templates via is_leader()
+	// (protected by mu). Without leader election the instance is always leader.
+	isLeader bool
 }
 
 // New creates a new Renderer component.
@@ -97,6 +100,7 @@ type Component struct {
 //   - stores: Map of resource type names to their stores (e.g., "ingresses" -> Store)
 //   - haproxyPodStore: Store containing HAProxy controller pods for pod-maxconn calculations
 //   - capabilities: HAProxy capabilities determined from local version
+//   - haproxyVersion: Local HAProxy version (e.g. "3.2.9") returned by haproxy_version()
 //   - logger: Structured logger for component logging
 //
 // Returns:
@@ -108,6 +112,7 @@ func New(
 	stores map[string]types.Store,
 	haproxyPodStore types.Store,
 	capabilities dataplane.Capabilities,
+	haproxyVersion string,
 	logger *slog.Logger,
 ) (*Component, error) {
 	// Log stores received during initialization
@@ -160,6 +165,8 @@ func New(
 		haproxyPodStore: haproxyPodStore,
 		logger:          logger,
 		capabilities:    capabilities,
+		haproxyVersion:  haproxyVersion,
+		isLeader:        !config.Controller.LeaderElection.Enabled,
 	}, nil
 }
 
@@ -169,7 +176,8 @@ func New(
 // The component is already subscribed to the EventBus (subscription happens in New()),
 // so this method only processes events:
 //   - ReconciliationTriggeredEvent: Starts template rendering
-//   - BecameLeaderEvent: Replays last rendered state for new leader-only components
+//   - BecameLeaderEvent: Re-renders the last rendered state as leader for new leader-only components
+//   - LostLeadershipEvent: Renders subsequent reconciliations as follower
 //
 // The component runs until the context is cancelled, at which point it
 // performs cleanup and returns.
@@ -203,6 +211,11 @@ func (c *Component) handleEvent(event busevents.Event) {
 
 	case *events.BecameLeaderEvent:
 		c.handleBecameLeader(ev)
+
+	case *events.LostLeadershipEvent:
+		c.mu.Lock()
+		c.isLeader = false
+		c.mu.Unlock()
 	}
 }
 
@@ -352,14 +365,8 @@ func (c *Component) handleReconciliationTriggered(event *events.ReconciliationTr
 		"auxiliary_files", auxFileCount,
 		"duration_ms", durationMs)
 
-	// Cache rendered output for leadership transition replay
+	// Remember that a config was rendered for leadership transitions
 	c.mu.Lock()
-	c.lastHAProxyConfig = productionHAProxyConfig
-	c.lastValidationConfig = validationHAProxyConfig
-	c.lastValidationPaths = validationPaths
-	c.lastAuxiliaryFiles = productionAuxiliaryFiles
-	c.lastAuxFileCount = auxFileCount
-	c.lastRenderDurationMs = durationMs
 	c.hasRenderedConfig = true
 	c.mu.Unlock()
 
@@ -374,89 +381,68 @@ func (c *Component) handleReconciliationTriggered(event *events.ReconciliationTr
 	))
 }
 
-// handleBecameLeader handles BecameLeaderEvent by re-publishing the last rendered config.
+// handleBecameLeader handles BecameLeaderEvent by re-rendering the last rendered config.
 //
 // This ensures DeploymentScheduler (which starts subscribing only after becoming leader)
 // receives the current rendered state, even if rendering occurred before leadership was acquired.
+// The config is rendered again rather than replayed, since templates using is_leader()
+// render differently on the leader.
 //
 // This prevents the "late subscriber problem" where leader-only components miss events
 // that were published before they started subscribing.
 func (c *Component) handleBecameLeader(_ *events.BecameLeaderEvent) {
-	c.mu.RLock()
+	c.mu.Lock()
+	c.isLeader = true
 	hasState := c.hasRenderedConfig
-	haproxyConfig := c.lastHAProxyConfig
-	validationConfig := c.lastValidationConfig
-	validationPaths := c.lastValidationPaths
-	auxiliaryFiles := c.lastAuxiliaryFiles
-	auxFileCount := c.lastAuxFileCount
-	durationMs := c.lastRenderDurationMs
-	c.mu.RUnlock()
+	c.mu.Unlock()
 
 	if !hasState {
 		c.logger.Debug("became leader but no rendered config available yet, skipping state replay")
 		return
 	}
 
-	c.logger.Info("became leader, re-publishing last rendered config for DeploymentScheduler",
-		"production_config_bytes", len(haproxyConfig),
-		"validation_config_bytes", len(validationConfig),
-		"auxiliary_files", auxFileCount)
+	c.logger.Info("became leader, re-rendering config for DeploymentScheduler")
 
-	// Re-publish the last rendered event to ensure new leader-only components receive it
-	c.eventBus.Publish(events.NewTemplateRenderedEvent(
-		haproxyConfig,
-		validationConfig,
-		validationPaths,
-		auxiliaryFiles,
-		auxFileCount,
-		durationMs,
-	))
+	c.handleReconciliationTriggered(events.NewReconciliationTriggeredEvent("became_leader"))
 }
 
 // renderAuxiliaryFiles renders all auxiliary files (maps, general files, SSL certificates).
+//
+// The files are rendered concurrently and ordered by name within each kind,
+// so the result does not depend on map iteration or render completion order.
 func (c *Component) renderAuxiliaryFiles(context map[string]interface{}) (*dataplane.AuxiliaryFiles, error) {
 	auxFiles := &dataplane.AuxiliaryFiles{}
 
-	// Render map files
-	for name := range c.config.Maps {
-		rendered, err := c.engine.Render(name, context)
-		if err != nil {
-			c.publishRenderFailure(name, err)
-			return nil, err
-		}
+	mapNames := slices.Sorted(maps.Keys(c.config.Maps))
+	fileNames := slices.Sorted(maps.Keys(c.config.Files))
+	certNames := slices.Sorted(maps.Keys(c.config.SSLCertificates))
 
-		auxFiles.MapFiles = append(auxFiles.MapFiles, auxiliaryfiles.MapFile{
-			Path:    name,
-			Content: rendered,
-		})
-	}
+	names := slices.Concat(mapNames, fileNames, certNames)
+	results := c.engine.RenderAll(names, context, 0)
 
-	// Render general files
-	for name := range c.config.Files {
-		rendered, err := c.engine.Render(name, context)
-		if err != nil {
-			c.publishRenderFailure(name, err)
-			return nil, err
+	for i, result := range results {
+		if result.Err != nil {
+			c.publishRenderFailure(result.TemplateName, result.Err)
+			return nil, result.Err
 		}
 
-		auxFiles.GeneralFiles = append(auxFiles.GeneralFiles, auxiliaryfiles.GeneralFile{
-			Filename: name,
-			Content:  rendered,
-		})
-	}
-
-	// Render SSL certificates
-	for name := range c.config.SSLCertificates {
-		rendered, err := c.engine.Render(name, context)
-		if err != nil {
-			c.publishRenderFailure(name, err)
-			return nil, err
+		switch {
+		case i < len(mapNames):
+			auxFiles.MapFiles = append(auxFiles.MapFiles, auxiliaryfiles.MapFile{
+				Path:    result.TemplateName,
+				Content: result.Output,
+			})
+		case i < len(mapNames)+len(fileNames):
+			auxFiles.GeneralFiles = append(auxFiles.GeneralFiles, auxiliaryfiles.GeneralFile{
+				Filename: result.TemplateName,
+				Content:  result.Output,
+			})
+		default:
+			auxFiles.SSLCertificates = append(auxFiles.SSLCertificates, auxiliaryfiles.SSLCertificate{
+				Path:    result.TemplateName,
+				Content: result.Output,
+			})
 		}
-
-		auxFiles.SSLCertificates = append(auxFiles.SSLCertificates, auxiliaryfiles.SSLCertificate{
-			Path:    name,
-			Content: rendered,
-		})
 	}
 
 	return auxFiles, nil
@@ -85,7 +85,7 @@ func TestNew_Success(t *testing.T) {
 
 	// Use capabilities for HAProxy 3.2+ to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, "3.2.0", logger)
 
 	require.NoError(t, err)
 	assert.NotNil(t, renderer)
@@ -113,7 +113,7 @@ func TestNew_InvalidTemplate(t *testing.T) {
 
 	// Use capabilities for HAProxy 3.2+ to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, "3.2.0", logger)
 
 	assert.Error(t, err)
 	assert.Nil(t, renderer)
@@ -158,7 +158,7 @@ defaults
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	// Subscribe to events
@@ -243,7 +243,7 @@ func TestRenderer_WithAuxiliaryFiles(t *testing.T) {
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	eventChan := bus.Subscribe(50)
@@ -305,7 +305,7 @@ func TestRenderer_RenderFailure(t *testing.T) {
 
 	// Use capabilities for HAProxy 3.2+ to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	eventChan := bus.Subscribe(50)
@@ -363,7 +363,7 @@ func TestRenderer_EmptyStores(t *testing.T) {
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	eventChan := bus.Subscribe(50)
@@ -437,7 +437,7 @@ func TestRenderer_MultipleStores(t *testing.T) {
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	eventChan := bus.Subscribe(50)
@@ -492,7 +492,7 @@ func TestRenderer_ContextCancellation(t *testing.T) {
 
 	// Use capabilities for HAProxy 3.2+ to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, haproxyPodStore, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	bus.Start()
@@ -542,7 +542,7 @@ func TestRenderer_MultipleReconciliations(t *testing.T) {
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	eventChan := bus.Subscribe(50)
@@ -630,7 +630,7 @@ func TestBuildRenderingContext(t *testing.T) {
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	// Build context
@@ -671,6 +671,48 @@ func TestBuildRenderingContext(t *testing.T) {
 	assert.Len(t, services, 1)
 }
 
+// TestBuildRenderingContext_LeaderStatus tests the leader status exposed to is_leader().
+func TestBuildRenderingContext_LeaderStatus(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
+	pathResolver := &templating.PathResolver{MapsDir: "/etc/haproxy/maps"}
+
+	newRenderer := func(t *testing.T, leaderElection bool) *Component {
+		t.Helper()
+		cfg := &config.Config{
+			HAProxyConfig: config.HAProxyConfig{
+				Template: "global\n    daemon\n",
+			},
+		}
+		cfg.Controller.LeaderElection.Enabled = leaderElection
+
+		renderer, err := New(busevents.NewEventBus(100), cfg, map[string]types.Store{}, &mockStore{}, capabilities, "3.2.0", logger)
+		require.NoError(t, err)
+		return renderer
+	}
+
+	isLeader := func(renderer *Component) interface{} {
+		ctx, _ := renderer.buildRenderingContext(pathResolver)
+		return ctx[templating.LeaderContextKey]
+	}
+
+	t.Run("single instance is leader", func(t *testing.T) {
+		renderer := newRenderer(t, false)
+		assert.Equal(t, true, isLeader(renderer))
+	})
+
+	t.Run("follows leader election", func(t *testing.T) {
+		renderer := newRenderer(t, true)
+		assert.Equal(t, false, isLeader(renderer))
+
+		renderer.handleEvent(events.NewBecameLeaderEvent("pod-1"))
+		assert.Equal(t, true, isLeader(renderer))
+
+		renderer.handleEvent(events.NewLostLeadershipEvent("pod-1", "lease_expired"))
+		assert.Equal(t, false, isLeader(renderer))
+	})
+}
+
 // TestPathResolverWithCapabilities_CRTListFallback tests CRT-list path resolution
 // based on HAProxy version capabilities. When CRT-list storage is not supported
 // (HAProxy < 3.2), CRT-list files should use the general files directory.
@@ -734,7 +776,7 @@ frontend test
 			assert.Equal(t, tt.expectCrtListSupported, capabilities.SupportsCrtList, "SupportsCrtList mismatch")
 			assert.Equal(t, tt.expectMapSupported, capabilities.SupportsMapStorage, "SupportsMapStorage mismatch")
 
-			renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+			renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 			require.NoError(t, err)
 
 			eventChan := bus.Subscribe(50)
@@ -813,7 +855,7 @@ frontend test
 
 	// Use HAProxy 3.2+ version to enable CRT-list support in tests
 	capabilities := dataplane.CapabilitiesFromVersion(&dataplane.Version{Major: 3, Minor: 2, Full: "3.2.0"})
-	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, logger)
+	renderer, err := New(bus, cfg, stores, &mockStore{}, capabilities, "3.2.0", logger)
 	require.NoError(t, err)
 
 	// Get the path resolver from the engine
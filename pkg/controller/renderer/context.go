@@ -15,6 +15,7 @@
 package renderer
 
 import (
+	"os"
 	"sort"
 
 	"haproxy-template-ic/pkg/core/config"
@@ -39,6 +40,7 @@ import (
 //	  "config": Config,  // Controller configuration (e.g., config.debug.headers.enabled)
 //	  "file_registry": FileRegistry,  // For dynamic auxiliary file registration
 //	  "pathResolver": PathResolver,  // For resolving file paths (e.g., {{ pathResolver.GetPath("cert.pem", "cert") }})
+//	  "env_vars": {"POD_NAME": "...", ...},  // Whitelisted controller environment (see TemplateEnvironmentVariables)
 //	  "capabilities": {  // HAProxy/DataPlane API capabilities
 //	    "supports_waf": true,           // WAF (Enterprise only)
 //	    "supports_keepalived": true,    // Keepalived/VRRP (Enterprise only)
@@ -83,6 +85,10 @@ import (
 //
 //	use_backend {{ backend_name }} if { req.hdr(host) -f {{ pathResolver.GetPath("host.map", "map") }} }
 //
+// And read whitelisted controller environment variables with a fallback:
+//
+//	# Rendered by {{ env("POD_NAME", "unknown") }} on node {{ env("NODE_NAME", "unknown") }}
+//
 // And conditionally generate Enterprise-specific configuration:
 //
 //	{%- if capabilities.supports_waf %}
@@ -138,6 +144,15 @@ func (c *Component) buildRenderingContext(pathResolver *templating.PathResolver)
 		"dataplane":         c.config.Dataplane,    // Add dataplane config for absolute path access
 		"capabilities":      c.capabilitiesToMap(), // Add HAProxy/DataPlane API capabilities
 	}
+	context[templating.EnvContextKey] = BuildTemplateEnvironment(os.LookupEnv)
+	context[templating.HAProxyVersionContextKey] = c.haproxyVersion
+	context[templating.WarningsContextKey] = templating.WarningFunc(func(message string) {
+		c.logger.Warn("template warning", "message", message)
+	})
+
+	c.mu.RLock()
+	context[templating.LeaderContextKey] = c.isLeader
+	c.mu.RUnlock()
 
 	// Merge extraContext variables into top-level context
 	MergeExtraContextInto(context, c.config)
@@ -188,6 +203,31 @@ func sortSnippetsByPriority(snippets map[string]config.TemplateSnippet) []string
 	return names
 }
 
+// TemplateEnvironmentVariables lists the controller environment variables exposed
+// to templates through the env() global function.
+//
+// Only these variables are injected into the rendering context. The process
+// environment may contain credentials, so it is never exposed as a whole.
+var TemplateEnvironmentVariables = []string{
+	"POD_NAME",
+	"POD_NAMESPACE",
+	"NODE_NAME",
+}
+
+// BuildTemplateEnvironment collects the whitelisted environment variables using lookup.
+// Variables that are not set are omitted so env() falls back to its default.
+//
+// Production code passes os.LookupEnv.
+func BuildTemplateEnvironment(lookup func(string) (string, bool)) map[string]string {
+	env := make(map[string]string, len(TemplateEnvironmentVariables))
+	for _, name := range TemplateEnvironmentVariables {
+		if value, ok := lookup(name); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
+
 // MergeExtraContextInto merges the extraContext variables from the config into the provided template context.
 //
 // This allows templates to access custom variables directly (e.g., {{ debug.enabled }})
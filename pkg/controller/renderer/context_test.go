@@ -131,3 +131,22 @@ func TestSortSnippetsByPriority(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildTemplateEnvironment(t *testing.T) {
+	processEnv := map[string]string{
+		"POD_NAME":           "haproxy-template-ic-0",
+		"NODE_NAME":          "worker-1",
+		"DATAPLANE_PASSWORD": "secret",
+	}
+	lookup := func(name string) (string, bool) {
+		value, ok := processEnv[name]
+		return value, ok
+	}
+
+	env := BuildTemplateEnvironment(lookup)
+
+	assert.Equal(t, map[string]string{
+		"POD_NAME":  "haproxy-template-ic-0",
+		"NODE_NAME": "worker-1",
+	}, env, "only whitelisted variables that are set should be exposed")
+}
@@ -0,0 +1,152 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testrunner
+
+import (
+	"fmt"
+	"sort"
+
+	"haproxy-template-ic/pkg/dataplane"
+)
+
+// DefaultDeterminismRuns is the number of renders per test used by
+// CheckDeterminism when runs is not set.
+const DefaultDeterminismRuns = 5
+
+// DeterminismResult contains the result of rendering a single validation test
+// repeatedly with identical input.
+type DeterminismResult struct {
+	// TestName is the name of the test whose fixtures were rendered.
+	TestName string
+
+	// Runs is the number of renders performed.
+	Runs int
+
+	// NonDeterministic lists the templates whose output differed between
+	// renders, sorted by name. haproxy.cfg is listed as "haproxy.cfg",
+	// auxiliary files by their name.
+	NonDeterministic []string
+
+	// RenderError is set if a render failed.
+	RenderError string
+}
+
+// Deterministic returns true if every render produced identical output.
+func (r *DeterminismResult) Deterministic() bool {
+	return r.RenderError == "" && len(r.NonDeterministic) == 0
+}
+
+// CheckDeterminism renders the fixtures of each validation test (or only of
+// testName, if set) runs times and reports the templates whose output was not
+// identical across renders.
+//
+// Templates with unstable output, e.g. from iterating a map, change the
+// rendered configuration without any change to the cluster and cause
+// needless HAProxy reloads. Assertions are not executed.
+func (r *Runner) CheckDeterminism(testName string, runs int) ([]DeterminismResult, error) {
+	if runs <= 0 {
+		runs = DefaultDeterminismRuns
+	}
+
+	testsToCheck := r.config.ValidationTests
+	if testName != "" {
+		testsToCheck = r.filterTests(r.config.ValidationTests, testName)
+		if len(testsToCheck) == 0 {
+			return nil, fmt.Errorf("test %q not found", testName)
+		}
+	}
+
+	names := make([]string, 0, len(testsToCheck))
+	for name := range testsToCheck {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make([]DeterminismResult, 0, len(names))
+	for _, name := range names {
+		results = append(results, r.checkTestDeterminism(name, runs))
+	}
+
+	return results, nil
+}
+
+// checkTestDeterminism renders a single test runs times and compares each
+// render to the first one.
+func (r *Runner) checkTestDeterminism(testName string, runs int) DeterminismResult {
+	result := DeterminismResult{TestName: testName, Runs: runs}
+
+	stores, err := r.createStoresFromFixtures(r.testFixtures(testName, r.config.ValidationTests[testName]))
+	if err != nil {
+		result.RenderError = fmt.Sprintf("failed to create fixture stores: %v", err)
+		return result
+	}
+
+	var first map[string]string
+	differing := make(map[string]bool)
+	for i := 0; i < runs; i++ {
+		haproxyConfig, auxiliaryFiles, err := r.renderWithStores(r.engineTemplate, stores, r.validationPaths)
+		if err != nil {
+			result.RenderError = err.Error()
+			return result
+		}
+
+		outputs := renderedOutputs(haproxyConfig, auxiliaryFiles)
+		if first == nil {
+			first = outputs
+			continue
+		}
+
+		for name, content := range outputs {
+			if previous, ok := first[name]; !ok || previous != content {
+				differing[name] = true
+			}
+		}
+		for name := range first {
+			if _, ok := outputs[name]; !ok {
+				differing[name] = true
+			}
+		}
+	}
+
+	for name := range differing {
+		result.NonDeterministic = append(result.NonDeterministic, name)
+	}
+	sort.Strings(result.NonDeterministic)
+
+	return result
+}
+
+// renderedOutputs maps each rendered template to its output.
+func renderedOutputs(haproxyConfig string, auxiliaryFiles *dataplane.AuxiliaryFiles) map[string]string {
+	outputs := map[string]string{"haproxy.cfg": haproxyConfig}
+	if auxiliaryFiles == nil {
+		return outputs
+	}
+
+	for _, mapFile := range auxiliaryFiles.MapFiles {
+		outputs[mapFile.Path] = mapFile.Content
+	}
+	for _, file := range auxiliaryFiles.GeneralFiles {
+		outputs[file.Filename] = file.Content
+	}
+	for _, cert := range auxiliaryFiles.SSLCertificates {
+		outputs[cert.Path] = cert.Content
+	}
+	for _, crtList := range auxiliaryFiles.CRTListFiles {
+		outputs[crtList.Path] = crtList.Content
+	}
+
+	return outputs
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testrunner
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"haproxy-template-ic/pkg/apis/haproxytemplate/v1alpha1"
+	"haproxy-template-ic/pkg/controller/conversion"
+	"haproxy-template-ic/pkg/dataplane"
+	"haproxy-template-ic/pkg/templating"
+)
+
+func TestRunner_CheckDeterminism(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	labels := map[string]interface{}{}
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		labels[key] = "value-" + key
+	}
+
+	config := &v1alpha1.HAProxyTemplateConfigSpec{
+		HAProxyConfig: v1alpha1.HAProxyConfig{
+			Template: `{%- for svc in resources.services.List() %}
+backend {{ svc.metadata.name }}
+{%- endfor %}
+`,
+		},
+		Maps: map[string]v1alpha1.MapFile{
+			// Iterates a map directly, which has no stable order
+			"unstable.map": {Template: `{%- for svc in resources.services.List() %}
+{%- for value in svc.metadata.labels.items() %}
+{{ value }}
+{%- endfor %}
+{%- endfor %}
+`},
+			// Iterating the sorted keys is stable
+			"stable.map": {Template: `{%- for svc in resources.services.List() %}
+{%- for key in svc.metadata.labels.keys() %}
+{{ key }} {{ svc.metadata.labels[key] }}
+{%- endfor %}
+{%- endfor %}
+`},
+		},
+		WatchedResources: map[string]v1alpha1.WatchedResource{
+			"services": {
+				APIVersion: "v1",
+				Resources:  "services",
+				IndexBy:    []string{"metadata.namespace", "metadata.name"},
+			},
+		},
+		ValidationTests: map[string]v1alpha1.ValidationTest{
+			"labels": {
+				Fixtures: map[string][]runtime.RawExtension{
+					"services": {mustMarshalRawExtension(map[string]interface{}{
+						"metadata": map[string]interface{}{"name": "web", "namespace": "default", "labels": labels},
+					})},
+				},
+			},
+		},
+	}
+
+	templates := map[string]string{"haproxy.cfg": config.HAProxyConfig.Template}
+	for name, mapFile := range config.Maps {
+		templates[name] = mapFile.Template
+	}
+	engine, err := templating.New(templating.EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	cfg, err := conversion.ConvertSpec(config)
+	require.NoError(t, err)
+
+	runner := New(cfg, engine, &dataplane.ValidationPaths{}, Options{Logger: logger})
+
+	results, err := runner.CheckDeterminism("", 20)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	result := results[0]
+	assert.Equal(t, "labels", result.TestName)
+	assert.Equal(t, 20, result.Runs)
+	assert.Empty(t, result.RenderError)
+	assert.False(t, result.Deterministic())
+	assert.Equal(t, []string{"unstable.map"}, result.NonDeterministic)
+
+	_, err = runner.CheckDeterminism("missing", 0)
+	assert.ErrorContains(t, err, `test "missing" not found`)
+}
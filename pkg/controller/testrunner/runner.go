@@ -62,6 +62,7 @@ type Runner struct {
 	debugFilters    bool                   // Enable detailed filter operation logging
 	traceTemplates  bool                   // Enable template execution tracing
 	capabilities    dataplane.Capabilities // HAProxy/DataPlane API capabilities
+	haproxyVersion  string                 // HAProxy version for haproxy_version()
 }
 
 // testEntry is a tuple of test name and test definition for worker processing.
@@ -90,6 +91,9 @@ type Options struct {
 	// Capabilities defines which features are available for the local HAProxy version.
 	// Used to determine path resolution (e.g., CRT-list paths fallback when not supported).
 	Capabilities dataplane.Capabilities
+
+	// HAProxyVersion is the HAProxy version returned by haproxy_version() in templates.
+	HAProxyVersion string
 }
 
 // TestResults contains the results of running validation tests.
@@ -210,6 +214,7 @@ func New(
 		debugFilters:    options.DebugFilters,
 		traceTemplates:  traceTemplates,
 		capabilities:    options.Capabilities,
+		haproxyVersion:  options.HAProxyVersion,
 	}
 }
 
@@ -548,21 +553,7 @@ func (r *Runner) runSingleTest(ctx context.Context, testName string, test config
 	}
 
 	// 1. Merge global fixtures with test-specific fixtures
-	fixtures := test.Fixtures
-
-	// Check for global fixtures in validationTests._global
-	if globalTest, hasGlobal := r.config.ValidationTests["_global"]; hasGlobal {
-		r.logger.Debug("Merging global fixtures with test fixtures",
-			"test", testName,
-			"global_fixture_types", len(globalTest.Fixtures),
-			"test_fixture_types", len(test.Fixtures))
-
-		fixtures = mergeFixtures(globalTest.Fixtures, test.Fixtures)
-
-		r.logger.Debug("Fixture merge completed",
-			"test", testName,
-			"merged_fixture_types", len(fixtures))
-	}
+	fixtures := r.testFixtures(testName, test)
 
 	// 2. Create resource stores from merged fixtures
 	stores, err := r.createStoresFromFixtures(fixtures)
@@ -610,6 +601,47 @@ func (r *Runner) runSingleTest(ctx context.Context, testName string, test config
 	return result
 }
 
+// testFixtures returns the fixtures of a test merged with the global fixtures
+// from validationTests._global, if present.
+func (r *Runner) testFixtures(testName string, test config.ValidationTest) map[string][]interface{} {
+	globalTest, hasGlobal := r.config.ValidationTests["_global"]
+	if !hasGlobal {
+		return test.Fixtures
+	}
+
+	r.logger.Debug("Merging global fixtures with test fixtures",
+		"test", testName,
+		"global_fixture_types", len(globalTest.Fixtures),
+		"test_fixture_types", len(test.Fixtures))
+
+	fixtures := mergeFixtures(globalTest.Fixtures, test.Fixtures)
+
+	r.logger.Debug("Fixture merge completed",
+		"test", testName,
+		"merged_fixture_types", len(fixtures))
+
+	return fixtures
+}
+
+// RenderTest renders the HAProxy configuration and auxiliary files for the
+// fixtures of a single validation test without running its assertions.
+//
+// Auxiliary file paths in the rendered output are resolved against paths, so
+// callers that deploy the result should pass the production directories.
+func (r *Runner) RenderTest(testName string, paths *dataplane.ValidationPaths) (string, *dataplane.AuxiliaryFiles, error) {
+	test, exists := r.config.ValidationTests[testName]
+	if !exists {
+		return "", nil, fmt.Errorf("validation test %q not found", testName)
+	}
+
+	stores, err := r.createStoresFromFixtures(r.testFixtures(testName, test))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create fixture stores: %w", err)
+	}
+
+	return r.renderWithStores(r.engineTemplate, stores, paths)
+}
+
 // storeAuxiliaryFiles stores rendered auxiliary files in the test result for --dump-rendered flag.
 func (r *Runner) storeAuxiliaryFiles(result *TestResult, auxiliaryFiles *dataplane.AuxiliaryFiles) {
 	if auxiliaryFiles == nil {
@@ -764,6 +796,7 @@ func (r *Runner) buildRenderingContext(stores map[string]types.Store, validation
 		"pathResolver":      pathResolver,
 		"dataplane":         r.config.Dataplane, // Add dataplane config for absolute path access
 	}
+	context[templating.HAProxyVersionContextKey] = r.haproxyVersion
 
 	// Merge extraContext variables into top-level context
 	renderer.MergeExtraContextInto(context, r.config)
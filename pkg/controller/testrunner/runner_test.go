@@ -400,6 +400,59 @@ backend {{ svc.metadata.namespace }}-{{ svc.metadata.name }}
 	}
 }
 
+func TestRunner_RenderTest(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	service := func(name string) runtime.RawExtension {
+		return mustMarshalRawExtension(map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name, "namespace": "default"},
+		})
+	}
+
+	config := &v1alpha1.HAProxyTemplateConfigSpec{
+		HAProxyConfig: v1alpha1.HAProxyConfig{
+			Template: `{%- for svc in resources.services.List() %}
+backend {{ svc.metadata.name }}
+{%- endfor %}
+`,
+		},
+		WatchedResources: map[string]v1alpha1.WatchedResource{
+			"services": {
+				APIVersion: "v1",
+				Resources:  "services",
+				IndexBy:    []string{"metadata.namespace", "metadata.name"},
+			},
+		},
+		ValidationTests: map[string]v1alpha1.ValidationTest{
+			"_global": {
+				Fixtures: map[string][]runtime.RawExtension{"services": {service("global-svc")}},
+			},
+			"local": {
+				Fixtures: map[string][]runtime.RawExtension{"services": {service("local-svc")}},
+			},
+		},
+	}
+
+	engine, err := templating.New(templating.EngineTypeGonja, map[string]string{
+		"haproxy.cfg": config.HAProxyConfig.Template,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	cfg, err := conversion.ConvertSpec(config)
+	require.NoError(t, err)
+
+	runner := New(cfg, engine, &dataplane.ValidationPaths{}, Options{Logger: logger})
+
+	haproxyConfig, auxFiles, err := runner.RenderTest("local", &dataplane.ValidationPaths{})
+	require.NoError(t, err)
+	assert.Contains(t, haproxyConfig, "backend global-svc")
+	assert.Contains(t, haproxyConfig, "backend local-svc")
+	assert.NotNil(t, auxFiles)
+
+	_, _, err = runner.RenderTest("missing", &dataplane.ValidationPaths{})
+	assert.ErrorContains(t, err, `validation test "missing" not found`)
+}
+
 func TestRunner_RenderError(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
 
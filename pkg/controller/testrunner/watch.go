@@ -0,0 +1,109 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testrunner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// FileWatcher triggers re-validation when a config file changes on disk.
+//
+// It is used by `controller validate --watch` to give template authors
+// continuous feedback. Rapid successive changes (e.g., an editor writing a
+// file in several chunks) are debounced into a single callback.
+type FileWatcher struct {
+	path     string
+	debounce time.Duration
+	watcher  *fsnotify.Watcher
+}
+
+// NewFileWatcher starts watching the file at path.
+//
+// The parent directory is watched instead of the file itself, because many
+// editors save by renaming a temporary file over the original, which would
+// silently drop a watch on the original file.
+func NewFileWatcher(path string, debounce time.Duration) (*FileWatcher, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", absPath, err)
+	}
+
+	return &FileWatcher{
+		path:     absPath,
+		debounce: debounce,
+		watcher:  watcher,
+	}, nil
+}
+
+// Run invokes onChange after every debounced change of the watched file.
+//
+// Blocks until the context is cancelled or the watcher fails. The watcher is
+// closed when Run returns.
+func (w *FileWatcher) Run(ctx context.Context, onChange func()) error {
+	defer w.watcher.Close()
+
+	var timer *time.Timer
+	var fire <-chan time.Time
+
+	for {
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil
+
+		case event, ok := <-w.watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(event.Name) != w.path || !event.Has(fsnotify.Write) && !event.Has(fsnotify.Create) {
+				continue
+			}
+
+			// Restart the debounce window on every change
+			if timer != nil {
+				timer.Stop()
+			}
+			timer = time.NewTimer(w.debounce)
+			fire = timer.C
+
+		case <-fire:
+			fire = nil
+			onChange()
+
+		case err, ok := <-w.watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("file watcher failed: %w", err)
+		}
+	}
+}
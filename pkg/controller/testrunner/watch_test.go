@@ -0,0 +1,68 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileWatcher_TriggersOnChange(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("v1"), 0o600))
+
+	watcher, err := NewFileWatcher(configPath, 50*time.Millisecond)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Run(ctx, func() { calls.Add(1) })
+	}()
+
+	// Rapid saves are debounced into a single re-validation
+	for _, content := range []string{"v2", "v3", "v4"} {
+		require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	// Editors that save atomically rename a temp file over the original
+	tmpPath := filepath.Join(dir, ".config.yaml.swp")
+	require.NoError(t, os.WriteFile(tmpPath, []byte("v5"), 0o600))
+	require.NoError(t, os.Rename(tmpPath, configPath))
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	// Changes to other files in the directory are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x"), 0o600))
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int32(2), calls.Load())
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestNewFileWatcher_MissingDirectory(t *testing.T) {
+	_, err := NewFileWatcher(filepath.Join(t.TempDir(), "missing", "config.yaml"), time.Millisecond)
+	require.Error(t, err)
+}
@@ -181,26 +181,47 @@ func (v *HAProxyValidatorComponent) handleTemplateRendered(event *events.Templat
 		return
 	}
 
+	// Report likely mistakes that passed validation as warnings
+	warnings := v.lintConfiguration(event.ValidationHAProxyConfig)
+
 	// Validation succeeded
 	durationMs := time.Since(startTime).Milliseconds()
 
 	v.logger.Info("HAProxy configuration validation completed",
-		"duration_ms", durationMs)
+		"duration_ms", durationMs,
+		"warnings", len(warnings))
 
 	// Cache validation result for leadership transition replay
 	v.mu.Lock()
 	v.lastValidationSucceeded = true
-	v.lastValidationWarnings = []string{} // No warnings
+	v.lastValidationWarnings = warnings
 	v.lastValidationDurationMs = durationMs
 	v.hasValidationResult = true
 	v.mu.Unlock()
 
 	v.eventBus.Publish(events.NewValidationCompletedEvent(
-		[]string{}, // No warnings
+		warnings,
 		durationMs,
 	))
 }
 
+// lintConfiguration returns the lint findings of a validated configuration
+// as warning messages.
+func (v *HAProxyValidatorComponent) lintConfiguration(config string) []string {
+	findings, err := dataplane.LintConfiguration(config)
+	if err != nil {
+		v.logger.Warn("failed to lint HAProxy configuration", "error", err)
+		return []string{}
+	}
+
+	warnings := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		v.logger.Warn("HAProxy configuration lint finding", "finding", finding.String())
+		warnings = append(warnings, finding.String())
+	}
+	return warnings
+}
+
 // handleBecameLeader handles BecameLeaderEvent by re-publishing the last validation result.
 //
 // This ensures DeploymentScheduler (which starts subscribing only after becoming leader)
@@ -149,6 +149,11 @@ type ControllerConfig struct {
 	// Default: 9090
 	MetricsPort int `yaml:"metrics_port"`
 
+	// EmitKubernetesEvents records significant sync operations (reloads,
+	// failures, drift corrections) as Kubernetes Events on the HAProxyTemplateConfig.
+	// Default: false
+	EmitKubernetesEvents bool `yaml:"emit_kubernetes_events"`
+
 	// LeaderElection configures leader election for high availability.
 	LeaderElection LeaderElectionConfig `yaml:"leader_election"`
 }
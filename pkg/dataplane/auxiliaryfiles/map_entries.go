@@ -0,0 +1,291 @@
+package auxiliaryfiles
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"haproxy-template-ic/pkg/dataplane/client"
+)
+
+// DefaultMaxMapEntryChanges is the number of entry changes above which a map
+// file is replaced as a whole instead of updated entry by entry.
+const DefaultMaxMapEntryChanges = 1000
+
+// maxMapLineSize is the longest map file line the entry diff accepts.
+const maxMapLineSize = 1024 * 1024
+
+// ErrMapNotSorted is returned by DiffMapEntries when a map file is not sorted
+// by key or contains a key more than once.
+var ErrMapNotSorted = errors.New("map entries are not sorted by key")
+
+// errTooManyMapEntryChanges aborts an entry diff that exceeds the change limit.
+var errTooManyMapEntryChanges = errors.New("too many map entry changes")
+
+// MapEntryOp is the kind of change to a map entry.
+type MapEntryOp int
+
+const (
+	// MapEntryAdd adds a key that is not in the current map.
+	MapEntryAdd MapEntryOp = iota
+
+	// MapEntryReplace changes the value of an existing key.
+	MapEntryReplace
+
+	// MapEntryDelete removes a key that is not in the desired map.
+	MapEntryDelete
+)
+
+// MapEntryChange is a single entry change between two versions of a map file.
+type MapEntryChange struct {
+	Op    MapEntryOp
+	Key   string
+	Value string
+}
+
+// mapEntryReader reads the entries of a map file sorted by key, one at a time.
+// The current key and value are only valid until the next call to next.
+type mapEntryReader struct {
+	scanner *bufio.Scanner
+	key     []byte
+	value   []byte
+	prevKey []byte
+	done    bool
+	started bool
+}
+
+func newMapEntryReader(r io.Reader) *mapEntryReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMapLineSize)
+	return &mapEntryReader{scanner: scanner}
+}
+
+// next advances to the next entry, skipping blank lines and comments.
+// It returns ErrMapNotSorted if the key does not sort after the previous one.
+func (r *mapEntryReader) next() error {
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		key, value := line, []byte(nil)
+		if i := bytes.IndexAny(line, " \t"); i >= 0 {
+			key, value = line[:i], bytes.TrimSpace(line[i+1:])
+		}
+
+		r.prevKey, r.key = r.key, r.prevKey
+		r.key = append(r.key[:0], key...)
+		r.value = append(r.value[:0], value...)
+		if r.started && bytes.Compare(r.key, r.prevKey) <= 0 {
+			return fmt.Errorf("%w: %q after %q", ErrMapNotSorted, r.key, r.prevKey)
+		}
+		r.started = true
+		return nil
+	}
+	r.done = true
+	return r.scanner.Err()
+}
+
+// DiffMapEntries compares two map files entry by entry and calls emit for
+// every change that turns current into desired.
+//
+// Both files are read as streams and must be sorted by key (byte order)
+// without duplicate keys, so memory use does not grow with the map size.
+// ErrMapNotSorted is returned as soon as either file violates this; changes
+// emitted up to that point are then incomplete. Blank lines and comments are
+// ignored, and whitespace between key and value is not significant.
+func DiffMapEntries(current, desired io.Reader, emit func(MapEntryChange) error) error {
+	cur, des := newMapEntryReader(current), newMapEntryReader(desired)
+	if err := cur.next(); err != nil {
+		return fmt.Errorf("current map: %w", err)
+	}
+	if err := des.next(); err != nil {
+		return fmt.Errorf("desired map: %w", err)
+	}
+
+	for !cur.done || !des.done {
+		var change *MapEntryChange
+		advanceCur, advanceDes := false, false
+
+		switch {
+		case des.done || (!cur.done && bytes.Compare(cur.key, des.key) < 0):
+			change = &MapEntryChange{Op: MapEntryDelete, Key: string(cur.key)}
+			advanceCur = true
+		case cur.done || bytes.Compare(des.key, cur.key) < 0:
+			change = &MapEntryChange{Op: MapEntryAdd, Key: string(des.key), Value: string(des.value)}
+			advanceDes = true
+		default:
+			if !bytes.Equal(cur.value, des.value) {
+				change = &MapEntryChange{Op: MapEntryReplace, Key: string(des.key), Value: string(des.value)}
+			}
+			advanceCur, advanceDes = true, true
+		}
+
+		if change != nil {
+			if err := emit(*change); err != nil {
+				return err
+			}
+		}
+		if advanceCur {
+			if err := cur.next(); err != nil {
+				return fmt.Errorf("current map: %w", err)
+			}
+		}
+		if advanceDes {
+			if err := des.next(); err != nil {
+				return fmt.Errorf("desired map: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MapEntryUpdate is a map file update applied as Runtime API entry changes.
+type MapEntryUpdate struct {
+	// File is the desired version of the map file.
+	File MapFile
+
+	// Changes are the entry changes from the stored to the desired version.
+	Changes []MapEntryChange
+}
+
+// SyncMapFilesIncremental works like SyncMapFiles, but updates map files
+// through runtime entry changes instead of replacing them, which avoids a
+// reload. See PlanMapEntryUpdates for the files that are still replaced.
+//
+// Entry changes take effect in the running HAProxy at once. Callers that
+// sync map files together with a configuration transaction should use
+// PlanMapEntryUpdates and apply the entry updates after the commit instead.
+func SyncMapFilesIncremental(ctx context.Context, c *client.DataplaneClient, diff *MapFileDiff, maxEntryChanges int) error {
+	files, updates, err := PlanMapEntryUpdates(ctx, c, diff, maxEntryChanges)
+	if err != nil {
+		return err
+	}
+	if err := SyncMapFiles(ctx, c, files); err != nil {
+		return err
+	}
+	return ApplyMapEntryUpdates(ctx, c, updates)
+}
+
+// PlanMapEntryUpdates splits the updates of diff into those applied as
+// Runtime API entry changes and a copy of diff holding the remaining
+// creates, updates and deletes, which are synced as files.
+//
+// A map file is still replaced as a whole if either version is not sorted by
+// key, more than maxEntryChanges entries change (DefaultMaxMapEntryChanges if
+// zero), the files differ only in comments or formatting, or the API version
+// lacks runtime map support.
+func PlanMapEntryUpdates(ctx context.Context, c *client.DataplaneClient, diff *MapFileDiff, maxEntryChanges int) (*MapFileDiff, []MapEntryUpdate, error) {
+	if diff == nil {
+		return nil, nil, nil
+	}
+	if maxEntryChanges <= 0 {
+		maxEntryChanges = DefaultMaxMapEntryChanges
+	}
+
+	files := &MapFileDiff{ToCreate: diff.ToCreate, ToDelete: diff.ToDelete}
+	if !c.Capabilities().SupportsRuntimeMaps {
+		files.ToUpdate = diff.ToUpdate
+		return files, nil, nil
+	}
+
+	var updates []MapEntryUpdate
+	for _, file := range diff.ToUpdate {
+		changes, err := planMapFileEntries(ctx, c, file, maxEntryChanges)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to update file '%s': %w", file.Path, err)
+		}
+		if len(changes) == 0 {
+			files.ToUpdate = append(files.ToUpdate, file)
+			continue
+		}
+		updates = append(updates, MapEntryUpdate{File: file, Changes: changes})
+	}
+
+	return files, updates, nil
+}
+
+// planMapFileEntries returns the entry changes between the stored and the
+// desired version of file. It returns no changes without an error if the
+// file has to be replaced as a whole instead.
+func planMapFileEntries(ctx context.Context, c *client.DataplaneClient, file MapFile, maxEntryChanges int) ([]MapEntryChange, error) {
+	current, err := c.OpenMapFileContent(ctx, file.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer current.Close()
+
+	var changes []MapEntryChange
+	err = DiffMapEntries(current, strings.NewReader(file.Content), func(change MapEntryChange) error {
+		if len(changes) == maxEntryChanges {
+			return errTooManyMapEntryChanges
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	switch {
+	case errors.Is(err, ErrMapNotSorted), errors.Is(err, errTooManyMapEntryChanges):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// ApplyMapEntryUpdates applies updates through the Runtime API. A map file is
+// replaced as a whole if the Runtime API rejects one of its entry changes,
+// e.g. because it is unavailable or the map is not loaded by HAProxy.
+//
+// The entry changes are force-synced, so the Dataplane API rewrites the stored
+// file from HAProxy's in-memory map, which drops comments and appends added
+// keys. The desired content is therefore stored afterwards without a reload,
+// so that the next sync compares against the rendered file again.
+func ApplyMapEntryUpdates(ctx context.Context, c *client.DataplaneClient, updates []MapEntryUpdate) error {
+	ops := &mapFileOps{client: c}
+
+	for _, update := range updates {
+		if applyMapEntryChanges(ctx, c, update) {
+			if err := c.UpdateMapFileWithoutReload(ctx, update.File.Path, update.File.Content); err != nil {
+				return fmt.Errorf("failed to store file '%s': %w", update.File.Path, err)
+			}
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Replacing the whole file also repairs partially applied changes
+		if err := ops.Update(ctx, update.File.Path, update.File.Content); err != nil {
+			return fmt.Errorf("failed to update file '%s': %w", update.File.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// applyMapEntryChanges applies the entry changes of update and reports
+// whether all of them succeeded.
+func applyMapEntryChanges(ctx context.Context, c *client.DataplaneClient, update MapEntryUpdate) bool {
+	path := update.File.Path
+	for _, change := range update.Changes {
+		var err error
+		switch change.Op {
+		case MapEntryAdd:
+			err = c.AddRuntimeMapEntry(ctx, path, change.Key, change.Value)
+		case MapEntryReplace:
+			err = c.ReplaceRuntimeMapEntry(ctx, path, change.Key, change.Value)
+		case MapEntryDelete:
+			err = c.DeleteRuntimeMapEntry(ctx, path, change.Key)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package auxiliaryfiles
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// collectMapEntryChanges runs DiffMapEntries and returns the emitted changes.
+func collectMapEntryChanges(t *testing.T, current, desired string) ([]MapEntryChange, error) {
+	t.Helper()
+
+	var changes []MapEntryChange
+	err := DiffMapEntries(strings.NewReader(current), strings.NewReader(desired), func(c MapEntryChange) error {
+		changes = append(changes, c)
+		return nil
+	})
+	return changes, err
+}
+
+func TestDiffMapEntries(t *testing.T) {
+	current := `# hosts
+a.example.com backend_a
+b.example.com backend_b
+c.example.com backend_c
+`
+	desired := `a.example.com    backend_a
+
+b.example.com backend_x
+d.example.com backend_d
+`
+
+	changes, err := collectMapEntryChanges(t, current, desired)
+	if err != nil {
+		t.Fatalf("DiffMapEntries failed: %v", err)
+	}
+
+	expected := []MapEntryChange{
+		{Op: MapEntryReplace, Key: "b.example.com", Value: "backend_x"},
+		{Op: MapEntryDelete, Key: "c.example.com"},
+		{Op: MapEntryAdd, Key: "d.example.com", Value: "backend_d"},
+	}
+	if fmt.Sprint(changes) != fmt.Sprint(expected) {
+		t.Fatalf("expected changes %v, got %v", expected, changes)
+	}
+}
+
+func TestDiffMapEntries_NotSorted(t *testing.T) {
+	for name, desired := range map[string]string{
+		"out of order":  "b.example.com 1\na.example.com 2\n",
+		"duplicate key": "a.example.com 1\na.example.com 2\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := collectMapEntryChanges(t, "a.example.com 1\n", desired)
+			if !errors.Is(err, ErrMapNotSorted) {
+				t.Fatalf("expected ErrMapNotSorted, got %v", err)
+			}
+		})
+	}
+}
+
+// mapEntryStream generates a sorted map file of n entries without holding it
+// in memory. Keys for which skip returns true are left out, and value returns
+// the value of each key.
+type mapEntryStream struct {
+	n, i    int
+	skip    func(int) bool
+	value   func(int) string
+	line    []byte
+	pending []byte
+}
+
+func (s *mapEntryStream) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.i == s.n {
+			return 0, io.EOF
+		}
+		if !s.skip(s.i) {
+			s.line = fmt.Appendf(s.line[:0], "host-%08d.example.com %s\n", s.i, s.value(s.i))
+			s.pending = s.line
+		}
+		s.i++
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+// BenchmarkDiffMapEntries_1M diffs two 1M-entry maps that differ in 1% of
+// their entries. Allocations stay per line, so memory use is independent of
+// the map size.
+func BenchmarkDiffMapEntries_1M(b *testing.B) {
+	const entries = 1_000_000
+
+	b.ReportAllocs()
+	for b.Loop() {
+		current := &mapEntryStream{
+			n:     entries,
+			skip:  func(i int) bool { return i%300 == 0 },
+			value: func(int) string { return "backend_a" },
+		}
+		desired := &mapEntryStream{
+			n:    entries,
+			skip: func(i int) bool { return i%300 == 1 },
+			value: func(i int) string {
+				if i%300 == 2 {
+					return "backend_b"
+				}
+				return "backend_a"
+			},
+		}
+
+		changes := 0
+		err := DiffMapEntries(current, desired, func(MapEntryChange) error {
+			changes++
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		if changes == 0 {
+			b.Fatal("expected changes")
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package builder constructs HAProxy configurations programmatically.
+//
+// It is an alternative to templating for callers that assemble their
+// configuration in Go: the Builder collects typed sections, validates them,
+// and produces a parser.StructuredConfig that can be passed to
+// dataplane.Client.SyncFromModel.
+//
+// Example:
+//
+//	config, err := builder.New().
+//	    WithGlobal(&models.Global{GlobalBase: models.GlobalBase{Daemon: true}}).
+//	    AddBackend("web", "http").
+//	    AddServer("web", "s1", "10.0.0.1", 80).
+//	    AddFrontend("http", "http", "web").
+//	    AddBind("http", "*", 80).
+//	    Build()
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// namePattern matches the characters HAProxy accepts in section, server and bind names.
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// Builder assembles an HAProxy configuration section by section.
+//
+// Methods return the Builder for chaining. Invalid input does not stop the
+// chain; all problems are collected and returned together by Build.
+// A Builder is not safe for concurrent use.
+type Builder struct {
+	global    *models.Global
+	defaults  []*models.Defaults
+	frontends []*models.Frontend
+	backends  []*models.Backend
+	errs      []error
+}
+
+// New creates an empty Builder.
+func New() *Builder {
+	return &Builder{}
+}
+
+// WithGlobal sets the global section.
+//
+// Without a global section, a sync removes the settings of the current one,
+// so callers syncing against a running instance should always set it.
+func (b *Builder) WithGlobal(global *models.Global) *Builder {
+	if global == nil {
+		b.errs = append(b.errs, fmt.Errorf("global section is nil"))
+		return b
+	}
+	b.global = global
+	return b
+}
+
+// AddDefaults adds a named defaults section.
+func (b *Builder) AddDefaults(defaults *models.Defaults) *Builder {
+	if defaults == nil {
+		b.errs = append(b.errs, fmt.Errorf("defaults section is nil"))
+		return b
+	}
+	if !b.validName("defaults section", defaults.Name) {
+		return b
+	}
+	for _, existing := range b.defaults {
+		if existing.Name == defaults.Name {
+			b.errs = append(b.errs, fmt.Errorf("defaults section '%s' is already defined", defaults.Name))
+			return b
+		}
+	}
+	b.defaults = append(b.defaults, defaults)
+	return b
+}
+
+// AddBackend adds a backend. An empty mode inherits the mode of the defaults section.
+func (b *Builder) AddBackend(name, mode string) *Builder {
+	if !b.validName("backend", name) || !b.validMode("backend", name, mode) {
+		return b
+	}
+	if b.backend(name) != nil {
+		b.errs = append(b.errs, fmt.Errorf("backend '%s' is already defined", name))
+		return b
+	}
+	b.backends = append(b.backends, &models.Backend{
+		BackendBase: models.BackendBase{Name: name, Mode: mode},
+	})
+	return b
+}
+
+// AddServer adds a server to a backend added before.
+func (b *Builder) AddServer(backend, name, address string, port int64) *Builder {
+	be := b.backend(backend)
+	if be == nil {
+		b.errs = append(b.errs, fmt.Errorf("server '%s': backend '%s' is not defined", name, backend))
+		return b
+	}
+	if !b.validName("server", name) || !b.validPort("server", name, port) {
+		return b
+	}
+	if address == "" {
+		b.errs = append(b.errs, fmt.Errorf("server '%s' in backend '%s' has no address", name, backend))
+		return b
+	}
+	if _, exists := be.Servers[name]; exists {
+		b.errs = append(b.errs, fmt.Errorf("server '%s' is already defined in backend '%s'", name, backend))
+		return b
+	}
+
+	if be.Servers == nil {
+		be.Servers = make(map[string]models.Server)
+	}
+	be.Servers[name] = models.Server{Name: name, Address: address, Port: &port}
+	return b
+}
+
+// AddFrontend adds a frontend. An empty mode inherits the mode of the defaults
+// section; defaultBackend is optional and must be added by Build time.
+func (b *Builder) AddFrontend(name, mode, defaultBackend string) *Builder {
+	if !b.validName("frontend", name) || !b.validMode("frontend", name, mode) {
+		return b
+	}
+	if b.frontend(name) != nil {
+		b.errs = append(b.errs, fmt.Errorf("frontend '%s' is already defined", name))
+		return b
+	}
+	b.frontends = append(b.frontends, &models.Frontend{
+		FrontendBase: models.FrontendBase{Name: name, Mode: mode, DefaultBackend: defaultBackend},
+	})
+	return b
+}
+
+// AddBind adds a listening address to a frontend added before. An empty
+// address or "*" listens on all addresses.
+//
+// The bind is named after its address and port, like binds without an
+// explicit name in a parsed configuration.
+func (b *Builder) AddBind(frontend, address string, port int64) *Builder {
+	fe := b.frontend(frontend)
+	if fe == nil {
+		b.errs = append(b.errs, fmt.Errorf("bind %s:%d: frontend '%s' is not defined", address, port, frontend))
+		return b
+	}
+	name := fmt.Sprintf("%s:%d", address, port)
+	if !b.validPort("bind", name, port) {
+		return b
+	}
+	if _, exists := fe.Binds[name]; exists {
+		b.errs = append(b.errs, fmt.Errorf("bind %s is already defined in frontend '%s'", name, frontend))
+		return b
+	}
+
+	if fe.Binds == nil {
+		fe.Binds = make(map[string]models.Bind)
+	}
+	fe.Binds[name] = models.Bind{
+		BindParams: models.BindParams{Name: name},
+		Address:    address,
+		Port:       &port,
+	}
+	return b
+}
+
+// Build validates the configuration and returns it.
+//
+// All errors collected while building are returned joined; the configuration
+// is only returned if there are none.
+func (b *Builder) Build() (*parser.StructuredConfig, error) {
+	errs := b.errs
+	for _, fe := range b.frontends {
+		if fe.DefaultBackend != "" && b.backend(fe.DefaultBackend) == nil {
+			errs = append(errs, fmt.Errorf("frontend '%s': default backend '%s' is not defined", fe.Name, fe.DefaultBackend))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return &parser.StructuredConfig{
+		Global:    b.global,
+		Defaults:  b.defaults,
+		Frontends: b.frontends,
+		Backends:  b.backends,
+	}, nil
+}
+
+// Render builds the configuration and renders it as HAProxy configuration text.
+func (b *Builder) Render() (string, error) {
+	config, err := b.Build()
+	if err != nil {
+		return "", err
+	}
+	return parser.Serialize(config)
+}
+
+func (b *Builder) backend(name string) *models.Backend {
+	for _, be := range b.backends {
+		if be.Name == name {
+			return be
+		}
+	}
+	return nil
+}
+
+func (b *Builder) frontend(name string) *models.Frontend {
+	for _, fe := range b.frontends {
+		if fe.Name == name {
+			return fe
+		}
+	}
+	return nil
+}
+
+func (b *Builder) validName(kind, name string) bool {
+	if !namePattern.MatchString(name) {
+		b.errs = append(b.errs, fmt.Errorf("invalid %s name '%s'", kind, name))
+		return false
+	}
+	return true
+}
+
+func (b *Builder) validMode(kind, name, mode string) bool {
+	switch mode {
+	case "", "http", "tcp":
+		return true
+	}
+	b.errs = append(b.errs, fmt.Errorf("%s '%s': invalid mode '%s' (expected http or tcp)", kind, name, mode))
+	return false
+}
+
+func (b *Builder) validPort(kind, name string, port int64) bool {
+	if port < 1 || port > 65535 {
+		b.errs = append(b.errs, fmt.Errorf("%s '%s': port %d is out of range", kind, name, port))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+func TestBuilder_Render(t *testing.T) {
+	config, err := New().
+		WithGlobal(&models.Global{GlobalBase: models.GlobalBase{Daemon: true}}).
+		AddDefaults(&models.Defaults{DefaultsBase: models.DefaultsBase{Name: "base", Mode: "http"}}).
+		AddBackend("web", "http").
+		AddServer("web", "s1", "10.0.0.1", 80).
+		AddFrontend("http", "", "web").
+		AddBind("http", "*", 80).
+		Render()
+	if err != nil {
+		t.Fatalf("Render() failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"global\n  daemon\n",
+		"defaults base\n  mode http\n",
+		"frontend http\n  bind *:80 name *:80\n  default_backend web\n",
+		"backend web\n  mode http\n  server s1 10.0.0.1:80\n",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("Expected rendered config to contain %q, got:\n%s", want, config)
+		}
+	}
+}
+
+func TestBuilder_Validation(t *testing.T) {
+	_, err := New().
+		AddBackend("web", "http").
+		AddBackend("web", "http").
+		AddBackend("api", "udp").
+		AddBackend("bad name", "").
+		AddServer("web", "s1", "10.0.0.1", 80).
+		AddServer("web", "s1", "10.0.0.2", 80).
+		AddServer("web", "s2", "", 80).
+		AddServer("web", "s3", "10.0.0.3", 0).
+		AddServer("missing", "s1", "10.0.0.1", 80).
+		AddFrontend("http", "http", "nowhere").
+		AddBind("http", "*", 70000).
+		AddBind("other", "*", 80).
+		Build()
+	if err == nil {
+		t.Fatal("Build() should fail")
+	}
+
+	for _, want := range []string{
+		"backend 'web' is already defined",
+		"backend 'api': invalid mode 'udp'",
+		"invalid backend name 'bad name'",
+		"server 's1' is already defined in backend 'web'",
+		"server 's2' in backend 'web' has no address",
+		"server 's3': port 0 is out of range",
+		"server 's1': backend 'missing' is not defined",
+		"frontend 'http': default backend 'nowhere' is not defined",
+		"bind '*:70000': port 70000 is out of range",
+		"bind *:80: frontend 'other' is not defined",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected error %q, got:\n%v", want, err)
+		}
+	}
+}
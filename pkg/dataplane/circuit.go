@@ -0,0 +1,189 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a client's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets syncs through. This is also the state of clients
+	// without a circuit breaker.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects syncs until the cooldown has elapsed.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single probe sync through after the cooldown.
+	// Its success closes the circuit, its failure opens it again.
+	CircuitHalfOpen
+)
+
+// String returns the lowercase name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions configures the circuit breaker of a Client.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failed syncs that opens
+	// the circuit (default: 5)
+	FailureThreshold int
+
+	// Window limits the failures counted towards FailureThreshold to those
+	// within this duration before the latest failure (default: 0, no limit)
+	Window time.Duration
+
+	// Cooldown is how long an open circuit rejects syncs before it lets a
+	// probe sync through (default: 30 seconds)
+	Cooldown time.Duration
+}
+
+// circuitBreaker stops a client from syncing after repeated failures.
+//
+// Failures are counted until a successful sync. Once FailureThreshold of them
+// happened within Window, the circuit opens and syncs fail immediately. After
+// Cooldown, the circuit half-opens and admits one probe sync whose outcome
+// closes or re-opens it.
+type circuitBreaker struct {
+	threshold int
+	window    time.Duration
+	cooldown  time.Duration
+
+	// now returns the current time (replaced in tests)
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures []time.Time
+	openedAt time.Time
+	probing  bool
+}
+
+// newCircuitBreaker creates a circuit breaker, applying defaults to unset options.
+func newCircuitBreaker(opts *CircuitBreakerOptions) *circuitBreaker {
+	cb := &circuitBreaker{
+		threshold: opts.FailureThreshold,
+		window:    opts.Window,
+		cooldown:  opts.Cooldown,
+		now:       time.Now,
+	}
+	if cb.threshold <= 0 {
+		cb.threshold = 5
+	}
+	if cb.cooldown <= 0 {
+		cb.cooldown = 30 * time.Second
+	}
+	return cb
+}
+
+// allow reports whether a sync may run. It returns a CircuitOpenError while
+// the circuit is open or a half-open probe is in flight.
+func (cb *circuitBreaker) allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen {
+		remaining := cb.cooldown - cb.now().Sub(cb.openedAt)
+		if remaining > 0 {
+			return NewCircuitOpenError(len(cb.failures), remaining)
+		}
+		cb.state = CircuitHalfOpen
+	}
+
+	if cb.state == CircuitHalfOpen {
+		if cb.probing {
+			return NewCircuitOpenError(len(cb.failures), 0)
+		}
+		cb.probing = true
+	}
+
+	return nil
+}
+
+// record updates the circuit with the outcome of a sync admitted by allow.
+// Syncs canceled by the caller are not counted either way.
+func (cb *circuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	probe := cb.probing
+	cb.probing = false
+
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if err == nil {
+		cb.state = CircuitClosed
+		cb.failures = nil
+		return
+	}
+
+	now := cb.now()
+	cb.failures = append(cb.failures, now)
+
+	if probe {
+		cb.open(now)
+		return
+	}
+
+	if cb.window > 0 {
+		recent := cb.failures[:0]
+		for _, t := range cb.failures {
+			if now.Sub(t) <= cb.window {
+				recent = append(recent, t)
+			}
+		}
+		cb.failures = recent
+	}
+
+	if len(cb.failures) >= cb.threshold {
+		cb.open(now)
+	}
+}
+
+// open opens the circuit at the given time. Must be called with mu held.
+func (cb *circuitBreaker) open(now time.Time) {
+	cb.state = CircuitOpen
+	cb.openedAt = now
+}
+
+// currentState returns the circuit state, reporting an open circuit whose
+// cooldown has elapsed as half-open.
+func (cb *circuitBreaker) currentState() CircuitState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitOpen && cb.now().Sub(cb.openedAt) >= cb.cooldown {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCircuitBreaker returns a circuit breaker driven by the returned clock.
+func newTestCircuitBreaker(opts *CircuitBreakerOptions) (*circuitBreaker, *time.Time) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cb := newCircuitBreaker(opts)
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	cb, now := newTestCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 3, Cooldown: time.Minute})
+	failure := errors.New("rejected")
+
+	// Failures below the threshold keep the circuit closed
+	for i := 0; i < 2; i++ {
+		require.NoError(t, cb.allow())
+		cb.record(failure)
+	}
+	assert.Equal(t, CircuitClosed, cb.currentState())
+
+	// The third consecutive failure opens it
+	require.NoError(t, cb.allow())
+	cb.record(failure)
+	assert.Equal(t, CircuitOpen, cb.currentState())
+
+	err := cb.allow()
+	var open *CircuitOpenError
+	require.True(t, errors.As(err, &open))
+	assert.Equal(t, 3, open.Failures)
+	assert.Equal(t, time.Minute, open.RetryAfter)
+
+	// After the cooldown a single probe is let through
+	*now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, cb.currentState())
+	require.NoError(t, cb.allow())
+	assert.Error(t, cb.allow(), "second sync during probe must be rejected")
+
+	// A failed probe re-opens the circuit for another cooldown
+	cb.record(failure)
+	assert.Equal(t, CircuitOpen, cb.currentState())
+	*now = now.Add(30 * time.Second)
+	require.Error(t, cb.allow())
+
+	// A successful probe closes it
+	*now = now.Add(30 * time.Second)
+	require.NoError(t, cb.allow())
+	cb.record(nil)
+	assert.Equal(t, CircuitClosed, cb.currentState())
+
+	// Failures are counted from zero again
+	require.NoError(t, cb.allow())
+	cb.record(failure)
+	assert.Equal(t, CircuitClosed, cb.currentState())
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 2})
+
+	cb.record(errors.New("rejected"))
+	cb.record(nil)
+	cb.record(errors.New("rejected"))
+
+	assert.Equal(t, CircuitClosed, cb.currentState())
+}
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	cb, now := newTestCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 2, Window: time.Minute})
+
+	cb.record(errors.New("rejected"))
+	*now = now.Add(2 * time.Minute)
+	cb.record(errors.New("rejected"))
+	assert.Equal(t, CircuitClosed, cb.currentState(), "failures outside the window must not count")
+
+	*now = now.Add(30 * time.Second)
+	cb.record(errors.New("rejected"))
+	assert.Equal(t, CircuitOpen, cb.currentState())
+}
+
+func TestCircuitBreaker_IgnoresCanceledSyncs(t *testing.T) {
+	cb, _ := newTestCircuitBreaker(&CircuitBreakerOptions{FailureThreshold: 1})
+
+	cb.record(fmt.Errorf("fetch failed: %w", context.Canceled))
+
+	assert.Equal(t, CircuitClosed, cb.currentState())
+}
+
+func TestSync_CircuitBreaker(t *testing.T) {
+	api := newFakeDataplaneAPI(t, baseTestConfig)
+	endpoint := api.endpoint()
+	endpoint.CircuitBreaker = &CircuitBreakerOptions{FailureThreshold: 2, Cooldown: time.Hour}
+
+	c, err := NewClient(context.Background(), endpoint)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, c.CircuitState())
+
+	// Colliding binds fail every sync
+	desired := baseTestConfig + `
+frontend https
+    bind *:443 name example-com
+    bind *:443 name example-org
+`
+	opts := DefaultSyncOptions()
+	opts.BindCollisionPolicy = BindCollisionReject
+	for i := 0; i < 2; i++ {
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+	}
+	assert.Equal(t, CircuitOpen, c.CircuitState())
+
+	requests := len(api.Requests())
+	_, err = c.Sync(context.Background(), baseTestConfig, nil, nil)
+
+	var syncErr *SyncError
+	require.True(t, errors.As(err, &syncErr))
+	assert.Equal(t, "circuit", syncErr.Stage)
+	var open *CircuitOpenError
+	require.True(t, errors.As(err, &open))
+	assert.Len(t, api.Requests(), requests, "open circuit must not contact the API")
+}
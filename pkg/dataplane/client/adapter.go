@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strconv"
 )
 
@@ -20,6 +21,12 @@ import (
 type VersionAdapter struct {
 	client     *DataplaneClient
 	maxRetries int
+
+	// cleanupStale deletes stale transactions when the transaction limit is hit
+	cleanupStale bool
+
+	// budget caps the retries shared with other retrying calls
+	budget *RetryBudget
 }
 
 // NewVersionAdapter creates a new VersionAdapter with the specified client and retry limit.
@@ -47,6 +54,22 @@ func NewVersionAdapter(client *DataplaneClient, maxRetries int) *VersionAdapter
 	}
 }
 
+// WithStaleTransactionCleanup makes the adapter delete stale transactions and
+// retry once when starting a transaction fails with ErrTransactionLimit.
+// See DataplaneClient.DeleteStaleTransactions for which transactions are stale.
+func (a *VersionAdapter) WithStaleTransactionCleanup(enabled bool) *VersionAdapter {
+	a.cleanupStale = enabled
+	return a
+}
+
+// WithRetryBudget makes every retry, whether after a version conflict or after
+// deleting stale transactions, take one from budget. Once the budget is used
+// up, the last error is returned wrapped in ErrRetryBudgetExhausted.
+func (a *VersionAdapter) WithRetryBudget(budget *RetryBudget) *VersionAdapter {
+	a.budget = budget
+	return a
+}
+
 // TransactionFunc is a function that executes operations within a transaction.
 // The function receives the transaction and should perform all desired operations.
 // If the function returns an error, the transaction will be aborted.
@@ -61,6 +84,9 @@ type TransactionFunc func(ctx context.Context, tx *Transaction) error
 // 4. Commits the transaction if successful
 // 5. Aborts the transaction if an error occurs
 // 6. Retries on 409 conflicts with the new version
+// 7. With WithStaleTransactionCleanup, deletes stale transactions and retries
+// once if the transaction limit is reached
+// 8. With WithRetryBudget, stops retrying once the budget is used up
 //
 // Returns the CommitResult from the successful commit.
 //
@@ -77,8 +103,13 @@ type TransactionFunc func(ctx context.Context, tx *Transaction) error
 //	})
 func (a *VersionAdapter) ExecuteTransaction(ctx context.Context, fn TransactionFunc) (*CommitResult, error) {
 	var lastErr error
+	cleaned := false
 
 	for attempt := 0; attempt <= a.maxRetries; attempt++ {
+		if attempt > 0 && !a.budget.Take() {
+			return nil, a.budget.exhaustedError(lastErr)
+		}
+
 		// Get current version
 		version, err := a.client.GetVersion(ctx)
 		if err != nil {
@@ -94,6 +125,14 @@ func (a *VersionAdapter) ExecuteTransaction(ctx context.Context, fn TransactionF
 				lastErr = err
 				continue
 			}
+			if !cleaned && a.shouldCleanup(err) {
+				// Transaction limit reached - retry once after deleting stale transactions
+				cleaned = true
+				if a.deleteStaleTransactions(ctx) {
+					lastErr = err
+					continue
+				}
+			}
 			return nil, fmt.Errorf("failed to create transaction: %w", err)
 		}
 
@@ -140,8 +179,13 @@ func (a *VersionAdapter) ExecuteTransaction(ctx context.Context, fn TransactionF
 // Returns an error if the operation fails or max retries are exceeded.
 func (a *VersionAdapter) ExecuteTransactionWithVersion(ctx context.Context, version int64, fn TransactionFunc) error {
 	var lastErr error
+	cleaned := false
 
 	for attempt := 0; attempt <= a.maxRetries; attempt++ {
+		if attempt > 0 && !a.budget.Take() {
+			return a.budget.exhaustedError(lastErr)
+		}
+
 		currentVersion := version
 
 		// If we're retrying, fetch the new version
@@ -161,6 +205,13 @@ func (a *VersionAdapter) ExecuteTransactionWithVersion(ctx context.Context, vers
 				lastErr = err
 				continue
 			}
+			if !cleaned && a.shouldCleanup(err) {
+				cleaned = true
+				if a.deleteStaleTransactions(ctx) {
+					lastErr = err
+					continue
+				}
+			}
 			return fmt.Errorf("failed to create transaction: %w", err)
 		}
 
@@ -190,6 +241,21 @@ func (a *VersionAdapter) ExecuteTransactionWithVersion(ctx context.Context, vers
 	return fmt.Errorf("transaction failed after %d retries: %w", a.maxRetries, lastErr)
 }
 
+// shouldCleanup reports whether err warrants deleting stale transactions.
+func (a *VersionAdapter) shouldCleanup(err error) bool {
+	return a.cleanupStale && errors.Is(err, ErrTransactionLimit)
+}
+
+// deleteStaleTransactions deletes stale transactions and reports whether any
+// were deleted, i.e. whether starting a transaction is worth retrying.
+func (a *VersionAdapter) deleteStaleTransactions(ctx context.Context) bool {
+	deleted, err := a.client.DeleteStaleTransactions(ctx)
+	if err != nil {
+		slog.Warn("Failed to delete stale transactions", "error", err)
+	}
+	return deleted > 0
+}
+
 // ParseVersionFromHeader extracts the version number from a Configuration-Version header.
 func ParseVersionFromHeader(header string) (int64, error) {
 	if header == "" {
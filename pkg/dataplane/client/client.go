@@ -11,6 +11,7 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -24,6 +25,18 @@ type Endpoint struct {
 	Password string
 	PodName  string // Kubernetes pod name for observability
 
+	// Credentials returns the basic auth credentials for each request (optional).
+	// When set, it takes precedence over Username and Password, e.g. to pick up
+	// rotated credentials without recreating the client.
+	Credentials func() (username, password string)
+
+	// HTTPClient is used for all requests to the endpoint (optional, defaults to a plain http.Client)
+	HTTPClient *http.Client
+
+	// TLSConfig configures TLS for requests to the endpoint, e.g. a client
+	// certificate for mutual TLS (optional). Ignored when HTTPClient is set.
+	TLSConfig *tls.Config
+
 	// Cached version info (optional, avoids redundant /v3/info calls if set)
 	CachedMajorVersion int
 	CachedMinorVersion int
@@ -36,6 +49,42 @@ func (e *Endpoint) HasCachedVersion() bool {
 	return e.CachedMajorVersion > 0
 }
 
+// httpClient returns the HTTP client to use for requests to this endpoint.
+func (e *Endpoint) httpClient() *http.Client {
+	if e.HTTPClient != nil {
+		return e.HTTPClient
+	}
+	if e.TLSConfig != nil {
+		return &http.Client{Transport: NewTLSTransport(e.TLSConfig)}
+	}
+	return &http.Client{}
+}
+
+// setBasicAuth adds the endpoint's basic auth credentials to req.
+// Endpoints authenticating with a client certificate only send no credentials.
+func (e *Endpoint) setBasicAuth(req *http.Request) {
+	username, password := e.Username, e.Password
+	if e.Credentials != nil {
+		username, password = e.Credentials()
+	}
+	if username == "" && password == "" {
+		return
+	}
+	req.SetBasicAuth(username, password)
+}
+
+// NewTLSTransport returns a copy of http.DefaultTransport using tlsConfig.
+func NewTLSTransport(tlsConfig *tls.Config) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// hasClientCertificate reports whether tlsConfig presents a client certificate.
+func hasClientCertificate(tlsConfig *tls.Config) bool {
+	return tlsConfig != nil && (len(tlsConfig.Certificates) > 0 || tlsConfig.GetClientCertificate != nil)
+}
+
 // DataplaneClient wraps the multi-version Clientset with additional functionality
 // for HAProxy Dataplane API operations. It automatically uses the appropriate
 // client version based on runtime detection.
@@ -56,12 +105,21 @@ type Config struct {
 	// Password for basic authentication
 	Password string
 
+	// Credentials returns the basic auth credentials for each request (optional).
+	// When set, Username and Password are ignored.
+	Credentials func() (username, password string)
+
 	// PodName is the Kubernetes pod name (for observability)
 	PodName string
 
 	// HTTPClient allows injecting a custom HTTP client (useful for testing)
 	HTTPClient *http.Client
 
+	// TLSConfig configures TLS for requests to the Dataplane API (optional).
+	// With a client certificate set, Username and Password may be left empty
+	// to authenticate with mutual TLS only. Ignored when HTTPClient is set.
+	TLSConfig *tls.Config
+
 	// Logger for logging request/response details on errors (optional)
 	// If nil, slog.Default() will be used
 	Logger *slog.Logger
@@ -82,11 +140,15 @@ func New(ctx context.Context, cfg *Config) (*DataplaneClient, error) {
 	if cfg.BaseURL == "" {
 		return nil, fmt.Errorf("baseURL is required")
 	}
-	if cfg.Username == "" {
-		return nil, fmt.Errorf("username is required")
-	}
-	if cfg.Password == "" {
-		return nil, fmt.Errorf("password is required")
+	// Basic auth is optional when authenticating with a client certificate
+	hasBasicAuth := cfg.Username != "" || cfg.Password != ""
+	if cfg.Credentials == nil && (hasBasicAuth || !hasClientCertificate(cfg.TLSConfig)) {
+		if cfg.Username == "" {
+			return nil, fmt.Errorf("username is required")
+		}
+		if cfg.Password == "" {
+			return nil, fmt.Errorf("password is required")
+		}
 	}
 
 	logger := cfg.Logger
@@ -96,10 +158,13 @@ func New(ctx context.Context, cfg *Config) (*DataplaneClient, error) {
 
 	// Create endpoint
 	endpoint := Endpoint{
-		URL:      cfg.BaseURL,
-		Username: cfg.Username,
-		Password: cfg.Password,
-		PodName:  cfg.PodName,
+		URL:         cfg.BaseURL,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		Credentials: cfg.Credentials,
+		PodName:     cfg.PodName,
+		HTTPClient:  cfg.HTTPClient,
+		TLSConfig:   cfg.TLSConfig,
 	}
 
 	// Create multi-version clientset with automatic version detection
@@ -162,10 +227,13 @@ func (c *DataplaneClient) BaseURL() string {
 // This is a convenience function for creating a client with default options.
 func NewFromEndpoint(ctx context.Context, endpoint *Endpoint, logger *slog.Logger) (*DataplaneClient, error) {
 	return New(ctx, &Config{
-		BaseURL:  endpoint.URL,
-		Username: endpoint.Username,
-		Password: endpoint.Password,
-		PodName:  endpoint.PodName,
-		Logger:   logger,
+		BaseURL:     endpoint.URL,
+		Username:    endpoint.Username,
+		Password:    endpoint.Password,
+		Credentials: endpoint.Credentials,
+		PodName:     endpoint.PodName,
+		HTTPClient:  endpoint.HTTPClient,
+		TLSConfig:   endpoint.TLSConfig,
+		Logger:      logger,
 	})
 }
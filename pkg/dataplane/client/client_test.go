@@ -16,6 +16,7 @@ package client
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -68,6 +69,20 @@ func TestNew_Validation(t *testing.T) {
 	}
 }
 
+func TestEndpoint_HTTPClientUsesTLSConfig(t *testing.T) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: "haproxy"}
+
+	endpoint := &Endpoint{URL: "https://haproxy:5555", TLSConfig: tlsConfig}
+	transport, ok := endpoint.httpClient().Transport.(*http.Transport)
+	require.True(t, ok, "expected an *http.Transport")
+	assert.Same(t, tlsConfig, transport.TLSClientConfig)
+
+	// An explicit HTTP client takes precedence
+	custom := &http.Client{}
+	endpoint.HTTPClient = custom
+	assert.Same(t, custom, endpoint.httpClient())
+}
+
 func TestNew_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/v3/info" {
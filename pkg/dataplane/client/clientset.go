@@ -212,39 +212,41 @@ func NewClientset(ctx context.Context, endpoint *Endpoint, logger *slog.Logger)
 
 	// Create request editor for basic auth
 	authEditor := func(ctx context.Context, req *http.Request) error {
-		req.SetBasicAuth(endpoint.Username, endpoint.Password)
+		endpoint.setBasicAuth(req)
 		return nil
 	}
 
+	httpClient := endpoint.httpClient()
+
 	// Create community clients for all supported versions
 	// Note: We create all clients regardless of detected version for maximum flexibility
-	v30Client, err := v30.NewClient(endpoint.URL, v30.WithRequestEditorFn(authEditor))
+	v30Client, err := v30.NewClient(endpoint.URL, v30.WithRequestEditorFn(authEditor), v30.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.0 client: %w", err)
 	}
 
-	v31Client, err := v31.NewClient(endpoint.URL, v31.WithRequestEditorFn(authEditor))
+	v31Client, err := v31.NewClient(endpoint.URL, v31.WithRequestEditorFn(authEditor), v31.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.1 client: %w", err)
 	}
 
-	v32Client, err := v32.NewClient(endpoint.URL, v32.WithRequestEditorFn(authEditor))
+	v32Client, err := v32.NewClient(endpoint.URL, v32.WithRequestEditorFn(authEditor), v32.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.2 client: %w", err)
 	}
 
 	// Create enterprise clients for all supported versions
-	v30eeClient, err := v30ee.NewClient(endpoint.URL, v30ee.WithRequestEditorFn(authEditor))
+	v30eeClient, err := v30ee.NewClient(endpoint.URL, v30ee.WithRequestEditorFn(authEditor), v30ee.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.0 enterprise client: %w", err)
 	}
 
-	v31eeClient, err := v31ee.NewClient(endpoint.URL, v31ee.WithRequestEditorFn(authEditor))
+	v31eeClient, err := v31ee.NewClient(endpoint.URL, v31ee.WithRequestEditorFn(authEditor), v31ee.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.1 enterprise client: %w", err)
 	}
 
-	v32eeClient, err := v32ee.NewClient(endpoint.URL, v32ee.WithRequestEditorFn(authEditor))
+	v32eeClient, err := v32ee.NewClient(endpoint.URL, v32ee.WithRequestEditorFn(authEditor), v32ee.WithHTTPClient(httpClient))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create v3.2 enterprise client: %w", err)
 	}
@@ -374,10 +376,9 @@ func DetectVersion(ctx context.Context, endpoint *Endpoint, _ *slog.Logger) (*Ve
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.SetBasicAuth(endpoint.Username, endpoint.Password)
+	endpoint.setBasicAuth(req)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := endpoint.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch version info: %w", err)
 	}
@@ -194,6 +194,107 @@ func (c *DataplaneClient) PushRawConfiguration(ctx context.Context, config strin
 	return reloadID, nil
 }
 
+// GetTransactionConfiguration retrieves the raw HAProxy configuration as it
+// would look after committing the given transaction.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) GetTransactionConfiguration(ctx context.Context, txID string) (string, error) {
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v32.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v31.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v30.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v32ee.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v31ee.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			return c.GetHAProxyConfiguration(ctx, &v30ee.GetHAProxyConfigurationParams{TransactionId: &txID})
+		},
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get transaction configuration: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read configuration response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("failed to get transaction configuration: status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return string(body), nil
+}
+
+// ValidateRawConfiguration asks the Dataplane API to validate a configuration
+// with HAProxy without applying it.
+//
+// Returns a *ConfigValidationError containing HAProxy's output if the
+// configuration is invalid, or another error if the request itself failed.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) ValidateRawConfiguration(ctx context.Context, config string) error {
+	skipVersion := true
+	onlyValidate := true
+
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v32.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v31.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v30.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v32ee.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v31ee.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			return c.PostHAProxyConfigurationWithTextBody(ctx, &v30ee.PostHAProxyConfigurationParams{SkipVersion: &skipVersion, OnlyValidate: &onlyValidate}, config)
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to validate raw configuration: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity {
+		return &ConfigValidationError{Message: extractErrorMessage(body)}
+	}
+
+	return fmt.Errorf("failed to validate raw configuration: status %d: %s", resp.StatusCode, string(body))
+}
+
+// ConfigValidationError is returned when HAProxy rejects a configuration
+// submitted for validation.
+type ConfigValidationError struct {
+	// Message is HAProxy's validation output
+	Message string
+}
+
+func (e *ConfigValidationError) Error() string {
+	return fmt.Sprintf("configuration is invalid: %s", e.Message)
+}
+
 // VersionConflictError represents a 409 conflict error with version information.
 type VersionConflictError struct {
 	ExpectedVersion int64
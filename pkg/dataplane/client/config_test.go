@@ -16,6 +16,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -273,3 +274,63 @@ func TestVersionConflictError(t *testing.T) {
 	assert.Contains(t, err.Error(), "45")
 	assert.Contains(t, err.Error(), "version conflict")
 }
+
+func TestValidateRawConfiguration(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		wantErr    string
+		invalid    bool
+	}{
+		{
+			name:       "valid configuration",
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "invalid configuration",
+			statusCode: http.StatusBadRequest,
+			body:       `{"code":400,"message":"unknown keyword 'foo'"}`,
+			wantErr:    "configuration is invalid: unknown keyword 'foo'",
+			invalid:    true,
+		},
+		{
+			name:       "server error",
+			statusCode: http.StatusInternalServerError,
+			body:       "boom",
+			wantErr:    "status 500: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, cleanup := createTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/v3/info" {
+					fmt.Fprintln(w, `{"api":{"version":"v3.2.6 87ad0bcf"}}`)
+					return
+				}
+
+				if r.URL.Path == "/services/haproxy/configuration/raw" && r.URL.Query().Get("only_validate") == "true" {
+					w.WriteHeader(tt.statusCode)
+					fmt.Fprint(w, tt.body)
+					return
+				}
+
+				w.WriteHeader(http.StatusNotFound)
+			})
+			defer cleanup()
+
+			err := client.ValidateRawConfiguration(context.Background(), "global\n")
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			var invalidErr *ConfigValidationError
+			assert.Equal(t, tt.invalid, errors.As(err, &invalidErr))
+		})
+	}
+}
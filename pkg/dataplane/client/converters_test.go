@@ -0,0 +1,331 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+	v30 "haproxy-template-ic/pkg/generated/dataplaneapi/v30"
+	v30ee "haproxy-template-ic/pkg/generated/dataplaneapi/v30ee"
+	v31 "haproxy-template-ic/pkg/generated/dataplaneapi/v31"
+	v31ee "haproxy-template-ic/pkg/generated/dataplaneapi/v31ee"
+	v32 "haproxy-template-ic/pkg/generated/dataplaneapi/v32"
+	v32ee "haproxy-template-ic/pkg/generated/dataplaneapi/v32ee"
+)
+
+// parseGlobal parses a global section and returns the resulting client-native model.
+func parseGlobal(t *testing.T, globalSection string) *models.Global {
+	t.Helper()
+
+	p, err := parser.New()
+	require.NoError(t, err)
+
+	conf, err := p.ParseFromString(globalSection)
+	require.NoError(t, err)
+	require.NotNil(t, conf.Global)
+
+	return conf.Global
+}
+
+// roundTripVia converts a client-native model into the API model T and back,
+// mirroring the conversion performed by the dispatcher when syncing.
+func roundTripVia[T any, M any](t *testing.T, model *M) *M {
+	t.Helper()
+
+	jsonData, err := MarshalForVersion(model)
+	require.NoError(t, err)
+
+	var apiModel T
+	require.NoError(t, json.Unmarshal(jsonData, &apiModel))
+
+	apiJSON, err := json.Marshal(apiModel)
+	require.NoError(t, err)
+
+	var result M
+	require.NoError(t, json.Unmarshal(apiJSON, &result))
+
+	return &result
+}
+
+// globalRoundTrips lists the version-specific round trips for the global section.
+var globalRoundTrips = map[string]func(*testing.T, *models.Global) *models.Global{
+	"v3.0":    roundTripVia[v30.Global, models.Global],
+	"v3.1":    roundTripVia[v31.Global, models.Global],
+	"v3.2":    roundTripVia[v32.Global, models.Global],
+	"v3.0 EE": roundTripVia[v30ee.Global, models.Global],
+	"v3.1 EE": roundTripVia[v31ee.Global, models.Global],
+	"v3.2 EE": roundTripVia[v32ee.Global, models.Global],
+}
+
+func TestMarshalForVersion_GlobalRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		check  func(t *testing.T, g *models.Global)
+	}{
+		{
+			name: "ssl-engine and ssl-mode-async",
+			config: `
+global
+    ssl-engine rdrand ALL
+    ssl-mode-async
+`,
+			check: func(t *testing.T, g *models.Global) {
+				t.Helper()
+				require.NotNil(t, g.SslOptions)
+				require.Len(t, g.SslOptions.SslEngines, 1)
+				require.NotNil(t, g.SslOptions.SslEngines[0].Name)
+				assert.Equal(t, "rdrand", *g.SslOptions.SslEngines[0].Name)
+				require.NotNil(t, g.SslOptions.SslEngines[0].Algorithms)
+				assert.Equal(t, "ALL", *g.SslOptions.SslEngines[0].Algorithms)
+				assert.Equal(t, "enabled", g.SslOptions.ModeAsync)
+			},
+		},
+		{
+			name: "cpu-map, nbthread and thread-groups",
+			config: `
+global
+    nbthread 8
+    thread-groups 2
+    cpu-map auto:1/1-4 0-3
+    cpu-map auto:2/1-4 4-7
+`,
+			check: func(t *testing.T, g *models.Global) {
+				t.Helper()
+				assert.Equal(t, int64(8), g.Nbthread)
+				assert.Equal(t, int64(2), g.ThreadGroups)
+				require.Len(t, g.CPUMaps, 2)
+				require.NotNil(t, g.CPUMaps[0].Process)
+				assert.Equal(t, "auto:1/1-4", *g.CPUMaps[0].Process)
+				require.NotNil(t, g.CPUMaps[1].CPUSet)
+				assert.Equal(t, "4-7", *g.CPUMaps[1].CPUSet)
+			},
+		},
+		{
+			name: "quic tuning",
+			config: `
+global
+    no-quic
+    tune.quic.frontend.conn-tx-buffers.limit 10
+    tune.quic.frontend.max-idle-timeout 30s
+    tune.quic.frontend.max-streams-bidi 100
+    tune.quic.max-frame-loss 10
+    tune.quic.reorder-ratio 50
+    tune.quic.retry-threshold 100
+    tune.quic.socket-owner connection
+    tune.quic.zero-copy-fwd-send on
+`,
+			check: func(t *testing.T, g *models.Global) {
+				t.Helper()
+				assert.True(t, g.NoQuic)
+				q := g.TuneQuicOptions
+				require.NotNil(t, q)
+				require.NotNil(t, q.FrontendConnTxBuffersLimit)
+				assert.Equal(t, int64(10), *q.FrontendConnTxBuffersLimit)
+				require.NotNil(t, q.FrontendMaxIdleTimeout)
+				assert.Equal(t, int64(30000), *q.FrontendMaxIdleTimeout)
+				require.NotNil(t, q.FrontendMaxStreamsBidi)
+				assert.Equal(t, int64(100), *q.FrontendMaxStreamsBidi)
+				require.NotNil(t, q.MaxFrameLoss)
+				assert.Equal(t, int64(10), *q.MaxFrameLoss)
+				require.NotNil(t, q.ReorderRatio)
+				assert.Equal(t, int64(50), *q.ReorderRatio)
+				require.NotNil(t, q.RetryThreshold)
+				assert.Equal(t, int64(100), *q.RetryThreshold)
+				assert.Equal(t, "connection", q.SocketOwner)
+				assert.Equal(t, "enabled", q.ZeroCopyFwdSend)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		original := parseGlobal(t, tt.config)
+		tt.check(t, original)
+
+		for version, roundTrip := range globalRoundTrips {
+			t.Run(tt.name+"/"+version, func(t *testing.T) {
+				result := roundTrip(t, original)
+
+				tt.check(t, result)
+				assert.True(t, original.Equal(*result), "round trip changed global: %v", original.Diff(*result))
+			})
+		}
+	}
+}
+
+func TestMarshalForVersion_GlobalCPUPolicyRoundTrip(t *testing.T) {
+	// cpu-policy and cpu-set are only part of the v3.2 API models; the global
+	// executor rejects them for older versions instead of dropping them.
+	original := parseGlobal(t, `
+global
+    cpu-policy performance
+    cpu-set drop-cpu 0
+`)
+
+	check := func(t *testing.T, g *models.Global) {
+		t.Helper()
+		assert.Equal(t, "performance", g.CPUPolicy)
+		require.Len(t, g.CPUSets, 1)
+		require.NotNil(t, g.CPUSets[0].Directive)
+		assert.Equal(t, "drop-cpu", *g.CPUSets[0].Directive)
+		assert.Equal(t, "0", g.CPUSets[0].Set)
+	}
+	check(t, original)
+
+	for _, version := range []string{"v3.2", "v3.2 EE"} {
+		t.Run(version, func(t *testing.T) {
+			result := globalRoundTrips[version](t, original)
+
+			check(t, result)
+			assert.True(t, original.Equal(*result), "round trip changed global: %v", original.Diff(*result))
+		})
+	}
+}
+
+func TestMarshalForVersion_GlobalQuicMaxTxMemRoundTrip(t *testing.T) {
+	// tune.quic.frontend.max-tx-mem is only part of the v3.2 API models; the
+	// global executor rejects it for older versions instead of dropping it.
+	original := parseGlobal(t, `
+global
+    tune.quic.frontend.max-tx-mem 1048576
+`)
+
+	check := func(t *testing.T, g *models.Global) {
+		t.Helper()
+		require.NotNil(t, g.TuneQuicOptions)
+		require.NotNil(t, g.TuneQuicOptions.FrontendMaxTxMemory)
+		assert.Equal(t, int64(1048576), *g.TuneQuicOptions.FrontendMaxTxMemory)
+	}
+	check(t, original)
+
+	for _, version := range []string{"v3.2", "v3.2 EE"} {
+		t.Run(version, func(t *testing.T) {
+			result := globalRoundTrips[version](t, original)
+
+			check(t, result)
+			assert.True(t, original.Equal(*result), "round trip changed global: %v", original.Diff(*result))
+		})
+	}
+}
+
+// bindRoundTrips lists the version-specific round trips for binds.
+var bindRoundTrips = map[string]func(*testing.T, *models.Bind) *models.Bind{
+	"v3.0":    roundTripVia[v30.Bind, models.Bind],
+	"v3.1":    roundTripVia[v31.Bind, models.Bind],
+	"v3.2":    roundTripVia[v32.Bind, models.Bind],
+	"v3.0 EE": roundTripVia[v30ee.Bind, models.Bind],
+	"v3.1 EE": roundTripVia[v31ee.Bind, models.Bind],
+	"v3.2 EE": roundTripVia[v32ee.Bind, models.Bind],
+}
+
+func TestMarshalForVersion_BindRoundTrip(t *testing.T) {
+	p, err := parser.New()
+	require.NoError(t, err)
+
+	conf, err := p.ParseFromString(`
+frontend https
+    bind quic4@:443 name quic ssl crt /etc/haproxy/certs/ alpn h3 quic-socket connection quic-cc-algo cubic
+`)
+	require.NoError(t, err)
+	require.Len(t, conf.Frontends, 1)
+
+	original, ok := conf.Frontends[0].Binds["quic"]
+	require.True(t, ok, "bind 'quic' not found")
+
+	check := func(t *testing.T, b *models.Bind) {
+		t.Helper()
+		assert.Equal(t, "quic4@", b.Address)
+		require.NotNil(t, b.Port)
+		assert.Equal(t, int64(443), *b.Port)
+		assert.Equal(t, "h3", b.Alpn)
+		assert.Equal(t, "connection", b.QuicSocket)
+		assert.Equal(t, "cubic", b.QuicCcAlgo)
+		assert.True(t, b.Ssl)
+	}
+	check(t, &original)
+
+	for version, roundTrip := range bindRoundTrips {
+		t.Run(version, func(t *testing.T) {
+			result := roundTrip(t, &original)
+
+			check(t, result)
+			assert.True(t, original.Equal(*result), "round trip changed bind: %v", original.Diff(*result))
+		})
+	}
+}
+
+// resolverRoundTrips lists the version-specific round trips for resolvers sections.
+var resolverRoundTrips = map[string]func(*testing.T, *models.Resolver) *models.Resolver{
+	"v3.0":    roundTripVia[v30.Resolver, models.Resolver],
+	"v3.1":    roundTripVia[v31.Resolver, models.Resolver],
+	"v3.2":    roundTripVia[v32.Resolver, models.Resolver],
+	"v3.0 EE": roundTripVia[v30ee.Resolver, models.Resolver],
+	"v3.1 EE": roundTripVia[v31ee.Resolver, models.Resolver],
+	"v3.2 EE": roundTripVia[v32ee.Resolver, models.Resolver],
+}
+
+func TestMarshalForVersion_ResolverRoundTrip(t *testing.T) {
+	p, err := parser.New()
+	require.NoError(t, err)
+
+	conf, err := p.ParseFromString(`
+resolvers dns
+    nameserver ns1 10.0.0.1:53
+    nameserver ns2 10.0.0.2:53
+    accepted_payload_size 8192
+    hold valid 10s
+    hold nx 30s
+    timeout resolve 1s
+    timeout retry 1s
+    resolve_retries 3
+`)
+	require.NoError(t, err)
+	require.Len(t, conf.Resolvers, 1)
+
+	original := conf.Resolvers[0]
+
+	check := func(t *testing.T, r *models.Resolver) {
+		t.Helper()
+		assert.Equal(t, int64(8192), r.AcceptedPayloadSize)
+		require.NotNil(t, r.HoldValid)
+		assert.Equal(t, int64(10000), *r.HoldValid)
+		require.NotNil(t, r.HoldNx)
+		assert.Equal(t, int64(30000), *r.HoldNx)
+		assert.Equal(t, int64(1000), r.TimeoutResolve)
+		assert.Equal(t, int64(3), r.ResolveRetries)
+
+		// Replacing the section must not drop its nameservers
+		require.Len(t, r.Nameservers, 2)
+		require.NotNil(t, r.Nameservers["ns1"].Port)
+		assert.Equal(t, "10.0.0.1", *r.Nameservers["ns1"].Address)
+		assert.Equal(t, int64(53), *r.Nameservers["ns1"].Port)
+	}
+	check(t, original)
+
+	for version, roundTrip := range resolverRoundTrips {
+		t.Run(version, func(t *testing.T) {
+			result := roundTrip(t, original)
+
+			check(t, result)
+			assert.True(t, original.Equal(*result), "round trip changed resolver: %v", original.Diff(*result))
+		})
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Interaction is a single recorded Dataplane API request/response pair.
+//
+// Only the request line and body are recorded; request headers are omitted so
+// that credentials never end up in recordings.
+type Interaction struct {
+	// Method is the HTTP method (GET, POST, PUT, DELETE)
+	Method string `json:"method"`
+
+	// URL is the request URI (path and query) without scheme and host
+	URL string `json:"url"`
+
+	// RequestBody is the request payload
+	RequestBody string `json:"request_body,omitempty"`
+
+	// StatusCode is the HTTP status code of the response
+	StatusCode int `json:"status_code"`
+
+	// ResponseHeader contains the response headers
+	ResponseHeader http.Header `json:"response_header,omitempty"`
+
+	// ResponseBody is the response payload
+	ResponseBody string `json:"response_body,omitempty"`
+}
+
+// Recorder captures Dataplane API interactions and writes them to a file.
+//
+// Interactions are appended as JSON lines while they happen, so a recording is
+// usable even if the process is terminated mid-sync. Recordings can be loaded
+// with LoadInteractions and served back with a ReplayTransport.
+//
+// Example:
+//
+//	recorder, err := client.NewRecorder("/tmp/dataplane.jsonl")
+//	if err != nil {
+//	    return err
+//	}
+//	defer recorder.Close()
+//
+//	httpClient := &http.Client{Transport: recorder.Wrap(nil)}
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder creates a recorder writing to the given file.
+// The file is created or truncated. It is only readable by the owner, since
+// recordings contain configurations, certificates, keys, and passwords.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording file: %w", err)
+	}
+
+	return &Recorder{
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}, nil
+}
+
+// Wrap returns a RoundTripper that records every interaction passing through base.
+// If base is nil, http.DefaultTransport is used.
+func (r *Recorder) Wrap(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingRoundTripper{base: base, recorder: r}
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+// record appends an interaction to the recording file. Interactions are not
+// kept in memory, so long-running recordings do not grow the process.
+func (r *Recorder) record(interaction *Interaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.encoder.Encode(interaction); err != nil {
+		return fmt.Errorf("failed to write interaction: %w", err)
+	}
+	return nil
+}
+
+// recordingRoundTripper is the RoundTripper returned by Recorder.Wrap.
+type recordingRoundTripper struct {
+	base     http.RoundTripper
+	recorder *Recorder
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readAndRestoreRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body for recording: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+	if err := t.recorder.record(&Interaction{
+		Method:         req.Method,
+		URL:            req.URL.RequestURI(),
+		RequestBody:    string(requestBody),
+		StatusCode:     resp.StatusCode,
+		ResponseHeader: resp.Header.Clone(),
+		ResponseBody:   string(responseBody),
+	}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// LoadInteractions reads a recording written by a Recorder.
+func LoadInteractions(path string) ([]Interaction, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording file: %w", err)
+	}
+	defer file.Close()
+
+	var interactions []Interaction
+	decoder := json.NewDecoder(bufio.NewReader(file))
+	for decoder.More() {
+		var interaction Interaction
+		if err := decoder.Decode(&interaction); err != nil {
+			return nil, fmt.Errorf("failed to decode interaction %d: %w", len(interactions)+1, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, nil
+}
+
+// ReplayTransport serves recorded interactions instead of contacting a Dataplane API.
+//
+// Each request is answered with the first unused interaction that has the same
+// method, request URI and body. Multipart bodies such as storage uploads are
+// compared without their random boundary. Identical requests are therefore
+// answered in the order they were recorded, which makes replays deterministic.
+// Requests without a matching interaction fail with an error.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport creates a transport replaying the given interactions.
+func NewReplayTransport(interactions []Interaction) *ReplayTransport {
+	return &ReplayTransport{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readAndRestoreRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	uri := req.URL.RequestURI()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range t.interactions {
+		interaction := &t.interactions[i]
+		if t.used[i] || interaction.Method != req.Method || interaction.URL != uri ||
+			normalizeMultipartBody(interaction.RequestBody) != normalizeMultipartBody(string(requestBody)) {
+			continue
+		}
+
+		t.used[i] = true
+
+		header := interaction.ResponseHeader.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+			StatusCode:    interaction.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(interaction.ResponseBody))),
+			ContentLength: int64(len(interaction.ResponseBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction matches %s %s", req.Method, uri)
+}
+
+// Remaining returns the number of recorded interactions that have not been replayed.
+func (t *ReplayTransport) Remaining() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	remaining := 0
+	for _, used := range t.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+// multipartBoundaryPlaceholder replaces the boundary of multipart bodies
+// before they are compared.
+const multipartBoundaryPlaceholder = "replay-boundary"
+
+// normalizeMultipartBody replaces the boundary of a multipart/form-data body
+// with a fixed placeholder. Storage uploads use a random boundary per request,
+// so bodies of a replayed sync would otherwise never match the recording.
+// Recordings omit request headers, so the boundary is taken from the body's
+// first delimiter line and must also close the body. Other bodies are
+// returned unchanged.
+func normalizeMultipartBody(body string) string {
+	firstLine, _, ok := strings.Cut(body, "\r\n")
+	if !ok || !strings.HasPrefix(firstLine, "--") {
+		return body
+	}
+	boundary := strings.TrimPrefix(firstLine, "--")
+	if boundary == "" || !strings.HasSuffix(body, "\r\n--"+boundary+"--\r\n") {
+		return body
+	}
+	return strings.ReplaceAll(body, "--"+boundary, "--"+multipartBoundaryPlaceholder)
+}
+
+// readAndRestoreRequestBody reads the request body and restores it for the actual request.
+func readAndRestoreRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_WritesInteractions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Reload-ID", "reload-1")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprintf(w, "echo:%s", body)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+
+	httpClient := &http.Client{Transport: recorder.Wrap(nil)}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL+"/services/haproxy/configuration/raw?skip_version=true", strings.NewReader("global"))
+	require.NoError(t, err)
+	req.SetBasicAuth("admin", "secret")
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The caller still sees the full response
+	assert.Equal(t, "echo:global", string(body))
+	require.NoError(t, recorder.Close())
+
+	interactions, err := LoadInteractions(path)
+	require.NoError(t, err)
+	require.Len(t, interactions, 1)
+
+	assert.Equal(t, http.MethodPost, interactions[0].Method)
+	assert.Equal(t, "/services/haproxy/configuration/raw?skip_version=true", interactions[0].URL)
+	assert.Equal(t, "global", interactions[0].RequestBody)
+	assert.Equal(t, http.StatusAccepted, interactions[0].StatusCode)
+	assert.Equal(t, "reload-1", interactions[0].ResponseHeader.Get("Reload-ID"))
+	assert.Equal(t, "echo:global", interactions[0].ResponseBody)
+}
+
+func TestNewRecorder_OwnerOnlyPermissions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recording.jsonl")
+	recorder, err := NewRecorder(path)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestReplayTransport_RoundTrip(t *testing.T) {
+	replay := NewReplayTransport([]Interaction{
+		{Method: http.MethodGet, URL: "/services/haproxy/configuration/version", StatusCode: 200, ResponseBody: "1"},
+		{Method: http.MethodGet, URL: "/services/haproxy/configuration/version", StatusCode: 200, ResponseBody: "2"},
+		{Method: http.MethodPost, URL: "/services/haproxy/transactions?version=1", StatusCode: 201, ResponseBody: `{"id":"tx-1"}`},
+	})
+	httpClient := &http.Client{Transport: replay}
+
+	get := func(url string) string {
+		t.Helper()
+		resp, err := httpClient.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// Identical requests are answered in recording order, regardless of host
+	assert.Equal(t, "1", get("http://a.invalid/services/haproxy/configuration/version"))
+	assert.Equal(t, "2", get("http://b.invalid/services/haproxy/configuration/version"))
+	assert.Equal(t, 1, replay.Remaining())
+
+	// Exhausted interactions are not served again
+	_, err := httpClient.Get("http://a.invalid/services/haproxy/configuration/version")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction matches GET /services/haproxy/configuration/version")
+
+	resp, err := httpClient.Post("http://a.invalid/services/haproxy/transactions?version=1", "application/json", http.NoBody)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Zero(t, replay.Remaining())
+}
+
+func TestReplayTransport_MultipartBoundary(t *testing.T) {
+	recorded, _, err := buildMultipartFilePayload("hosts.map", "a.example.com 1\n")
+	require.NoError(t, err)
+	replay := NewReplayTransport([]Interaction{
+		{Method: http.MethodPost, URL: "/services/haproxy/storage/maps", RequestBody: recorded.String(), StatusCode: 201},
+	})
+	httpClient := &http.Client{Transport: replay}
+
+	post := func(content string) error {
+		t.Helper()
+		body, contentType, err := buildMultipartFilePayload("hosts.map", content)
+		require.NoError(t, err)
+		resp, err := httpClient.Post("http://a.invalid/services/haproxy/storage/maps", contentType, body)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	// Different content does not match despite the normalized boundary
+	err = post("b.example.com 2\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction matches")
+
+	// The same upload matches although it uses a new random boundary
+	require.NoError(t, post("a.example.com 1\n"))
+	assert.Zero(t, replay.Remaining())
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	v30 "haproxy-template-ic/pkg/generated/dataplaneapi/v30"
+	v30ee "haproxy-template-ic/pkg/generated/dataplaneapi/v30ee"
+	v31 "haproxy-template-ic/pkg/generated/dataplaneapi/v31"
+	v31ee "haproxy-template-ic/pkg/generated/dataplaneapi/v31ee"
+	v32 "haproxy-template-ic/pkg/generated/dataplaneapi/v32"
+	v32ee "haproxy-template-ic/pkg/generated/dataplaneapi/v32ee"
+)
+
+// Reload states reported by the Dataplane API.
+const (
+	ReloadStatusInProgress = "in_progress"
+	ReloadStatusSucceeded  = "succeeded"
+	ReloadStatusFailed     = "failed"
+)
+
+// ReloadStatus describes the state of an HAProxy reload.
+type ReloadStatus struct {
+	// ID is the reload identifier returned in the Reload-ID header
+	ID string `json:"id"`
+
+	// Status is one of the ReloadStatus* constants
+	Status string `json:"status"`
+
+	// Response contains HAProxy's output for failed reloads
+	Response string `json:"response,omitempty"`
+}
+
+// GetReloadStatus retrieves the state of the reload with the given ID.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) GetReloadStatus(ctx context.Context, reloadID string) (*ReloadStatus, error) {
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			return c.GetReload(ctx, reloadID)
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reload %s: %w", reloadID, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reload response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get reload %s: status %d: %s", reloadID, resp.StatusCode, string(body))
+	}
+
+	var status ReloadStatus
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, fmt.Errorf("failed to parse reload response: %w", err)
+	}
+
+	return &status, nil
+}
+
+// Reload triggers an HAProxy reload without changing the configuration by
+// pushing the current raw configuration back unchanged.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+//
+// Returns the ID of the scheduled reload, or an empty string if the Dataplane
+// API accepted the push without scheduling a reload.
+func (c *DataplaneClient) Reload(ctx context.Context) (string, error) {
+	config, err := c.GetRawConfiguration(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to trigger reload: %w", err)
+	}
+
+	reloadID, err := c.PushRawConfiguration(ctx, config)
+	if err != nil {
+		return "", fmt.Errorf("failed to trigger reload: %w", err)
+	}
+
+	return reloadID, nil
+}
+
+// WaitForReload polls the reload with the given ID every interval until it
+// is no longer in progress or ctx is done.
+//
+// A failed reload is returned together with an error containing HAProxy's output.
+func (c *DataplaneClient) WaitForReload(ctx context.Context, reloadID string, interval time.Duration) (*ReloadStatus, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := c.GetReloadStatus(ctx, reloadID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch status.Status {
+		case ReloadStatusSucceeded:
+			return status, nil
+		case ReloadStatusFailed:
+			return status, fmt.Errorf("reload %s failed: %s", reloadID, status.Response)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("waiting for reload %s: %w", reloadID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
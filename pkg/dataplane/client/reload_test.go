@@ -0,0 +1,80 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// makeReloadHandler serves a reload that stays in progress for the given
+// number of polls before reporting finalStatus.
+func makeReloadHandler(inProgressPolls int32, finalStatus string, polls *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/info":
+			fmt.Fprintln(w, `{"api":{"version":"v3.2.6 87ad0bcf"}}`)
+		case "/services/haproxy/reloads/reload-1":
+			status := finalStatus
+			if polls.Add(1) <= inProgressPolls {
+				status = ReloadStatusInProgress
+			}
+			fmt.Fprintf(w, `{"id":"reload-1","status":%q,"response":"config error"}`, status)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func TestWaitForReload(t *testing.T) {
+	t.Run("succeeded after polling", func(t *testing.T) {
+		var polls atomic.Int32
+		client, cleanup := createTestClientWithServer(t, makeReloadHandler(2, ReloadStatusSucceeded, &polls))
+		defer cleanup()
+
+		status, err := client.WaitForReload(context.Background(), "reload-1", time.Millisecond)
+		require.NoError(t, err)
+		assert.Equal(t, ReloadStatusSucceeded, status.Status)
+		assert.Equal(t, int32(3), polls.Load())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		var polls atomic.Int32
+		client, cleanup := createTestClientWithServer(t, makeReloadHandler(0, ReloadStatusFailed, &polls))
+		defer cleanup()
+
+		status, err := client.WaitForReload(context.Background(), "reload-1", time.Millisecond)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "config error")
+		assert.Equal(t, ReloadStatusFailed, status.Status)
+	})
+
+	t.Run("unknown reload", func(t *testing.T) {
+		var polls atomic.Int32
+		client, cleanup := createTestClientWithServer(t, makeReloadHandler(0, ReloadStatusSucceeded, &polls))
+		defer cleanup()
+
+		_, err := client.GetReloadStatus(context.Background(), "missing")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "status 404")
+	})
+}
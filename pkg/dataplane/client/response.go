@@ -15,12 +15,51 @@
 package client
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"strings"
 )
 
+// ErrRuntimeUnavailable indicates that the Dataplane API could not reach the
+// HAProxy Runtime API socket while applying a change.
+//
+// Errors returned by CheckRuntimeResponse wrap this error when the response
+// signals runtime unavailability, so callers can detect it with errors.Is.
+// CheckResponse never returns it, since configuration and transaction calls do
+// not depend on the runtime socket.
+var ErrRuntimeUnavailable = errors.New("runtime API unavailable")
+
+// ErrTransactionLimit indicates that the Dataplane API refused to start a
+// transaction because too many transactions are open.
+//
+// Errors returned by CheckResponse wrap this error when the response signals
+// the limit, so callers can detect it with errors.Is.
+var ErrTransactionLimit = errors.New("too many open transactions")
+
+// transactionLimitMarkers are lower-case fragments of Dataplane API error
+// messages reported when max_open_transactions is reached.
+var transactionLimitMarkers = []string{
+	"max number of opened transactions",
+	"maximum number of open transactions",
+	"too many open transactions",
+	"too many transactions",
+}
+
+// runtimeUnavailableMarkers are lower-case fragments of Dataplane API error
+// messages reported when the Runtime API socket is missing or unreachable.
+var runtimeUnavailableMarkers = []string{
+	"runtime api not available",
+	"runtime api is not available",
+	"runtime not configured",
+	"no runtime",
+	"master socket",
+	"stats socket",
+}
+
 // CheckResponse validates an HTTP response status code and logs failures with full context.
 // It reads and logs the response body for debugging, then returns a user-friendly error.
 //
@@ -36,6 +75,17 @@ import (
 //	    return err
 //	}
 func CheckResponse(resp *http.Response, operation string) error {
+	return checkResponse(resp, operation, false)
+}
+
+// CheckRuntimeResponse works like CheckResponse for calls that are applied
+// through the HAProxy Runtime API. In addition, it wraps ErrRuntimeUnavailable
+// when the response reports that the runtime socket cannot be reached.
+func CheckRuntimeResponse(resp *http.Response, operation string) error {
+	return checkResponse(resp, operation, true)
+}
+
+func checkResponse(resp *http.Response, operation string, runtime bool) error {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		return nil
 	}
@@ -56,5 +106,60 @@ func CheckResponse(resp *http.Response, operation string) error {
 		)
 	}
 
+	if runtime && readErr == nil && isRuntimeUnavailable(resp.StatusCode, body) {
+		return fmt.Errorf("%s failed with status %d: %w", operation, resp.StatusCode, ErrRuntimeUnavailable)
+	}
+
+	if readErr == nil && isTransactionLimit(resp.StatusCode, body) {
+		return fmt.Errorf("%s failed with status %d: %w", operation, resp.StatusCode, ErrTransactionLimit)
+	}
+
 	return fmt.Errorf("%s failed with status %d", operation, resp.StatusCode)
 }
+
+// isTransactionLimit checks if a failed response reports that the maximum
+// number of open transactions is reached. Depending on the version, the
+// Dataplane API answers with 429 or a generic error carrying the message.
+func isTransactionLimit(statusCode int, body []byte) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	message := strings.ToLower(extractErrorMessage(body))
+	for _, marker := range transactionLimitMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isRuntimeUnavailable checks if a failed response reports that the Runtime API
+// cannot be reached. The Dataplane API answers with 503 or another server error
+// mentioning the runtime socket in that case. A bare 503 is not enough, as it
+// may as well come from a proxy in front of the Dataplane API.
+func isRuntimeUnavailable(statusCode int, body []byte) bool {
+	if statusCode < http.StatusInternalServerError {
+		return false
+	}
+
+	message := strings.ToLower(string(body))
+	for _, marker := range runtimeUnavailableMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// extractErrorMessage returns the message of a Dataplane API error response
+// ({"code":...,"message":...}), or the trimmed body if it is not JSON.
+func extractErrorMessage(body []byte) string {
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Message != "" {
+		return apiErr.Message
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRuntimeResponse_RuntimeUnavailable(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantErr     bool
+		unavailable bool
+	}{
+		{name: "success", statusCode: http.StatusOK, body: "{}"},
+		{name: "service unavailable with runtime message", statusCode: http.StatusServiceUnavailable, body: `{"code":503,"message":"runtime API not available"}`, wantErr: true, unavailable: true},
+		{name: "bare service unavailable", statusCode: http.StatusServiceUnavailable, body: "", wantErr: true},
+		{name: "runtime message", statusCode: http.StatusInternalServerError, body: `{"message":"Runtime API not available"}`, wantErr: true, unavailable: true},
+		{name: "unrelated server error", statusCode: http.StatusInternalServerError, body: `{"message":"backend not found"}`, wantErr: true},
+		{name: "client error mentioning runtime", statusCode: http.StatusBadRequest, body: `{"message":"no runtime"}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			err := CheckRuntimeResponse(resp, "server update")
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "server update failed with status")
+			assert.Equal(t, tt.unavailable, errors.Is(err, ErrRuntimeUnavailable))
+		})
+	}
+}
+
+func TestCheckResponse_IgnoresRuntimeUnavailable(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Body:       io.NopCloser(strings.NewReader(`{"code":503,"message":"runtime API not available"}`)),
+	}
+
+	err := CheckResponse(resp, "backend creation")
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrRuntimeUnavailable))
+}
+
+func TestCheckResponse_TransactionLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		limit      bool
+	}{
+		{name: "too many requests", statusCode: http.StatusTooManyRequests, body: "", limit: true},
+		{name: "limit message", statusCode: http.StatusInternalServerError, body: `{"code":500,"message":"max number of opened transactions reached: 20"}`, limit: true},
+		{name: "unrelated error", statusCode: http.StatusInternalServerError, body: `{"message":"transaction not found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tt.statusCode,
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			err := CheckResponse(resp, "start transaction")
+			require.Error(t, err)
+			assert.Equal(t, tt.limit, errors.Is(err, ErrTransactionLimit))
+		})
+	}
+}
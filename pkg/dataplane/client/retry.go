@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -47,6 +48,71 @@ type RetryConfig struct {
 
 	// Logger for retry attempts. If nil, no logging is performed.
 	Logger *slog.Logger
+
+	// Budget caps the retries shared with other retrying calls. Each retry
+	// takes one from the budget; once it is used up, the error is returned
+	// wrapped in ErrRetryBudgetExhausted. If nil, only MaxAttempts applies.
+	Budget *RetryBudget
+}
+
+// ErrRetryBudgetExhausted indicates that an operation was not retried because
+// its RetryBudget was used up.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget caps the total number of retries across several retrying calls,
+// regardless of why they retry. It is safe for concurrent use.
+type RetryBudget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+}
+
+// NewRetryBudget creates a RetryBudget allowing limit retries in total.
+// A limit of 0 or less allows unlimited retries, which are still counted.
+func NewRetryBudget(limit int) *RetryBudget {
+	return &RetryBudget{limit: limit}
+}
+
+// Take takes one retry from the budget and reports whether the retry is allowed.
+// A nil budget allows every retry.
+func (b *RetryBudget) Take() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit > 0 && b.used >= b.limit {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// Used returns the number of retries taken so far.
+func (b *RetryBudget) Used() int {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.used
+}
+
+// Limit returns the total number of retries allowed (0 if unlimited).
+func (b *RetryBudget) Limit() int {
+	if b == nil {
+		return 0
+	}
+	return b.limit
+}
+
+// exhaustedError wraps the error of the operation that could not be retried.
+func (b *RetryBudget) exhaustedError(err error) error {
+	return fmt.Errorf("%w after %d retries: %w", ErrRetryBudgetExhausted, b.Used(), err)
 }
 
 // DefaultRetryConfig returns a RetryConfig with sensible defaults.
@@ -203,6 +269,10 @@ func WithRetry[T any](ctx context.Context, config RetryConfig, fn func(attempt i
 			return zero, err
 		}
 
+		if !config.Budget.Take() {
+			return zero, config.Budget.exhaustedError(err)
+		}
+
 		// Log retry attempt
 		if config.Logger != nil {
 			config.Logger.Warn("Operation failed, retrying",
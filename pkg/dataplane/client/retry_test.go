@@ -94,6 +94,49 @@ func TestWithRetry_ExhaustsMaxAttempts(t *testing.T) {
 	assert.True(t, errors.As(err, &vce), "should return version conflict error")
 }
 
+func TestWithRetry_SharedBudget(t *testing.T) {
+	budget := NewRetryBudget(3)
+	config := RetryConfig{
+		MaxAttempts: 3,
+		RetryIf:     IsVersionConflict(),
+		Backoff:     BackoffNone,
+		Budget:      budget,
+	}
+	conflict := func(attempt int) (string, error) {
+		return "", &VersionConflictError{ExpectedVersion: 1, ActualVersion: "2"}
+	}
+
+	// The first call takes 2 retries from the budget
+	_, err := WithRetry(context.Background(), config, conflict)
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrRetryBudgetExhausted))
+	assert.Equal(t, 2, budget.Used())
+
+	// The second call has a single retry left
+	attempts := 0
+	_, err = WithRetry(context.Background(), config, func(attempt int) (string, error) {
+		attempts++
+		return conflict(attempt)
+	})
+	require.ErrorIs(t, err, ErrRetryBudgetExhausted)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, 3, budget.Used())
+	var vce *VersionConflictError
+	assert.True(t, errors.As(err, &vce), "should wrap the last error")
+}
+
+func TestRetryBudget_Unlimited(t *testing.T) {
+	budget := NewRetryBudget(0)
+	for range 10 {
+		assert.True(t, budget.Take())
+	}
+	assert.Equal(t, 10, budget.Used())
+
+	var nilBudget *RetryBudget
+	assert.True(t, nilBudget.Take())
+	assert.Equal(t, 0, nilBudget.Used())
+}
+
 func TestWithRetry_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	v30 "haproxy-template-ic/pkg/generated/dataplaneapi/v30"
+	v30ee "haproxy-template-ic/pkg/generated/dataplaneapi/v30ee"
+	v31 "haproxy-template-ic/pkg/generated/dataplaneapi/v31"
+	v31ee "haproxy-template-ic/pkg/generated/dataplaneapi/v31ee"
+	v32 "haproxy-template-ic/pkg/generated/dataplaneapi/v32"
+	v32ee "haproxy-template-ic/pkg/generated/dataplaneapi/v32ee"
+)
+
+// Server admin states accepted by SetServerAdminState.
+const (
+	ServerAdminStateReady = "ready"
+	ServerAdminStateDrain = "drain"
+	ServerAdminStateMaint = "maint"
+)
+
+// SetServerAdminState changes the admin state of a server through the Runtime API.
+// The change takes effect immediately without a reload, but is not persisted
+// in the configuration.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) SetServerAdminState(ctx context.Context, backend, server, state string) error {
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			s := v32.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v32.RuntimeServer{AdminState: &s})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			s := v31.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v31.RuntimeServer{AdminState: &s})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			s := v30.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v30.RuntimeServer{AdminState: &s})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			s := v32ee.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v32ee.RuntimeServer{AdminState: &s})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			s := v31ee.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v31ee.RuntimeServer{AdminState: &s})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			s := v30ee.RuntimeServerAdminState(state)
+			return c.ReplaceRuntimeServer(ctx, backend, server, v30ee.RuntimeServer{AdminState: &s})
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set admin state of server %s/%s: %w", backend, server, err)
+	}
+	defer resp.Body.Close()
+
+	return CheckRuntimeResponse(resp, fmt.Sprintf("set admin state of server %s/%s to %s", backend, server, state))
+}
+
+// RuntimeServer is the runtime state of a server as reported by the Runtime API.
+type RuntimeServer struct {
+	// Name is the server name
+	Name string `json:"name"`
+
+	// AdminState is one of the ServerAdminState* constants
+	AdminState string `json:"admin_state"`
+
+	// OperationalState is "up", "down", "stopping", or "unknown"
+	OperationalState string `json:"operational_state"`
+}
+
+// GetRuntimeServers retrieves the runtime state of all servers of a backend
+// through the Runtime API.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) GetRuntimeServers(ctx context.Context, backend string) ([]RuntimeServer, error) {
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32:   func(c *v32.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+		V31:   func(c *v31.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+		V30:   func(c *v30.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+		V32EE: func(c *v32ee.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+		V31EE: func(c *v31ee.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+		V30EE: func(c *v30ee.Client) (*http.Response, error) { return c.GetAllRuntimeServer(ctx, backend) },
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get runtime servers of backend %s: %w", backend, err)
+	}
+	defer resp.Body.Close()
+
+	if err := CheckRuntimeResponse(resp, fmt.Sprintf("get runtime servers of backend %s", backend)); err != nil {
+		return nil, err
+	}
+
+	var servers []RuntimeServer
+	if err := json.NewDecoder(resp.Body).Decode(&servers); err != nil {
+		return nil, fmt.Errorf("failed to decode runtime servers of backend %s: %w", backend, err)
+	}
+
+	return servers, nil
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	v30 "haproxy-template-ic/pkg/generated/dataplaneapi/v30"
+	v30ee "haproxy-template-ic/pkg/generated/dataplaneapi/v30ee"
+	v31 "haproxy-template-ic/pkg/generated/dataplaneapi/v31"
+	v31ee "haproxy-template-ic/pkg/generated/dataplaneapi/v31ee"
+	v32 "haproxy-template-ic/pkg/generated/dataplaneapi/v32"
+	v32ee "haproxy-template-ic/pkg/generated/dataplaneapi/v32ee"
+)
+
+// AddRuntimeMapEntry adds an entry to a loaded map through the Runtime API.
+// The change takes effect immediately without a reload and is synced to the map file.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) AddRuntimeMapEntry(ctx context.Context, mapName, key, value string) error {
+	// Write the change through to the map file so it survives the next reload
+	forceSync := true
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v32.AddMapEntryParams{ForceSync: &forceSync}, v32.MapEntry{Key: &key, Value: &value})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v31.AddMapEntryParams{ForceSync: &forceSync}, v31.MapEntry{Key: &key, Value: &value})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v30.AddMapEntryParams{ForceSync: &forceSync}, v30.MapEntry{Key: &key, Value: &value})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v32ee.AddMapEntryParams{ForceSync: &forceSync}, v32ee.MapEntry{Key: &key, Value: &value})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v31ee.AddMapEntryParams{ForceSync: &forceSync}, v31ee.MapEntry{Key: &key, Value: &value})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			return c.AddMapEntry(ctx, mapName, &v30ee.AddMapEntryParams{ForceSync: &forceSync}, v30ee.MapEntry{Key: &key, Value: &value})
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add entry '%s' to map '%s': %w", key, mapName, err)
+	}
+	defer resp.Body.Close()
+
+	return CheckRuntimeResponse(resp, fmt.Sprintf("add entry '%s' to map '%s'", key, mapName))
+}
+
+// ReplaceRuntimeMapEntry changes the value of an entry of a loaded map through the Runtime API.
+// The change takes effect immediately without a reload and is synced to the map file.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) ReplaceRuntimeMapEntry(ctx context.Context, mapName, key, value string) error {
+	// Write the change through to the map file so it survives the next reload
+	forceSync := true
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			params := &v32.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v32.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			params := &v31.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v31.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			params := &v30.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v30.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			params := &v32ee.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v32ee.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			params := &v31ee.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v31ee.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			params := &v30ee.ReplaceRuntimeMapEntryParams{ForceSync: &forceSync}
+			return c.ReplaceRuntimeMapEntry(ctx, mapName, key, params, v30ee.ReplaceRuntimeMapEntryJSONRequestBody{Value: value})
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replace entry '%s' of map '%s': %w", key, mapName, err)
+	}
+	defer resp.Body.Close()
+
+	return CheckRuntimeResponse(resp, fmt.Sprintf("replace entry '%s' of map '%s'", key, mapName))
+}
+
+// DeleteRuntimeMapEntry removes an entry from a loaded map through the Runtime API.
+// The change takes effect immediately without a reload and is synced to the map file.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) DeleteRuntimeMapEntry(ctx context.Context, mapName, key string) error {
+	// Write the change through to the map file so it survives the next reload
+	forceSync := true
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v32.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v31.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v30.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v32ee.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v31ee.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			return c.DeleteRuntimeMapEntry(ctx, mapName, key, &v30ee.DeleteRuntimeMapEntryParams{ForceSync: &forceSync})
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete entry '%s' from map '%s': %w", key, mapName, err)
+	}
+	defer resp.Body.Close()
+
+	return CheckRuntimeResponse(resp, fmt.Sprintf("delete entry '%s' from map '%s'", key, mapName))
+}
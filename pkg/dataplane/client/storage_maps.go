@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	v30 "haproxy-template-ic/pkg/generated/dataplaneapi/v30"
@@ -80,6 +81,35 @@ func (c *DataplaneClient) GetMapFileContent(ctx context.Context, name string) (s
 	return readRawStorageContent(resp, "map file", name)
 }
 
+// OpenMapFileContent streams the content of a specific map file by name.
+// Unlike GetMapFileContent, the content is not buffered in memory; the caller
+// must close the returned reader.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) OpenMapFileContent(ctx context.Context, name string) (io.ReadCloser, error) {
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32:   func(c *v32.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+		V31:   func(c *v31.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+		V30:   func(c *v30.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+		V32EE: func(c *v32ee.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+		V31EE: func(c *v31ee.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+		V30EE: func(c *v30ee.Client) (*http.Response, error) { return c.GetOneStorageMap(ctx, name) },
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map file '%s': %w", name, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("map file '%s' not found", name)
+		}
+		return nil, fmt.Errorf("get map file '%s' failed with status %d", name, resp.StatusCode)
+	}
+
+	return resp.Body, nil
+}
+
 // CreateMapFile creates a new map file using multipart form-data.
 // Works with all HAProxy DataPlane API versions (v3.0+).
 func (c *DataplaneClient) CreateMapFile(ctx context.Context, name, content string) error {
@@ -122,27 +152,47 @@ func (c *DataplaneClient) CreateMapFile(ctx context.Context, name, content strin
 // while CREATE operations accept multipart/form-data.
 // Works with all HAProxy DataPlane API versions (v3.0+).
 func (c *DataplaneClient) UpdateMapFile(ctx context.Context, name, content string) error {
+	return c.replaceMapFile(ctx, name, content, false)
+}
+
+// UpdateMapFileWithoutReload updates an existing map file in storage without
+// reloading HAProxy. It is meant for map files whose entries were already
+// changed in the running HAProxy through the Runtime API, so that the stored
+// file matches the desired content again.
+// Works with all HAProxy DataPlane API versions (v3.0+).
+func (c *DataplaneClient) UpdateMapFileWithoutReload(ctx context.Context, name, content string) error {
+	return c.replaceMapFile(ctx, name, content, true)
+}
+
+// replaceMapFile replaces the content of a stored map file, optionally
+// skipping the reload the Dataplane API otherwise triggers.
+func (c *DataplaneClient) replaceMapFile(ctx context.Context, name, content string, skipReload bool) error {
 	// Use text/plain content-type for UPDATE (API v3 requirement)
 	body := bytes.NewReader([]byte(content))
 
+	var skip *bool
+	if skipReload {
+		skip = &skipReload
+	}
+
 	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
 		V32: func(c *v32.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v32.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 		V31: func(c *v31.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v31.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 		V30: func(c *v30.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v30.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 		V32EE: func(c *v32ee.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v32ee.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 		V31EE: func(c *v31ee.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v31ee.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 		V30EE: func(c *v30ee.Client) (*http.Response, error) {
-			return c.ReplaceStorageMapFileWithBody(ctx, name, nil, "text/plain", body)
+			return c.ReplaceStorageMapFileWithBody(ctx, name, &v30ee.ReplaceStorageMapFileParams{SkipReload: skip}, "text/plain", body)
 		},
 	})
 
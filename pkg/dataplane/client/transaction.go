@@ -116,8 +116,10 @@ func (c *DataplaneClient) CreateTransaction(ctx context.Context, version int64)
 	}
 
 	if resp.StatusCode != 201 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("failed to start transaction: status %d: %s", resp.StatusCode, string(body))
+		if err := CheckResponse(resp, "start transaction"); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to start transaction: unexpected status %d", resp.StatusCode)
 	}
 
 	// Parse transaction response
@@ -138,6 +140,86 @@ func (c *DataplaneClient) CreateTransaction(ctx context.Context, version int64)
 	}, nil
 }
 
+// DeleteStaleTransactions deletes open transactions that were started on an
+// older configuration version than the current one.
+//
+// Such transactions can no longer be committed, since the Dataplane API rejects
+// outdated transactions, but they keep counting towards max_open_transactions
+// until they are deleted. They are typically left behind by syncs that were
+// interrupted before they could abort their transaction. Transactions on the
+// current version may still be in use and are kept.
+//
+// Returns the number of deleted transactions.
+func (c *DataplaneClient) DeleteStaleTransactions(ctx context.Context) (int, error) {
+	version, err := c.GetVersion(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get version: %w", err)
+	}
+
+	resp, err := c.Dispatch(ctx, CallFunc[*http.Response]{
+		V32: func(c *v32.Client) (*http.Response, error) {
+			status := v32.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v32.GetTransactionsParams{Status: &status})
+		},
+		V31: func(c *v31.Client) (*http.Response, error) {
+			status := v31.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v31.GetTransactionsParams{Status: &status})
+		},
+		V30: func(c *v30.Client) (*http.Response, error) {
+			status := v30.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v30.GetTransactionsParams{Status: &status})
+		},
+		V32EE: func(c *v32ee.Client) (*http.Response, error) {
+			status := v32ee.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v32ee.GetTransactionsParams{Status: &status})
+		},
+		V31EE: func(c *v31ee.Client) (*http.Response, error) {
+			status := v31ee.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v31ee.GetTransactionsParams{Status: &status})
+		},
+		V30EE: func(c *v30ee.Client) (*http.Response, error) {
+			status := v30ee.GetTransactionsParamsStatusInProgress
+			return c.GetTransactions(ctx, &v30ee.GetTransactionsParams{Status: &status})
+		},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := CheckResponse(resp, "list transactions"); err != nil {
+		return 0, err
+	}
+
+	var transactions []struct {
+		ID      string `json:"id"`
+		Version int64  `json:"_version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&transactions); err != nil {
+		return 0, fmt.Errorf("failed to parse transactions: %w", err)
+	}
+
+	deleted := 0
+	for _, t := range transactions {
+		if t.Version >= version {
+			continue
+		}
+
+		tx := &Transaction{ID: t.ID, Version: t.Version, client: c}
+		if err := tx.Abort(ctx); err != nil {
+			return deleted, fmt.Errorf("failed to delete stale transaction %s: %w", t.ID, err)
+		}
+		slog.Info("Deleted stale transaction",
+			"transaction_id", t.ID,
+			"version", t.Version,
+			"current_version", version,
+		)
+		deleted++
+	}
+
+	return deleted, nil
+}
+
 // CommitResult contains information about a transaction commit operation.
 type CommitResult struct {
 	// StatusCode is the HTTP status code from the commit response.
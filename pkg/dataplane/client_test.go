@@ -0,0 +1,98 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Close(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+`
+	desired := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+	const drainRequest = "PUT /services/haproxy/runtime/backends/web/servers/srv2"
+
+	// startSlowSync starts a sync that stays in flight for the drain grace
+	// period and returns a channel closed when it finished.
+	startSlowSync := func(t *testing.T, c *Client, api *fakeDataplaneAPI, gracePeriod time.Duration) <-chan struct{} {
+		t.Helper()
+
+		opts := DefaultSyncOptions()
+		opts.DrainBeforeDelete = true
+		opts.DrainGracePeriod = gracePeriod
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, _ = c.Sync(context.Background(), desired, nil, opts)
+		}()
+
+		require.Eventually(t, func() bool {
+			return slices.Contains(api.Requests(), drainRequest)
+		}, 5*time.Second, 5*time.Millisecond, "sync must reach the drain phase")
+		return done
+	}
+
+	t.Run("double close is a no-op", func(t *testing.T) {
+		c, _ := newTestClient(t, current)
+
+		require.NoError(t, c.Close())
+		require.NoError(t, c.Close())
+
+		_, err := c.Sync(context.Background(), desired, nil, nil)
+		assert.True(t, errors.Is(err, ErrClientClosed))
+	})
+
+	t.Run("waits for in-flight sync", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		done := startSlowSync(t, c, api, 200*time.Millisecond)
+
+		require.NoError(t, c.Close())
+
+		select {
+		case <-done:
+		default:
+			t.Fatal("Close returned before the in-flight sync finished")
+		}
+	})
+
+	t.Run("gives up after the close timeout", func(t *testing.T) {
+		api := newFakeDataplaneAPI(t, current)
+		endpoint := api.endpoint()
+		endpoint.CloseTimeout = 20 * time.Millisecond
+		c, err := NewClient(context.Background(), endpoint)
+		require.NoError(t, err)
+
+		done := startSlowSync(t, c, api, 500*time.Millisecond)
+
+		err = c.Close()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 operations still in flight")
+		<-done
+	})
+}
@@ -0,0 +1,84 @@
+package comparator
+
+import (
+	"strings"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// unnamedDefaultsPrefix is the prefix of the names the parser generates for
+// defaults sections without a name ("unnamed_defaults_1", "unnamed_defaults_2", ...).
+const unnamedDefaultsPrefix = "unnamed_defaults_"
+
+// isAnonymousDefaults reports whether name identifies a defaults section that
+// has no name in the configuration.
+func isAnonymousDefaults(name string) bool {
+	return name == "" || strings.HasPrefix(name, unnamedDefaultsPrefix)
+}
+
+// normalizeAnonymousDefaults gives the anonymous defaults sections of desired
+// the identity of the anonymous defaults sections of current.
+//
+// Anonymous defaults have no identity of their own. The parser numbers them in
+// order of appearance, and the Dataplane API may store them under a generated
+// name, so the same template can yield a different name than the one in
+// current. Matched by name, that section would be deleted and re-created on
+// every sync. Instead, anonymous defaults without a counterpart of the same
+// name are paired in order of appearance, and the desired ones take the name
+// of their current counterpart, including in "from" references.
+func normalizeAnonymousDefaults(current, desired *parser.StructuredConfig) {
+	currentNames := make(map[string]bool, len(current.Defaults))
+	for _, d := range current.Defaults {
+		if d != nil {
+			currentNames[d.Name] = true
+		}
+	}
+	desiredNames := make(map[string]bool, len(desired.Defaults))
+	for _, d := range desired.Defaults {
+		if d != nil {
+			desiredNames[d.Name] = true
+		}
+	}
+
+	var unmatched []string
+	for _, d := range current.Defaults {
+		if d != nil && d.Name != "" && isAnonymousDefaults(d.Name) && !desiredNames[d.Name] {
+			unmatched = append(unmatched, d.Name)
+		}
+	}
+
+	renames := make(map[string]string)
+	for _, d := range desired.Defaults {
+		if len(unmatched) == 0 {
+			break
+		}
+		if d == nil || !isAnonymousDefaults(d.Name) || currentNames[d.Name] {
+			continue
+		}
+		if d.Name != "" {
+			renames[d.Name] = unmatched[0]
+		}
+		d.Name = unmatched[0]
+		unmatched = unmatched[1:]
+	}
+	if len(renames) == 0 {
+		return
+	}
+
+	rename := func(from *string) {
+		if renamed, ok := renames[*from]; ok {
+			*from = renamed
+		}
+	}
+	for _, d := range desired.Defaults {
+		if d != nil {
+			rename(&d.From)
+		}
+	}
+	for _, frontend := range desired.Frontends {
+		rename(&frontend.From)
+	}
+	for _, backend := range desired.Backends {
+		rename(&backend.From)
+	}
+}
@@ -0,0 +1,119 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// anonymousDefaultsConfig renders a configuration with an anonymous defaults
+// section using the given connect timeout.
+func anonymousDefaultsConfig(connectTimeout string) string {
+	return `
+global
+    daemon
+
+defaults
+    mode http
+    timeout connect ` + connectTimeout + `
+
+frontend http
+    bind :80
+    default_backend web
+
+backend web
+    server srv1 10.0.0.1:80
+`
+}
+
+func TestCompare_AnonymousDefaultsRerender(t *testing.T) {
+	current, desired := parseTestConfigs(t, anonymousDefaultsConfig("5s"), anonymousDefaultsConfig("5s"))
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+	if len(diff.Operations) != 0 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected no operations, got %d", len(diff.Operations))
+	}
+}
+
+func TestCompare_AnonymousDefaultsStoredUnderGeneratedName(t *testing.T) {
+	// The Dataplane API stores the anonymous defaults under a generated name
+	// that differs from the one the parser assigns to the re-rendered template
+	stored := `
+global
+    daemon
+
+defaults unnamed_defaults_3
+    mode http
+    timeout connect 5s
+
+frontend http from unnamed_defaults_3
+    bind :80
+    default_backend web
+
+backend web from unnamed_defaults_3
+    server srv1 10.0.0.1:80
+`
+
+	t.Run("unchanged", func(t *testing.T) {
+		current, desired := parseTestConfigs(t, stored, anonymousDefaultsConfig("5s"))
+
+		diff, err := New().Compare(current, desired)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 0 {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Expected no operations, got %d", len(diff.Operations))
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		current, desired := parseTestConfigs(t, stored, anonymousDefaultsConfig("10s"))
+
+		diff, err := New().Compare(current, desired)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 1 {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+		}
+
+		op := diff.Operations[0]
+		if op.Type() != sections.OperationUpdate || op.Section() != "defaults" {
+			t.Fatalf("Expected defaults update, got %s", op.Describe())
+		}
+		if op.Target() != "unnamed_defaults_3" {
+			t.Errorf("Expected update to target unnamed_defaults_3, got %s", op.Target())
+		}
+	})
+}
+
+func TestCompare_NamedDefaultsKeepIdentity(t *testing.T) {
+	current, desired := parseTestConfigs(t, `
+global
+    daemon
+
+defaults unnamed_defaults_3
+    mode http
+`, `
+global
+    daemon
+
+defaults base
+    mode http
+`)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+	if len(diff.Operations) != 2 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected create and delete, got %d operations", len(diff.Operations))
+	}
+}
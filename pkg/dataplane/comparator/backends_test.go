@@ -0,0 +1,67 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+func backendConfig(balance string) string {
+	return `
+global
+    daemon
+
+defaults
+    mode http
+
+backend api
+    ` + balance + `
+    server srv1 10.0.0.1:8080 check weight 10
+    server srv2 10.0.0.2:8080 check weight 20
+`
+}
+
+func TestCompare_BalanceChangeOnlyUpdatesBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		desired string
+	}{
+		{
+			name:    "roundrobin to leastconn",
+			current: "balance roundrobin",
+			desired: "balance leastconn",
+		},
+		{
+			name:    "roundrobin to consistent source hashing",
+			current: "balance roundrobin",
+			desired: "balance source\n    hash-type consistent",
+		},
+		{
+			name:    "uri hashing to roundrobin",
+			current: "balance uri\n    hash-type consistent",
+			desired: "balance roundrobin",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, backendConfig(tt.current), backendConfig(tt.desired))
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected only a backend update, got %d operations", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "backend" || op.Type() != sections.OperationUpdate {
+				t.Errorf("Expected backend update, got %s", op.Describe())
+			}
+		})
+	}
+}
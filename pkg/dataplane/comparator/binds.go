@@ -0,0 +1,257 @@
+package comparator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// BindCollisionPolicy determines how NormalizeBinds handles multiple binds
+// listening on the same address:port within a frontend.
+type BindCollisionPolicy int
+
+const (
+	// BindCollisionIgnore skips collision detection (default).
+	BindCollisionIgnore BindCollisionPolicy = iota
+
+	// BindCollisionReject returns a BindCollisionError for every collision.
+	BindCollisionReject
+
+	// BindCollisionMerge keeps one bind of a collision group if all binds in the
+	// group are identical apart from their name. Binds with differing options
+	// cannot be merged safely and are still rejected.
+	BindCollisionMerge
+)
+
+// BindCollisionError reports binds sharing the same address:port within a frontend.
+type BindCollisionError struct {
+	// Frontend is the name of the frontend containing the colliding binds
+	Frontend string
+
+	// Address is the shared listen address (e.g., "*:443")
+	Address string
+
+	// Binds are the names of the colliding binds, sorted alphabetically
+	Binds []string
+}
+
+// Error implements the error interface.
+func (e *BindCollisionError) Error() string {
+	return fmt.Sprintf("frontend '%s' has %d binds on %s: %s",
+		e.Frontend, len(e.Binds), e.Address, strings.Join(e.Binds, ", "))
+}
+
+// NormalizeBinds detects binds listening on the same address:port within a frontend.
+//
+// Templates iterating over hostnames can emit several binds for the same socket
+// under different names. HAProxy rejects such configurations with errors that are
+// hard to trace back to the template, so collisions are detected before comparison.
+//
+// Binds on the same address:port that differ in thread, interface or namespace
+// are separate listeners and do not collide. The parsed model does not carry
+// "process" or "shards", so binds differing only in those are reported as
+// colliding; detection is therefore opt-in.
+//
+// With BindCollisionIgnore nothing is checked.
+// With BindCollisionReject the first collision is returned as *BindCollisionError.
+// With BindCollisionMerge identical duplicates are removed from the frontend in
+// place, keeping the bind with the alphabetically first name.
+func NormalizeBinds(config *parser.StructuredConfig, policy BindCollisionPolicy) error {
+	if config == nil || policy == BindCollisionIgnore {
+		return nil
+	}
+
+	for _, frontend := range config.Frontends {
+		if err := normalizeFrontendBinds(frontend, policy); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeFrontendBinds applies the collision policy to a single frontend.
+func normalizeFrontendBinds(frontend *models.Frontend, policy BindCollisionPolicy) error {
+	if frontend == nil || len(frontend.Binds) < 2 {
+		return nil
+	}
+
+	// Group bind names by listener
+	groups := make(map[bindListener][]string)
+	for name := range frontend.Binds {
+		listener := newBindListener(frontend.Binds[name])
+		groups[listener] = append(groups[listener], name)
+	}
+
+	// Iterate listeners in sorted order for deterministic errors
+	listeners := make([]bindListener, 0, len(groups))
+	for listener := range groups {
+		listeners = append(listeners, listener)
+	}
+	sort.Slice(listeners, func(i, j int) bool {
+		return listeners[i].less(listeners[j])
+	})
+
+	for _, listener := range listeners {
+		names := groups[listener]
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+
+		if policy != BindCollisionMerge || !bindsEqualIgnoringName(frontend.Binds, names) {
+			return &BindCollisionError{
+				Frontend: frontend.Name,
+				Address:  listener.address,
+				Binds:    names,
+			}
+		}
+
+		for _, name := range names[1:] {
+			delete(frontend.Binds, name)
+		}
+	}
+
+	return nil
+}
+
+// bindListener identifies the socket a bind listens on. Binds on the same
+// address:port but different threads, interfaces or network namespaces are
+// distinct listeners.
+type bindListener struct {
+	address   string
+	thread    string
+	iface     string
+	namespace string
+}
+
+// newBindListener returns the listener of a bind.
+func newBindListener(bind models.Bind) bindListener {
+	return bindListener{
+		address:   bindListenAddress(bind),
+		thread:    bind.Thread,
+		iface:     bind.Interface,
+		namespace: bind.Namespace,
+	}
+}
+
+// less orders listeners by address first, then by the remaining fields.
+func (l bindListener) less(other bindListener) bool {
+	if l.address != other.address {
+		return l.address < other.address
+	}
+	if l.thread != other.thread {
+		return l.thread < other.thread
+	}
+	if l.iface != other.iface {
+		return l.iface < other.iface
+	}
+	return l.namespace < other.namespace
+}
+
+// bindListenAddress returns the normalized address:port a bind listens on.
+// "*" and an empty address both denote all IPv4 addresses.
+func bindListenAddress(bind models.Bind) string {
+	address := bind.Address
+	if address == "" {
+		address = "*"
+	}
+
+	if bind.Port == nil {
+		return address
+	}
+
+	listen := address + ":" + strconv.FormatInt(*bind.Port, 10)
+	if bind.PortRangeEnd != nil {
+		listen += "-" + strconv.FormatInt(*bind.PortRangeEnd, 10)
+	}
+	return listen
+}
+
+// bindsEqualIgnoringName checks if all named binds have identical parameters.
+func bindsEqualIgnoringName(binds map[string]models.Bind, names []string) bool {
+	first := binds[names[0]]
+	first.Name = ""
+
+	for _, name := range names[1:] {
+		other := binds[name]
+		other.Name = ""
+		if !first.Equal(other) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AddBindsBeforeRemoval reorders ordered operations so that a bind moving to
+// a new address or port is created before the old bind is deleted.
+//
+// A bind whose address or port changes is usually named after them and shows
+// up as a delete of the old bind and a create of the new one, with the delete
+// first. This moves the bind deletes of each frontend (or log-forward) that
+// also creates binds to right after its last bind create, so that the section
+// keeps listening throughout. Deletes whose address:port one of the new binds
+// listens on stay first, since HAProxy cannot bind the same address twice.
+// Binds that keep their name are updated in place and are left as they are.
+func AddBindsBeforeRemoval(ops []Operation) []Operation {
+	created := make(map[string]map[string]bool)
+	lastCreate := make(map[string]int)
+	for i, op := range ops {
+		bind, parent, ok := bindOperation(op, sections.OperationCreate)
+		if !ok {
+			continue
+		}
+		if created[parent] == nil {
+			created[parent] = make(map[string]bool)
+		}
+		created[parent][bindListenAddress(*bind)] = true
+		lastCreate[parent] = i
+	}
+
+	moved := make(map[int]bool)
+	deferred := make(map[string][]Operation)
+	for i, op := range ops {
+		bind, parent, ok := bindOperation(op, sections.OperationDelete)
+		if !ok || created[parent] == nil || created[parent][bindListenAddress(*bind)] || i > lastCreate[parent] {
+			continue
+		}
+		moved[i] = true
+		deferred[parent] = append(deferred[parent], op)
+	}
+	if len(moved) == 0 {
+		return ops
+	}
+
+	reordered := make([]Operation, 0, len(ops))
+	for i, op := range ops {
+		if moved[i] {
+			continue
+		}
+		reordered = append(reordered, op)
+		if _, parent, ok := bindOperation(op, sections.OperationCreate); ok && lastCreate[parent] == i {
+			reordered = append(reordered, deferred[parent]...)
+		}
+	}
+	return reordered
+}
+
+// bindOperation returns the bind and parent section name of a bind operation
+// of the given type.
+func bindOperation(op Operation, opType sections.OperationType) (*models.Bind, string, bool) {
+	if op.Section() != "bind" || op.Type() != opType {
+		return nil, "", false
+	}
+	bind, ok := op.Model().(*models.Bind)
+	if !ok || bind == nil {
+		return nil, "", false
+	}
+	parent, _, _ := strings.Cut(op.Target(), "/")
+	return bind, parent, true
+}
@@ -0,0 +1,269 @@
+package comparator
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+func parseTestConfig(t *testing.T, config string) *parser.StructuredConfig {
+	t.Helper()
+	p, err := parser.New()
+	if err != nil {
+		t.Fatalf("Failed to create parser: %v", err)
+	}
+
+	parsed, err := p.ParseFromString(config)
+	if err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+
+	return parsed
+}
+
+func TestNormalizeBinds_CollisionOnSamePort(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend https
+    bind *:443 name example-com ssl crt /etc/haproxy/certs/
+    bind *:443 name example-org ssl crt /etc/haproxy/certs/
+    bind *:80 name http
+`)
+
+	err := NormalizeBinds(config, BindCollisionReject)
+	if err == nil {
+		t.Fatal("Expected bind collision error, got nil")
+	}
+
+	var collision *BindCollisionError
+	if !errors.As(err, &collision) {
+		t.Fatalf("Expected *BindCollisionError, got %T: %v", err, err)
+	}
+
+	if collision.Frontend != "https" {
+		t.Errorf("Expected frontend 'https', got %q", collision.Frontend)
+	}
+	if collision.Address != "*:443" {
+		t.Errorf("Expected address '*:443', got %q", collision.Address)
+	}
+	if !reflect.DeepEqual(collision.Binds, []string{"example-com", "example-org"}) {
+		t.Errorf("Expected binds [example-com example-org], got %v", collision.Binds)
+	}
+	if got := err.Error(); got != "frontend 'https' has 2 binds on *:443: example-com, example-org" {
+		t.Errorf("Unexpected error message: %s", got)
+	}
+}
+
+func TestNormalizeBinds_WildcardAddressesCollide(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend http
+    bind *:80 name a
+    bind :80 name b
+`)
+
+	var collision *BindCollisionError
+	if err := NormalizeBinds(config, BindCollisionReject); !errors.As(err, &collision) {
+		t.Fatalf("Expected *BindCollisionError for '*:80' and ':80', got %v", err)
+	}
+}
+
+func TestNormalizeBinds_NoCollision(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend http
+    bind *:80 name http
+    bind *:443 name https
+    bind 10.0.0.1:80 name internal
+    bind :::80 name ipv6
+`)
+
+	if err := NormalizeBinds(config, BindCollisionReject); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(config.Frontends[0].Binds) != 4 {
+		t.Errorf("Expected all 4 binds to be kept, got %d", len(config.Frontends[0].Binds))
+	}
+}
+
+func TestNormalizeBinds_IgnoredByDefault(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend https
+    bind *:443 name example-com
+    bind *:443 name example-org
+`)
+
+	var policy BindCollisionPolicy
+	if err := NormalizeBinds(config, policy); err != nil {
+		t.Fatalf("Expected collisions to be ignored by the zero policy, got %v", err)
+	}
+	if len(config.Frontends[0].Binds) != 2 {
+		t.Errorf("Expected binds to be left untouched, got %d", len(config.Frontends[0].Binds))
+	}
+}
+
+func TestNormalizeBinds_SeparateListenersOnSamePort(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend http
+    bind :80 name a thread 1
+    bind :80 name b thread 2
+    bind :8080 name eth0 interface eth0
+    bind :8080 name eth1 interface eth1
+    bind :8443 name default
+    bind :8443 name blue namespace blue
+`)
+
+	if err := NormalizeBinds(config, BindCollisionReject); err != nil {
+		t.Fatalf("Expected binds on different threads, interfaces or namespaces not to collide, got %v", err)
+	}
+	if len(config.Frontends[0].Binds) != 6 {
+		t.Errorf("Expected all 6 binds to be kept, got %d", len(config.Frontends[0].Binds))
+	}
+}
+
+func TestNormalizeBinds_MergeIdenticalBinds(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend https
+    bind *:443 name example-org ssl crt /etc/haproxy/certs/
+    bind *:443 name example-com ssl crt /etc/haproxy/certs/
+`)
+
+	if err := NormalizeBinds(config, BindCollisionMerge); err != nil {
+		t.Fatalf("Expected identical binds to be merged, got %v", err)
+	}
+
+	binds := config.Frontends[0].Binds
+	if len(binds) != 1 {
+		t.Fatalf("Expected 1 bind after merge, got %d", len(binds))
+	}
+	if _, ok := binds["example-com"]; !ok {
+		t.Errorf("Expected alphabetically first bind 'example-com' to be kept, got %v", binds)
+	}
+}
+
+func TestNormalizeBinds_MergeRejectsDifferingBinds(t *testing.T) {
+	config := parseTestConfig(t, `
+frontend https
+    bind *:443 name example-com ssl crt /etc/haproxy/certs/example.com.pem
+    bind *:443 name example-org ssl crt /etc/haproxy/certs/example.org.pem
+`)
+
+	var collision *BindCollisionError
+	if err := NormalizeBinds(config, BindCollisionMerge); !errors.As(err, &collision) {
+		t.Fatalf("Expected *BindCollisionError for binds with different options, got %v", err)
+	}
+	if len(config.Frontends[0].Binds) != 2 {
+		t.Errorf("Expected binds to be left untouched, got %d", len(config.Frontends[0].Binds))
+	}
+}
+
+// TestCompare_QUICBindNoSpuriousUpdate verifies that a QUIC bind does not produce
+// updates on re-sync, even when the Dataplane API writes its options in a different order.
+func TestCompare_QUICBindNoSpuriousUpdate(t *testing.T) {
+	desiredConfig := `
+frontend https
+    bind *:443 name tcp ssl crt /etc/haproxy/certs/ alpn h2,http/1.1
+    bind quic4@:443 name quic ssl crt /etc/haproxy/certs/ alpn h3 quic-socket connection quic-cc-algo cubic
+`
+	currentConfig := `
+frontend https
+    bind *:443 name tcp ssl crt /etc/haproxy/certs/ alpn h2,http/1.1
+    bind quic4@:443 name quic quic-cc-algo cubic quic-socket connection alpn h3 ssl crt /etc/haproxy/certs/
+`
+
+	current, desired := parseTestConfigs(t, currentConfig, desiredConfig)
+
+	if err := NormalizeBinds(desired, BindCollisionReject); err != nil {
+		t.Fatalf("QUIC and TCP binds on the same port must not collide: %v", err)
+	}
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 0 {
+		t.Errorf("Expected no operations for unchanged QUIC bind, got %d", len(diff.Operations))
+		logOperations(t, diff.Operations)
+	}
+}
+
+func TestCompare_QUICBindOptionChange(t *testing.T) {
+	currentConfig := `
+frontend https
+    bind quic4@:443 name quic ssl crt /etc/haproxy/certs/ alpn h3 quic-socket connection
+`
+	desiredConfig := `
+frontend https
+    bind quic4@:443 name quic ssl crt /etc/haproxy/certs/ alpn h3 quic-socket listener
+`
+
+	current, desired := parseTestConfigs(t, currentConfig, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		t.Fatalf("Expected 1 operation for changed quic-socket, got %d", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Section() != "bind" || op.Type() != sections.OperationUpdate {
+		t.Errorf("Expected bind update, got %s", op.Describe())
+	}
+}
+
+func TestAddBindsBeforeRemoval(t *testing.T) {
+	compare := func(t *testing.T, currentBinds, desiredBinds string) []Operation {
+		t.Helper()
+		current := parseTestConfig(t, "\nfrontend http\n    mode http\n"+currentBinds)
+		desired := parseTestConfig(t, "\nfrontend http\n    mode http\n"+desiredBinds)
+
+		diff, err := New().Compare(current, desired)
+		if err != nil {
+			t.Fatalf("Compare failed: %v", err)
+		}
+		return AddBindsBeforeRemoval(diff.Operations)
+	}
+
+	bindOps := func(ops []Operation) []string {
+		var targets []string
+		for _, op := range ops {
+			if op.Section() == "bind" {
+				targets = append(targets, opTypeName(op.Type())+" "+op.Target())
+			}
+		}
+		return targets
+	}
+
+	t.Run("creates the new bind before deleting the old one on a port change", func(t *testing.T) {
+		ops := compare(t, "    bind *:80\n", "    bind *:8080\n")
+
+		want := []string{"create http/*:8080", "delete http/*:80"}
+		if got := bindOps(ops); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected bind operations %v, got %v", want, got)
+		}
+	})
+
+	t.Run("keeps deletes first when the new bind reuses the address", func(t *testing.T) {
+		ops := compare(t, "    bind *:80 name old\n", "    bind *:80 name new\n")
+
+		want := []string{"delete http/old", "create http/new"}
+		if got := bindOps(ops); !reflect.DeepEqual(got, want) {
+			t.Errorf("Expected bind operations %v, got %v", want, got)
+		}
+	})
+}
+
+func opTypeName(opType sections.OperationType) string {
+	switch opType {
+	case sections.OperationCreate:
+		return "create"
+	case sections.OperationUpdate:
+		return "update"
+	default:
+		return "delete"
+	}
+}
@@ -2,6 +2,9 @@ package comparator
 
 import (
 	"fmt"
+	"maps"
+
+	"github.com/haproxytech/client-native/v6/models"
 
 	"haproxy-template-ic/pkg/dataplane/comparator/sections"
 	"haproxy-template-ic/pkg/dataplane/parser"
@@ -10,6 +13,7 @@ import (
 const (
 	parentTypeFrontend = "frontend"
 	parentTypeBackend  = "backend"
+	parentTypeGlobal   = "global"
 )
 
 // Comparator performs fine-grained comparison between HAProxy configurations.
@@ -21,6 +25,12 @@ type Comparator struct {
 	// Future: Add section-specific comparators here
 	// backendComparator *sections.BackendComparator
 	// serverComparator  *sections.ServerComparator
+
+	// fieldDefaults treats optional fields left unset as their HAProxy defaults
+	fieldDefaults bool
+
+	// stableServerNames names desired servers after their endpoint
+	stableServerNames bool
 }
 
 // New creates a new Comparator instance.
@@ -28,6 +38,63 @@ func New() *Comparator {
 	return &Comparator{}
 }
 
+// WithFieldDefaults makes the comparator treat optional fields that are unset
+// on one side as equal to the same field set to its HAProxy default on the
+// other side (e.g. an omitted server weight and "weight 1"), instead of
+// producing an update. It returns the comparator for chaining.
+func (c *Comparator) WithFieldDefaults() *Comparator {
+	c.fieldDefaults = true
+	return c
+}
+
+// WithStableServerNames makes the comparator rename the servers of the desired
+// configuration to the StableServerName of their endpoint before comparing, so
+// that servers rendered with index-based names match the current servers by
+// endpoint and reordering endpoints produces no renames. It returns the
+// comparator for chaining.
+func (c *Comparator) WithStableServerNames() *Comparator {
+	c.stableServerNames = true
+	return c
+}
+
+// copyNormalizedSections returns a copy of config whose defaults sections,
+// frontends and backends are shallow copies, so that normalizing them leaves
+// config unchanged. The server maps of backends are copied as well if
+// withServers is set, since field default normalization updates servers in
+// place. Everything else is shared with config.
+func copyNormalizedSections(config *parser.StructuredConfig, withServers bool) *parser.StructuredConfig {
+	copied := *config
+
+	copied.Defaults = make([]*models.Defaults, len(config.Defaults))
+	for i, d := range config.Defaults {
+		if d != nil {
+			d := *d
+			copied.Defaults[i] = &d
+		}
+	}
+
+	copied.Frontends = make([]*models.Frontend, len(config.Frontends))
+	for i, frontend := range config.Frontends {
+		if frontend != nil {
+			frontend := *frontend
+			copied.Frontends[i] = &frontend
+		}
+	}
+
+	copied.Backends = make([]*models.Backend, len(config.Backends))
+	for i, backend := range config.Backends {
+		if backend != nil {
+			backend := *backend
+			if withServers {
+				backend.Servers = maps.Clone(backend.Servers)
+			}
+			copied.Backends[i] = &backend
+		}
+	}
+
+	return &copied
+}
+
 // appendOperationsIfNotEmpty is a helper method that appends operations and marks as modified if operations exist.
 // This reduces cyclomatic complexity by extracting the common pattern used throughout comparison functions.
 func appendOperationsIfNotEmpty(dst *[]Operation, src []Operation, modified *bool) {
@@ -98,6 +165,15 @@ func compareMapEntries[T any](
 // single attribute changes (e.g., server weight), only that attribute is
 // updated rather than replacing the entire resource.
 //
+// Both configurations are normalized before they are compared. Normalization
+// works on shallow copies of the affected sections, so current and desired are
+// left unchanged:
+//   - anonymous defaults sections of desired are renamed after their
+//     counterparts in current, including in "from" references
+//   - with WithStableServerNames, the servers of desired are renamed
+//   - with WithFieldDefaults, optional fields set to their HAProxy default on
+//     one side are set the same way on the other
+//
 // Example:
 //
 //	comparator := comparator.New()
@@ -118,15 +194,61 @@ func (c *Comparator) Compare(current, desired *parser.StructuredConfig) (*Config
 		return nil, fmt.Errorf("desired configuration is nil")
 	}
 
+	current = copyNormalizedSections(current, c.fieldDefaults)
+	desired = copyNormalizedSections(desired, c.fieldDefaults)
+
+	normalizeAnonymousDefaults(current, desired)
+
+	if c.stableServerNames {
+		if err := applyStableServerNames(desired); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.fieldDefaults {
+		normalizeFieldDefaults(current, desired)
+	}
+
 	summary := NewDiffSummary()
+
+	// Server-only changes skip the section-by-section comparison
+	operations, ok := c.compareServersOnly(current, desired, &summary)
+	if !ok {
+		operations = c.compareAll(current, desired, &summary)
+	}
+
+	// Update summary counts
+	for _, op := range operations {
+		switch op.Type() {
+		case sections.OperationCreate:
+			summary.TotalCreates++
+		case sections.OperationUpdate:
+			summary.TotalUpdates++
+		case sections.OperationDelete:
+			summary.TotalDeletes++
+		}
+	}
+
+	// Order operations by dependencies
+	orderedOps := OrderOperations(operations)
+
+	return &ConfigDiff{
+		Operations: orderedOps,
+		Summary:    summary,
+		Current:    current,
+	}, nil
+}
+
+// compareAll compares every section of current and desired.
+func (c *Comparator) compareAll(current, desired *parser.StructuredConfig, summary *DiffSummary) []Operation {
 	var operations []Operation
 
 	// Compare global section
-	globalOps := c.compareGlobal(current, desired, &summary)
+	globalOps := c.compareGlobal(current, desired, summary)
 	operations = append(operations, globalOps...)
 
 	// Compare defaults sections
-	defaultsOps := c.compareDefaults(current, desired, &summary)
+	defaultsOps := c.compareDefaults(current, desired, summary)
 	operations = append(operations, defaultsOps...)
 
 	// Compare http-errors sections
@@ -174,11 +296,11 @@ func (c *Comparator) Compare(current, desired *parser.StructuredConfig) (*Config
 	operations = append(operations, crtStoresOps...)
 
 	// Compare frontends
-	frontendOps := c.compareFrontends(current, desired, &summary)
+	frontendOps := c.compareFrontends(current, desired, summary)
 	operations = append(operations, frontendOps...)
 
 	// Compare backends
-	backendOps := c.compareBackends(current, desired, &summary)
+	backendOps := c.compareBackends(current, desired, summary)
 	operations = append(operations, backendOps...)
 
 	// Future: Add more section comparisons here using the .Equal() pattern:
@@ -202,23 +324,5 @@ func (c *Comparator) Compare(current, desired *parser.StructuredConfig) (*Config
 	// - Rules (models.Rule.Equal)
 	// etc.
 
-	// Update summary counts
-	for _, op := range operations {
-		switch op.Type() {
-		case sections.OperationCreate:
-			summary.TotalCreates++
-		case sections.OperationUpdate:
-			summary.TotalUpdates++
-		case sections.OperationDelete:
-			summary.TotalDeletes++
-		}
-	}
-
-	// Order operations by dependencies
-	orderedOps := OrderOperations(operations)
-
-	return &ConfigDiff{
-		Operations: orderedOps,
-		Summary:    summary,
-	}, nil
+	return operations
 }
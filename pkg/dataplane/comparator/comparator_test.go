@@ -1,6 +1,7 @@
 package comparator
 
 import (
+	"reflect"
 	"testing"
 
 	"haproxy-template-ic/pkg/dataplane/comparator/sections"
@@ -651,6 +652,47 @@ func verifyUserlistOpsMatch(t *testing.T, operations []Operation, hasUserlistOps
 	}
 }
 
+// TestCompare_LeavesInputsUnchanged tests that normalizing defaults names,
+// server names and field defaults does not modify the configurations passed
+// to Compare.
+func TestCompare_LeavesInputsUnchanged(t *testing.T) {
+	currentConfig := `
+global
+    daemon
+
+defaults unnamed_defaults_3
+    mode http
+
+backend web from unnamed_defaults_3
+    server srv_120e9814c44c 10.0.0.1:8080 weight 1
+`
+	desiredConfig := `
+global
+    daemon
+
+defaults
+    mode http
+
+backend web
+    use-server srv1 if { path_beg /admin }
+    server srv1 10.0.0.1:8080
+    server srv2 10.0.0.2:8080 track srv1
+`
+	current, desired := parseTestConfigs(t, currentConfig, desiredConfig)
+
+	if _, err := New().WithStableServerNames().WithFieldDefaults().Compare(current, desired); err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	wantCurrent, wantDesired := parseTestConfigs(t, currentConfig, desiredConfig)
+	if !reflect.DeepEqual(current, wantCurrent) {
+		t.Error("Compare() modified the current configuration")
+	}
+	if !reflect.DeepEqual(desired, wantDesired) {
+		t.Error("Compare() modified the desired configuration")
+	}
+}
+
 // stringContains is a helper function for checking if a string contains a substring.
 func stringContains(s, substr string) bool {
 	return len(s) >= len(substr) && (s == substr || len(s) > len(substr) && containsSubstring(s, substr))
@@ -125,7 +125,7 @@ func (c *Comparator) compareModifiedBackends(desiredBackends, currentBackends ma
 		appendOperationsIfNotEmpty(&operations, stickRuleOps, &backendModified)
 
 		// Compare HTTP after response rules within this backend
-		httpAfterRuleOps := c.compareHTTPAfterResponseRules(name, currentBackend.HTTPAfterResponseRuleList, desiredBackend.HTTPAfterResponseRuleList)
+		httpAfterRuleOps := c.compareHTTPAfterResponseRules("backend", name, currentBackend.HTTPAfterResponseRuleList, desiredBackend.HTTPAfterResponseRuleList)
 		appendOperationsIfNotEmpty(&operations, httpAfterRuleOps, &backendModified)
 
 		// Compare server switching rules within this backend
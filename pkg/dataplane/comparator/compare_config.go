@@ -1,6 +1,8 @@
 package comparator
 
 import (
+	"slices"
+
 	"github.com/haproxytech/client-native/v6/models"
 
 	"haproxy-template-ic/pkg/dataplane/comparator/sections"
@@ -44,20 +46,67 @@ func (c *Comparator) compareHTTPErrors(current, desired *parser.StructuredConfig
 
 	// Find modified http-errors sections
 	for name, desiredHTTPError := range desiredMap {
-		if currentHTTPError, exists := currentMap[name]; exists {
-			// Compare http-errors sections using Equal() method
-			if !httpErrorsEqual(currentHTTPError, desiredHTTPError) {
-				operations = append(operations, sections.NewHTTPErrorsSectionUpdate(desiredHTTPError))
-			}
+		currentHTTPError, exists := currentMap[name]
+		if !exists {
+			continue
+		}
+
+		// Error files are compared per status code, so reordering them is not a change
+		// and a changed file is reported by its code
+		if !httpErrorsEqualWithoutErrorFiles(currentHTTPError, desiredHTTPError) {
+			operations = append(operations, sections.NewHTTPErrorsSectionUpdate(desiredHTTPError))
+		} else if codes := changedErrorFileCodes(currentHTTPError, desiredHTTPError); len(codes) > 0 {
+			operations = append(operations, sections.NewHTTPErrorsSectionErrorFilesUpdate(desiredHTTPError, codes))
 		}
 	}
 
 	return operations
 }
 
-// httpErrorsEqual compares two http-errors sections for equality.
-func httpErrorsEqual(h1, h2 *models.HTTPErrorsSection) bool {
-	return h1.Equal(*h2)
+// httpErrorsEqualWithoutErrorFiles compares two http-errors sections excluding
+// their error files, which are compared separately by changedErrorFileCodes.
+func httpErrorsEqualWithoutErrorFiles(h1, h2 *models.HTTPErrorsSection) bool {
+	h1Copy := *h1
+	h2Copy := *h2
+
+	h1Copy.ErrorFiles = nil
+	h2Copy.ErrorFiles = nil
+
+	return h1Copy.Equal(h2Copy)
+}
+
+// changedErrorFileCodes returns the status codes whose error file was added,
+// removed or changed between two http-errors sections, in ascending order.
+func changedErrorFileCodes(current, desired *models.HTTPErrorsSection) []int64 {
+	currentFiles := errorFilesByCode(current)
+	desiredFiles := errorFilesByCode(desired)
+
+	var codes []int64
+	for code, file := range desiredFiles {
+		if currentFile, exists := currentFiles[code]; !exists || currentFile != file {
+			codes = append(codes, code)
+		}
+	}
+	for code := range currentFiles {
+		if _, exists := desiredFiles[code]; !exists {
+			codes = append(codes, code)
+		}
+	}
+
+	slices.Sort(codes)
+	return codes
+}
+
+// errorFilesByCode maps the status codes of an http-errors section to their
+// error file. If a code is listed more than once, the last entry is used.
+func errorFilesByCode(section *models.HTTPErrorsSection) map[int64]string {
+	files := make(map[int64]string, len(section.ErrorFiles))
+	for _, errorFile := range section.ErrorFiles {
+		if errorFile != nil {
+			files[errorFile.Code] = errorFile.File
+		}
+	}
+	return files
 }
 
 // compareMailers compares mailers sections between current and desired configurations.
@@ -87,7 +136,7 @@ func (c *Comparator) compareMailers(current, desired *parser.StructuredConfig) [
 			continue
 		}
 
-		operations = append(operations, sections.NewMailersSectionCreate(mailers))
+		operations = append(operations, sections.NewMailersSectionCreate(mailersWithoutEntries(mailers)))
 
 		// Also create mailer entries for this new mailers section
 		// Compare against an empty mailers section to get all mailer entry create operations
@@ -118,7 +167,7 @@ func (c *Comparator) compareMailers(current, desired *parser.StructuredConfig) [
 
 			// Compare mailers section attributes (excluding mailer entries which we already compared)
 			if !mailersEqualWithoutMailerEntries(currentMailers, desiredMailers) {
-				operations = append(operations, sections.NewMailersSectionUpdate(desiredMailers))
+				operations = append(operations, sections.NewMailersSectionUpdate(mailersWithoutEntries(desiredMailers)))
 			}
 		}
 	}
@@ -141,6 +190,16 @@ func mailersEqualWithoutMailerEntries(m1, m2 *models.MailersSection) bool {
 	return m1Copy.Equal(m2Copy)
 }
 
+// mailersWithoutEntries returns a copy of a mailers section without its mailer
+// entries. Entries are synced by their own operations, so section create and
+// edit requests only carry the section attributes (e.g. timeout) and an edit
+// can never replace or drop the existing entries.
+func mailersWithoutEntries(m *models.MailersSection) *models.MailersSection {
+	mCopy := *m
+	mCopy.MailerEntries = nil
+	return &mCopy
+}
+
 // compareMailerEntries compares mailer entry configurations within a mailers section.
 func (c *Comparator) compareMailerEntries(mailersSection string, currentMailers, desiredMailers *models.MailersSection) []Operation {
 	return compareMapEntries(
@@ -203,6 +262,9 @@ func (c *Comparator) comparePeers(current, desired *parser.StructuredConfig) []O
 		if len(peerEntryOps) > 0 {
 			operations = append(operations, peerEntryOps...)
 		}
+		operations = append(operations, c.compareTables(name, emptyPeer, peer)...)
+		operations = append(operations, c.comparePeerBinds(name, emptyPeer, peer)...)
+		operations = append(operations, c.comparePeerServers(name, emptyPeer, peer)...)
 	}
 
 	// Find deleted peer sections
@@ -221,7 +283,17 @@ func (c *Comparator) comparePeers(current, desired *parser.StructuredConfig) []O
 			peerEntryOps := c.comparePeerEntries(name, currentPeer, desiredPeer)
 			appendOperationsIfNotEmpty(&operations, peerEntryOps, &peerModified)
 
-			// Compare peers section attributes (excluding peer entries which we already compared)
+			// Compare stick-tables within this peers section
+			tableOps := c.compareTables(name, currentPeer, desiredPeer)
+			appendOperationsIfNotEmpty(&operations, tableOps, &peerModified)
+
+			// Compare the local peer's binds and servers within this peers section
+			bindOps := c.comparePeerBinds(name, currentPeer, desiredPeer)
+			appendOperationsIfNotEmpty(&operations, bindOps, &peerModified)
+			serverOps := c.comparePeerServers(name, currentPeer, desiredPeer)
+			appendOperationsIfNotEmpty(&operations, serverOps, &peerModified)
+
+			// Compare peers section attributes (excluding the children we already compared)
 			if !peersEqualWithoutPeerEntries(currentPeer, desiredPeer) {
 				operations = append(operations, sections.NewPeerSectionUpdate(desiredPeer))
 			}
@@ -231,17 +303,24 @@ func (c *Comparator) comparePeers(current, desired *parser.StructuredConfig) []O
 	return operations
 }
 
-// peersEqualWithoutPeerEntries checks if two peer sections are equal, excluding peer entries.
+// peersEqualWithoutPeerEntries checks if two peer sections are equal, excluding peer entries,
+// tables, binds and servers.
 // Uses the HAProxy models' built-in Equal() method to compare peer section attributes
-// automatically, excluding peer entries we compare separately.
+// automatically, excluding the children we compare separately.
 func peersEqualWithoutPeerEntries(p1, p2 *models.PeerSection) bool {
 	// Create copies to avoid modifying originals
 	p1Copy := *p1
 	p2Copy := *p2
 
-	// Clear peer entries so they don't affect comparison
+	// Clear children so they don't affect comparison
 	p1Copy.PeerEntries = nil
 	p2Copy.PeerEntries = nil
+	p1Copy.Tables = nil
+	p2Copy.Tables = nil
+	p1Copy.Binds = nil
+	p2Copy.Binds = nil
+	p1Copy.Servers = nil
+	p2Copy.Servers = nil
 
 	return p1Copy.Equal(p2Copy)
 }
@@ -270,6 +349,68 @@ func peerEntriesEqual(p1, p2 *models.PeerEntry) bool {
 	return p1.Equal(*p2)
 }
 
+// comparePeerBinds compares the binds of a peers section by name.
+// A bind declares the address the local peer listens on when it is
+// configured with "bind"/"server" lines instead of a "peer" line.
+func (c *Comparator) comparePeerBinds(peersSection string, currentPeer, desiredPeer *models.PeerSection) []Operation {
+	return compareMapEntries(
+		currentPeer.Binds,
+		desiredPeer.Binds,
+		func(bind *models.Bind) Operation {
+			return sections.NewPeerBindCreate(peersSection, bind)
+		},
+		func(bind *models.Bind) Operation {
+			return sections.NewPeerBindDelete(peersSection, bind)
+		},
+		func(bind *models.Bind) Operation {
+			return sections.NewPeerBindUpdate(peersSection, bind)
+		},
+		func(b1, b2 *models.Bind) bool { return b1.Equal(*b2) },
+	)
+}
+
+// comparePeerServers compares the servers of a peers section by name.
+func (c *Comparator) comparePeerServers(peersSection string, currentPeer, desiredPeer *models.PeerSection) []Operation {
+	return compareMapEntries(
+		currentPeer.Servers,
+		desiredPeer.Servers,
+		func(server *models.Server) Operation {
+			return sections.NewPeerServerCreate(peersSection, server)
+		},
+		func(server *models.Server) Operation {
+			return sections.NewPeerServerDelete(peersSection, server)
+		},
+		func(server *models.Server) Operation {
+			return sections.NewPeerServerUpdate(peersSection, server)
+		},
+		serversEqual,
+	)
+}
+
+// compareTables compares stick-table definitions within a peers section.
+func (c *Comparator) compareTables(peersSection string, currentPeer, desiredPeer *models.PeerSection) []Operation {
+	return compareMapEntries(
+		currentPeer.Tables,
+		desiredPeer.Tables,
+		func(table *models.Table) Operation {
+			return sections.NewTableCreate(peersSection, table)
+		},
+		func(table *models.Table) Operation {
+			return sections.NewTableDelete(peersSection, table)
+		},
+		func(table *models.Table) Operation {
+			return sections.NewTableUpdate(peersSection, table)
+		},
+		tablesEqual,
+	)
+}
+
+// tablesEqual checks if two stick-tables are equal.
+// Uses the HAProxy models' built-in Equal() method to compare ALL attributes.
+func tablesEqual(t1, t2 *models.Table) bool {
+	return t1.Equal(*t2)
+}
+
 // compareCaches compares cache sections between current and desired configurations.
 func (c *Comparator) compareCaches(current, desired *parser.StructuredConfig) []Operation {
 	var operations []Operation
@@ -345,9 +486,16 @@ func (c *Comparator) compareRings(current, desired *parser.StructuredConfig) []O
 
 	// Find added ring sections
 	for name, ring := range desiredMap {
-		if _, exists := currentMap[name]; !exists {
-			operations = append(operations, sections.NewRingCreate(ring))
+		if _, exists := currentMap[name]; exists {
+			continue
 		}
+
+		operations = append(operations, sections.NewRingCreate(ringWithoutServers(ring)))
+
+		// Also create the servers of the new ring
+		emptyRing := &models.Ring{}
+		emptyRing.Name = name
+		operations = append(operations, c.compareRingServers(name, emptyRing, ring)...)
 	}
 
 	// Find deleted ring sections
@@ -360,8 +508,10 @@ func (c *Comparator) compareRings(current, desired *parser.StructuredConfig) []O
 	// Find modified ring sections
 	for name, desiredRing := range desiredMap {
 		if currentRing, exists := currentMap[name]; exists {
-			if !ringEqual(currentRing, desiredRing) {
-				operations = append(operations, sections.NewRingUpdate(desiredRing))
+			operations = append(operations, c.compareRingServers(name, currentRing, desiredRing)...)
+
+			if !ringEqualWithoutServers(currentRing, desiredRing) {
+				operations = append(operations, sections.NewRingUpdate(ringWithoutServers(desiredRing)))
 			}
 		}
 	}
@@ -369,9 +519,37 @@ func (c *Comparator) compareRings(current, desired *parser.StructuredConfig) []O
 	return operations
 }
 
-// ringEqual compares two ring sections for equality.
-func ringEqual(r1, r2 *models.Ring) bool {
-	return r1.Equal(*r2)
+// ringEqualWithoutServers compares two ring sections for equality, excluding
+// their servers which are compared separately.
+func ringEqualWithoutServers(r1, r2 *models.Ring) bool {
+	return ringWithoutServers(r1).Equal(*ringWithoutServers(r2))
+}
+
+// ringWithoutServers returns a copy of a ring section without its servers.
+// Servers are synced by their own operations, so a ring edit can never
+// replace or drop the existing servers.
+func ringWithoutServers(r *models.Ring) *models.Ring {
+	rCopy := *r
+	rCopy.Servers = nil
+	return &rCopy
+}
+
+// compareRingServers compares the servers of a ring section by name.
+func (c *Comparator) compareRingServers(ringName string, currentRing, desiredRing *models.Ring) []Operation {
+	return compareMapEntries(
+		currentRing.Servers,
+		desiredRing.Servers,
+		func(server *models.Server) Operation {
+			return sections.NewRingServerCreate(ringName, server)
+		},
+		func(server *models.Server) Operation {
+			return sections.NewRingServerDelete(ringName, server)
+		},
+		func(server *models.Server) Operation {
+			return sections.NewRingServerUpdate(ringName, server)
+		},
+		serversEqual,
+	)
 }
 
 // comparePrograms compares program sections between current and desired configurations.
@@ -9,6 +9,12 @@ import (
 
 // compareGlobal compares global section configurations between current and desired.
 // The global section is a singleton - it always exists and can only be updated, not created or deleted.
+//
+// Log lines are diffed by position as log target children, like those of
+// frontends and backends, so changing one log line updates only that target.
+// All other global fields (process settings, stats sockets, tune, SSL, lua,
+// performance options, ...) are compared as a whole and replaced by a single
+// global update. Rings are separate sections compared on their own.
 func (c *Comparator) compareGlobal(current, desired *parser.StructuredConfig, summary *DiffSummary) []Operation {
 	var operations []Operation
 
@@ -18,16 +24,31 @@ func (c *Comparator) compareGlobal(current, desired *parser.StructuredConfig, su
 		return operations
 	}
 
-	// Compare using built-in Equal() method
-	// This automatically compares all global attributes (log settings, stats socket, maxconn, etc.)
-	if !current.Global.Equal(*desired.Global) {
+	// Compare using built-in Equal() method, without log targets
+	// The global replace does not touch log targets, they are compared below
+	if !globalsEqualWithoutLogTargets(current.Global, desired.Global) {
 		operations = append(operations, sections.NewGlobalUpdate(desired.Global))
 		summary.GlobalChanged = true
 	}
 
+	logTargetOps := c.compareLogTargets(parentTypeGlobal, "", current.Global.LogTargetList, desired.Global.LogTargetList)
+	if len(logTargetOps) > 0 {
+		operations = append(operations, logTargetOps...)
+		summary.GlobalChanged = true
+	}
+
 	return operations
 }
 
+// globalsEqualWithoutLogTargets compares two global sections, ignoring their log targets.
+func globalsEqualWithoutLogTargets(g1, g2 *models.Global) bool {
+	g1Copy := *g1
+	g2Copy := *g2
+	g1Copy.LogTargetList = nil
+	g2Copy.LogTargetList = nil
+	return g1Copy.Equal(g2Copy)
+}
+
 // compareDefaults compares defaults section configurations between current and desired.
 // HAProxy can have multiple defaults sections (identified by name).
 func (c *Comparator) compareDefaults(current, desired *parser.StructuredConfig, summary *DiffSummary) []Operation {
@@ -74,6 +74,10 @@ func (c *Comparator) compareAddedFrontends(desiredFrontends, currentFrontends ma
 		responseRuleOps := c.compareHTTPResponseRules(parentTypeFrontend, name, emptyFrontend.HTTPResponseRuleList, frontend.HTTPResponseRuleList)
 		operations = append(operations, responseRuleOps...)
 
+		// Compare HTTP after response rules
+		httpAfterRuleOps := c.compareHTTPAfterResponseRules(parentTypeFrontend, name, emptyFrontend.HTTPAfterResponseRuleList, frontend.HTTPAfterResponseRuleList)
+		operations = append(operations, httpAfterRuleOps...)
+
 		// Compare TCP request rules
 		tcpRequestRuleOps := c.compareTCPRequestRules(parentTypeFrontend, name, emptyFrontend.TCPRequestRuleList, frontend.TCPRequestRuleList)
 		operations = append(operations, tcpRequestRuleOps...)
@@ -126,6 +130,10 @@ func (c *Comparator) compareModifiedFrontends(desiredFrontends, currentFrontends
 		responseRuleOps := c.compareHTTPResponseRules(parentTypeFrontend, name, currentFrontend.HTTPResponseRuleList, desiredFrontend.HTTPResponseRuleList)
 		appendOperationsIfNotEmpty(&operations, responseRuleOps, &frontendModified)
 
+		// Compare HTTP after response rules within this frontend
+		httpAfterRuleOps := c.compareHTTPAfterResponseRules(parentTypeFrontend, name, currentFrontend.HTTPAfterResponseRuleList, desiredFrontend.HTTPAfterResponseRuleList)
+		appendOperationsIfNotEmpty(&operations, httpAfterRuleOps, &frontendModified)
+
 		// Compare TCP request rules within this frontend
 		tcpRequestRuleOps := c.compareTCPRequestRules(parentTypeFrontend, name, currentFrontend.TCPRequestRuleList, desiredFrontend.TCPRequestRuleList)
 		appendOperationsIfNotEmpty(&operations, tcpRequestRuleOps, &frontendModified)
@@ -180,6 +188,8 @@ func frontendsEqualWithoutNestedCollections(f1, f2 *models.Frontend) bool {
 	f2Copy.HTTPRequestRuleList = nil
 	f1Copy.HTTPResponseRuleList = nil
 	f2Copy.HTTPResponseRuleList = nil
+	f1Copy.HTTPAfterResponseRuleList = nil
+	f2Copy.HTTPAfterResponseRuleList = nil
 	f1Copy.TCPRequestRuleList = nil
 	f2Copy.TCPRequestRuleList = nil
 	f1Copy.BackendSwitchingRuleList = nil
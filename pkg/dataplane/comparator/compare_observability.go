@@ -7,7 +7,8 @@ import (
 	"haproxy-template-ic/pkg/dataplane/parser"
 )
 
-// compareLogTargets compares log target configurations within a frontend or backend.
+// compareLogTargets compares log target configurations within a frontend, a
+// backend or the global section (parentName is ignored for global).
 // Log targets are compared by position since they don't have unique identifiers.
 func (c *Comparator) compareLogTargets(parentType, parentName string, currentLogs, desiredLogs models.LogTargets) []Operation {
 	var operations []Operation
@@ -38,27 +39,36 @@ func (c *Comparator) compareLogTargets(parentType, parentName string, currentLog
 }
 
 func (c *Comparator) createLogTargetOperation(parentType, parentName string, logTarget *models.LogTarget, index int) []Operation {
-	if parentType == parentTypeFrontend {
+	switch parentType {
+	case parentTypeFrontend:
 		return []Operation{sections.NewLogTargetFrontendCreate(parentName, logTarget, index)}
+	case parentTypeGlobal:
+		return []Operation{sections.NewLogTargetGlobalCreate(logTarget, index)}
 	}
 	return []Operation{sections.NewLogTargetBackendCreate(parentName, logTarget, index)}
 }
 
 func (c *Comparator) deleteLogTargetOperation(parentType, parentName string, logTarget *models.LogTarget, index int) []Operation {
-	if parentType == parentTypeFrontend {
+	switch parentType {
+	case parentTypeFrontend:
 		return []Operation{sections.NewLogTargetFrontendDelete(parentName, logTarget, index)}
+	case parentTypeGlobal:
+		return []Operation{sections.NewLogTargetGlobalDelete(logTarget, index)}
 	}
 	return []Operation{sections.NewLogTargetBackendDelete(parentName, logTarget, index)}
 }
 
 func (c *Comparator) updateLogTargetOperation(parentType, parentName string, currentLog, desiredLog *models.LogTarget, index int) []Operation {
-	if !currentLog.Equal(*desiredLog) {
-		if parentType == parentTypeFrontend {
-			return []Operation{sections.NewLogTargetFrontendUpdate(parentName, desiredLog, index)}
-		}
-		return []Operation{sections.NewLogTargetBackendUpdate(parentName, desiredLog, index)}
+	if currentLog.Equal(*desiredLog) {
+		return nil
+	}
+	switch parentType {
+	case parentTypeFrontend:
+		return []Operation{sections.NewLogTargetFrontendUpdate(parentName, desiredLog, index)}
+	case parentTypeGlobal:
+		return []Operation{sections.NewLogTargetGlobalUpdate(desiredLog, index)}
 	}
-	return nil
+	return []Operation{sections.NewLogTargetBackendUpdate(parentName, desiredLog, index)}
 }
 
 // compareLogForwards compares log-forward sections between current and desired configurations.
@@ -86,6 +96,10 @@ func (c *Comparator) compareLogForwards(current, desired *parser.StructuredConfi
 	for name, logForward := range desiredMap {
 		if _, exists := currentMap[name]; !exists {
 			operations = append(operations, sections.NewLogForwardCreate(logForward))
+
+			// Create nested binds of the new log-forward section
+			operations = append(operations, c.compareLogForwardBinds(name, nil, logForward.Binds)...)
+			operations = append(operations, c.compareDgramBinds(name, nil, logForward.DgramBinds)...)
 		}
 	}
 
@@ -99,6 +113,9 @@ func (c *Comparator) compareLogForwards(current, desired *parser.StructuredConfi
 	// Find modified log-forward sections
 	for name, desiredLogForward := range desiredMap {
 		if currentLogForward, exists := currentMap[name]; exists {
+			operations = append(operations, c.compareLogForwardBinds(name, currentLogForward.Binds, desiredLogForward.Binds)...)
+			operations = append(operations, c.compareDgramBinds(name, currentLogForward.DgramBinds, desiredLogForward.DgramBinds)...)
+
 			if !logForwardEqual(currentLogForward, desiredLogForward) {
 				operations = append(operations, sections.NewLogForwardUpdate(desiredLogForward))
 			}
@@ -108,7 +125,88 @@ func (c *Comparator) compareLogForwards(current, desired *parser.StructuredConfi
 	return operations
 }
 
-// logForwardEqual compares two log-forward sections for equality.
+// compareLogForwardBinds compares bind configurations within a log-forward section.
+// Binds are identified by their name (Name field in the map key).
+func (c *Comparator) compareLogForwardBinds(logForwardName string, currentBinds, desiredBinds map[string]models.Bind) []Operation {
+	var operations []Operation
+
+	// Find added binds
+	for name := range desiredBinds {
+		if _, exists := currentBinds[name]; !exists {
+			bind := desiredBinds[name]
+			operations = append(operations, sections.NewBindLogForwardCreate(logForwardName, name, &bind))
+		}
+	}
+
+	// Find deleted binds
+	for name := range currentBinds {
+		if _, exists := desiredBinds[name]; !exists {
+			bind := currentBinds[name]
+			operations = append(operations, sections.NewBindLogForwardDelete(logForwardName, name, &bind))
+		}
+	}
+
+	// Find modified binds
+	for name := range desiredBinds {
+		currentBind, exists := currentBinds[name]
+		if !exists {
+			continue
+		}
+		desiredBind := desiredBinds[name]
+		if !currentBind.Equal(desiredBind) {
+			operations = append(operations, sections.NewBindLogForwardUpdate(logForwardName, name, &desiredBind))
+		}
+	}
+
+	return operations
+}
+
+// compareDgramBinds compares dgram-bind configurations within a log-forward section.
+// Dgram-binds are identified by their name (Name field in the map key).
+func (c *Comparator) compareDgramBinds(logForwardName string, currentBinds, desiredBinds map[string]models.DgramBind) []Operation {
+	var operations []Operation
+
+	// Find added dgram-binds
+	for name := range desiredBinds {
+		if _, exists := currentBinds[name]; !exists {
+			bind := desiredBinds[name]
+			operations = append(operations, sections.NewDgramBindLogForwardCreate(logForwardName, name, &bind))
+		}
+	}
+
+	// Find deleted dgram-binds
+	for name := range currentBinds {
+		if _, exists := desiredBinds[name]; !exists {
+			bind := currentBinds[name]
+			operations = append(operations, sections.NewDgramBindLogForwardDelete(logForwardName, name, &bind))
+		}
+	}
+
+	// Find modified dgram-binds
+	for name := range desiredBinds {
+		currentBind, exists := currentBinds[name]
+		if !exists {
+			continue
+		}
+		desiredBind := desiredBinds[name]
+		if !currentBind.Equal(desiredBind) {
+			operations = append(operations, sections.NewDgramBindLogForwardUpdate(logForwardName, name, &desiredBind))
+		}
+	}
+
+	return operations
+}
+
+// logForwardEqual compares two log-forward sections for equality, excluding
+// binds and dgram-binds which are compared separately.
 func logForwardEqual(l1, l2 *models.LogForward) bool {
-	return l1.Equal(*l2)
+	l1Copy := *l1
+	l2Copy := *l2
+
+	l1Copy.Binds = nil
+	l2Copy.Binds = nil
+	l1Copy.DgramBinds = nil
+	l2Copy.DgramBinds = nil
+
+	return l1Copy.Equal(l2Copy)
 }
@@ -1,6 +1,8 @@
 package comparator
 
 import (
+	"slices"
+
 	"github.com/haproxytech/client-native/v6/models"
 
 	"haproxy-template-ic/pkg/dataplane/comparator/sections"
@@ -101,28 +103,24 @@ func (c *Comparator) compareModifiedACLs(parentType, parentName string, desiredA
 }
 
 // compareHTTPRequestRules compares HTTP request rule configurations within a frontend or backend.
-// Rules are compared by position since they don't have unique identifiers.
+// Rules are matched by content fingerprint so that inserting or removing a rule
+// produces a single operation instead of rewriting every rule after it.
+// Conditions are compared in normalized form (see normalizeCondition).
 func (c *Comparator) compareHTTPRequestRules(parentType, parentName string, currentRules, desiredRules models.HTTPRequestRules) []Operation {
 	var operations []Operation
 
-	// Compare rules by position
-	maxLen := len(currentRules)
-	if len(desiredRules) > maxLen {
-		maxLen = len(desiredRules)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		hasCurrentRule := i < len(currentRules)
-		hasDesiredRule := i < len(desiredRules)
-
-		if !hasCurrentRule && hasDesiredRule {
-			ops := c.createHTTPRequestRuleOperation(parentType, parentName, desiredRules[i], i)
+	current := withNormalizedConditions(currentRules, httpRequestRuleCondTest)
+	desired := withNormalizedConditions(desiredRules, httpRequestRuleCondTest)
+	for _, edit := range diffRuleLists(ruleFingerprints(current), ruleFingerprints(desired)) {
+		switch edit.opType {
+		case sections.OperationDelete:
+			ops := c.deleteHTTPRequestRuleOperation(parentType, parentName, currentRules[edit.currentIndex], edit.currentIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && !hasDesiredRule {
-			ops := c.deleteHTTPRequestRuleOperation(parentType, parentName, currentRules[i], i)
+		case sections.OperationCreate:
+			ops := c.createHTTPRequestRuleOperation(parentType, parentName, desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && hasDesiredRule {
-			ops := c.updateHTTPRequestRuleOperation(parentType, parentName, currentRules[i], desiredRules[i], i)
+		case sections.OperationUpdate:
+			ops := c.updateHTTPRequestRuleOperation(parentType, parentName, currentRules[edit.currentIndex], desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
 		}
 	}
@@ -155,28 +153,23 @@ func (c *Comparator) updateHTTPRequestRuleOperation(parentType, parentName strin
 }
 
 // compareHTTPResponseRules compares HTTP response rule configurations within a frontend or backend.
-// Rules are compared by position since they don't have unique identifiers.
+// Rules are matched by content fingerprint so that inserting or removing a rule
+// produces a single operation instead of rewriting every rule after it.
 func (c *Comparator) compareHTTPResponseRules(parentType, parentName string, currentRules, desiredRules models.HTTPResponseRules) []Operation {
 	var operations []Operation
 
-	// Compare rules by position
-	maxLen := len(currentRules)
-	if len(desiredRules) > maxLen {
-		maxLen = len(desiredRules)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		hasCurrentRule := i < len(currentRules)
-		hasDesiredRule := i < len(desiredRules)
-
-		if !hasCurrentRule && hasDesiredRule {
-			ops := c.createHTTPResponseRuleOperation(parentType, parentName, desiredRules[i], i)
+	current := withNormalizedConditions(currentRules, httpResponseRuleCondTest)
+	desired := withNormalizedConditions(desiredRules, httpResponseRuleCondTest)
+	for _, edit := range diffRuleLists(ruleFingerprints(current), ruleFingerprints(desired)) {
+		switch edit.opType {
+		case sections.OperationDelete:
+			ops := c.deleteHTTPResponseRuleOperation(parentType, parentName, currentRules[edit.currentIndex], edit.currentIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && !hasDesiredRule {
-			ops := c.deleteHTTPResponseRuleOperation(parentType, parentName, currentRules[i], i)
+		case sections.OperationCreate:
+			ops := c.createHTTPResponseRuleOperation(parentType, parentName, desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && hasDesiredRule {
-			ops := c.updateHTTPResponseRuleOperation(parentType, parentName, currentRules[i], desiredRules[i], i)
+		case sections.OperationUpdate:
+			ops := c.updateHTTPResponseRuleOperation(parentType, parentName, currentRules[edit.currentIndex], desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
 		}
 	}
@@ -209,28 +202,30 @@ func (c *Comparator) updateHTTPResponseRuleOperation(parentType, parentName stri
 }
 
 // compareTCPRequestRules compares TCP request rule configurations within a frontend or backend.
-// Rules are compared by position since they don't have unique identifiers.
+//
+// The connection, session and content phases (and inspect-delay) share one
+// list, but HAProxy evaluates each phase on its own, so only the order within
+// a phase matters. Lists that differ only in how the phases are interleaved
+// are equal; otherwise rules are matched by content fingerprint like HTTP rules.
 func (c *Comparator) compareTCPRequestRules(parentType, parentName string, currentRules, desiredRules models.TCPRequestRules) []Operation {
-	var operations []Operation
-
-	// Compare rules by position
-	maxLen := len(currentRules)
-	if len(desiredRules) > maxLen {
-		maxLen = len(desiredRules)
+	current := withNormalizedConditions(currentRules, tcpRequestRuleCondTest)
+	desired := withNormalizedConditions(desiredRules, tcpRequestRuleCondTest)
+	if tcpRequestPhasesEqual(current, desired) {
+		return nil
 	}
 
-	for i := 0; i < maxLen; i++ {
-		hasCurrentRule := i < len(currentRules)
-		hasDesiredRule := i < len(desiredRules)
+	var operations []Operation
 
-		if !hasCurrentRule && hasDesiredRule {
-			ops := c.createTCPRequestRuleOperation(parentType, parentName, desiredRules[i], i)
+	for _, edit := range diffRuleLists(ruleFingerprints(current), ruleFingerprints(desired)) {
+		switch edit.opType {
+		case sections.OperationDelete:
+			ops := c.deleteTCPRequestRuleOperation(parentType, parentName, currentRules[edit.currentIndex], edit.currentIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && !hasDesiredRule {
-			ops := c.deleteTCPRequestRuleOperation(parentType, parentName, currentRules[i], i)
+		case sections.OperationCreate:
+			ops := c.createTCPRequestRuleOperation(parentType, parentName, desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
-		} else if hasCurrentRule && hasDesiredRule {
-			ops := c.updateTCPRequestRuleOperation(parentType, parentName, currentRules[i], desiredRules[i], i)
+		case sections.OperationUpdate:
+			ops := c.updateTCPRequestRuleOperation(parentType, parentName, currentRules[edit.currentIndex], desiredRules[edit.desiredIndex], edit.desiredIndex)
 			operations = append(operations, ops...)
 		}
 	}
@@ -238,6 +233,34 @@ func (c *Comparator) compareTCPRequestRules(parentType, parentName string, curre
 	return operations
 }
 
+// tcpRequestPhasesEqual reports whether two TCP request rule lists contain the
+// same rules in the same order within each phase (rule type).
+func tcpRequestPhasesEqual(currentRules, desiredRules models.TCPRequestRules) bool {
+	if len(currentRules) != len(desiredRules) {
+		return false
+	}
+
+	byPhase := func(rules models.TCPRequestRules) map[string][]string {
+		phases := make(map[string][]string)
+		fingerprints := ruleFingerprints(rules)
+		for i, rule := range rules {
+			phases[rule.Type] = append(phases[rule.Type], fingerprints[i])
+		}
+		return phases
+	}
+
+	current, desired := byPhase(currentRules), byPhase(desiredRules)
+	if len(current) != len(desired) {
+		return false
+	}
+	for phase, rules := range current {
+		if !slices.Equal(rules, desired[phase]) {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Comparator) createTCPRequestRuleOperation(parentType, parentName string, rule *models.TCPRequestRule, index int) []Operation {
 	if parentType == parentTypeFrontend {
 		return []Operation{sections.NewTCPRequestRuleFrontendCreate(parentName, rule, index)}
@@ -337,42 +360,48 @@ func (c *Comparator) compareStickRules(backendName string, currentRules, desired
 	return operations
 }
 
-// compareHTTPAfterResponseRules compares HTTP after response rule configurations within a backend.
-// Rules are compared by position since they don't have unique identifiers.
-// Backend-only (frontends do not support HTTP after response rules).
-func (c *Comparator) compareHTTPAfterResponseRules(backendName string, currentRules, desiredRules models.HTTPAfterResponseRules) []Operation {
+// compareHTTPAfterResponseRules compares HTTP after response rule configurations within a frontend or backend.
+// Rules are matched by content fingerprint like HTTP request and response rules.
+func (c *Comparator) compareHTTPAfterResponseRules(parentType, parentName string, currentRules, desiredRules models.HTTPAfterResponseRules) []Operation {
 	var operations []Operation
 
-	// Compare rules by position
-	maxLen := len(currentRules)
-	if len(desiredRules) > maxLen {
-		maxLen = len(desiredRules)
+	current := withNormalizedConditions(currentRules, httpAfterResponseRuleCondTest)
+	desired := withNormalizedConditions(desiredRules, httpAfterResponseRuleCondTest)
+	for _, edit := range diffRuleLists(ruleFingerprints(current), ruleFingerprints(desired)) {
+		switch edit.opType {
+		case sections.OperationDelete:
+			operations = append(operations, c.deleteHTTPAfterResponseRuleOperation(parentType, parentName, currentRules[edit.currentIndex], edit.currentIndex))
+		case sections.OperationCreate:
+			operations = append(operations, c.createHTTPAfterResponseRuleOperation(parentType, parentName, desiredRules[edit.desiredIndex], edit.desiredIndex))
+		case sections.OperationUpdate:
+			if !currentRules[edit.currentIndex].Equal(*desiredRules[edit.desiredIndex]) {
+				operations = append(operations, c.updateHTTPAfterResponseRuleOperation(parentType, parentName, desiredRules[edit.desiredIndex], edit.desiredIndex))
+			}
+		}
 	}
 
-	for i := 0; i < maxLen; i++ {
-		hasCurrentRule := i < len(currentRules)
-		hasDesiredRule := i < len(desiredRules)
+	return operations
+}
 
-		if !hasCurrentRule && hasDesiredRule {
-			// Rule added at this position
-			rule := desiredRules[i]
-			operations = append(operations, sections.NewHTTPAfterResponseRuleBackendCreate(backendName, rule, i))
-		} else if hasCurrentRule && !hasDesiredRule {
-			// Rule removed at this position
-			rule := currentRules[i]
-			operations = append(operations, sections.NewHTTPAfterResponseRuleBackendDelete(backendName, rule, i))
-		} else if hasCurrentRule && hasDesiredRule {
-			// Both exist - check if modified
-			currentRule := currentRules[i]
-			desiredRule := desiredRules[i]
+func (c *Comparator) createHTTPAfterResponseRuleOperation(parentType, parentName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	if parentType == parentTypeFrontend {
+		return sections.NewHTTPAfterResponseRuleFrontendCreate(parentName, rule, index)
+	}
+	return sections.NewHTTPAfterResponseRuleBackendCreate(parentName, rule, index)
+}
 
-			if !currentRule.Equal(*desiredRule) {
-				operations = append(operations, sections.NewHTTPAfterResponseRuleBackendUpdate(backendName, desiredRule, i))
-			}
-		}
+func (c *Comparator) deleteHTTPAfterResponseRuleOperation(parentType, parentName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	if parentType == parentTypeFrontend {
+		return sections.NewHTTPAfterResponseRuleFrontendDelete(parentName, rule, index)
 	}
+	return sections.NewHTTPAfterResponseRuleBackendDelete(parentName, rule, index)
+}
 
-	return operations
+func (c *Comparator) updateHTTPAfterResponseRuleOperation(parentType, parentName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	if parentType == parentTypeFrontend {
+		return sections.NewHTTPAfterResponseRuleFrontendUpdate(parentName, rule, index)
+	}
+	return sections.NewHTTPAfterResponseRuleBackendUpdate(parentName, rule, index)
 }
 
 // compareBackendSwitchingRules compares backend switching rule configurations within a frontend.
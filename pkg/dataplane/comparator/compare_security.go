@@ -38,16 +38,23 @@ func (c *Comparator) findAddedUserlists(desired, current map[string]*models.User
 	for name, userlist := range desired {
 		if _, exists := current[name]; !exists {
 			operations = append(operations, sections.NewUserlistCreate(userlist))
-			// Explicitly create each user (Dataplane API may not persist users from request body)
-			for _, user := range userlist.Users {
-				userCopy := user
-				operations = append(operations, sections.NewUserCreate(name, &userCopy))
-			}
+			operations = append(operations, userCreates(name, userlist)...)
 		}
 	}
 	return operations
 }
 
+// userCreates returns create operations for every user of a userlist.
+// Users are created explicitly since the Dataplane API may not persist them from the userlist request body.
+func userCreates(userlistName string, userlist *models.Userlist) []Operation {
+	operations := make([]Operation, 0, len(userlist.Users))
+	for _, user := range userlist.Users {
+		userCopy := user
+		operations = append(operations, sections.NewUserCreate(userlistName, &userCopy))
+	}
+	return operations
+}
+
 // findDeletedUserlists identifies userlist sections that need to be removed.
 func findDeletedUserlists(current, desired map[string]*models.Userlist) []Operation {
 	var operations []Operation
@@ -69,10 +76,11 @@ func (c *Comparator) findModifiedUserlists(current, desired map[string]*models.U
 		}
 
 		if userlistMetadataChanged(currentUserlist, desiredUserlist) {
-			// Recreate entire userlist if metadata changed
+			// Userlists have no update endpoint, so recreate the userlist and its users
 			operations = append(operations,
 				sections.NewUserlistDelete(currentUserlist),
 				sections.NewUserlistCreate(desiredUserlist))
+			operations = append(operations, userCreates(name, desiredUserlist)...)
 		} else {
 			// Compare users for fine-grained operations
 			userOps := c.compareUserlistUsers(name, currentUserlist, desiredUserlist)
@@ -82,36 +90,18 @@ func (c *Comparator) findModifiedUserlists(current, desired map[string]*models.U
 	return operations
 }
 
-// userlistMetadataChanged checks if userlist metadata (excluding users and groups) has changed.
+// userlistMetadataChanged checks if any userlist attribute other than its users has changed.
+// Groups are included since users reference them and they have no fine-grained operations.
 func userlistMetadataChanged(current, desired *models.Userlist) bool {
-	// Compare metadata fields (currently only Name is in UserlistBase)
-	// Users and Groups are compared separately for fine-grained operations
-	// For now, we only check if there are other structural changes
-	// If groups are present and different, that requires recreating the userlist
-	if !groupsEqual(current.Groups, desired.Groups) {
-		return true
-	}
-
-	return false
-}
-
-// groupsEqual compares two group maps for equality.
-func groupsEqual(g1, g2 map[string]models.Group) bool {
-	if len(g1) != len(g2) {
-		return false
-	}
+	// Create copies to avoid modifying originals
+	currentCopy := *current
+	desiredCopy := *desired
 
-	for name, group1 := range g1 {
-		group2, exists := g2[name]
-		if !exists {
-			return false
-		}
-		if !group1.Equal(group2) {
-			return false
-		}
-	}
+	// Clear users so they don't affect comparison
+	currentCopy.Users = nil
+	desiredCopy.Users = nil
 
-	return true
+	return !currentCopy.Equal(desiredCopy)
 }
 
 // compareUserlistUsers compares users within a userlist and generates fine-grained user operations.
@@ -0,0 +1,83 @@
+package comparator
+
+import (
+	"strings"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+// normalizeCondition returns the rule condition (cond_test) cond rewritten to
+// a canonical spelling, so that two conditions HAProxy treats as identical
+// compare equal instead of causing a rule update on every sync.
+//
+// Only purely syntactic rewrites outside of anonymous ACLs are applied:
+//   - terms are separated by single spaces
+//   - "||" is spelled "or"
+//   - a standalone "!" is joined with the named ACL it negates ("! is_api" becomes "!is_api")
+//
+// The contents of "{ ... }" are left untouched since they are ACL patterns,
+// and conditions containing quotes or escapes are returned unchanged. Named
+// ACLs are not resolved: "if is_api" and "if { path_beg /api }" remain
+// different conditions even if is_api is defined as "path_beg /api".
+func normalizeCondition(cond string) string {
+	if strings.ContainsAny(cond, "\"'\\") {
+		return cond
+	}
+
+	words := strings.Fields(cond)
+	normalized := make([]string, 0, len(words))
+	depth := 0
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case word == "{":
+			depth++
+		case word == "}" && depth > 0:
+			depth--
+		case depth > 0:
+			// ACL pattern, kept verbatim
+		case word == "||":
+			word = "or"
+		case word == "!" && i+1 < len(words) && isNamedACL(words[i+1]):
+			i++
+			word = "!" + words[i]
+		}
+		normalized = append(normalized, word)
+	}
+
+	return strings.Join(normalized, " ")
+}
+
+// isNamedACL reports whether a condition term refers to a named ACL rather
+// than an operator, another negation or an anonymous ACL.
+func isNamedACL(word string) bool {
+	switch word {
+	case "{", "}", "!", "or", "||":
+		return false
+	}
+	return !strings.HasPrefix(word, "!")
+}
+
+// withNormalizedConditions returns shallow copies of rules whose conditions
+// are normalized with normalizeCondition. condTest selects the condition of a
+// rule. The copies are only used for comparison; operations are built from
+// the original rules.
+func withNormalizedConditions[T any](rules []*T, condTest func(*T) *string) []*T {
+	normalized := make([]*T, len(rules))
+	for i, rule := range rules {
+		clone := *rule
+		if cond := condTest(&clone); *cond != "" {
+			*cond = normalizeCondition(*cond)
+		}
+		normalized[i] = &clone
+	}
+	return normalized
+}
+
+func httpRequestRuleCondTest(rule *models.HTTPRequestRule) *string { return &rule.CondTest }
+
+func httpResponseRuleCondTest(rule *models.HTTPResponseRule) *string { return &rule.CondTest }
+
+func httpAfterResponseRuleCondTest(rule *models.HTTPAfterResponseRule) *string { return &rule.CondTest }
+
+func tcpRequestRuleCondTest(rule *models.TCPRequestRule) *string { return &rule.CondTest }
@@ -0,0 +1,74 @@
+package comparator
+
+import (
+	"fmt"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// DanglingDefaultsError reports a section inheriting from a defaults section
+// that does not exist.
+type DanglingDefaultsError struct {
+	// Section is the type of the referencing section ("frontend", "backend" or "defaults")
+	Section string
+
+	// Name is the name of the referencing section
+	Name string
+
+	// Defaults is the name of the missing defaults section
+	Defaults string
+}
+
+// Error implements the error interface.
+func (e *DanglingDefaultsError) Error() string {
+	return fmt.Sprintf("%s '%s' inherits from defaults section '%s', which does not exist",
+		e.Section, e.Name, e.Defaults)
+}
+
+// ValidateDefaultsReferences checks that every "from" reference of a frontend,
+// backend or defaults section names a defaults section of the same configuration.
+//
+// HAProxy rejects dangling references when loading the configuration, after
+// the sync already sent the sections to the Dataplane API. Checking before
+// comparison reports the section at fault instead. The first dangling
+// reference is returned as *DanglingDefaultsError.
+func ValidateDefaultsReferences(config *parser.StructuredConfig) error {
+	if config == nil {
+		return nil
+	}
+
+	defaults := make(map[string]bool, len(config.Defaults))
+	for _, d := range config.Defaults {
+		if d != nil {
+			defaults[d.Name] = true
+		}
+	}
+
+	check := func(section, name, from string) error {
+		if from == "" || defaults[from] {
+			return nil
+		}
+		return &DanglingDefaultsError{Section: section, Name: name, Defaults: from}
+	}
+
+	for _, d := range config.Defaults {
+		if d == nil {
+			continue
+		}
+		if err := check("defaults", d.Name, d.From); err != nil {
+			return err
+		}
+	}
+	for _, frontend := range config.Frontends {
+		if err := check("frontend", frontend.Name, frontend.From); err != nil {
+			return err
+		}
+	}
+	for _, backend := range config.Backends {
+		if err := check("backend", backend.Name, backend.From); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
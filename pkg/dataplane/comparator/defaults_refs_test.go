@@ -0,0 +1,115 @@
+package comparator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+const defaultsRefsBase = `
+global
+    daemon
+
+defaults base
+    mode http
+    timeout connect 5s
+
+defaults tcp
+    mode tcp
+`
+
+func TestValidateDefaultsReferences(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr *DanglingDefaultsError
+	}{
+		{
+			name: "existing defaults",
+			config: defaultsRefsBase + `
+frontend http from base
+    bind :80
+    default_backend web
+
+backend web from base
+    server srv1 10.0.0.1:80
+`,
+		},
+		{
+			name: "no from reference",
+			config: defaultsRefsBase + `
+backend web
+    server srv1 10.0.0.1:80
+`,
+		},
+		{
+			name: "backend from missing defaults",
+			config: defaultsRefsBase + `
+backend web from missing-defaults
+    server srv1 10.0.0.1:80
+`,
+			wantErr: &DanglingDefaultsError{Section: "backend", Name: "web", Defaults: "missing-defaults"},
+		},
+		{
+			name: "frontend from missing defaults",
+			config: defaultsRefsBase + `
+frontend http from missing-defaults
+    bind :80
+`,
+			wantErr: &DanglingDefaultsError{Section: "frontend", Name: "http", Defaults: "missing-defaults"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, config := parseTestConfigs(t, defaultsRefsBase, tt.config)
+
+			err := ValidateDefaultsReferences(config)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("ValidateDefaultsReferences() = %v, want nil", err)
+				}
+				return
+			}
+
+			var danglingErr *DanglingDefaultsError
+			if !errors.As(err, &danglingErr) {
+				t.Fatalf("ValidateDefaultsReferences() = %v, want *DanglingDefaultsError", err)
+			}
+			if *danglingErr != *tt.wantErr {
+				t.Errorf("ValidateDefaultsReferences() = %+v, want %+v", *danglingErr, *tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompare_BackendFromChange(t *testing.T) {
+	current := defaultsRefsBase + `
+backend web from base
+    server srv1 10.0.0.1:80
+`
+	desired := defaultsRefsBase + `
+backend web from tcp
+    server srv1 10.0.0.1:80
+`
+	currentCfg, desiredCfg := parseTestConfigs(t, current, desired)
+
+	diff, err := New().Compare(currentCfg, desiredCfg)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+	}
+
+	backend, ok := diff.Operations[0].Model().(*models.Backend)
+	if !ok {
+		t.Fatalf("Expected backend model, got %T", diff.Operations[0].Model())
+	}
+	if backend.From != "tcp" {
+		t.Errorf("Expected backend to inherit from 'tcp', got %q", backend.From)
+	}
+}
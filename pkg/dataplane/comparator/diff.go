@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+	"haproxy-template-ic/pkg/dataplane/parser"
 )
 
 // ConfigDiff represents the difference between two HAProxy configurations.
@@ -18,6 +19,10 @@ type ConfigDiff struct {
 
 	// Summary provides a high-level overview of changes
 	Summary DiffSummary
+
+	// Current is the current configuration the operations were computed
+	// against, used to report the changed fields of updates (see FieldDiffs)
+	Current *parser.StructuredConfig
 }
 
 // DiffSummary provides a high-level overview of configuration changes.
@@ -199,12 +204,12 @@ func OrderOperations(ops []Operation) []Operation {
 	}
 
 	// Sort creates by priority (ascending: parents first)
-	sort.Slice(creates, func(i, j int) bool {
+	sort.SliceStable(creates, func(i, j int) bool {
 		return creates[i].Priority() < creates[j].Priority()
 	})
 
 	// Sort deletes by priority (descending: children first)
-	sort.Slice(deletes, func(i, j int) bool {
+	sort.SliceStable(deletes, func(i, j int) bool {
 		return deletes[i].Priority() > deletes[j].Priority()
 	})
 
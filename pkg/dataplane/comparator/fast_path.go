@@ -0,0 +1,120 @@
+package comparator
+
+import (
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// equaler is implemented by the client-native models compared in the fast path.
+type equaler[T any] interface {
+	Equal(t T, opts ...models.Options) bool
+}
+
+// compareServersOnly is the fast path for the most common reconcile: servers
+// added, removed or changed within otherwise unchanged backends.
+//
+// It returns ok=false as soon as any non-server difference is found, in which
+// case the caller must fall back to the full comparison. Sections are matched
+// by name and compared with the models' Equal() methods, which is at least as
+// strict as the full comparison, so the fast path never hides a change the
+// full comparison would have found.
+func (c *Comparator) compareServersOnly(current, desired *parser.StructuredConfig, summary *DiffSummary) (operations []Operation, ok bool) {
+	currentBackends, ok := serverOnlyChanges(current, desired)
+	if !ok {
+		return nil, false
+	}
+
+	for _, desiredBackend := range desired.Backends {
+		serverOps := c.compareServers(desiredBackend.Name, currentBackends[desiredBackend.Name], desiredBackend, summary)
+		if len(serverOps) > 0 {
+			operations = append(operations, serverOps...)
+			summary.BackendsModified = append(summary.BackendsModified, desiredBackend.Name)
+		}
+	}
+
+	return operations, true
+}
+
+// serverOnlyChanges reports whether current and desired differ at most in the
+// servers of their backends. It also returns the current backends by name.
+func serverOnlyChanges(current, desired *parser.StructuredConfig) (map[string]*models.Backend, bool) {
+	if (current.Global == nil) != (desired.Global == nil) {
+		return nil, false
+	}
+	if current.Global != nil && !current.Global.Equal(*desired.Global) {
+		return nil, false
+	}
+
+	if !sectionsEqual(current.Defaults, desired.Defaults, func(d *models.Defaults) string { return d.Name }) ||
+		!sectionsEqual(current.Frontends, desired.Frontends, func(f *models.Frontend) string { return f.Name }) ||
+		!sectionsEqual(current.Peers, desired.Peers, func(p *models.PeerSection) string { return p.Name }) ||
+		!sectionsEqual(current.Resolvers, desired.Resolvers, func(r *models.Resolver) string { return r.Name }) ||
+		!sectionsEqual(current.Mailers, desired.Mailers, func(m *models.MailersSection) string { return m.Name }) ||
+		!sectionsEqual(current.Caches, desired.Caches, cacheName) ||
+		!sectionsEqual(current.Rings, desired.Rings, func(r *models.Ring) string { return r.Name }) ||
+		!sectionsEqual(current.HTTPErrors, desired.HTTPErrors, func(h *models.HTTPErrorsSection) string { return h.Name }) ||
+		!sectionsEqual(current.Userlists, desired.Userlists, func(u *models.Userlist) string { return u.Name }) ||
+		!sectionsEqual(current.Programs, desired.Programs, func(p *models.Program) string { return p.Name }) ||
+		!sectionsEqual(current.LogForwards, desired.LogForwards, func(l *models.LogForward) string { return l.Name }) ||
+		!sectionsEqual(current.FCGIApps, desired.FCGIApps, func(f *models.FCGIApp) string { return f.Name }) ||
+		!sectionsEqual(current.CrtStores, desired.CrtStores, func(s *models.CrtStore) string { return s.Name }) {
+		return nil, false
+	}
+
+	if len(current.Backends) != len(desired.Backends) {
+		return nil, false
+	}
+	currentBackends := make(map[string]*models.Backend, len(current.Backends))
+	for _, backend := range current.Backends {
+		currentBackends[backend.Name] = backend
+	}
+	for _, desiredBackend := range desired.Backends {
+		currentBackend, exists := currentBackends[desiredBackend.Name]
+		if !exists || desiredBackend.Name == "" {
+			return nil, false
+		}
+
+		// Shallow copies share everything but the servers map with the originals
+		currentCopy := *currentBackend
+		desiredCopy := *desiredBackend
+		currentCopy.Servers = nil
+		desiredCopy.Servers = nil
+		if !currentCopy.Equal(desiredCopy) {
+			return nil, false
+		}
+	}
+
+	return currentBackends, true
+}
+
+// sectionsEqual reports whether two section lists contain the same sections,
+// matched by name.
+func sectionsEqual[T equaler[T]](current, desired []*T, name func(*T) string) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	if len(desired) == 0 {
+		return true
+	}
+
+	byName := make(map[string]*T, len(current))
+	for _, section := range current {
+		byName[name(section)] = section
+	}
+	for _, section := range desired {
+		currentSection, exists := byName[name(section)]
+		if !exists || !(*currentSection).Equal(*section) {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheName returns the name of a cache section, which is optional in the model.
+func cacheName(c *models.Cache) string {
+	if c.Name == nil {
+		return ""
+	}
+	return *c.Name
+}
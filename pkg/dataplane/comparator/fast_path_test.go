@@ -0,0 +1,201 @@
+package comparator
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// serverChurnConfig renders a configuration with the given number of backends,
+// each with one server per address in servers, plus a frontend routing to them.
+func serverChurnConfig(backends int, servers []string, extra string) string {
+	var b strings.Builder
+	b.WriteString(`
+global
+    daemon
+
+defaults
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+
+frontend http
+    bind *:80
+    default_backend be0
+`)
+	for i := 0; i < backends; i++ {
+		fmt.Fprintf(&b, "\nbackend be%d\n    balance roundrobin\n    option httpchk GET /healthz\n", i)
+		for j, addr := range servers {
+			fmt.Fprintf(&b, "    server srv%d %s:8080 check\n", j, addr)
+		}
+	}
+	b.WriteString(extra)
+	return b.String()
+}
+
+// fullCompare runs the full comparison, bypassing the server-only fast path.
+func fullCompare(current, desired *parser.StructuredConfig) ([]Operation, DiffSummary) {
+	summary := NewDiffSummary()
+	operations := New().compareAll(current, desired, &summary)
+	return operations, summary
+}
+
+func sortedDescriptions(operations []Operation) []string {
+	descriptions := make([]string, 0, len(operations))
+	for _, op := range operations {
+		descriptions = append(descriptions, fmt.Sprintf("%d %s %s", op.Type(), op.Section(), op.Describe()))
+	}
+	sort.Strings(descriptions)
+	return descriptions
+}
+
+func TestCompare_ServerOnlyFastPathMatchesFullPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		current []string
+		desired []string
+	}{
+		{
+			name:    "server added",
+			current: []string{"10.0.0.1", "10.0.0.2"},
+			desired: []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"},
+		},
+		{
+			name:    "server removed",
+			current: []string{"10.0.0.1", "10.0.0.2"},
+			desired: []string{"10.0.0.1"},
+		},
+		{
+			name:    "server address changed",
+			current: []string{"10.0.0.1", "10.0.0.2"},
+			desired: []string{"10.0.0.1", "10.0.0.9"},
+		},
+		{
+			name:    "no changes",
+			current: []string{"10.0.0.1"},
+			desired: []string{"10.0.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, serverChurnConfig(3, tt.current, ""), serverChurnConfig(3, tt.desired, ""))
+
+			fastSummary := NewDiffSummary()
+			fastOps, ok := New().compareServersOnly(current, desired, &fastSummary)
+			if !ok {
+				t.Fatal("Expected the fast path to handle a server-only change")
+			}
+
+			fullOps, fullSummary := fullCompare(current, desired)
+
+			if got, want := sortedDescriptions(fastOps), sortedDescriptions(fullOps); !reflect.DeepEqual(got, want) {
+				t.Fatalf("Fast path operations differ from full path:\nfast: %v\nfull: %v", got, want)
+			}
+
+			sort.Strings(fastSummary.BackendsModified)
+			sort.Strings(fullSummary.BackendsModified)
+			for _, s := range []*DiffSummary{&fastSummary, &fullSummary} {
+				for _, m := range []map[string][]string{s.ServersAdded, s.ServersModified, s.ServersDeleted} {
+					for backend := range m {
+						sort.Strings(m[backend])
+					}
+				}
+			}
+			if !reflect.DeepEqual(fastSummary, fullSummary) {
+				t.Fatalf("Fast path summary differs from full path:\nfast: %+v\nfull: %+v", fastSummary, fullSummary)
+			}
+		})
+	}
+}
+
+func TestCompare_ServerOnlyFastPathFallsBack(t *testing.T) {
+	servers := []string{"10.0.0.1", "10.0.0.2"}
+	churned := []string{"10.0.0.1", "10.0.0.3"}
+
+	tests := []struct {
+		name    string
+		current string
+		desired string
+	}{
+		{
+			name:    "backend attribute changed",
+			current: serverChurnConfig(2, servers, ""),
+			desired: strings.Replace(serverChurnConfig(2, churned, ""), "balance roundrobin", "balance leastconn", 1),
+		},
+		{
+			name:    "frontend changed",
+			current: serverChurnConfig(2, servers, ""),
+			desired: strings.Replace(serverChurnConfig(2, churned, ""), "default_backend be0", "default_backend be1", 1),
+		},
+		{
+			name:    "backend added",
+			current: serverChurnConfig(2, servers, ""),
+			desired: serverChurnConfig(3, churned, ""),
+		},
+		{
+			name:    "other section added",
+			current: serverChurnConfig(2, servers, ""),
+			desired: serverChurnConfig(2, churned, "\ncache static\n    total-max-size 4\n"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, tt.current, tt.desired)
+
+			summary := NewDiffSummary()
+			if _, ok := New().compareServersOnly(current, desired, &summary); ok {
+				t.Fatal("Expected the fast path to fall back on a non-server change")
+			}
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+			fullOps, _ := fullCompare(current, desired)
+			if got, want := sortedDescriptions(diff.Operations), sortedDescriptions(fullOps); !reflect.DeepEqual(got, want) {
+				t.Fatalf("Compare() operations differ from full path:\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+// BenchmarkCompare_ServerChurn compares the server-only fast path with the full
+// comparison for a typical endpoint update across many backends.
+func BenchmarkCompare_ServerChurn(b *testing.B) {
+	p, err := parser.New()
+	if err != nil {
+		b.Fatalf("Failed to create parser: %v", err)
+	}
+	current, err := p.ParseFromString(serverChurnConfig(200, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, ""))
+	if err != nil {
+		b.Fatalf("Failed to parse current config: %v", err)
+	}
+	desired, err := p.ParseFromString(serverChurnConfig(200, []string{"10.0.0.1", "10.0.0.2", "10.0.0.4"}, ""))
+	if err != nil {
+		b.Fatalf("Failed to parse desired config: %v", err)
+	}
+
+	b.Run("fast path", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := New().Compare(current, desired); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("full path", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			summary := NewDiffSummary()
+			OrderOperations(New().compareAll(current, desired, &summary))
+		}
+	})
+}
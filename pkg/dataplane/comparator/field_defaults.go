@@ -0,0 +1,135 @@
+package comparator
+
+import (
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// fieldDefault is the HAProxy default of an optional field of a section of type T.
+type fieldDefault[T any] struct {
+	// field is the configuration keyword the default belongs to
+	field string
+
+	// normalize makes a field that is unset on one side match the other side
+	// if the other side sets it to the default value.
+	normalize func(current, desired *T)
+
+	// inherited reports whether a defaults section sets the field, in which
+	// case sections inherit that value instead of the HAProxy default (optional).
+	inherited func(*models.Defaults) bool
+}
+
+// serverFieldDefaults are the HAProxy defaults of optional server parameters.
+var serverFieldDefaults = []fieldDefault[models.ServerParams]{
+	int64FieldDefault("weight", 1, func(p *models.ServerParams) **int64 { return &p.Weight }),
+	int64FieldDefault("inter", 2000, func(p *models.ServerParams) **int64 { return &p.Inter }),
+	int64FieldDefault("rise", 2, func(p *models.ServerParams) **int64 { return &p.Rise }),
+	int64FieldDefault("fall", 3, func(p *models.ServerParams) **int64 { return &p.Fall }),
+}
+
+// backendFieldDefaults are the HAProxy defaults of optional backend attributes.
+var backendFieldDefaults = []fieldDefault[models.Backend]{
+	{
+		field:     "balance",
+		normalize: normalizeBalanceDefault,
+		inherited: func(d *models.Defaults) bool { return d.Balance != nil },
+	},
+}
+
+// int64FieldDefault returns the default of an optional integer field.
+func int64FieldDefault[T any](field string, value int64, ptr func(*T) **int64) fieldDefault[T] {
+	return fieldDefault[T]{
+		field: field,
+		normalize: func(current, desired *T) {
+			c, d := ptr(current), ptr(desired)
+			switch {
+			case *c == nil && *d != nil && **d == value:
+				*c = *d
+			case *d == nil && *c != nil && **c == value:
+				*d = *c
+			}
+		},
+	}
+}
+
+// normalizeBalanceDefault treats an absent balance as "balance roundrobin".
+func normalizeBalanceDefault(current, desired *models.Backend) {
+	roundrobin := "roundrobin"
+	isDefault := func(b *models.Balance) bool {
+		return b != nil && b.Equal(models.Balance{Algorithm: &roundrobin})
+	}
+
+	switch {
+	case current.Balance == nil && isDefault(desired.Balance):
+		current.Balance = desired.Balance
+	case desired.Balance == nil && isDefault(current.Balance):
+		desired.Balance = current.Balance
+	}
+}
+
+// normalizeFieldDefaults aligns optional fields that are set to their HAProxy
+// default on one side and unset on the other, so that they compare equal.
+//
+// The Dataplane API writes some defaults explicitly, which would otherwise make
+// a desired configuration that omits them produce an update on every sync.
+// Only sections and servers present on both sides are touched. Fields a
+// defaults section or default-server may set are skipped wherever one does,
+// since the inherited value then replaces the HAProxy default.
+func normalizeFieldDefaults(current, desired *parser.StructuredConfig) {
+	inheritedServer := defaultsSet(current, desired, func(d *models.Defaults) bool { return d.DefaultServer != nil })
+
+	currentBackends := make(map[string]*models.Backend, len(current.Backends))
+	for _, backend := range current.Backends {
+		currentBackends[backend.Name] = backend
+	}
+
+	for _, desiredBackend := range desired.Backends {
+		currentBackend, exists := currentBackends[desiredBackend.Name]
+		if !exists {
+			continue
+		}
+
+		for _, d := range backendFieldDefaults {
+			if d.inherited != nil && defaultsSet(current, desired, d.inherited) {
+				continue
+			}
+			d.normalize(currentBackend, desiredBackend)
+		}
+
+		if inheritedServer || currentBackend.DefaultServer != nil || desiredBackend.DefaultServer != nil {
+			continue
+		}
+		normalizeServerFieldDefaults(currentBackend.Servers, desiredBackend.Servers)
+	}
+}
+
+// normalizeServerFieldDefaults applies serverFieldDefaults to the servers
+// present in both maps.
+func normalizeServerFieldDefaults(currentServers, desiredServers map[string]models.Server) {
+	for name, desiredServer := range desiredServers {
+		currentServer, exists := currentServers[name]
+		if !exists {
+			continue
+		}
+
+		for _, d := range serverFieldDefaults {
+			d.normalize(&currentServer.ServerParams, &desiredServer.ServerParams)
+		}
+		currentServers[name] = currentServer
+		desiredServers[name] = desiredServer
+	}
+}
+
+// defaultsSet reports whether any defaults section of either configuration
+// satisfies set.
+func defaultsSet(current, desired *parser.StructuredConfig, set func(*models.Defaults) bool) bool {
+	for _, config := range []*parser.StructuredConfig{current, desired} {
+		for _, d := range config.Defaults {
+			if d != nil && set(d) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package comparator
+
+import (
+	"testing"
+)
+
+const fieldDefaultsBase = `
+global
+    daemon
+
+defaults
+    mode http
+`
+
+func TestCompare_FieldDefaults(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     string
+		desired     string
+		wantChanges bool
+	}{
+		{
+			name:    "omitted weight matches reported default",
+			current: "backend web\n    server srv1 10.0.0.1:80 weight 1\n",
+			desired: "backend web\n    server srv1 10.0.0.1:80\n",
+		},
+		{
+			name:    "explicit default matches omitted field",
+			current: "backend web\n    server srv1 10.0.0.1:80 check\n",
+			desired: "backend web\n    server srv1 10.0.0.1:80 check inter 2s rise 2 fall 3\n",
+		},
+		{
+			name:    "omitted balance matches roundrobin",
+			current: "backend web\n    balance roundrobin\n",
+			desired: "backend web\n",
+		},
+		{
+			name:        "non-default value is still an update",
+			current:     "backend web\n    server srv1 10.0.0.1:80 weight 10\n",
+			desired:     "backend web\n    server srv1 10.0.0.1:80\n",
+			wantChanges: true,
+		},
+		{
+			name:        "default-server replaces the HAProxy default",
+			current:     "backend web\n    default-server weight 10\n    server srv1 10.0.0.1:80 weight 1\n",
+			desired:     "backend web\n    default-server weight 10\n    server srv1 10.0.0.1:80\n",
+			wantChanges: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, fieldDefaultsBase+tt.current, fieldDefaultsBase+tt.desired)
+
+			diff, err := New().WithFieldDefaults().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if diff.Summary.HasChanges() != tt.wantChanges {
+				logOperations(t, diff.Operations)
+				t.Errorf("Expected changes: %v, got %d operations", tt.wantChanges, len(diff.Operations))
+			}
+		})
+	}
+}
+
+func TestCompare_FieldDefaultsDisabledByDefault(t *testing.T) {
+	current, desired := parseTestConfigs(t,
+		fieldDefaultsBase+"backend web\n    server srv1 10.0.0.1:80 weight 1\n",
+		fieldDefaultsBase+"backend web\n    server srv1 10.0.0.1:80\n")
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 || diff.Operations[0].Section() != "server" {
+		logOperations(t, diff.Operations)
+		t.Fatal("Expected a server update without field defaults")
+	}
+}
@@ -0,0 +1,118 @@
+package comparator
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// FieldDiff is a single field changed by an update operation.
+type FieldDiff struct {
+	// Field is the Dataplane API name of the field, e.g. "weight".
+	// Fields of nested objects are joined with dots, e.g. "stick_table.size".
+	Field string
+
+	// Old is the current value, empty if the field is not set
+	Old string
+
+	// New is the desired value, empty if the field is unset by the update
+	New string
+}
+
+// FieldDiffs returns the fields an update operation changes, compared to the
+// resource in current, sorted by field name.
+//
+// Only backend and server updates are supported. For backends, only the
+// attributes of the section itself are compared; children such as servers
+// and rules have operations of their own. Returns nil for other operations
+// and if the resource does not exist in current.
+func FieldDiffs(op Operation, current *parser.StructuredConfig) []FieldDiff {
+	if op.Type() != sections.OperationUpdate || current == nil {
+		return nil
+	}
+
+	switch desired := op.Model().(type) {
+	case *models.Backend:
+		for _, backend := range current.Backends {
+			if backend.Name == desired.Name {
+				return diffFields(backend.BackendBase, desired.BackendBase)
+			}
+		}
+	case *models.Server:
+		backendName, _, _ := strings.Cut(op.Target(), "/")
+		for _, backend := range current.Backends {
+			if backend.Name != backendName {
+				continue
+			}
+			if server, exists := backend.Servers[desired.Name]; exists {
+				return diffFields(server, *desired)
+			}
+		}
+	}
+
+	return nil
+}
+
+// diffFields compares the JSON representation of two models field by field.
+func diffFields(current, desired any) []FieldDiff {
+	currentFields, desiredFields := flattenFields(current), flattenFields(desired)
+	if currentFields == nil || desiredFields == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(currentFields)+len(desiredFields))
+	for name := range currentFields {
+		names = append(names, name)
+	}
+	for name := range desiredFields {
+		if _, exists := currentFields[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var diffs []FieldDiff
+	for _, name := range names {
+		if currentFields[name] != desiredFields[name] {
+			diffs = append(diffs, FieldDiff{Field: name, Old: currentFields[name], New: desiredFields[name]})
+		}
+	}
+	return diffs
+}
+
+// flattenFields returns the fields set in model, keyed by their JSON name.
+// Returns nil if model cannot be represented as a JSON object.
+func flattenFields(model any) map[string]string {
+	data, err := json.Marshal(model)
+	if err != nil {
+		return nil
+	}
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	var flatten func(prefix string, object map[string]any)
+	flatten = func(prefix string, object map[string]any) {
+		for key, value := range object {
+			switch v := value.(type) {
+			case map[string]any:
+				flatten(prefix+key+".", v)
+			case string:
+				fields[prefix+key] = v
+			default:
+				encoded, _ := json.Marshal(v)
+				fields[prefix+key] = string(encoded)
+			}
+		}
+	}
+	flatten("", object)
+
+	return fields
+}
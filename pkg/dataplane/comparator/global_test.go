@@ -0,0 +1,188 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const globalBase = `
+global
+    daemon
+    maxconn 4096
+    stats socket /var/run/haproxy.sock mode 660 level admin expose-fd listeners
+    tune.bufsize 32768
+    ssl-default-bind-options no-sslv3 no-tlsv10
+    setenv FOO bar
+`
+
+const globalRing = `
+ring logbuf
+    format rfc5424
+    maxlen 1200
+    size 32764
+    server s1 127.0.0.1:514
+`
+
+func TestCompare_GlobalUnchanged(t *testing.T) {
+	config := globalBase + `    log stdout format raw local0 info
+    log ring@logbuf local1 notice
+` + globalRing
+
+	current, desired := parseTestConfigs(t, config, config)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 0 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected no operations for an identical global section, got %d", len(diff.Operations))
+	}
+	if diff.Summary.GlobalChanged {
+		t.Error("Expected GlobalChanged to be false")
+	}
+}
+
+func TestCompare_GlobalLogTargets(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentLogs    string
+		desiredLogs    string
+		expectedType   sections.OperationType
+		expectedDetail string
+	}{
+		{
+			name:           "create log target",
+			currentLogs:    "    log stdout format raw local0 info\n",
+			desiredLogs:    "    log stdout format raw local0 info\n    log ring@logbuf local1 notice\n",
+			expectedType:   sections.OperationCreate,
+			expectedDetail: "Create log target (ring@logbuf) in global section",
+		},
+		{
+			name:           "update log target",
+			currentLogs:    "    log stdout format raw local0 info\n    log ring@logbuf local1 notice\n",
+			desiredLogs:    "    log stdout format raw local0 info\n    log ring@logbuf local1 warning\n",
+			expectedType:   sections.OperationUpdate,
+			expectedDetail: "Update log target (ring@logbuf) in global section",
+		},
+		{
+			name:           "delete log target",
+			currentLogs:    "    log stdout format raw local0 info\n    log ring@logbuf local1 notice\n",
+			desiredLogs:    "    log stdout format raw local0 info\n",
+			expectedType:   sections.OperationDelete,
+			expectedDetail: "Delete log target (ring@logbuf) from global section",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, globalBase+tt.currentLogs+globalRing, globalBase+tt.desiredLogs+globalRing)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			// Only the changed log line is touched, the global section is not replaced
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "log_target" || op.Type() != tt.expectedType {
+				t.Errorf("Expected log_target operation of type %v, got %s", tt.expectedType, op.Describe())
+			}
+			if op.Describe() != tt.expectedDetail {
+				t.Errorf("Expected description %q, got %q", tt.expectedDetail, op.Describe())
+			}
+			if !diff.Summary.GlobalChanged {
+				t.Error("Expected GlobalChanged to be true")
+			}
+		})
+	}
+}
+
+func TestCompare_GlobalSettingsChangeKeepsLogTargets(t *testing.T) {
+	logs := "    log stdout format raw local0 info\n"
+	desiredBase := `
+global
+    daemon
+    maxconn 8192
+    stats socket /var/run/haproxy.sock mode 660 level admin expose-fd listeners
+    tune.bufsize 32768
+    ssl-default-bind-options no-sslv3 no-tlsv10
+    setenv FOO bar
+`
+
+	current, desired := parseTestConfigs(t, globalBase+logs, desiredBase+logs)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+	}
+	if got := diff.Operations[0].Describe(); got != "Update global section" {
+		t.Errorf("Unexpected operation: %s", got)
+	}
+}
+
+func TestCompare_ServerStateFileDirectives(t *testing.T) {
+	current := globalBase + `
+defaults
+    mode http
+
+backend web
+    server s1 127.0.0.1:80
+`
+	desired := globalBase + `    server-state-base /var/lib/haproxy
+    server-state-file state
+
+defaults
+    mode http
+    load-server-state-from-file global
+
+backend web
+    load-server-state-from-file local
+    server-state-file-name web
+    server s1 127.0.0.1:80
+`
+
+	c, d := parseTestConfigs(t, current, desired)
+	diff, err := New().Compare(c, d)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	expected := []string{
+		"Update global section",
+		"Update defaults section 'unnamed_defaults_1'",
+		"Update backend 'web'",
+	}
+	if len(diff.Operations) != len(expected) {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected %d operations, got %d", len(expected), len(diff.Operations))
+	}
+	for i, op := range diff.Operations {
+		if op.Describe() != expected[i] {
+			t.Errorf("Operation %d: expected %q, got %q", i, expected[i], op.Describe())
+		}
+	}
+
+	// Once applied, the directives produce no further changes
+	c, d = parseTestConfigs(t, desired, desired)
+	diff, err = New().Compare(c, d)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+	if len(diff.Operations) != 0 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected no operations, got %d", len(diff.Operations))
+	}
+}
@@ -0,0 +1,105 @@
+package comparator
+
+import (
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const httpErrorsBase = `
+global
+    daemon
+
+http-errors site
+`
+
+func TestCompare_HTTPErrorsErrorFiles(t *testing.T) {
+	current := httpErrorsBase + `    errorfile 400 /etc/haproxy/errors/400.http
+    errorfile 404 /etc/haproxy/errors/404.http
+    errorfile 503 /etc/haproxy/errors/503.http
+`
+
+	tests := []struct {
+		name           string
+		desiredConfig  string
+		expectedDetail string
+	}{
+		{
+			name: "change one error file",
+			desiredConfig: httpErrorsBase + `    errorfile 400 /etc/haproxy/errors/400.http
+    errorfile 404 /etc/haproxy/errors/not-found.http
+    errorfile 503 /etc/haproxy/errors/503.http
+`,
+			expectedDetail: "Update errorfile 404 in http-errors section 'site'",
+		},
+		{
+			name: "add and remove error files",
+			desiredConfig: httpErrorsBase + `    errorfile 400 /etc/haproxy/errors/400.http
+    errorfile 404 /etc/haproxy/errors/404.http
+    errorfile 500 /etc/haproxy/errors/500.http
+`,
+			expectedDetail: "Update errorfile 500, 503 in http-errors section 'site'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			currentCfg, desiredCfg := parseTestConfigs(t, current, tt.desiredConfig)
+
+			diff, err := New().Compare(currentCfg, desiredCfg)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "http_errors" || op.Type() != sections.OperationUpdate {
+				t.Errorf("Expected http_errors update, got %s", op.Describe())
+			}
+			if op.Describe() != tt.expectedDetail {
+				t.Errorf("Expected description %q, got %q", tt.expectedDetail, op.Describe())
+			}
+
+			// The section is replaced as a whole, so the model must carry every error file
+			section, ok := op.Model().(*models.HTTPErrorsSection)
+			if !ok {
+				t.Fatalf("Expected *models.HTTPErrorsSection model, got %T", op.Model())
+			}
+			if len(section.ErrorFiles) != 3 {
+				t.Errorf("Expected 3 error files in update, got %d", len(section.ErrorFiles))
+			}
+			for code, file := range errorFilesByCode(desiredCfg.HTTPErrors[0]) {
+				if errorFilesByCode(section)[code] != file {
+					t.Errorf("Expected errorfile %d to be %q in update", code, file)
+				}
+			}
+		})
+	}
+}
+
+func TestCompare_HTTPErrorsReorderedErrorFiles(t *testing.T) {
+	current := httpErrorsBase + `    errorfile 404 /etc/haproxy/errors/404.http
+    errorfile 503 /etc/haproxy/errors/503.http
+`
+	desired := httpErrorsBase + `    errorfile 503 /etc/haproxy/errors/503.http
+    errorfile 404 /etc/haproxy/errors/404.http
+`
+
+	currentCfg, desiredCfg := parseTestConfigs(t, current, desired)
+
+	diff, err := New().Compare(currentCfg, desiredCfg)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 0 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected no operations for reordered error files, got %d", len(diff.Operations))
+	}
+}
@@ -0,0 +1,129 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const logForwardBase = `
+log-forward syslog
+    backlog 10
+`
+
+func TestCompare_LogForwardDgramBinds(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentConfig  string
+		desiredConfig  string
+		expectedType   sections.OperationType
+		expectedDetail string
+	}{
+		{
+			name:           "create dgram-bind",
+			currentConfig:  logForwardBase,
+			desiredConfig:  logForwardBase + "    dgram-bind 0.0.0.0:514 name udp\n",
+			expectedType:   sections.OperationCreate,
+			expectedDetail: "Create dgram-bind 'udp' in log-forward 'syslog'",
+		},
+		{
+			name:           "update dgram-bind",
+			currentConfig:  logForwardBase + "    dgram-bind 0.0.0.0:514 name udp\n",
+			desiredConfig:  logForwardBase + "    dgram-bind 0.0.0.0:1514 name udp\n",
+			expectedType:   sections.OperationUpdate,
+			expectedDetail: "Update dgram-bind 'udp' in log-forward 'syslog'",
+		},
+		{
+			name:           "delete dgram-bind",
+			currentConfig:  logForwardBase + "    dgram-bind 0.0.0.0:514 name udp\n",
+			desiredConfig:  logForwardBase,
+			expectedType:   sections.OperationDelete,
+			expectedDetail: "Delete dgram-bind 'udp' from log-forward 'syslog'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, tt.currentConfig, tt.desiredConfig)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "dgram_bind" || op.Type() != tt.expectedType {
+				t.Errorf("Expected dgram_bind operation of type %v, got %s", tt.expectedType, op.Describe())
+			}
+			if op.Describe() != tt.expectedDetail {
+				t.Errorf("Expected description %q, got %q", tt.expectedDetail, op.Describe())
+			}
+		})
+	}
+}
+
+func TestCompare_LogForwardUnchangedBindsNotTouched(t *testing.T) {
+	currentConfig := logForwardBase + `    bind 0.0.0.0:601 name tcp
+    dgram-bind 0.0.0.0:514 name udp
+    dgram-bind 0.0.0.0:1514 name udp-alt
+`
+	desiredConfig := logForwardBase + `    bind 0.0.0.0:601 name tcp
+    dgram-bind 0.0.0.0:514 name udp
+    dgram-bind 0.0.0.0:2514 name udp-alt
+`
+
+	current, desired := parseTestConfigs(t, currentConfig, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected only the changed dgram-bind to be updated, got %d operations", len(diff.Operations))
+	}
+
+	if got := diff.Operations[0].Describe(); got != "Update dgram-bind 'udp-alt' in log-forward 'syslog'" {
+		t.Errorf("Unexpected operation: %s", got)
+	}
+}
+
+func TestCompare_NewLogForwardCreatesBindsAfterSection(t *testing.T) {
+	currentConfig := `
+global
+    daemon
+`
+	desiredConfig := currentConfig + logForwardBase + `    bind 0.0.0.0:601 name tcp
+    dgram-bind 0.0.0.0:514 name udp
+`
+
+	current, desired := parseTestConfigs(t, currentConfig, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 3 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected log-forward, bind and dgram-bind creates, got %d operations", len(diff.Operations))
+	}
+
+	sectionIdx := findOperationIndex(diff.Operations, "log_forward")
+	bindIdx := findOperationIndex(diff.Operations, "bind")
+	dgramBindIdx := findOperationIndex(diff.Operations, "dgram_bind")
+	if sectionIdx == -1 || bindIdx == -1 || dgramBindIdx == -1 {
+		logOperations(t, diff.Operations)
+		t.Fatal("Missing expected operations")
+	}
+	if sectionIdx > bindIdx || sectionIdx > dgramBindIdx {
+		logOperations(t, diff.Operations)
+		t.Error("Log-forward section must be created before its binds")
+	}
+}
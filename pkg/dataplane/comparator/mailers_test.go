@@ -0,0 +1,74 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const mailersBase = `
+global
+    daemon
+
+mailers alerts
+    timeout mail 10s
+    mailer smtp1 192.168.1.100:25
+    mailer smtp2 192.168.1.101:25
+`
+
+func TestCompare_MailersTimeoutChangeKeepsEntries(t *testing.T) {
+	desiredConfig := `
+global
+    daemon
+
+mailers alerts
+    timeout mail 20s
+    mailer smtp1 192.168.1.100:25
+    mailer smtp2 192.168.1.101:25
+`
+
+	current, desired := parseTestConfigs(t, mailersBase, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected only a mailers update, got %d operations", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Section() != "mailers" || op.Type() != sections.OperationUpdate {
+		t.Errorf("Expected mailers update, got %s", op.Describe())
+	}
+}
+
+func TestCompare_MailersEntriesCreatedAfterSection(t *testing.T) {
+	current, desired := parseTestConfigs(t, "\nglobal\n    daemon\n", mailersBase)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	ordered := OrderOperations(diff.Operations)
+	if len(ordered) != 3 {
+		logOperations(t, ordered)
+		t.Fatalf("Expected mailers create and 2 mailer entry creates, got %d operations", len(ordered))
+	}
+
+	if ordered[0].Section() != "mailers" {
+		logOperations(t, ordered)
+		t.Fatalf("Expected mailers section to be created first, got %s", ordered[0].Describe())
+	}
+	for _, op := range ordered[1:] {
+		if op.Section() != "mailer_entry" || op.Type() != sections.OperationCreate {
+			t.Errorf("Expected mailer entry create, got %s", op.Describe())
+		}
+		if op.Priority() <= ordered[0].Priority() {
+			t.Errorf("Expected mailer entry priority above mailers (%d), got %d", ordered[0].Priority(), op.Priority())
+		}
+	}
+}
@@ -37,4 +37,11 @@ type Operation interface {
 	// Describe returns a human-readable description of the operation
 	// for logging and debugging.
 	Describe() string
+
+	// Target returns a stable identifier of the affected resource within
+	// its section, e.g. "api/srv1" for server srv1 in backend api.
+	Target() string
+
+	// Model returns the configuration model the operation applies.
+	Model() any
 }
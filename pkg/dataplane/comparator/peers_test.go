@@ -0,0 +1,186 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const peersBase = `
+global
+    daemon
+
+peers mypeers
+    peer haproxy1 10.0.0.1:10000
+`
+
+func TestCompare_PeersTables(t *testing.T) {
+	tests := []struct {
+		name           string
+		currentConfig  string
+		desiredConfig  string
+		expectedType   sections.OperationType
+		expectedDetail string
+	}{
+		{
+			name:           "create table",
+			currentConfig:  peersBase,
+			desiredConfig:  peersBase + "    table sessions type ip size 100k expire 30m\n",
+			expectedType:   sections.OperationCreate,
+			expectedDetail: "Create table 'sessions' in peer section 'mypeers'",
+		},
+		{
+			name:           "update table",
+			currentConfig:  peersBase + "    table sessions type ip size 100k expire 30m\n",
+			desiredConfig:  peersBase + "    table sessions type ip size 200k expire 30m\n",
+			expectedType:   sections.OperationUpdate,
+			expectedDetail: "Update table 'sessions' in peer section 'mypeers'",
+		},
+		{
+			name:           "delete table",
+			currentConfig:  peersBase + "    table sessions type ip size 100k expire 30m\n",
+			desiredConfig:  peersBase,
+			expectedType:   sections.OperationDelete,
+			expectedDetail: "Delete table 'sessions' from peer section 'mypeers'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, tt.currentConfig, tt.desiredConfig)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "table" || op.Type() != tt.expectedType {
+				t.Errorf("Expected table operation of type %v, got %s", tt.expectedType, op.Describe())
+			}
+			if op.Describe() != tt.expectedDetail {
+				t.Errorf("Expected description %q, got %q", tt.expectedDetail, op.Describe())
+			}
+		})
+	}
+}
+
+func TestCompare_PeersTableCreatedBeforeReferencingBackend(t *testing.T) {
+	desiredConfig := peersBase + `    table sessions type ip size 100k expire 30m
+
+backend web
+    http-request track-sc0 src table mypeers/sessions
+`
+
+	current, desired := parseTestConfigs(t, peersBase, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	tableIdx := findOperationIndex(diff.Operations, "table")
+	backendIdx := findOperationIndex(diff.Operations, "backend")
+	if tableIdx == -1 || backendIdx == -1 {
+		logOperations(t, diff.Operations)
+		t.Fatal("Missing expected operations")
+	}
+	if tableIdx > backendIdx {
+		logOperations(t, diff.Operations)
+		t.Error("Table must be created before the backend referencing it")
+	}
+}
+
+const peersBindBase = `
+global
+    daemon
+
+peers mypeers
+    bind 10.0.0.1:10000
+    server haproxy2 10.0.0.2:10000
+`
+
+func TestCompare_PeersBindsAndServers(t *testing.T) {
+	tests := []struct {
+		name            string
+		desiredConfig   string
+		expectedSection string
+		expectedType    sections.OperationType
+	}{
+		{
+			name:            "update bind",
+			desiredConfig:   strings.Replace(peersBindBase, "bind 10.0.0.1:10000", "bind 10.0.0.1:10000 v4v6", 1),
+			expectedSection: "peer_bind",
+			expectedType:    sections.OperationUpdate,
+		},
+		{
+			name:            "create server",
+			desiredConfig:   peersBindBase + "    server haproxy3 10.0.0.3:10000\n",
+			expectedSection: "peer_server",
+			expectedType:    sections.OperationCreate,
+		},
+		{
+			name:            "update server",
+			desiredConfig:   strings.Replace(peersBindBase, "10.0.0.2:10000", "10.0.0.4:10000", 1),
+			expectedSection: "peer_server",
+			expectedType:    sections.OperationUpdate,
+		},
+		{
+			name:            "delete server",
+			desiredConfig:   strings.Replace(peersBindBase, "    server haproxy2 10.0.0.2:10000\n", "", 1),
+			expectedSection: "peer_server",
+			expectedType:    sections.OperationDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, peersBindBase, tt.desiredConfig)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected 1 operation, got %d", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != tt.expectedSection || op.Type() != tt.expectedType {
+				t.Errorf("Expected %s operation of type %v, got %s", tt.expectedSection, tt.expectedType, op.Describe())
+			}
+		})
+	}
+}
+
+func TestCompare_PeersBindsAndServersCreatedAfterSection(t *testing.T) {
+	current, desired := parseTestConfigs(t, "\nglobal\n    daemon\n", peersBindBase)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	ordered := OrderOperations(diff.Operations)
+	if len(ordered) != 3 {
+		logOperations(t, ordered)
+		t.Fatalf("Expected peers, bind and server creates, got %d operations", len(ordered))
+	}
+
+	if ordered[0].Section() != "peers" {
+		t.Fatalf("Expected peers section to be created first, got %s", ordered[0].Describe())
+	}
+	for _, op := range ordered[1:] {
+		if op.Type() != sections.OperationCreate || (op.Section() != "peer_bind" && op.Section() != "peer_server") {
+			t.Errorf("Expected peers bind or server create, got %s", op.Describe())
+		}
+	}
+}
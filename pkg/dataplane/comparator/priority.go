@@ -0,0 +1,241 @@
+package comparator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// defaultSectionPriorities maps each section type to its built-in priority.
+var defaultSectionPriorities = map[string]int{
+	"global":                   sections.PriorityGlobal,
+	"defaults":                 sections.PriorityDefaults,
+	"userlist":                 sections.PriorityUserlist,
+	"crt_store":                sections.PriorityCrtStore,
+	"log_forward":              sections.PriorityLogForward,
+	"fcgi_app":                 sections.PriorityFCGIApp,
+	"program":                  sections.PriorityProgram,
+	"peers":                    sections.PriorityPeer,
+	"ring":                     sections.PriorityRing,
+	"mailers":                  sections.PriorityMailers,
+	"user":                     sections.PriorityUser,
+	"cache":                    sections.PriorityCache,
+	"resolver":                 sections.PriorityResolver,
+	"table":                    sections.PriorityTable,
+	"http_errors":              sections.PriorityHTTPErrors,
+	"frontend":                 sections.PriorityFrontend,
+	"backend":                  sections.PriorityBackend,
+	"bind":                     sections.PriorityBind,
+	"dgram_bind":               sections.PriorityDgramBind,
+	"server":                   sections.PriorityServer,
+	"server_template":          sections.PriorityServer,
+	"mailer_entry":             sections.PriorityMailerEntry,
+	"peer_entry":               sections.PriorityPeerEntry,
+	"nameserver":               sections.PriorityNameserver,
+	"ring_server":              sections.PriorityRingServer,
+	"peer_bind":                sections.PriorityPeerBind,
+	"peer_server":              sections.PriorityPeerServer,
+	"acl":                      sections.PriorityACL,
+	"http_request_rule":        sections.PriorityRule,
+	"http_response_rule":       sections.PriorityRule,
+	"tcp_request_rule":         sections.PriorityRule,
+	"tcp_response_rule":        sections.PriorityRule,
+	"capture":                  sections.PriorityCapture,
+	"stick_rule":               sections.PriorityStickRule,
+	"http_after_response_rule": sections.PriorityHTTPAfterRule,
+	"server_switching_rule":    sections.PriorityServerSwitchingRule,
+	"backend_switching_rule":   sections.PriorityBackendSwitchingRule,
+	"http_check":               sections.PriorityHTTPCheck,
+	"log_target":               sections.PriorityLogTarget,
+	"tcp_check":                sections.PriorityTCPCheck,
+	"filter":                   sections.PriorityFilter,
+}
+
+// OrderProfile selects a predefined table of section priorities, which decides
+// the order sections are created and deleted in.
+type OrderProfile int
+
+const (
+	// OrderProfileRelaxed uses the built-in section priorities (default).
+	// Section types that do not depend on each other share a priority, so
+	// their relative order follows the comparison.
+	OrderProfileRelaxed OrderProfile = iota
+
+	// OrderProfileStrict gives every top-level section type its own priority
+	// and creates each section before the sections that can reference it:
+	// crt-stores, rings and the other shared sections come before defaults,
+	// and backends before the frontends routing to them. Use it with HAProxy
+	// versions that require sections to be declared before they are used.
+	OrderProfileStrict
+)
+
+// String returns the profile name.
+func (p OrderProfile) String() string {
+	switch p {
+	case OrderProfileRelaxed:
+		return "relaxed"
+	case OrderProfileStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("OrderProfile(%d)", int(p))
+	}
+}
+
+// strictSectionPriorities are the priorities OrderProfileStrict changes.
+var strictSectionPriorities = map[string]int{
+	"crt_store":   11,
+	"ring":        12,
+	"log_forward": 13,
+	"userlist":    14,
+	"mailers":     16,
+	"resolver":    17,
+	"cache":       18,
+	"http_errors": 19,
+	"fcgi_app":    20,
+	"program":     21,
+	"defaults":    22,
+	"frontend":    31,
+}
+
+// ProfilePriorities returns the priority overrides that apply profile, with
+// overrides taking precedence over the profile's priorities. It returns nil
+// for OrderProfileRelaxed without overrides, and an error for unknown profiles.
+func ProfilePriorities(profile OrderProfile, overrides map[string]int) (map[string]int, error) {
+	var table map[string]int
+	switch profile {
+	case OrderProfileRelaxed:
+		if len(overrides) == 0 {
+			return nil, nil
+		}
+	case OrderProfileStrict:
+		table = strictSectionPriorities
+	default:
+		return nil, fmt.Errorf("unknown order profile %s", profile)
+	}
+
+	merged := make(map[string]int, len(table)+len(overrides))
+	for section, priority := range table {
+		merged[section] = priority
+	}
+	for section, priority := range overrides {
+		merged[section] = priority
+	}
+	return merged, nil
+}
+
+// ruleDependencies are the sections a rule can only be created after:
+// its parent and the ACLs its conditions refer to.
+var ruleDependencies = []string{"frontend", "backend", "acl"}
+
+// sectionDependencies maps a section type to the section types that must be
+// created before it (and deleted after it). Overrides must preserve these.
+var sectionDependencies = map[string][]string{
+	"frontend":                 {"defaults"},
+	"backend":                  {"defaults"},
+	"user":                     {"userlist"},
+	"table":                    {"peers"},
+	"peer_entry":               {"peers"},
+	"mailer_entry":             {"mailers"},
+	"nameserver":               {"resolver"},
+	"ring_server":              {"ring"},
+	"peer_bind":                {"peers"},
+	"peer_server":              {"peers"},
+	"bind":                     {"frontend", "log_forward"},
+	"dgram_bind":               {"log_forward"},
+	"server":                   {"backend"},
+	"server_template":          {"backend"},
+	"acl":                      {"frontend", "backend"},
+	"filter":                   {"frontend", "backend"},
+	"log_target":               {"frontend", "backend"},
+	"capture":                  {"frontend"},
+	"http_check":               {"backend"},
+	"tcp_check":                {"backend"},
+	"http_request_rule":        ruleDependencies,
+	"http_response_rule":       ruleDependencies,
+	"tcp_request_rule":         ruleDependencies,
+	"tcp_response_rule":        ruleDependencies,
+	"http_after_response_rule": ruleDependencies,
+	"stick_rule":               ruleDependencies,
+	"server_switching_rule":    {"backend", "acl", "server"},
+	"backend_switching_rule":   {"frontend", "acl"},
+}
+
+// ValidatePriorityOverrides checks that priority overrides refer to known
+// section types and keep every section ordered after the sections it depends on.
+func ValidatePriorityOverrides(overrides map[string]int) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	effective := make(map[string]int, len(defaultSectionPriorities))
+	for section, priority := range defaultSectionPriorities {
+		effective[section] = priority
+	}
+
+	var unknown []string
+	for section, priority := range overrides {
+		if _, ok := effective[section]; !ok {
+			unknown = append(unknown, section)
+			continue
+		}
+		effective[section] = priority
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown section types in priority overrides: %s", strings.Join(unknown, ", "))
+	}
+
+	var violations []string
+	for section, deps := range sectionDependencies {
+		for _, dep := range deps {
+			if effective[section] <= effective[dep] {
+				violations = append(violations, fmt.Sprintf("%s (%d) must have a higher priority than %s (%d)",
+					section, effective[section], dep, effective[dep]))
+			}
+		}
+	}
+	if len(violations) > 0 {
+		sort.Strings(violations)
+		return fmt.Errorf("priority overrides violate section dependencies: %s", strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// OrderOperationsWithOverrides orders operations like OrderOperations, using
+// the overridden priority for section types present in overrides.
+//
+// Overrides are validated first; operations of overridden sections report the
+// new priority from Priority() so results and logs reflect the actual order.
+func OrderOperationsWithOverrides(ops []Operation, overrides map[string]int) ([]Operation, error) {
+	if err := ValidatePriorityOverrides(overrides); err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return OrderOperations(ops), nil
+	}
+
+	adjusted := make([]Operation, len(ops))
+	for i, op := range ops {
+		if priority, ok := overrides[op.Section()]; ok && priority != op.Priority() {
+			op = &priorityOverride{Operation: op, priority: priority}
+		}
+		adjusted[i] = op
+	}
+
+	return OrderOperations(adjusted), nil
+}
+
+// priorityOverride wraps an operation to report an overridden priority.
+type priorityOverride struct {
+	Operation
+	priority int
+}
+
+func (o *priorityOverride) Priority() int { return o.priority }
+
+func (o *priorityOverride) ParentSection() string { return sections.ParentSectionOf(o.Operation) }
+
+func (o *priorityOverride) Name() string { return sections.NameOf(o.Operation) }
@@ -0,0 +1,186 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+)
+
+const priorityTestConfig = `
+global
+    daemon
+`
+
+func TestOrderOperationsWithOverrides_ReordersIndependentSections(t *testing.T) {
+	desiredConfig := priorityTestConfig + `
+cache static
+    total-max-size 4
+
+http-errors site
+    errorfile 503 /etc/haproxy/errors/503.http
+`
+
+	current, desired := parseTestConfigs(t, priorityTestConfig, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	cacheIdx := findOperationIndex(diff.Operations, "cache")
+	errorsIdx := findOperationIndex(diff.Operations, "http_errors")
+	if cacheIdx == -1 || errorsIdx == -1 {
+		logOperations(t, diff.Operations)
+		t.Fatal("Missing expected operations")
+	}
+	if cacheIdx > errorsIdx {
+		t.Fatal("Expected cache to be created before http-errors by default")
+	}
+
+	ordered, err := OrderOperationsWithOverrides(diff.Operations, map[string]int{"http_errors": 12})
+	if err != nil {
+		t.Fatalf("OrderOperationsWithOverrides() failed: %v", err)
+	}
+
+	cacheIdx = findOperationIndex(ordered, "cache")
+	errorsIdx = findOperationIndex(ordered, "http_errors")
+	if errorsIdx > cacheIdx {
+		logOperations(t, ordered)
+		t.Error("Expected http-errors to be created before cache with override")
+	}
+	if got := ordered[errorsIdx].Priority(); got != 12 {
+		t.Errorf("Expected overridden priority 12, got %d", got)
+	}
+}
+
+func TestValidatePriorityOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]int
+		wantErr   string
+	}{
+		{
+			name:      "no overrides",
+			overrides: nil,
+		},
+		{
+			name:      "independent sections",
+			overrides: map[string]int{"cache": 26, "ring": 5},
+		},
+		{
+			name:      "server before backend",
+			overrides: map[string]int{"server": 25},
+			wantErr:   "server (25) must have a higher priority than backend (30)",
+		},
+		{
+			name:      "parent moved after children",
+			overrides: map[string]int{"peers": 45},
+			wantErr:   "peer_entry (40) must have a higher priority than peers (45)",
+		},
+		{
+			name:      "unknown section",
+			overrides: map[string]int{"listen": 30},
+			wantErr:   "unknown section types in priority overrides: listen",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePriorityOverrides(tt.overrides)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestOrderProfileStrict_ReordersSections(t *testing.T) {
+	desiredConfig := priorityTestConfig + `
+defaults
+    mode http
+
+http-errors site
+    errorfile 503 /etc/haproxy/errors/503.http
+
+cache static
+    total-max-size 4
+
+frontend http
+    bind *:80
+    default_backend web
+
+backend web
+    server s1 127.0.0.1:8080
+`
+
+	current, desired := parseTestConfigs(t, priorityTestConfig, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	// The built-in priorities create http-errors after defaults
+	if findOperationIndex(diff.Operations, "http_errors") < findOperationIndex(diff.Operations, "defaults") {
+		logOperations(t, diff.Operations)
+		t.Fatal("Expected http-errors to be created after defaults by default")
+	}
+
+	priorities, err := ProfilePriorities(OrderProfileStrict, nil)
+	if err != nil {
+		t.Fatalf("ProfilePriorities() failed: %v", err)
+	}
+	ordered, err := OrderOperationsWithOverrides(diff.Operations, priorities)
+	if err != nil {
+		t.Fatalf("OrderOperationsWithOverrides() failed: %v", err)
+	}
+
+	var sectionOrder []string
+	for _, op := range ordered {
+		sectionOrder = append(sectionOrder, op.Section())
+	}
+	expected := []string{"cache", "http_errors", "defaults", "backend", "frontend", "bind", "server"}
+	if strings.Join(sectionOrder, ",") != strings.Join(expected, ",") {
+		logOperations(t, ordered)
+		t.Fatalf("Expected sections in order %v, got %v", expected, sectionOrder)
+	}
+
+	if got := ordered[findOperationIndex(ordered, "frontend")].Priority(); got != 31 {
+		t.Errorf("Expected frontend priority 31, got %d", got)
+	}
+}
+
+func TestProfilePriorities(t *testing.T) {
+	// The relaxed profile keeps the built-in priorities
+	priorities, err := ProfilePriorities(OrderProfileRelaxed, nil)
+	if err != nil || priorities != nil {
+		t.Fatalf("Expected no overrides for the relaxed profile, got %v, %v", priorities, err)
+	}
+
+	// The strict table is a valid set of overrides
+	priorities, err = ProfilePriorities(OrderProfileStrict, nil)
+	if err != nil {
+		t.Fatalf("ProfilePriorities() failed: %v", err)
+	}
+	if err := ValidatePriorityOverrides(priorities); err != nil {
+		t.Fatalf("Strict profile violates section dependencies: %v", err)
+	}
+
+	// Explicit overrides take precedence over the profile
+	priorities, err = ProfilePriorities(OrderProfileStrict, map[string]int{"cache": 12, "bind": 45})
+	if err != nil {
+		t.Fatalf("ProfilePriorities() failed: %v", err)
+	}
+	if priorities["cache"] != 12 || priorities["bind"] != 45 || priorities["defaults"] != 22 {
+		t.Errorf("Unexpected merged priorities: %v", priorities)
+	}
+
+	if _, err := ProfilePriorities(OrderProfile(99), nil); err == nil || !strings.Contains(err.Error(), "unknown order profile OrderProfile(99)") {
+		t.Errorf("Expected unknown profile error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const resolversBase = `
+resolvers dns
+    nameserver ns1 10.0.0.1:53
+    nameserver ns2 10.0.0.2:53
+`
+
+func TestCompare_ResolverTuningDoesNotChurnNameservers(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		desired string
+	}{
+		{
+			name:    "hold valid",
+			current: "    hold valid 10s\n",
+			desired: "    hold valid 30s\n",
+		},
+		{
+			name:    "accepted_payload_size",
+			current: "    accepted_payload_size 512\n",
+			desired: "    accepted_payload_size 8192\n",
+		},
+		{
+			name:    "timeout resolve",
+			current: "    timeout resolve 1s\n",
+			desired: "    timeout resolve 2s\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, resolversBase+tt.current, resolversBase+tt.desired)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected only a resolver update, got %d operations", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "resolver" || op.Type() != sections.OperationUpdate {
+				t.Errorf("Expected resolver update, got %s", op.Describe())
+			}
+		})
+	}
+}
+
+func TestCompare_ResolverNameserverChangeLeavesSectionAlone(t *testing.T) {
+	desiredConfig := `
+resolvers dns
+    nameserver ns1 10.0.0.1:53
+    nameserver ns2 10.0.0.3:53
+`
+
+	current, desired := parseTestConfigs(t, resolversBase, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected only a nameserver update, got %d operations", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Section() != "nameserver" || op.Type() != sections.OperationUpdate {
+		t.Errorf("Expected nameserver update, got %s", op.Describe())
+	}
+}
@@ -0,0 +1,88 @@
+package comparator
+
+import (
+	"strings"
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const ringsBase = `
+global
+    daemon
+
+ring logbuffer
+    format rfc3164
+    maxlen 1200
+    size 32764
+    timeout connect 5s
+    timeout server 10s
+    server syslog1 192.168.1.10:514
+`
+
+func TestCompare_RingServers(t *testing.T) {
+	tests := []struct {
+		name     string
+		desired  string
+		wantType sections.OperationType
+	}{
+		{
+			name:     "create",
+			desired:  ringsBase + "    server syslog2 192.168.1.11:514\n",
+			wantType: sections.OperationCreate,
+		},
+		{
+			name:     "update",
+			desired:  strings.Replace(ringsBase, "192.168.1.10:514", "192.168.1.12:514", 1),
+			wantType: sections.OperationUpdate,
+		},
+		{
+			name:     "delete",
+			desired:  strings.Replace(ringsBase, "    server syslog1 192.168.1.10:514\n", "", 1),
+			wantType: sections.OperationDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, ringsBase, tt.desired)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected a single ring server operation, got %d operations", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Section() != "ring_server" || op.Type() != tt.wantType {
+				t.Errorf("Expected ring server operation of type %v, got %s", tt.wantType, op.Describe())
+			}
+		})
+	}
+}
+
+func TestCompare_RingServersCreatedAfterRing(t *testing.T) {
+	current, desired := parseTestConfigs(t, "\nglobal\n    daemon\n", ringsBase)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	ordered := OrderOperations(diff.Operations)
+	if len(ordered) != 2 {
+		logOperations(t, ordered)
+		t.Fatalf("Expected ring create and ring server create, got %d operations", len(ordered))
+	}
+
+	if ordered[0].Section() != "ring" {
+		t.Fatalf("Expected ring section to be created first, got %s", ordered[0].Describe())
+	}
+	if ordered[1].Section() != "ring_server" || ordered[1].Type() != sections.OperationCreate {
+		t.Errorf("Expected ring server create, got %s", ordered[1].Describe())
+	}
+}
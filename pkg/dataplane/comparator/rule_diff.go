@@ -0,0 +1,100 @@
+package comparator
+
+import (
+	"fmt"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// ruleEdit is a single step in transforming an ordered rule list into another.
+//
+// Deletes refer to positions in the current list, creates and updates to
+// positions in the desired list.
+type ruleEdit struct {
+	opType       sections.OperationType
+	currentIndex int
+	desiredIndex int
+}
+
+// ruleFingerprints returns a content fingerprint for each rule in a list.
+// Rules with equal fingerprints are interchangeable.
+func ruleFingerprints[T interface{ MarshalBinary() ([]byte, error) }](rules []T) []string {
+	fingerprints := make([]string, len(rules))
+	for i, rule := range rules {
+		data, err := rule.MarshalBinary()
+		if err != nil {
+			// Never match a rule that cannot be fingerprinted
+			fingerprints[i] = fmt.Sprintf("unfingerprintable:%p", any(rule))
+			continue
+		}
+		fingerprints[i] = string(data)
+	}
+	return fingerprints
+}
+
+// diffRuleLists computes the edits turning the current rule list into the
+// desired one, keeping the longest common subsequence of rules in place.
+//
+// Within each gap between kept rules, current and desired rules are paired up
+// as updates; surplus current rules are deleted and surplus desired rules
+// created. The Dataplane API shifts subsequent rules on insert and delete, so
+// edits are returned in the order they must be applied: deletes from the end
+// of the list backwards, then creates from the start forwards, then updates.
+// OrderOperations preserves this order for operations of equal priority.
+func diffRuleLists(current, desired []string) []ruleEdit {
+	// lcs[i][j] is the length of the longest common subsequence of current[i:] and desired[j:]
+	lcs := make([][]int, len(current)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(desired)+1)
+	}
+	for i := len(current) - 1; i >= 0; i-- {
+		for j := len(desired) - 1; j >= 0; j-- {
+			if current[i] == desired[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var deletes, creates, updates []ruleEdit
+	flushGap := func(currentGap, desiredGap []int) {
+		paired := min(len(currentGap), len(desiredGap))
+		for k := 0; k < paired; k++ {
+			updates = append(updates, ruleEdit{opType: sections.OperationUpdate, currentIndex: currentGap[k], desiredIndex: desiredGap[k]})
+		}
+		for _, idx := range currentGap[paired:] {
+			deletes = append(deletes, ruleEdit{opType: sections.OperationDelete, currentIndex: idx})
+		}
+		for _, idx := range desiredGap[paired:] {
+			creates = append(creates, ruleEdit{opType: sections.OperationCreate, desiredIndex: idx})
+		}
+	}
+
+	var currentGap, desiredGap []int
+	i, j := 0, 0
+	for i < len(current) || j < len(desired) {
+		switch {
+		case i < len(current) && j < len(desired) && current[i] == desired[j]:
+			flushGap(currentGap, desiredGap)
+			currentGap, desiredGap = currentGap[:0], desiredGap[:0]
+			i++
+			j++
+		case j == len(desired) || (i < len(current) && lcs[i+1][j] >= lcs[i][j+1]):
+			currentGap = append(currentGap, i)
+			i++
+		default:
+			desiredGap = append(desiredGap, j)
+			j++
+		}
+	}
+	flushGap(currentGap, desiredGap)
+
+	edits := make([]ruleEdit, 0, len(deletes)+len(creates)+len(updates))
+	for k := len(deletes) - 1; k >= 0; k-- {
+		edits = append(edits, deletes[k])
+	}
+	edits = append(edits, creates...)
+	edits = append(edits, updates...)
+	return edits
+}
@@ -0,0 +1,337 @@
+package comparator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// rulesConfig builds a frontend with one http-request rule per header name.
+func rulesConfig(headers ...string) string {
+	var b strings.Builder
+	b.WriteString(`
+global
+    daemon
+
+defaults
+    mode http
+
+frontend http
+    bind :80
+`)
+	for _, header := range headers {
+		fmt.Fprintf(&b, "    http-request set-header %s 1\n", header)
+	}
+	return b.String()
+}
+
+func headerNames(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("X-Rule-%d", i)
+	}
+	return names
+}
+
+func TestCompare_HTTPRequestRuleInsertedInMiddle(t *testing.T) {
+	currentHeaders := headerNames(10)
+	desiredHeaders := append(append(append([]string{}, currentHeaders[:5]...), "X-Inserted"), currentHeaders[5:]...)
+
+	current, desired := parseTestConfigs(t, rulesConfig(currentHeaders...), rulesConfig(desiredHeaders...))
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected a single create, got %d operations", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Type() != sections.OperationCreate || op.Section() != "http_request_rule" {
+		t.Errorf("Expected http-request rule create, got %s", op.Describe())
+	}
+}
+
+func TestCompare_HTTPRequestRuleRemovedFromMiddle(t *testing.T) {
+	currentHeaders := headerNames(10)
+	desiredHeaders := append(append([]string{}, currentHeaders[:3]...), currentHeaders[4:]...)
+
+	current, desired := parseTestConfigs(t, rulesConfig(currentHeaders...), rulesConfig(desiredHeaders...))
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected a single delete, got %d operations", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Type() != sections.OperationDelete || op.Section() != "http_request_rule" {
+		t.Errorf("Expected http-request rule delete, got %s", op.Describe())
+	}
+}
+
+// TestDiffRuleLists applies the computed edits the way the Dataplane API does
+// (inserts and deletes shift subsequent rules) and checks the result.
+func TestDiffRuleLists(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       []string
+		desired       []string
+		expectedEdits int
+	}{
+		{name: "identical", current: []string{"a", "b", "c"}, desired: []string{"a", "b", "c"}, expectedEdits: 0},
+		{name: "append", current: []string{"a", "b"}, desired: []string{"a", "b", "c", "d"}, expectedEdits: 2},
+		{name: "prepend", current: []string{"b", "c"}, desired: []string{"a", "b", "c"}, expectedEdits: 1},
+		{name: "modify", current: []string{"a", "b", "c"}, desired: []string{"a", "x", "c"}, expectedEdits: 1},
+		{name: "remove tail", current: []string{"a", "b", "c", "d"}, desired: []string{"a", "b"}, expectedEdits: 2},
+		{name: "mixed", current: []string{"a", "b", "c", "d", "e"}, desired: []string{"x", "a", "c", "y", "z", "e", "w"}, expectedEdits: 5},
+		{name: "reorder", current: []string{"a", "b", "c"}, desired: []string{"c", "a", "b"}, expectedEdits: 2},
+		{name: "to empty", current: []string{"a", "b"}, desired: nil, expectedEdits: 2},
+		{name: "from empty", current: nil, desired: []string{"a", "b"}, expectedEdits: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := diffRuleLists(tt.current, tt.desired)
+			if len(edits) != tt.expectedEdits {
+				t.Errorf("Expected %d edits, got %d: %+v", tt.expectedEdits, len(edits), edits)
+			}
+
+			list := append([]string{}, tt.current...)
+			for _, edit := range edits {
+				switch edit.opType {
+				case sections.OperationDelete:
+					list = append(list[:edit.currentIndex], list[edit.currentIndex+1:]...)
+				case sections.OperationCreate:
+					list = append(list[:edit.desiredIndex], append([]string{tt.desired[edit.desiredIndex]}, list[edit.desiredIndex:]...)...)
+				case sections.OperationUpdate:
+					list[edit.desiredIndex] = tt.desired[edit.desiredIndex]
+				}
+			}
+
+			if strings.Join(list, ",") != strings.Join(tt.desired, ",") {
+				t.Errorf("Applying edits produced %v, expected %v", list, tt.desired)
+			}
+		})
+	}
+}
+
+func TestCompare_HTTPAfterResponseRulesInFrontend(t *testing.T) {
+	base := rulesConfig()
+
+	tests := []struct {
+		name          string
+		currentConfig string
+		desiredConfig string
+		expectedType  sections.OperationType
+		expectedDesc  string
+	}{
+		{
+			name:          "create",
+			currentConfig: base,
+			desiredConfig: base + "    http-after-response set-header X-Served-By haproxy\n",
+			expectedType:  sections.OperationCreate,
+			expectedDesc:  "Create HTTP after response rule (set-header) in frontend 'http'",
+		},
+		{
+			name:          "update",
+			currentConfig: base + "    http-after-response set-header X-Served-By haproxy\n",
+			desiredConfig: base + "    http-after-response set-header X-Served-By edge\n",
+			expectedType:  sections.OperationUpdate,
+			expectedDesc:  "Update HTTP after response rule (set-header) in frontend 'http'",
+		},
+		{
+			name:          "delete",
+			currentConfig: base + "    http-after-response set-header X-Served-By haproxy\n",
+			desiredConfig: base,
+			expectedType:  sections.OperationDelete,
+			expectedDesc:  "Delete HTTP after response rule (set-header) from frontend 'http'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, tt.currentConfig, tt.desiredConfig)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected only an after-response rule operation, got %d operations", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Type() != tt.expectedType || op.Section() != "http_after_response_rule" {
+				t.Errorf("Expected %v http_after_response_rule, got %s", tt.expectedType, op.Describe())
+			}
+			if got := op.Describe(); got != tt.expectedDesc {
+				t.Errorf("Expected %q, got %q", tt.expectedDesc, got)
+			}
+		})
+	}
+}
+
+func TestCompare_TCPRequestSessionRules(t *testing.T) {
+	base := rulesConfig() + "    tcp-request connection accept if { src 10.0.0.0/8 }\n"
+	session := "    tcp-request session reject if { src 192.168.0.0/16 }\n"
+	content := "    tcp-request content accept if { req.len gt 0 }\n"
+
+	tests := []struct {
+		name          string
+		currentConfig string
+		desiredConfig string
+		expectedType  sections.OperationType
+	}{
+		{
+			name:          "create",
+			currentConfig: base + content,
+			desiredConfig: base + session + content,
+			expectedType:  sections.OperationCreate,
+		},
+		{
+			name:          "update action",
+			currentConfig: base + strings.Replace(session, "reject", "accept", 1) + content,
+			desiredConfig: base + session + content,
+			expectedType:  sections.OperationUpdate,
+		},
+		{
+			name:          "delete",
+			currentConfig: base + session + content,
+			desiredConfig: base + content,
+			expectedType:  sections.OperationDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, desired := parseTestConfigs(t, tt.currentConfig, tt.desiredConfig)
+
+			diff, err := New().Compare(current, desired)
+			if err != nil {
+				t.Fatalf("Compare() failed: %v", err)
+			}
+
+			if len(diff.Operations) != 1 {
+				logOperations(t, diff.Operations)
+				t.Fatalf("Expected a single tcp-request rule operation, got %d operations", len(diff.Operations))
+			}
+
+			op := diff.Operations[0]
+			if op.Type() != tt.expectedType || op.Section() != "tcp_request_rule" {
+				t.Errorf("Expected %v tcp_request_rule, got %s", tt.expectedType, op.Describe())
+			}
+			if op.Type() != sections.OperationDelete {
+				rule, ok := op.Model().(*models.TCPRequestRule)
+				if !ok || rule.Type != models.TCPRequestRuleTypeSession || rule.Action != models.TCPRequestRuleActionReject {
+					t.Errorf("Expected a session reject rule, got %+v", op.Model())
+				}
+			}
+		})
+	}
+
+	t.Run("phases interleaved differently", func(t *testing.T) {
+		current, desired := parseTestConfigs(t, base+session+content, rulesConfig()+content+session+
+			"    tcp-request connection accept if { src 10.0.0.0/8 }\n")
+
+		diff, err := New().Compare(current, desired)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 0 {
+			logOperations(t, diff.Operations)
+			t.Errorf("Expected no operations for rules kept in order within their phase, got %d", len(diff.Operations))
+		}
+	})
+
+	t.Run("order within a phase", func(t *testing.T) {
+		other := "    tcp-request session accept if { src 172.16.0.0/12 }\n"
+		current, desired := parseTestConfigs(t, base+session+other, base+other+session)
+
+		diff, err := New().Compare(current, desired)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) == 0 {
+			t.Error("Expected operations for session rules reordered within their phase")
+		}
+	})
+}
+
+func TestNormalizeCondition(t *testing.T) {
+	tests := []struct {
+		cond string
+		want string
+	}{
+		{"is_api", "is_api"},
+		{"is_api || is_admin", "is_api or is_admin"},
+		{"! is_api", "!is_api"},
+		{"{ path_beg /api } || ! is_internal", "{ path_beg /api } or !is_internal"},
+		// Anonymous ACL patterns are kept verbatim
+		{"{ hdr(x-op) -m str || }", "{ hdr(x-op) -m str || }"},
+		// Negated anonymous ACLs are not rewritten
+		{"! { src 10.0.0.0/8 }", "! { src 10.0.0.0/8 }"},
+		// Quoted conditions are not touched
+		{`{ hdr(x) -m str "a || b" } || ! is_api`, `{ hdr(x) -m str "a || b" } || ! is_api`},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeCondition(tt.cond); got != tt.want {
+			t.Errorf("normalizeCondition(%q) = %q, want %q", tt.cond, got, tt.want)
+		}
+	}
+}
+
+func TestCompare_HTTPRequestRuleConditionNormalization(t *testing.T) {
+	config := func(cond string) string {
+		return `
+global
+    daemon
+
+defaults
+    mode http
+
+frontend http
+    bind :80
+    acl is_internal src 10.0.0.0/8
+    http-request deny if ` + cond + `
+`
+	}
+
+	// The current config holds the spelling the server returns
+	current, desired := parseTestConfigs(t, config("{ path_beg /api } or !is_internal"), config("{ path_beg /api } || ! is_internal"))
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+	if len(diff.Operations) != 0 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected no operations for an equivalent condition, got %d", len(diff.Operations))
+	}
+
+	// A changed ACL pattern is still an update
+	current, desired = parseTestConfigs(t, config("{ path_beg /api } or !is_internal"), config("{ path_beg /admin } || ! is_internal"))
+
+	diff, err = New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+	if len(diff.Operations) != 1 || diff.Operations[0].Type() != sections.OperationUpdate {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected a single update, got %d operations", len(diff.Operations))
+	}
+}
@@ -379,6 +379,127 @@ func PeerEntryDelete(peerSectionName string) func(ctx context.Context, c *client
 	}
 }
 
+// =============================================================================
+// Table Executors (Peer container)
+// =============================================================================
+
+// TableCreate returns an executor for creating stick-tables in peers sections.
+func TableCreate(peerSectionName string) func(ctx context.Context, c *client.DataplaneClient, txID string, containerName string, childName string, model *models.Table) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.Table) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.Table) (*http.Response, error) {
+				params := &v32.CreateTableParams{TransactionId: &txID}
+				return clientset.V32().CreateTable(ctx, peerSectionName, params, m)
+			},
+			func(m v31.Table) (*http.Response, error) {
+				params := &v31.CreateTableParams{TransactionId: &txID}
+				return clientset.V31().CreateTable(ctx, peerSectionName, params, m)
+			},
+			func(m v30.Table) (*http.Response, error) {
+				params := &v30.CreateTableParams{TransactionId: &txID}
+				return clientset.V30().CreateTable(ctx, peerSectionName, params, m)
+			},
+			func(m v32ee.Table) (*http.Response, error) {
+				params := &v32ee.CreateTableParams{TransactionId: &txID}
+				return clientset.V32EE().CreateTable(ctx, peerSectionName, params, m)
+			},
+			func(m v31ee.Table) (*http.Response, error) {
+				params := &v31ee.CreateTableParams{TransactionId: &txID}
+				return clientset.V31EE().CreateTable(ctx, peerSectionName, params, m)
+			},
+			func(m v30ee.Table) (*http.Response, error) {
+				params := &v30ee.CreateTableParams{TransactionId: &txID}
+				return clientset.V30EE().CreateTable(ctx, peerSectionName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "table creation")
+	}
+}
+
+// TableUpdate returns an executor for updating stick-tables in peers sections.
+func TableUpdate(peerSectionName string) func(ctx context.Context, c *client.DataplaneClient, txID string, containerName string, childName string, model *models.Table) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.Table) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.Table) (*http.Response, error) {
+				params := &v32.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V32().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+			func(name string, m v31.Table) (*http.Response, error) {
+				params := &v31.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V31().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+			func(name string, m v30.Table) (*http.Response, error) {
+				params := &v30.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V30().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+			func(name string, m v32ee.Table) (*http.Response, error) {
+				params := &v32ee.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+			func(name string, m v31ee.Table) (*http.Response, error) {
+				params := &v31ee.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+			func(name string, m v30ee.Table) (*http.Response, error) {
+				params := &v30ee.ReplaceTableParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceTable(ctx, peerSectionName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "table update")
+	}
+}
+
+// TableDelete returns an executor for deleting stick-tables from peers sections.
+func TableDelete(peerSectionName string) func(ctx context.Context, c *client.DataplaneClient, txID string, containerName string, childName string, model *models.Table) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.Table) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteTableParams{TransactionId: &txID}
+				return clientset.V32().DeleteTable(ctx, peerSectionName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteTableParams{TransactionId: &txID}
+				return clientset.V31().DeleteTable(ctx, peerSectionName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteTableParams{TransactionId: &txID}
+				return clientset.V30().DeleteTable(ctx, peerSectionName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteTableParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteTable(ctx, peerSectionName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteTableParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteTable(ctx, peerSectionName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteTableParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteTable(ctx, peerSectionName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "table deletion")
+	}
+}
+
 // =============================================================================
 // Nameserver Executors (Resolver container)
 // =============================================================================
@@ -1347,6 +1347,127 @@ func LogTargetBackendDelete() func(ctx context.Context, c *client.DataplaneClien
 	}
 }
 
+// =============================================================================
+// Log Target Executors (Global)
+// =============================================================================
+
+// LogTargetGlobalCreate returns an executor for creating log targets in the global section.
+func LogTargetGlobalCreate() func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, model *models.LogTarget) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, model *models.LogTarget) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreateChild(ctx, c, "", index, model,
+			func(_ string, idx int, m v32.LogTarget) (*http.Response, error) {
+				params := &v32.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v31.LogTarget) (*http.Response, error) {
+				params := &v31.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v30.LogTarget) (*http.Response, error) {
+				params := &v30.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v32ee.LogTarget) (*http.Response, error) {
+				params := &v32ee.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32EE().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v31ee.LogTarget) (*http.Response, error) {
+				params := &v31ee.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31EE().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v30ee.LogTarget) (*http.Response, error) {
+				params := &v30ee.CreateLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30EE().CreateLogTargetGlobal(ctx, idx, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "log target creation in global section")
+	}
+}
+
+// LogTargetGlobalUpdate returns an executor for updating log targets in the global section.
+func LogTargetGlobalUpdate() func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, model *models.LogTarget) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, model *models.LogTarget) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchReplaceChild(ctx, c, "", index, model,
+			func(_ string, idx int, m v32.LogTarget) (*http.Response, error) {
+				params := &v32.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v31.LogTarget) (*http.Response, error) {
+				params := &v31.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v30.LogTarget) (*http.Response, error) {
+				params := &v30.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v32ee.LogTarget) (*http.Response, error) {
+				params := &v32ee.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v31ee.LogTarget) (*http.Response, error) {
+				params := &v31ee.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+			func(_ string, idx int, m v30ee.LogTarget) (*http.Response, error) {
+				params := &v30ee.ReplaceLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceLogTargetGlobal(ctx, idx, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "log target update in global section")
+	}
+}
+
+// LogTargetGlobalDelete returns an executor for deleting log targets from the global section.
+func LogTargetGlobalDelete() func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, _ *models.LogTarget) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, index int, _ *models.LogTarget) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDeleteChild(ctx, c, "", index,
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v32.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v31.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v30.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v32ee.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v31ee.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+			func(_ string, idx int) (*http.Response, error) {
+				params := &v30ee.DeleteLogTargetGlobalParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteLogTargetGlobal(ctx, idx, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "log target deletion from global section")
+	}
+}
+
 // =============================================================================
 // TCP Request Rule Executors (Frontend)
 // =============================================================================
@@ -1832,7 +1953,7 @@ func StickRuleBackendDelete() func(ctx context.Context, c *client.DataplaneClien
 }
 
 // =============================================================================
-// HTTP After Response Rule Executors (Backend only)
+// HTTP After Response Rule Executors
 // =============================================================================
 
 // HTTPAfterResponseRuleBackendCreate returns an executor for creating HTTP after response rules in backends.
@@ -1952,6 +2073,123 @@ func HTTPAfterResponseRuleBackendDelete() func(ctx context.Context, c *client.Da
 	}
 }
 
+// HTTPAfterResponseRuleFrontendCreate returns an executor for creating HTTP after response rules in frontends.
+func HTTPAfterResponseRuleFrontendCreate() func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, model *models.HTTPAfterResponseRule) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, model *models.HTTPAfterResponseRule) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreateChild(ctx, c, parent, index, model,
+			func(p string, idx int, m v32.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v32.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v31.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v31.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v30.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v30.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v32ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v32ee.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32EE().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v31ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v31ee.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31EE().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v30ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v30ee.CreateHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30EE().CreateHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "HTTP after response rule creation in frontend")
+	}
+}
+
+// HTTPAfterResponseRuleFrontendUpdate returns an executor for updating HTTP after response rules in frontends.
+func HTTPAfterResponseRuleFrontendUpdate() func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, model *models.HTTPAfterResponseRule) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, model *models.HTTPAfterResponseRule) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchReplaceChild(ctx, c, parent, index, model,
+			func(p string, idx int, m v32.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v32.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v31.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v31.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v30.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v30.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v32ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v32ee.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v31ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v31ee.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+			func(p string, idx int, m v30ee.HttpAfterResponseRule) (*http.Response, error) {
+				params := &v30ee.ReplaceHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceHTTPAfterResponseRuleFrontend(ctx, p, idx, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "HTTP after response rule update in frontend")
+	}
+}
+
+// HTTPAfterResponseRuleFrontendDelete returns an executor for deleting HTTP after response rules from frontends.
+func HTTPAfterResponseRuleFrontendDelete() func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, _ *models.HTTPAfterResponseRule) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, index int, _ *models.HTTPAfterResponseRule) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDeleteChild(ctx, c, parent, index,
+			func(p string, idx int) (*http.Response, error) {
+				params := &v32.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+			func(p string, idx int) (*http.Response, error) {
+				params := &v31.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+			func(p string, idx int) (*http.Response, error) {
+				params := &v30.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+			func(p string, idx int) (*http.Response, error) {
+				params := &v32ee.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+			func(p string, idx int) (*http.Response, error) {
+				params := &v31ee.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+			func(p string, idx int) (*http.Response, error) {
+				params := &v30ee.DeleteHTTPAfterResponseRuleFrontendParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteHTTPAfterResponseRuleFrontend(ctx, p, idx, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "HTTP after response rule deletion from frontend")
+	}
+}
+
 // =============================================================================
 // Server Switching Rule Executors (Backend only)
 // =============================================================================
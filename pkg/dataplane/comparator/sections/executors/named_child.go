@@ -140,6 +140,248 @@ func BindFrontendDelete(frontendName string) func(ctx context.Context, c *client
 	}
 }
 
+// =============================================================================
+// Bind Executors (LogForward)
+// =============================================================================
+
+// BindLogForwardCreate returns an executor for creating binds in log-forward sections.
+func BindLogForwardCreate(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.Bind) (*http.Response, error) {
+				params := &v32.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v31.Bind) (*http.Response, error) {
+				params := &v31.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v30.Bind) (*http.Response, error) {
+				params := &v30.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v32ee.Bind) (*http.Response, error) {
+				params := &v32ee.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v31ee.Bind) (*http.Response, error) {
+				params := &v31ee.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v30ee.Bind) (*http.Response, error) {
+				params := &v30ee.CreateBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().CreateBindLogForward(ctx, logForwardName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind creation in log-forward")
+	}
+}
+
+// BindLogForwardUpdate returns an executor for updating binds in log-forward sections.
+func BindLogForwardUpdate(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.Bind) (*http.Response, error) {
+				params := &v32.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v31.Bind) (*http.Response, error) {
+				params := &v31.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v30.Bind) (*http.Response, error) {
+				params := &v30.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v32ee.Bind) (*http.Response, error) {
+				params := &v32ee.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v31ee.Bind) (*http.Response, error) {
+				params := &v31ee.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v30ee.Bind) (*http.Response, error) {
+				params := &v30ee.ReplaceBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceBindLogForward(ctx, logForwardName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind update in log-forward")
+	}
+}
+
+// BindLogForwardDelete returns an executor for deleting binds from log-forward sections.
+func BindLogForwardDelete(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteBindLogForward(ctx, logForwardName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind deletion from log-forward")
+	}
+}
+
+// =============================================================================
+// Dgram Bind Executors (LogForward)
+// =============================================================================
+
+// DgramBindLogForwardCreate returns an executor for creating dgram-binds in log-forward sections.
+func DgramBindLogForwardCreate(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.DgramBind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.DgramBind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.DgramBind) (*http.Response, error) {
+				params := &v32.CreateDgramBindParams{TransactionId: &txID}
+				return clientset.V32().CreateDgramBind(ctx, logForwardName, params, m)
+			},
+			func(m v31.DgramBind) (*http.Response, error) {
+				params := &v31.CreateDgramBindParams{TransactionId: &txID}
+				return clientset.V31().CreateDgramBind(ctx, logForwardName, params, m)
+			},
+			func(m v30.DgramBind) (*http.Response, error) {
+				params := &v30.CreateDgramBindParams{TransactionId: &txID}
+				return clientset.V30().CreateDgramBind(ctx, logForwardName, params, m)
+			},
+			func(m v32ee.DgramBind) (*http.Response, error) {
+				params := &v32ee.CreateDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().CreateDgramBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v31ee.DgramBind) (*http.Response, error) {
+				params := &v31ee.CreateDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().CreateDgramBindLogForward(ctx, logForwardName, params, m)
+			},
+			func(m v30ee.DgramBind) (*http.Response, error) {
+				params := &v30ee.CreateDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().CreateDgramBindLogForward(ctx, logForwardName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "dgram-bind creation in log-forward")
+	}
+}
+
+// DgramBindLogForwardUpdate returns an executor for updating dgram-binds in log-forward sections.
+func DgramBindLogForwardUpdate(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.DgramBind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.DgramBind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.DgramBind) (*http.Response, error) {
+				params := &v32.ReplaceDgramBindParams{TransactionId: &txID}
+				return clientset.V32().ReplaceDgramBind(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v31.DgramBind) (*http.Response, error) {
+				params := &v31.ReplaceDgramBindParams{TransactionId: &txID}
+				return clientset.V31().ReplaceDgramBind(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v30.DgramBind) (*http.Response, error) {
+				params := &v30.ReplaceDgramBindParams{TransactionId: &txID}
+				return clientset.V30().ReplaceDgramBind(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v32ee.DgramBind) (*http.Response, error) {
+				params := &v32ee.ReplaceDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceDgramBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v31ee.DgramBind) (*http.Response, error) {
+				params := &v31ee.ReplaceDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceDgramBindLogForward(ctx, logForwardName, name, params, m)
+			},
+			func(name string, m v30ee.DgramBind) (*http.Response, error) {
+				params := &v30ee.ReplaceDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceDgramBindLogForward(ctx, logForwardName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "dgram-bind update in log-forward")
+	}
+}
+
+// DgramBindLogForwardDelete returns an executor for deleting dgram-binds from log-forward sections.
+func DgramBindLogForwardDelete(logForwardName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.DgramBind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.DgramBind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteDgramBindParams{TransactionId: &txID}
+				return clientset.V32().DeleteDgramBind(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteDgramBindParams{TransactionId: &txID}
+				return clientset.V31().DeleteDgramBind(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteDgramBindParams{TransactionId: &txID}
+				return clientset.V30().DeleteDgramBind(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteDgramBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteDgramBindLogForward(ctx, logForwardName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteDgramBindLogForwardParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteDgramBindLogForward(ctx, logForwardName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "dgram-bind deletion from log-forward")
+	}
+}
+
 // =============================================================================
 // Server Template Executors (Backend)
 // =============================================================================
@@ -359,6 +601,7 @@ func serverUpdateWithTransaction(ctx context.Context, c *client.DataplaneClient,
 }
 
 // serverUpdateWithVersion updates a server using version-based update.
+// The Dataplane API applies such changes through the Runtime API when possible.
 func serverUpdateWithVersion(ctx context.Context, c *client.DataplaneClient, backendName, childName string, model *models.Server, version64 int64) error {
 	clientset := c.Clientset()
 
@@ -398,7 +641,7 @@ func serverUpdateWithVersion(ctx context.Context, c *client.DataplaneClient, bac
 		return err
 	}
 	defer resp.Body.Close()
-	return client.CheckResponse(resp, "server update in backend")
+	return client.CheckRuntimeResponse(resp, "server update in backend")
 }
 
 // ServerDelete returns an executor for deleting servers from backends.
@@ -439,3 +682,366 @@ func ServerDelete(backendName string) func(ctx context.Context, c *client.Datapl
 		return client.CheckResponse(resp, "server deletion from backend")
 	}
 }
+
+// =============================================================================
+// Server Executors (Ring)
+// =============================================================================
+
+// RingServerCreate returns an executor for creating servers in ring sections.
+func RingServerCreate(ringName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.Server) (*http.Response, error) {
+				params := &v32.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V32().CreateServerRing(ctx, ringName, params, m)
+			},
+			func(m v31.Server) (*http.Response, error) {
+				params := &v31.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V31().CreateServerRing(ctx, ringName, params, m)
+			},
+			func(m v30.Server) (*http.Response, error) {
+				params := &v30.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V30().CreateServerRing(ctx, ringName, params, m)
+			},
+			func(m v32ee.Server) (*http.Response, error) {
+				params := &v32ee.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V32EE().CreateServerRing(ctx, ringName, params, m)
+			},
+			func(m v31ee.Server) (*http.Response, error) {
+				params := &v31ee.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V31EE().CreateServerRing(ctx, ringName, params, m)
+			},
+			func(m v30ee.Server) (*http.Response, error) {
+				params := &v30ee.CreateServerRingParams{TransactionId: &txID}
+				return clientset.V30EE().CreateServerRing(ctx, ringName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server creation in ring")
+	}
+}
+
+// RingServerUpdate returns an executor for updating servers in ring sections.
+func RingServerUpdate(ringName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.Server) (*http.Response, error) {
+				params := &v32.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V32().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+			func(name string, m v31.Server) (*http.Response, error) {
+				params := &v31.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V31().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+			func(name string, m v30.Server) (*http.Response, error) {
+				params := &v30.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V30().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+			func(name string, m v32ee.Server) (*http.Response, error) {
+				params := &v32ee.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+			func(name string, m v31ee.Server) (*http.Response, error) {
+				params := &v31ee.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+			func(name string, m v30ee.Server) (*http.Response, error) {
+				params := &v30ee.ReplaceServerRingParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceServerRing(ctx, ringName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server update in ring")
+	}
+}
+
+// RingServerDelete returns an executor for deleting servers from ring sections.
+func RingServerDelete(ringName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V32().DeleteServerRing(ctx, ringName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V31().DeleteServerRing(ctx, ringName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V30().DeleteServerRing(ctx, ringName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteServerRing(ctx, ringName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteServerRing(ctx, ringName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteServerRingParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteServerRing(ctx, ringName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server deletion from ring")
+	}
+}
+
+// =============================================================================
+// Bind Executors (Peers)
+// =============================================================================
+
+// PeerBindCreate returns an executor for creating binds in peers sections.
+func PeerBindCreate(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.Bind) (*http.Response, error) {
+				params := &v32.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V32().CreateBindPeer(ctx, peersName, params, m)
+			},
+			func(m v31.Bind) (*http.Response, error) {
+				params := &v31.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V31().CreateBindPeer(ctx, peersName, params, m)
+			},
+			func(m v30.Bind) (*http.Response, error) {
+				params := &v30.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V30().CreateBindPeer(ctx, peersName, params, m)
+			},
+			func(m v32ee.Bind) (*http.Response, error) {
+				params := &v32ee.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V32EE().CreateBindPeer(ctx, peersName, params, m)
+			},
+			func(m v31ee.Bind) (*http.Response, error) {
+				params := &v31ee.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V31EE().CreateBindPeer(ctx, peersName, params, m)
+			},
+			func(m v30ee.Bind) (*http.Response, error) {
+				params := &v30ee.CreateBindPeerParams{TransactionId: &txID}
+				return clientset.V30EE().CreateBindPeer(ctx, peersName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind creation in peers")
+	}
+}
+
+// PeerBindUpdate returns an executor for updating binds in peers sections.
+func PeerBindUpdate(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.Bind) (*http.Response, error) {
+				params := &v32.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V32().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v31.Bind) (*http.Response, error) {
+				params := &v31.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V31().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v30.Bind) (*http.Response, error) {
+				params := &v30.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V30().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v32ee.Bind) (*http.Response, error) {
+				params := &v32ee.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v31ee.Bind) (*http.Response, error) {
+				params := &v31ee.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v30ee.Bind) (*http.Response, error) {
+				params := &v30ee.ReplaceBindPeerParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceBindPeer(ctx, peersName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind update in peers")
+	}
+}
+
+// PeerBindDelete returns an executor for deleting binds from peers sections.
+func PeerBindDelete(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Bind) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.Bind) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V32().DeleteBindPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V31().DeleteBindPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V30().DeleteBindPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteBindPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteBindPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteBindPeerParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteBindPeer(ctx, peersName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "bind deletion from peers")
+	}
+}
+
+// =============================================================================
+// Server Executors (Peers)
+// =============================================================================
+
+// PeerServerCreate returns an executor for creating servers in peers sections.
+func PeerServerCreate(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, _ string, model *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchCreate(ctx, c, model,
+			func(m v32.Server) (*http.Response, error) {
+				params := &v32.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V32().CreateServerPeer(ctx, peersName, params, m)
+			},
+			func(m v31.Server) (*http.Response, error) {
+				params := &v31.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V31().CreateServerPeer(ctx, peersName, params, m)
+			},
+			func(m v30.Server) (*http.Response, error) {
+				params := &v30.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V30().CreateServerPeer(ctx, peersName, params, m)
+			},
+			func(m v32ee.Server) (*http.Response, error) {
+				params := &v32ee.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V32EE().CreateServerPeer(ctx, peersName, params, m)
+			},
+			func(m v31ee.Server) (*http.Response, error) {
+				params := &v31ee.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V31EE().CreateServerPeer(ctx, peersName, params, m)
+			},
+			func(m v30ee.Server) (*http.Response, error) {
+				params := &v30ee.CreateServerPeerParams{TransactionId: &txID}
+				return clientset.V30EE().CreateServerPeer(ctx, peersName, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server creation in peers")
+	}
+}
+
+// PeerServerUpdate returns an executor for updating servers in peers sections.
+func PeerServerUpdate(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, model *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchUpdate(ctx, c, childName, model,
+			func(name string, m v32.Server) (*http.Response, error) {
+				params := &v32.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V32().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v31.Server) (*http.Response, error) {
+				params := &v31.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V31().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v30.Server) (*http.Response, error) {
+				params := &v30.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V30().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v32ee.Server) (*http.Response, error) {
+				params := &v32ee.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V32EE().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v31ee.Server) (*http.Response, error) {
+				params := &v31ee.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V31EE().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+			func(name string, m v30ee.Server) (*http.Response, error) {
+				params := &v30ee.ReplaceServerPeerParams{TransactionId: &txID}
+				return clientset.V30EE().ReplaceServerPeer(ctx, peersName, name, params, m)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server update in peers")
+	}
+}
+
+// PeerServerDelete returns an executor for deleting servers from peers sections.
+func PeerServerDelete(peersName string) func(ctx context.Context, c *client.DataplaneClient, txID string, parent string, childName string, model *models.Server) error {
+	return func(ctx context.Context, c *client.DataplaneClient, txID string, _ string, childName string, _ *models.Server) error {
+		clientset := c.Clientset()
+
+		resp, err := client.DispatchDelete(ctx, c, childName,
+			func(name string) (*http.Response, error) {
+				params := &v32.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V32().DeleteServerPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V31().DeleteServerPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V30().DeleteServerPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v32ee.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V32EE().DeleteServerPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v31ee.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V31EE().DeleteServerPeer(ctx, peersName, name, params)
+			},
+			func(name string) (*http.Response, error) {
+				params := &v30ee.DeleteServerPeerParams{TransactionId: &txID}
+				return clientset.V30EE().DeleteServerPeer(ctx, peersName, name, params)
+			},
+		)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		return client.CheckResponse(resp, "server deletion from peers")
+	}
+}
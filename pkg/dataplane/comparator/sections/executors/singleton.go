@@ -3,6 +3,7 @@ package executors
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/haproxytech/client-native/v6/models"
@@ -33,10 +34,16 @@ func GlobalUpdate() func(ctx context.Context, c *client.DataplaneClient, txID st
 				return clientset.V32().ReplaceGlobal(ctx, params, m)
 			},
 			func(_ string, m v31.Global) (*http.Response, error) {
+				if err := checkGlobalSupportedBeforeV32(model, "v3.1"); err != nil {
+					return nil, err
+				}
 				params := &v31.ReplaceGlobalParams{TransactionId: &txID}
 				return clientset.V31().ReplaceGlobal(ctx, params, m)
 			},
 			func(_ string, m v30.Global) (*http.Response, error) {
+				if err := checkGlobalSupportedBeforeV32(model, "v3.0"); err != nil {
+					return nil, err
+				}
 				params := &v30.ReplaceGlobalParams{TransactionId: &txID}
 				return clientset.V30().ReplaceGlobal(ctx, params, m)
 			},
@@ -45,10 +52,16 @@ func GlobalUpdate() func(ctx context.Context, c *client.DataplaneClient, txID st
 				return clientset.V32EE().ReplaceGlobal(ctx, params, m)
 			},
 			func(_ string, m v31ee.Global) (*http.Response, error) {
+				if err := checkGlobalSupportedBeforeV32(model, "v3.1"); err != nil {
+					return nil, err
+				}
 				params := &v31ee.ReplaceGlobalParams{TransactionId: &txID}
 				return clientset.V31EE().ReplaceGlobal(ctx, params, m)
 			},
 			func(_ string, m v30ee.Global) (*http.Response, error) {
+				if err := checkGlobalSupportedBeforeV32(model, "v3.0"); err != nil {
+					return nil, err
+				}
 				params := &v30ee.ReplaceGlobalParams{TransactionId: &txID}
 				return clientset.V30EE().ReplaceGlobal(ctx, params, m)
 			},
@@ -60,3 +73,19 @@ func GlobalUpdate() func(ctx context.Context, c *client.DataplaneClient, txID st
 		return client.CheckResponse(resp, "global section update")
 	}
 }
+
+// checkGlobalSupportedBeforeV32 rejects global directives that the DataPlane API
+// only models from v3.2 on. Older API versions would silently drop them from
+// the request, resetting HAProxy's CPU binding or QUIC tuning on the next reload.
+func checkGlobalSupportedBeforeV32(model *models.Global, apiVersion string) error {
+	if model.CPUPolicy != "" {
+		return fmt.Errorf("global directive cpu-policy requires DataPlane API v3.2+, got %s", apiVersion)
+	}
+	if len(model.CPUSets) > 0 {
+		return fmt.Errorf("global directive cpu-set requires DataPlane API v3.2+, got %s", apiVersion)
+	}
+	if model.TuneQuicOptions != nil && model.TuneQuicOptions.FrontendMaxTxMemory != nil {
+		return fmt.Errorf("global directive tune.quic.frontend.max-tx-mem requires DataPlane API v3.2+, got %s", apiVersion)
+	}
+	return nil
+}
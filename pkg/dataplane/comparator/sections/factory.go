@@ -7,6 +7,8 @@ package sections
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/haproxytech/client-native/v6/models"
 
@@ -31,6 +33,48 @@ type Operation interface {
 
 	// Describe returns a human-readable description of the operation
 	Describe() string
+
+	// Target returns a stable identifier of the affected resource within its
+	// section, e.g. "api/srv1" for server srv1 in backend api
+	Target() string
+
+	// Model returns the configuration model the operation applies
+	Model() any
+}
+
+// ChildOperation is implemented by operations on resources within a parent
+// section, whose Target is "parent/child".
+type ChildOperation interface {
+	// ParentSection returns the section type of the parent, e.g. "backend"
+	// for a server. Frontends and backends may share a name, so the parent
+	// name alone does not identify the parent section.
+	ParentSection() string
+}
+
+// ParentSectionOf returns the parent section type of a ChildOperation, or ""
+// for other operations.
+func ParentSectionOf(op Operation) string {
+	if child, ok := op.(ChildOperation); ok {
+		return child.ParentSection()
+	}
+	return ""
+}
+
+// NamedOperation is implemented by operations that know the name of the
+// resource they affect within its section.
+type NamedOperation interface {
+	// Name returns the name of the affected resource, e.g. "srv1" for server
+	// srv1 in backend api. Rules have no names and return their index.
+	Name() string
+}
+
+// NameOf returns the resource name of a NamedOperation, or the Target of other
+// operations.
+func NameOf(op Operation) string {
+	if named, ok := op.(NamedOperation); ok {
+		return named.Name()
+	}
+	return op.Target()
 }
 
 // ptrStr safely dereferences a string pointer, returning empty string if nil.
@@ -208,6 +252,7 @@ func NewACLFrontendCreate(frontendName string, acl *models.ACL, index int) Opera
 		OperationCreate,
 		"acl",
 		PriorityACL,
+		"frontend",
 		frontendName,
 		index,
 		acl,
@@ -223,6 +268,7 @@ func NewACLFrontendUpdate(frontendName string, acl *models.ACL, index int) Opera
 		OperationUpdate,
 		"acl",
 		PriorityACL,
+		"frontend",
 		frontendName,
 		index,
 		acl,
@@ -238,6 +284,7 @@ func NewACLFrontendDelete(frontendName string, acl *models.ACL, index int) Opera
 		OperationDelete,
 		"acl",
 		PriorityACL,
+		"frontend",
 		frontendName,
 		index,
 		acl,
@@ -253,6 +300,7 @@ func NewACLBackendCreate(backendName string, acl *models.ACL, index int) Operati
 		OperationCreate,
 		"acl",
 		PriorityACL,
+		"backend",
 		backendName,
 		index,
 		acl,
@@ -268,6 +316,7 @@ func NewACLBackendUpdate(backendName string, acl *models.ACL, index int) Operati
 		OperationUpdate,
 		"acl",
 		PriorityACL,
+		"backend",
 		backendName,
 		index,
 		acl,
@@ -283,6 +332,7 @@ func NewACLBackendDelete(backendName string, acl *models.ACL, index int) Operati
 		OperationDelete,
 		"acl",
 		PriorityACL,
+		"backend",
 		backendName,
 		index,
 		acl,
@@ -322,6 +372,7 @@ func NewHTTPRequestRuleFrontendCreate(frontendName string, rule *models.HTTPRequ
 		OperationCreate,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -337,6 +388,7 @@ func NewHTTPRequestRuleFrontendUpdate(frontendName string, rule *models.HTTPRequ
 		OperationUpdate,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -352,6 +404,7 @@ func NewHTTPRequestRuleFrontendDelete(frontendName string, rule *models.HTTPRequ
 		OperationDelete,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -367,6 +420,7 @@ func NewHTTPRequestRuleBackendCreate(backendName string, rule *models.HTTPReques
 		OperationCreate,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -382,6 +436,7 @@ func NewHTTPRequestRuleBackendUpdate(backendName string, rule *models.HTTPReques
 		OperationUpdate,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -397,6 +452,7 @@ func NewHTTPRequestRuleBackendDelete(backendName string, rule *models.HTTPReques
 		OperationDelete,
 		"http_request_rule",
 		PriorityRule, // HTTP request rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -436,6 +492,7 @@ func NewHTTPResponseRuleFrontendCreate(frontendName string, rule *models.HTTPRes
 		OperationCreate,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -451,6 +508,7 @@ func NewHTTPResponseRuleFrontendUpdate(frontendName string, rule *models.HTTPRes
 		OperationUpdate,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -466,6 +524,7 @@ func NewHTTPResponseRuleFrontendDelete(frontendName string, rule *models.HTTPRes
 		OperationDelete,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -481,6 +540,7 @@ func NewHTTPResponseRuleBackendCreate(backendName string, rule *models.HTTPRespo
 		OperationCreate,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -496,6 +556,7 @@ func NewHTTPResponseRuleBackendUpdate(backendName string, rule *models.HTTPRespo
 		OperationUpdate,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -511,6 +572,7 @@ func NewHTTPResponseRuleBackendDelete(backendName string, rule *models.HTTPRespo
 		OperationDelete,
 		"http_response_rule",
 		PriorityRule, // HTTP response rules use PriorityRule
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -550,6 +612,7 @@ func NewBackendSwitchingRuleFrontendCreate(frontendName string, rule *models.Bac
 		OperationCreate,
 		"backend_switching_rule",
 		PriorityBackendSwitchingRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -565,6 +628,7 @@ func NewBackendSwitchingRuleFrontendUpdate(frontendName string, rule *models.Bac
 		OperationUpdate,
 		"backend_switching_rule",
 		PriorityBackendSwitchingRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -580,6 +644,7 @@ func NewBackendSwitchingRuleFrontendDelete(frontendName string, rule *models.Bac
 		OperationDelete,
 		"backend_switching_rule",
 		PriorityBackendSwitchingRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -599,6 +664,7 @@ func NewFilterFrontendCreate(frontendName string, filter *models.Filter, index i
 		OperationCreate,
 		"filter",
 		PriorityFilter,
+		"frontend",
 		frontendName,
 		index,
 		filter,
@@ -614,6 +680,7 @@ func NewFilterFrontendUpdate(frontendName string, filter *models.Filter, index i
 		OperationUpdate,
 		"filter",
 		PriorityFilter,
+		"frontend",
 		frontendName,
 		index,
 		filter,
@@ -629,6 +696,7 @@ func NewFilterFrontendDelete(frontendName string, filter *models.Filter, index i
 		OperationDelete,
 		"filter",
 		PriorityFilter,
+		"frontend",
 		frontendName,
 		index,
 		filter,
@@ -644,6 +712,7 @@ func NewFilterBackendCreate(backendName string, filter *models.Filter, index int
 		OperationCreate,
 		"filter",
 		PriorityFilter,
+		"backend",
 		backendName,
 		index,
 		filter,
@@ -659,6 +728,7 @@ func NewFilterBackendUpdate(backendName string, filter *models.Filter, index int
 		OperationUpdate,
 		"filter",
 		PriorityFilter,
+		"backend",
 		backendName,
 		index,
 		filter,
@@ -674,6 +744,7 @@ func NewFilterBackendDelete(backendName string, filter *models.Filter, index int
 		OperationDelete,
 		"filter",
 		PriorityFilter,
+		"backend",
 		backendName,
 		index,
 		filter,
@@ -693,6 +764,7 @@ func NewLogTargetFrontendCreate(frontendName string, logTarget *models.LogTarget
 		OperationCreate,
 		"log_target",
 		PriorityLogTarget,
+		"frontend",
 		frontendName,
 		index,
 		logTarget,
@@ -708,6 +780,7 @@ func NewLogTargetFrontendUpdate(frontendName string, logTarget *models.LogTarget
 		OperationUpdate,
 		"log_target",
 		PriorityLogTarget,
+		"frontend",
 		frontendName,
 		index,
 		logTarget,
@@ -723,6 +796,7 @@ func NewLogTargetFrontendDelete(frontendName string, logTarget *models.LogTarget
 		OperationDelete,
 		"log_target",
 		PriorityLogTarget,
+		"frontend",
 		frontendName,
 		index,
 		logTarget,
@@ -738,6 +812,7 @@ func NewLogTargetBackendCreate(backendName string, logTarget *models.LogTarget,
 		OperationCreate,
 		"log_target",
 		PriorityLogTarget,
+		"backend",
 		backendName,
 		index,
 		logTarget,
@@ -753,6 +828,7 @@ func NewLogTargetBackendUpdate(backendName string, logTarget *models.LogTarget,
 		OperationUpdate,
 		"log_target",
 		PriorityLogTarget,
+		"backend",
 		backendName,
 		index,
 		logTarget,
@@ -768,6 +844,7 @@ func NewLogTargetBackendDelete(backendName string, logTarget *models.LogTarget,
 		OperationDelete,
 		"log_target",
 		PriorityLogTarget,
+		"backend",
 		backendName,
 		index,
 		logTarget,
@@ -777,6 +854,54 @@ func NewLogTargetBackendDelete(backendName string, logTarget *models.LogTarget,
 	)
 }
 
+// NewLogTargetGlobalCreate creates an operation to create a log target in the global section.
+func NewLogTargetGlobalCreate(logTarget *models.LogTarget, index int) Operation {
+	return NewIndexChildOp(
+		OperationCreate,
+		"log_target",
+		PriorityLogTarget,
+		"global",
+		"global",
+		index,
+		logTarget,
+		IdentityLogTarget,
+		executors.LogTargetGlobalCreate(),
+		func() string { return describeLogTarget(OperationCreate, logTarget, "global", "", index) },
+	)
+}
+
+// NewLogTargetGlobalUpdate creates an operation to update a log target in the global section.
+func NewLogTargetGlobalUpdate(logTarget *models.LogTarget, index int) Operation {
+	return NewIndexChildOp(
+		OperationUpdate,
+		"log_target",
+		PriorityLogTarget,
+		"global",
+		"global",
+		index,
+		logTarget,
+		IdentityLogTarget,
+		executors.LogTargetGlobalUpdate(),
+		func() string { return describeLogTarget(OperationUpdate, logTarget, "global", "", index) },
+	)
+}
+
+// NewLogTargetGlobalDelete creates an operation to delete a log target from the global section.
+func NewLogTargetGlobalDelete(logTarget *models.LogTarget, index int) Operation {
+	return NewIndexChildOp(
+		OperationDelete,
+		"log_target",
+		PriorityLogTarget,
+		"global",
+		"global",
+		index,
+		logTarget,
+		NilLogTarget,
+		executors.LogTargetGlobalDelete(),
+		func() string { return describeLogTarget(OperationDelete, logTarget, "global", "", index) },
+	)
+}
+
 // =============================================================================
 // Bind Factory Functions (Name-based child)
 // =============================================================================
@@ -827,16 +952,22 @@ func describeLogTarget(opType OperationType, logTarget *models.LogTarget, parent
 		identifier = fmt.Sprintf("(%s)", identifier)
 	}
 
+	// The global section is a singleton without a name
+	parent := fmt.Sprintf("%s '%s'", parentType, parentName)
+	if parentName == "" {
+		parent = parentType + " section"
+	}
+
 	// Use appropriate verb based on operation type
 	switch opType {
 	case OperationCreate:
-		return fmt.Sprintf("Create log target %s in %s '%s'", identifier, parentType, parentName)
+		return fmt.Sprintf("Create log target %s in %s", identifier, parent)
 	case OperationUpdate:
-		return fmt.Sprintf("Update log target %s in %s '%s'", identifier, parentType, parentName)
+		return fmt.Sprintf("Update log target %s in %s", identifier, parent)
 	case OperationDelete:
-		return fmt.Sprintf("Delete log target %s from %s '%s'", identifier, parentType, parentName)
+		return fmt.Sprintf("Delete log target %s from %s", identifier, parent)
 	default:
-		return fmt.Sprintf("Unknown operation on log target %s in %s '%s'", identifier, parentType, parentName)
+		return fmt.Sprintf("Unknown operation on log target %s in %s", identifier, parent)
 	}
 }
 
@@ -1001,7 +1132,7 @@ func describeServerSwitchingRule(opType OperationType, rule *models.ServerSwitch
 }
 
 // describeHTTPAfterResponseRule generates a human-readable description for an HTTP after response rule operation.
-func describeHTTPAfterResponseRule(opType OperationType, rule *models.HTTPAfterResponseRule, backendName string, index int) string {
+func describeHTTPAfterResponseRule(opType OperationType, rule *models.HTTPAfterResponseRule, parentType, parentName string, index int) string {
 	identifier := rule.Type
 	if identifier == "" {
 		identifier = fmt.Sprintf("at index %d", index)
@@ -1010,13 +1141,13 @@ func describeHTTPAfterResponseRule(opType OperationType, rule *models.HTTPAfterR
 	}
 	switch opType {
 	case OperationCreate:
-		return fmt.Sprintf("Create HTTP after response rule %s in backend '%s'", identifier, backendName)
+		return fmt.Sprintf("Create HTTP after response rule %s in %s '%s'", identifier, parentType, parentName)
 	case OperationUpdate:
-		return fmt.Sprintf("Update HTTP after response rule %s in backend '%s'", identifier, backendName)
+		return fmt.Sprintf("Update HTTP after response rule %s in %s '%s'", identifier, parentType, parentName)
 	case OperationDelete:
-		return fmt.Sprintf("Delete HTTP after response rule %s from backend '%s'", identifier, backendName)
+		return fmt.Sprintf("Delete HTTP after response rule %s from %s '%s'", identifier, parentType, parentName)
 	default:
-		return fmt.Sprintf("Unknown operation on HTTP after response rule %s in backend '%s'", identifier, backendName)
+		return fmt.Sprintf("Unknown operation on HTTP after response rule %s in %s '%s'", identifier, parentType, parentName)
 	}
 }
 
@@ -1026,6 +1157,7 @@ func NewBindFrontendCreate(frontendName, bindName string, bind *models.Bind) Ope
 		OperationCreate,
 		"bind",
 		PriorityBind,
+		"frontend",
 		frontendName,
 		bindName,
 		bind,
@@ -1041,6 +1173,7 @@ func NewBindFrontendUpdate(frontendName, bindName string, bind *models.Bind) Ope
 		OperationUpdate,
 		"bind",
 		PriorityBind,
+		"frontend",
 		frontendName,
 		bindName,
 		bind,
@@ -1056,6 +1189,7 @@ func NewBindFrontendDelete(frontendName, bindName string, bind *models.Bind) Ope
 		OperationDelete,
 		"bind",
 		PriorityBind,
+		"frontend",
 		frontendName,
 		bindName,
 		bind,
@@ -1075,6 +1209,7 @@ func NewUserCreate(userlistName string, user *models.User) Operation {
 		OperationCreate,
 		"user",
 		PriorityUser,
+		"userlist",
 		userlistName,
 		user,
 		IdentityUser,
@@ -1090,6 +1225,7 @@ func NewUserUpdate(userlistName string, user *models.User) Operation {
 		OperationUpdate,
 		"user",
 		PriorityUser,
+		"userlist",
 		userlistName,
 		user,
 		IdentityUser,
@@ -1105,6 +1241,7 @@ func NewUserDelete(userlistName string, user *models.User) Operation {
 		OperationDelete,
 		"user",
 		PriorityUser,
+		"userlist",
 		userlistName,
 		user,
 		NilUser,
@@ -1123,7 +1260,8 @@ func NewMailerEntryCreate(mailersName string, entry *models.MailerEntry) Operati
 	return NewContainerChildOp(
 		OperationCreate,
 		"mailer_entry",
-		PriorityMailers,
+		PriorityMailerEntry,
+		"mailers",
 		mailersName,
 		entry,
 		IdentityMailerEntry,
@@ -1138,7 +1276,8 @@ func NewMailerEntryUpdate(mailersName string, entry *models.MailerEntry) Operati
 	return NewContainerChildOp(
 		OperationUpdate,
 		"mailer_entry",
-		PriorityMailers,
+		PriorityMailerEntry,
+		"mailers",
 		mailersName,
 		entry,
 		IdentityMailerEntry,
@@ -1153,7 +1292,8 @@ func NewMailerEntryDelete(mailersName string, entry *models.MailerEntry) Operati
 	return NewContainerChildOp(
 		OperationDelete,
 		"mailer_entry",
-		PriorityMailers,
+		PriorityMailerEntry,
+		"mailers",
 		mailersName,
 		entry,
 		NilMailerEntry,
@@ -1173,6 +1313,7 @@ func NewPeerEntryCreate(peerSectionName string, entry *models.PeerEntry) Operati
 		OperationCreate,
 		"peer_entry",
 		PriorityPeerEntry,
+		"peers",
 		peerSectionName,
 		entry,
 		IdentityPeerEntry,
@@ -1188,6 +1329,7 @@ func NewPeerEntryUpdate(peerSectionName string, entry *models.PeerEntry) Operati
 		OperationUpdate,
 		"peer_entry",
 		PriorityPeerEntry,
+		"peers",
 		peerSectionName,
 		entry,
 		IdentityPeerEntry,
@@ -1203,6 +1345,7 @@ func NewPeerEntryDelete(peerSectionName string, entry *models.PeerEntry) Operati
 		OperationDelete,
 		"peer_entry",
 		PriorityPeerEntry,
+		"peers",
 		peerSectionName,
 		entry,
 		NilPeerEntry,
@@ -1212,6 +1355,158 @@ func NewPeerEntryDelete(peerSectionName string, entry *models.PeerEntry) Operati
 	)
 }
 
+// =============================================================================
+// Peers Bind/Server Factory Functions (Named child)
+// =============================================================================
+
+// NewPeerBindCreate creates an operation to create a bind in a peers section.
+func NewPeerBindCreate(peersName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationCreate,
+		"peer_bind",
+		PriorityPeerBind,
+		"peers",
+		peersName,
+		bind.Name,
+		bind,
+		IdentityBind,
+		executors.PeerBindCreate(peersName),
+		DescribeNamedChild(OperationCreate, "bind", bind.Name, "peers", peersName),
+	)
+}
+
+// NewPeerBindUpdate creates an operation to update a bind in a peers section.
+func NewPeerBindUpdate(peersName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationUpdate,
+		"peer_bind",
+		PriorityPeerBind,
+		"peers",
+		peersName,
+		bind.Name,
+		bind,
+		IdentityBind,
+		executors.PeerBindUpdate(peersName),
+		DescribeNamedChild(OperationUpdate, "bind", bind.Name, "peers", peersName),
+	)
+}
+
+// NewPeerBindDelete creates an operation to delete a bind from a peers section.
+func NewPeerBindDelete(peersName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationDelete,
+		"peer_bind",
+		PriorityPeerBind,
+		"peers",
+		peersName,
+		bind.Name,
+		bind,
+		NilBind,
+		executors.PeerBindDelete(peersName),
+		DescribeNamedChild(OperationDelete, "bind", bind.Name, "peers", peersName),
+	)
+}
+
+// NewPeerServerCreate creates an operation to create a server in a peers section.
+func NewPeerServerCreate(peersName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationCreate,
+		"peer_server",
+		PriorityPeerServer,
+		"peers",
+		peersName,
+		server.Name,
+		server,
+		IdentityServer,
+		executors.PeerServerCreate(peersName),
+		DescribeNamedChild(OperationCreate, "server", server.Name, "peers", peersName),
+	)
+}
+
+// NewPeerServerUpdate creates an operation to update a server in a peers section.
+func NewPeerServerUpdate(peersName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationUpdate,
+		"peer_server",
+		PriorityPeerServer,
+		"peers",
+		peersName,
+		server.Name,
+		server,
+		IdentityServer,
+		executors.PeerServerUpdate(peersName),
+		DescribeNamedChild(OperationUpdate, "server", server.Name, "peers", peersName),
+	)
+}
+
+// NewPeerServerDelete creates an operation to delete a server from a peers section.
+func NewPeerServerDelete(peersName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationDelete,
+		"peer_server",
+		PriorityPeerServer,
+		"peers",
+		peersName,
+		server.Name,
+		server,
+		NilServer,
+		executors.PeerServerDelete(peersName),
+		DescribeNamedChild(OperationDelete, "server", server.Name, "peers", peersName),
+	)
+}
+
+// =============================================================================
+// Table Factory Functions (Container child)
+// =============================================================================
+
+// NewTableCreate creates an operation to create a stick-table in a peers section.
+func NewTableCreate(peerSectionName string, table *models.Table) Operation {
+	return NewContainerChildOp(
+		OperationCreate,
+		"table",
+		PriorityTable,
+		"peers",
+		peerSectionName,
+		table,
+		IdentityTable,
+		TableName,
+		executors.TableCreate(peerSectionName),
+		DescribeContainerChild(OperationCreate, "table", table.Name, "peer section", peerSectionName),
+	)
+}
+
+// NewTableUpdate creates an operation to update a stick-table in a peers section.
+func NewTableUpdate(peerSectionName string, table *models.Table) Operation {
+	return NewContainerChildOp(
+		OperationUpdate,
+		"table",
+		PriorityTable,
+		"peers",
+		peerSectionName,
+		table,
+		IdentityTable,
+		TableName,
+		executors.TableUpdate(peerSectionName),
+		DescribeContainerChild(OperationUpdate, "table", table.Name, "peer section", peerSectionName),
+	)
+}
+
+// NewTableDelete creates an operation to delete a stick-table in a peers section.
+func NewTableDelete(peerSectionName string, table *models.Table) Operation {
+	return NewContainerChildOp(
+		OperationDelete,
+		"table",
+		PriorityTable,
+		"peers",
+		peerSectionName,
+		table,
+		NilTable,
+		TableName,
+		executors.TableDelete(peerSectionName),
+		DescribeContainerChild(OperationDelete, "table", table.Name, "peer section", peerSectionName),
+	)
+}
+
 // =============================================================================
 // Nameserver Factory Functions (Container child)
 // =============================================================================
@@ -1222,6 +1517,7 @@ func NewNameserverCreate(resolverName string, nameserver *models.Nameserver) Ope
 		OperationCreate,
 		"nameserver",
 		PriorityResolver,
+		"resolver",
 		resolverName,
 		nameserver,
 		IdentityNameserver,
@@ -1237,6 +1533,7 @@ func NewNameserverUpdate(resolverName string, nameserver *models.Nameserver) Ope
 		OperationUpdate,
 		"nameserver",
 		PriorityResolver,
+		"resolver",
 		resolverName,
 		nameserver,
 		IdentityNameserver,
@@ -1252,6 +1549,7 @@ func NewNameserverDelete(resolverName string, nameserver *models.Nameserver) Ope
 		OperationDelete,
 		"nameserver",
 		PriorityResolver,
+		"resolver",
 		resolverName,
 		nameserver,
 		NilNameserver,
@@ -1271,6 +1569,7 @@ func NewServerCreate(backendName string, server *models.Server) Operation {
 		OperationCreate,
 		"server",
 		PriorityServer,
+		"backend",
 		backendName,
 		server.Name,
 		server,
@@ -1286,6 +1585,7 @@ func NewServerUpdate(backendName string, server *models.Server) Operation {
 		OperationUpdate,
 		"server",
 		PriorityServer,
+		"backend",
 		backendName,
 		server.Name,
 		server,
@@ -1301,6 +1601,7 @@ func NewServerDelete(backendName string, server *models.Server) Operation {
 		OperationDelete,
 		"server",
 		PriorityServer,
+		"backend",
 		backendName,
 		server.Name,
 		server,
@@ -1320,6 +1621,7 @@ func NewServerTemplateCreate(backendName string, serverTemplate *models.ServerTe
 		OperationCreate,
 		"server_template",
 		PriorityServer, // Server templates use same priority as servers
+		"backend",
 		backendName,
 		serverTemplate.Prefix,
 		serverTemplate,
@@ -1335,6 +1637,7 @@ func NewServerTemplateUpdate(backendName string, serverTemplate *models.ServerTe
 		OperationUpdate,
 		"server_template",
 		PriorityServer, // Server templates use same priority as servers
+		"backend",
 		backendName,
 		serverTemplate.Prefix,
 		serverTemplate,
@@ -1350,6 +1653,7 @@ func NewServerTemplateDelete(backendName string, serverTemplate *models.ServerTe
 		OperationDelete,
 		"server_template",
 		PriorityServer, // Server templates use same priority as servers
+		"backend",
 		backendName,
 		serverTemplate.Prefix,
 		serverTemplate,
@@ -1437,6 +1741,32 @@ func NewHTTPErrorsSectionUpdate(section *models.HTTPErrorsSection) Operation {
 	)
 }
 
+// NewHTTPErrorsSectionErrorFilesUpdate creates an operation to update the error
+// files of the given status codes in an http-errors section.
+//
+// The Dataplane API only replaces http-errors sections as a whole, so the
+// operation carries the complete section including unchanged error files;
+// only its description is limited to the changed codes.
+func NewHTTPErrorsSectionErrorFilesUpdate(section *models.HTTPErrorsSection, codes []int64) Operation {
+	codeList := make([]string, len(codes))
+	for i, code := range codes {
+		codeList[i] = strconv.FormatInt(code, 10)
+	}
+
+	return NewTopLevelOp(
+		OperationUpdate,
+		"http_errors",
+		PriorityHTTPErrors,
+		section,
+		IdentityHTTPErrorsSection,
+		HTTPErrorsSectionName,
+		executors.HTTPErrorsSectionUpdate(),
+		func() string {
+			return fmt.Sprintf("Update errorfile %s in http-errors section '%s'", strings.Join(codeList, ", "), section.Name)
+		},
+	)
+}
+
 // NewHTTPErrorsSectionDelete creates an operation to delete an http-errors section.
 func NewHTTPErrorsSectionDelete(section *models.HTTPErrorsSection) Operation {
 	return NewTopLevelOp(
@@ -1497,6 +1827,102 @@ func NewLogForwardDelete(logForward *models.LogForward) Operation {
 	)
 }
 
+// NewBindLogForwardCreate creates an operation to create a bind in a log-forward section.
+func NewBindLogForwardCreate(logForwardName, bindName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationCreate,
+		"bind",
+		PriorityBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		IdentityBind,
+		executors.BindLogForwardCreate(logForwardName),
+		DescribeNamedChild(OperationCreate, "bind", bindName, "log-forward", logForwardName),
+	)
+}
+
+// NewBindLogForwardUpdate creates an operation to update a bind in a log-forward section.
+func NewBindLogForwardUpdate(logForwardName, bindName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationUpdate,
+		"bind",
+		PriorityBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		IdentityBind,
+		executors.BindLogForwardUpdate(logForwardName),
+		DescribeNamedChild(OperationUpdate, "bind", bindName, "log-forward", logForwardName),
+	)
+}
+
+// NewBindLogForwardDelete creates an operation to delete a bind from a log-forward section.
+func NewBindLogForwardDelete(logForwardName, bindName string, bind *models.Bind) Operation {
+	return NewNameChildOp(
+		OperationDelete,
+		"bind",
+		PriorityBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		NilBind,
+		executors.BindLogForwardDelete(logForwardName),
+		DescribeNamedChild(OperationDelete, "bind", bindName, "log-forward", logForwardName),
+	)
+}
+
+// NewDgramBindLogForwardCreate creates an operation to create a dgram-bind in a log-forward section.
+func NewDgramBindLogForwardCreate(logForwardName, bindName string, bind *models.DgramBind) Operation {
+	return NewNameChildOp(
+		OperationCreate,
+		"dgram_bind",
+		PriorityDgramBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		IdentityDgramBind,
+		executors.DgramBindLogForwardCreate(logForwardName),
+		DescribeNamedChild(OperationCreate, "dgram-bind", bindName, "log-forward", logForwardName),
+	)
+}
+
+// NewDgramBindLogForwardUpdate creates an operation to update a dgram-bind in a log-forward section.
+func NewDgramBindLogForwardUpdate(logForwardName, bindName string, bind *models.DgramBind) Operation {
+	return NewNameChildOp(
+		OperationUpdate,
+		"dgram_bind",
+		PriorityDgramBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		IdentityDgramBind,
+		executors.DgramBindLogForwardUpdate(logForwardName),
+		DescribeNamedChild(OperationUpdate, "dgram-bind", bindName, "log-forward", logForwardName),
+	)
+}
+
+// NewDgramBindLogForwardDelete creates an operation to delete a dgram-bind from a log-forward section.
+func NewDgramBindLogForwardDelete(logForwardName, bindName string, bind *models.DgramBind) Operation {
+	return NewNameChildOp(
+		OperationDelete,
+		"dgram_bind",
+		PriorityDgramBind,
+		"log_forward",
+		logForwardName,
+		bindName,
+		bind,
+		NilDgramBind,
+		executors.DgramBindLogForwardDelete(logForwardName),
+		DescribeNamedChild(OperationDelete, "dgram-bind", bindName, "log-forward", logForwardName),
+	)
+}
+
 // =============================================================================
 // MailersSection Factory Functions
 // =============================================================================
@@ -1730,6 +2156,54 @@ func NewRingDelete(ring *models.Ring) Operation {
 	)
 }
 
+// NewRingServerCreate creates an operation to create a server in a ring section.
+func NewRingServerCreate(ringName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationCreate,
+		"ring_server",
+		PriorityRingServer,
+		"ring",
+		ringName,
+		server.Name,
+		server,
+		IdentityServer,
+		executors.RingServerCreate(ringName),
+		DescribeNamedChild(OperationCreate, "server", server.Name, "ring", ringName),
+	)
+}
+
+// NewRingServerUpdate creates an operation to update a server in a ring section.
+func NewRingServerUpdate(ringName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationUpdate,
+		"ring_server",
+		PriorityRingServer,
+		"ring",
+		ringName,
+		server.Name,
+		server,
+		IdentityServer,
+		executors.RingServerUpdate(ringName),
+		DescribeNamedChild(OperationUpdate, "server", server.Name, "ring", ringName),
+	)
+}
+
+// NewRingServerDelete creates an operation to delete a server from a ring section.
+func NewRingServerDelete(ringName string, server *models.Server) Operation {
+	return NewNameChildOp(
+		OperationDelete,
+		"ring_server",
+		PriorityRingServer,
+		"ring",
+		ringName,
+		server.Name,
+		server,
+		NilServer,
+		executors.RingServerDelete(ringName),
+		DescribeNamedChild(OperationDelete, "server", server.Name, "ring", ringName),
+	)
+}
+
 // =============================================================================
 // CrtStore Factory Functions
 // =============================================================================
@@ -1864,6 +2338,7 @@ func NewTCPRequestRuleFrontendCreate(frontendName string, rule *models.TCPReques
 		OperationCreate,
 		"tcp_request_rule",
 		PriorityRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -1879,6 +2354,7 @@ func NewTCPRequestRuleFrontendUpdate(frontendName string, rule *models.TCPReques
 		OperationUpdate,
 		"tcp_request_rule",
 		PriorityRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -1894,6 +2370,7 @@ func NewTCPRequestRuleFrontendDelete(frontendName string, rule *models.TCPReques
 		OperationDelete,
 		"tcp_request_rule",
 		PriorityRule,
+		"frontend",
 		frontendName,
 		index,
 		rule,
@@ -1909,6 +2386,7 @@ func NewTCPRequestRuleBackendCreate(backendName string, rule *models.TCPRequestR
 		OperationCreate,
 		"tcp_request_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -1924,6 +2402,7 @@ func NewTCPRequestRuleBackendUpdate(backendName string, rule *models.TCPRequestR
 		OperationUpdate,
 		"tcp_request_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -1939,6 +2418,7 @@ func NewTCPRequestRuleBackendDelete(backendName string, rule *models.TCPRequestR
 		OperationDelete,
 		"tcp_request_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -1958,6 +2438,7 @@ func NewTCPResponseRuleBackendCreate(backendName string, rule *models.TCPRespons
 		OperationCreate,
 		"tcp_response_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -1973,6 +2454,7 @@ func NewTCPResponseRuleBackendUpdate(backendName string, rule *models.TCPRespons
 		OperationUpdate,
 		"tcp_response_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -1988,6 +2470,7 @@ func NewTCPResponseRuleBackendDelete(backendName string, rule *models.TCPRespons
 		OperationDelete,
 		"tcp_response_rule",
 		PriorityRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2007,6 +2490,7 @@ func NewStickRuleBackendCreate(backendName string, rule *models.StickRule, index
 		OperationCreate,
 		"stick_rule",
 		PriorityStickRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2022,6 +2506,7 @@ func NewStickRuleBackendUpdate(backendName string, rule *models.StickRule, index
 		OperationUpdate,
 		"stick_rule",
 		PriorityStickRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2037,6 +2522,7 @@ func NewStickRuleBackendDelete(backendName string, rule *models.StickRule, index
 		OperationDelete,
 		"stick_rule",
 		PriorityStickRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2047,21 +2533,78 @@ func NewStickRuleBackendDelete(backendName string, rule *models.StickRule, index
 }
 
 // =============================================================================
-// HTTP After Response Rule Factory Functions (Index-based child, Backend only)
+// HTTP After Response Rule Factory Functions (Index-based child)
 // =============================================================================
 
+// NewHTTPAfterResponseRuleFrontendCreate creates an operation to create an HTTP after-response rule in a frontend.
+func NewHTTPAfterResponseRuleFrontendCreate(frontendName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	return NewIndexChildOp(
+		OperationCreate,
+		"http_after_response_rule",
+		PriorityHTTPAfterRule,
+		"frontend",
+		frontendName,
+		index,
+		rule,
+		IdentityHTTPAfterResponseRule,
+		executors.HTTPAfterResponseRuleFrontendCreate(),
+		func() string {
+			return describeHTTPAfterResponseRule(OperationCreate, rule, "frontend", frontendName, index)
+		},
+	)
+}
+
+// NewHTTPAfterResponseRuleFrontendUpdate creates an operation to update an HTTP after-response rule in a frontend.
+func NewHTTPAfterResponseRuleFrontendUpdate(frontendName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	return NewIndexChildOp(
+		OperationUpdate,
+		"http_after_response_rule",
+		PriorityHTTPAfterRule,
+		"frontend",
+		frontendName,
+		index,
+		rule,
+		IdentityHTTPAfterResponseRule,
+		executors.HTTPAfterResponseRuleFrontendUpdate(),
+		func() string {
+			return describeHTTPAfterResponseRule(OperationUpdate, rule, "frontend", frontendName, index)
+		},
+	)
+}
+
+// NewHTTPAfterResponseRuleFrontendDelete creates an operation to delete an HTTP after-response rule from a frontend.
+func NewHTTPAfterResponseRuleFrontendDelete(frontendName string, rule *models.HTTPAfterResponseRule, index int) Operation {
+	return NewIndexChildOp(
+		OperationDelete,
+		"http_after_response_rule",
+		PriorityHTTPAfterRule,
+		"frontend",
+		frontendName,
+		index,
+		rule,
+		NilHTTPAfterResponseRule,
+		executors.HTTPAfterResponseRuleFrontendDelete(),
+		func() string {
+			return describeHTTPAfterResponseRule(OperationDelete, rule, "frontend", frontendName, index)
+		},
+	)
+}
+
 // NewHTTPAfterResponseRuleBackendCreate creates an operation to create an HTTP after-response rule in a backend.
 func NewHTTPAfterResponseRuleBackendCreate(backendName string, rule *models.HTTPAfterResponseRule, index int) Operation {
 	return NewIndexChildOp(
 		OperationCreate,
 		"http_after_response_rule",
 		PriorityHTTPAfterRule,
+		"backend",
 		backendName,
 		index,
 		rule,
 		IdentityHTTPAfterResponseRule,
 		executors.HTTPAfterResponseRuleBackendCreate(),
-		func() string { return describeHTTPAfterResponseRule(OperationCreate, rule, backendName, index) },
+		func() string {
+			return describeHTTPAfterResponseRule(OperationCreate, rule, "backend", backendName, index)
+		},
 	)
 }
 
@@ -2071,12 +2614,15 @@ func NewHTTPAfterResponseRuleBackendUpdate(backendName string, rule *models.HTTP
 		OperationUpdate,
 		"http_after_response_rule",
 		PriorityHTTPAfterRule,
+		"backend",
 		backendName,
 		index,
 		rule,
 		IdentityHTTPAfterResponseRule,
 		executors.HTTPAfterResponseRuleBackendUpdate(),
-		func() string { return describeHTTPAfterResponseRule(OperationUpdate, rule, backendName, index) },
+		func() string {
+			return describeHTTPAfterResponseRule(OperationUpdate, rule, "backend", backendName, index)
+		},
 	)
 }
 
@@ -2086,12 +2632,15 @@ func NewHTTPAfterResponseRuleBackendDelete(backendName string, rule *models.HTTP
 		OperationDelete,
 		"http_after_response_rule",
 		PriorityHTTPAfterRule,
+		"backend",
 		backendName,
 		index,
 		rule,
 		NilHTTPAfterResponseRule,
 		executors.HTTPAfterResponseRuleBackendDelete(),
-		func() string { return describeHTTPAfterResponseRule(OperationDelete, rule, backendName, index) },
+		func() string {
+			return describeHTTPAfterResponseRule(OperationDelete, rule, "backend", backendName, index)
+		},
 	)
 }
 
@@ -2105,6 +2654,7 @@ func NewServerSwitchingRuleBackendCreate(backendName string, rule *models.Server
 		OperationCreate,
 		"server_switching_rule",
 		PriorityServerSwitchingRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2120,6 +2670,7 @@ func NewServerSwitchingRuleBackendUpdate(backendName string, rule *models.Server
 		OperationUpdate,
 		"server_switching_rule",
 		PriorityServerSwitchingRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2135,6 +2686,7 @@ func NewServerSwitchingRuleBackendDelete(backendName string, rule *models.Server
 		OperationDelete,
 		"server_switching_rule",
 		PriorityServerSwitchingRule,
+		"backend",
 		backendName,
 		index,
 		rule,
@@ -2154,6 +2706,7 @@ func NewHTTPCheckBackendCreate(backendName string, check *models.HTTPCheck, inde
 		OperationCreate,
 		"http_check",
 		PriorityHTTPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2169,6 +2722,7 @@ func NewHTTPCheckBackendUpdate(backendName string, check *models.HTTPCheck, inde
 		OperationUpdate,
 		"http_check",
 		PriorityHTTPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2184,6 +2738,7 @@ func NewHTTPCheckBackendDelete(backendName string, check *models.HTTPCheck, inde
 		OperationDelete,
 		"http_check",
 		PriorityHTTPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2203,6 +2758,7 @@ func NewTCPCheckBackendCreate(backendName string, check *models.TCPCheck, index
 		OperationCreate,
 		"tcp_check",
 		PriorityTCPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2218,6 +2774,7 @@ func NewTCPCheckBackendUpdate(backendName string, check *models.TCPCheck, index
 		OperationUpdate,
 		"tcp_check",
 		PriorityTCPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2233,6 +2790,7 @@ func NewTCPCheckBackendDelete(backendName string, check *models.TCPCheck, index
 		OperationDelete,
 		"tcp_check",
 		PriorityTCPCheck,
+		"backend",
 		backendName,
 		index,
 		check,
@@ -2252,6 +2810,7 @@ func NewCaptureFrontendCreate(frontendName string, capture *models.Capture, inde
 		OperationCreate,
 		"capture",
 		PriorityCapture,
+		"frontend",
 		frontendName,
 		index,
 		capture,
@@ -2267,6 +2826,7 @@ func NewCaptureFrontendUpdate(frontendName string, capture *models.Capture, inde
 		OperationUpdate,
 		"capture",
 		PriorityCapture,
+		"frontend",
 		frontendName,
 		index,
 		capture,
@@ -2282,6 +2842,7 @@ func NewCaptureFrontendDelete(frontendName string, capture *models.Capture, inde
 		OperationDelete,
 		"capture",
 		PriorityCapture,
+		"frontend",
 		frontendName,
 		index,
 		capture,
@@ -337,10 +337,19 @@ func TestServerFactoryFunctions(t *testing.T) {
 			assert.Equal(t, "server", op.Section())
 			assert.Equal(t, PriorityServer, op.Priority())
 			assert.Contains(t, op.Describe(), tt.wantDescContains)
+			assert.Equal(t, "backend", ParentSectionOf(op))
 		})
 	}
 }
 
+func TestParentSectionOf(t *testing.T) {
+	acl := &models.ACL{ACLName: "is_api"}
+
+	assert.Equal(t, "frontend", ParentSectionOf(NewACLFrontendCreate("web", acl, 0)))
+	assert.Equal(t, "backend", ParentSectionOf(NewACLBackendCreate("web", acl, 0)))
+	assert.Equal(t, "", ParentSectionOf(NewBackendCreate(&models.Backend{BackendBase: models.BackendBase{Name: "web"}})))
+}
+
 func TestBindFactoryFunctions(t *testing.T) {
 	bind := &models.Bind{BindParams: models.BindParams{Name: "http-bind"}}
 
@@ -155,6 +155,9 @@ func MailerEntryName(m *models.MailerEntry) string { return m.Name }
 // PeerEntryName extracts the name from a PeerEntry model.
 func PeerEntryName(p *models.PeerEntry) string { return p.Name }
 
+// TableName extracts the name from a Table model.
+func TableName(t *models.Table) string { return t.Name }
+
 // NameserverName extracts the name from a Nameserver model.
 func NameserverName(n *models.Nameserver) string { return n.Name }
 
@@ -210,6 +213,9 @@ func NilACL(_ *models.ACL) *models.ACL { return nil }
 // NilBind returns nil, used for delete operations where model isn't needed.
 func NilBind(_ *models.Bind) *models.Bind { return nil }
 
+// NilDgramBind returns nil, used for delete operations where model isn't needed.
+func NilDgramBind(_ *models.DgramBind) *models.DgramBind { return nil }
+
 // NilServer returns nil, used for delete operations where model isn't needed.
 func NilServer(_ *models.Server) *models.Server { return nil }
 
@@ -228,6 +234,9 @@ func NilMailerEntry(_ *models.MailerEntry) *models.MailerEntry { return nil }
 // NilPeerEntry returns nil, used for delete operations where model isn't needed.
 func NilPeerEntry(_ *models.PeerEntry) *models.PeerEntry { return nil }
 
+// NilTable returns nil, used for delete operations where model isn't needed.
+func NilTable(_ *models.Table) *models.Table { return nil }
+
 // NilNameserver returns nil, used for delete operations where model isn't needed.
 func NilNameserver(_ *models.Nameserver) *models.Nameserver { return nil }
 
@@ -326,6 +335,9 @@ func IdentityACL(a *models.ACL) *models.ACL { return a }
 // IdentityBind returns the model as-is.
 func IdentityBind(b *models.Bind) *models.Bind { return b }
 
+// IdentityDgramBind returns the model as-is.
+func IdentityDgramBind(b *models.DgramBind) *models.DgramBind { return b }
+
 // IdentityServer returns the model as-is.
 func IdentityServer(s *models.Server) *models.Server { return s }
 
@@ -344,6 +356,9 @@ func IdentityMailerEntry(m *models.MailerEntry) *models.MailerEntry { return m }
 // IdentityPeerEntry returns the model as-is.
 func IdentityPeerEntry(p *models.PeerEntry) *models.PeerEntry { return p }
 
+// IdentityTable returns the model as-is.
+func IdentityTable(t *models.Table) *models.Table { return t }
+
 // IdentityNameserver returns the model as-is.
 func IdentityNameserver(n *models.Nameserver) *models.Nameserver { return n }
 
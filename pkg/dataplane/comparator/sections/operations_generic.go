@@ -8,6 +8,7 @@ package sections
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"haproxy-template-ic/pkg/dataplane/client"
 )
@@ -42,19 +43,25 @@ const (
 	PriorityCache    = 15
 	PriorityResolver = 15
 
-	// Priority 20-25 - HTTP errors and other mid-level.
+	// Priority 20-25 - Peers tables, HTTP errors and other mid-level.
+	// Tables must exist before backends that reference them via stick-table peers.
+	PriorityTable      = 20
 	PriorityHTTPErrors = 25
 
 	// Priority 30 - Frontend/Backend sections.
 	PriorityFrontend = 30
 	PriorityBackend  = 30
 
-	// Priority 40 - Direct children of frontends/backends.
+	// Priority 40 - Direct children of frontends/backends/log-forwards.
 	PriorityBind        = 40
+	PriorityDgramBind   = 40
 	PriorityServer      = 40
 	PriorityMailerEntry = 40
 	PriorityPeerEntry   = 40
 	PriorityNameserver  = 40
+	PriorityRingServer  = 40
+	PriorityPeerBind    = 40
+	PriorityPeerServer  = 40
 
 	// Priority 50 - ACLs.
 	PriorityACL = 50
@@ -164,6 +171,9 @@ func (op *TopLevelOp[TModel, TAPI]) Type() OperationType { return op.opType }
 func (op *TopLevelOp[TModel, TAPI]) Section() string     { return op.sectionName }
 func (op *TopLevelOp[TModel, TAPI]) Priority() int       { return op.priorityVal }
 func (op *TopLevelOp[TModel, TAPI]) Describe() string    { return op.describeFn() }
+func (op *TopLevelOp[TModel, TAPI]) Target() string      { return op.nameFn(op.model) }
+func (op *TopLevelOp[TModel, TAPI]) Name() string        { return op.nameFn(op.model) }
+func (op *TopLevelOp[TModel, TAPI]) Model() any          { return op.model }
 
 func (op *TopLevelOp[TModel, TAPI]) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
 	name := op.nameFn(op.model)
@@ -190,6 +200,7 @@ type IndexChildOp[TModel any, TAPI any] struct {
 	opType      OperationType
 	sectionName string
 	priorityVal int
+	parentType  string
 	parentName  string
 	index       int
 	model       TModel
@@ -203,6 +214,7 @@ func NewIndexChildOp[TModel any, TAPI any](
 	opType OperationType,
 	sectionName string,
 	priority int,
+	parentType string,
 	parentName string,
 	index int,
 	model TModel,
@@ -214,6 +226,7 @@ func NewIndexChildOp[TModel any, TAPI any](
 		opType:      opType,
 		sectionName: sectionName,
 		priorityVal: priority,
+		parentType:  parentType,
 		parentName:  parentName,
 		index:       index,
 		model:       model,
@@ -227,6 +240,12 @@ func (op *IndexChildOp[TModel, TAPI]) Type() OperationType { return op.opType }
 func (op *IndexChildOp[TModel, TAPI]) Section() string     { return op.sectionName }
 func (op *IndexChildOp[TModel, TAPI]) Priority() int       { return op.priorityVal }
 func (op *IndexChildOp[TModel, TAPI]) Describe() string    { return op.describeFn() }
+func (op *IndexChildOp[TModel, TAPI]) Target() string {
+	return op.parentName + "/" + strconv.Itoa(op.index)
+}
+func (op *IndexChildOp[TModel, TAPI]) Name() string          { return strconv.Itoa(op.index) }
+func (op *IndexChildOp[TModel, TAPI]) Model() any            { return op.model }
+func (op *IndexChildOp[TModel, TAPI]) ParentSection() string { return op.parentType }
 
 func (op *IndexChildOp[TModel, TAPI]) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
 	// For delete operations, we don't need to transform
@@ -251,6 +270,7 @@ type NameChildOp[TModel any, TAPI any] struct {
 	opType      OperationType
 	sectionName string
 	priorityVal int
+	parentType  string
 	parentName  string
 	childName   string
 	model       TModel
@@ -264,6 +284,7 @@ func NewNameChildOp[TModel any, TAPI any](
 	opType OperationType,
 	sectionName string,
 	priority int,
+	parentType string,
 	parentName string,
 	childName string,
 	model TModel,
@@ -275,6 +296,7 @@ func NewNameChildOp[TModel any, TAPI any](
 		opType:      opType,
 		sectionName: sectionName,
 		priorityVal: priority,
+		parentType:  parentType,
 		parentName:  parentName,
 		childName:   childName,
 		model:       model,
@@ -284,10 +306,14 @@ func NewNameChildOp[TModel any, TAPI any](
 	}
 }
 
-func (op *NameChildOp[TModel, TAPI]) Type() OperationType { return op.opType }
-func (op *NameChildOp[TModel, TAPI]) Section() string     { return op.sectionName }
-func (op *NameChildOp[TModel, TAPI]) Priority() int       { return op.priorityVal }
-func (op *NameChildOp[TModel, TAPI]) Describe() string    { return op.describeFn() }
+func (op *NameChildOp[TModel, TAPI]) Type() OperationType   { return op.opType }
+func (op *NameChildOp[TModel, TAPI]) Section() string       { return op.sectionName }
+func (op *NameChildOp[TModel, TAPI]) Priority() int         { return op.priorityVal }
+func (op *NameChildOp[TModel, TAPI]) Describe() string      { return op.describeFn() }
+func (op *NameChildOp[TModel, TAPI]) Target() string        { return op.parentName + "/" + op.childName }
+func (op *NameChildOp[TModel, TAPI]) Name() string          { return op.childName }
+func (op *NameChildOp[TModel, TAPI]) Model() any            { return op.model }
+func (op *NameChildOp[TModel, TAPI]) ParentSection() string { return op.parentType }
 
 func (op *NameChildOp[TModel, TAPI]) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
 	// For delete operations, we don't need to transform
@@ -340,6 +366,8 @@ func (op *SingletonOp[TModel, TAPI]) Type() OperationType { return OperationUpda
 func (op *SingletonOp[TModel, TAPI]) Section() string     { return op.sectionName }
 func (op *SingletonOp[TModel, TAPI]) Priority() int       { return op.priorityVal }
 func (op *SingletonOp[TModel, TAPI]) Describe() string    { return op.describeFn() }
+func (op *SingletonOp[TModel, TAPI]) Target() string      { return op.sectionName }
+func (op *SingletonOp[TModel, TAPI]) Model() any          { return op.model }
 
 func (op *SingletonOp[TModel, TAPI]) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
 	apiModel := op.transformFn(op.model)
@@ -357,6 +385,7 @@ type ContainerChildOp[TModel any, TAPI any] struct {
 	opType        OperationType
 	sectionName   string
 	priorityVal   int
+	parentType    string
 	containerName string
 	model         TModel
 	transformFn   func(TModel) TAPI
@@ -370,6 +399,7 @@ func NewContainerChildOp[TModel any, TAPI any](
 	opType OperationType,
 	sectionName string,
 	priority int,
+	parentType string,
 	containerName string,
 	model TModel,
 	transformFn func(TModel) TAPI,
@@ -381,6 +411,7 @@ func NewContainerChildOp[TModel any, TAPI any](
 		opType:        opType,
 		sectionName:   sectionName,
 		priorityVal:   priority,
+		parentType:    parentType,
 		containerName: containerName,
 		model:         model,
 		transformFn:   transformFn,
@@ -394,6 +425,12 @@ func (op *ContainerChildOp[TModel, TAPI]) Type() OperationType { return op.opTyp
 func (op *ContainerChildOp[TModel, TAPI]) Section() string     { return op.sectionName }
 func (op *ContainerChildOp[TModel, TAPI]) Priority() int       { return op.priorityVal }
 func (op *ContainerChildOp[TModel, TAPI]) Describe() string    { return op.describeFn() }
+func (op *ContainerChildOp[TModel, TAPI]) Target() string {
+	return op.containerName + "/" + op.nameFn(op.model)
+}
+func (op *ContainerChildOp[TModel, TAPI]) Name() string          { return op.nameFn(op.model) }
+func (op *ContainerChildOp[TModel, TAPI]) Model() any            { return op.model }
+func (op *ContainerChildOp[TModel, TAPI]) ParentSection() string { return op.parentType }
 
 func (op *ContainerChildOp[TModel, TAPI]) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
 	childName := op.nameFn(op.model)
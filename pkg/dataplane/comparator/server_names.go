@@ -0,0 +1,147 @@
+package comparator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/haproxytech/client-native/v6/models"
+
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+// stableServerNamePrefix and stableServerNameLength define the names returned
+// by StableServerName. The stable_server_name template filter produces the
+// same names, so both must change together.
+const (
+	stableServerNamePrefix = "srv_"
+	stableServerNameLength = 12
+)
+
+// StableServerName derives a server name from the identity of an endpoint,
+// e.g. "10.0.0.1:8080", or from another key that identifies the server.
+//
+// The name depends on nothing but the key, so an endpoint keeps its server
+// name when other endpoints are added, removed or reordered.
+func StableServerName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return stableServerNamePrefix + hex.EncodeToString(sum[:])[:stableServerNameLength]
+}
+
+// ServerEndpointKey returns the key StableServerName derives the name of a
+// server from: its address, followed by ":port" if it has one.
+func ServerEndpointKey(address string, port *int64) string {
+	if port == nil {
+		return address
+	}
+	return address + ":" + strconv.FormatInt(*port, 10)
+}
+
+// ServerNameCollisionError reports two servers of a backend that would get the
+// same stable name because they share an endpoint.
+type ServerNameCollisionError struct {
+	// Backend is the name of the backend
+	Backend string
+
+	// Servers are the names of the colliding servers as rendered
+	Servers [2]string
+
+	// Endpoint is the shared endpoint key
+	Endpoint string
+}
+
+// Error implements the error interface.
+func (e *ServerNameCollisionError) Error() string {
+	return fmt.Sprintf("servers '%s' and '%s' of backend '%s' share endpoint %s and cannot be given a stable name",
+		e.Servers[0], e.Servers[1], e.Backend, e.Endpoint)
+}
+
+// applyStableServerNames renames the servers of all backends of config to the
+// StableServerName of their endpoint.
+//
+// Servers that are named by index (srv1, srv2, ...) get a different name when
+// the endpoint list changes order, and the comparator would replace them even
+// though the endpoints stayed the same. Stable names make servers match by
+// endpoint instead.
+//
+// References to renamed servers are rewritten as well, so use-server rules and
+// "track" parameters keep pointing at the same servers.
+func applyStableServerNames(config *parser.StructuredConfig) error {
+	// renames maps backend names to the new name of each of its servers
+	renames := make(map[string]map[string]string, len(config.Backends))
+
+	for _, backend := range config.Backends {
+		if len(backend.Servers) == 0 {
+			continue
+		}
+
+		renamed := make(map[string]models.Server, len(backend.Servers))
+		renamedFrom := make(map[string]string, len(backend.Servers))
+		for _, oldName := range slices.Sorted(maps.Keys(backend.Servers)) {
+			server := backend.Servers[oldName]
+			key := ServerEndpointKey(server.Address, server.Port)
+			name := StableServerName(key)
+			if other, exists := renamedFrom[name]; exists {
+				return &ServerNameCollisionError{Backend: backend.Name, Servers: [2]string{other, oldName}, Endpoint: key}
+			}
+			server.Name = name
+			renamed[name] = server
+			renamedFrom[name] = oldName
+		}
+		backend.Servers = renamed
+
+		renames[backend.Name] = make(map[string]string, len(renamedFrom))
+		for name, oldName := range renamedFrom {
+			renames[backend.Name][oldName] = name
+		}
+	}
+
+	for _, backend := range config.Backends {
+		renameServerReferences(backend, renames)
+	}
+	return nil
+}
+
+// renameServerReferences rewrites the use-server targets and the "track"
+// parameters of the servers of backend that refer to renamed servers.
+func renameServerReferences(backend *models.Backend, renames map[string]map[string]string) {
+	if len(backend.ServerSwitchingRuleList) > 0 {
+		rules := make(models.ServerSwitchingRules, len(backend.ServerSwitchingRuleList))
+		for i, rule := range backend.ServerSwitchingRuleList {
+			if rule != nil {
+				if name, ok := renames[backend.Name][rule.TargetServer]; ok {
+					renamedRule := *rule
+					renamedRule.TargetServer = name
+					rule = &renamedRule
+				}
+			}
+			rules[i] = rule
+		}
+		backend.ServerSwitchingRuleList = rules
+	}
+
+	for name, server := range backend.Servers {
+		if server.Track == "" {
+			continue
+		}
+		// "track <server>" refers to a server of the same backend
+		trackedBackend, trackedServer, found := strings.Cut(server.Track, "/")
+		if !found {
+			trackedBackend, trackedServer = backend.Name, server.Track
+		}
+		newName, ok := renames[trackedBackend][trackedServer]
+		if !ok {
+			continue
+		}
+		if found {
+			server.Track = trackedBackend + "/" + newName
+		} else {
+			server.Track = newName
+		}
+		backend.Servers[name] = server
+	}
+}
@@ -0,0 +1,120 @@
+package comparator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestStableServerName(t *testing.T) {
+	port := int64(8080)
+	key := ServerEndpointKey("10.0.0.1", &port)
+	if key != "10.0.0.1:8080" {
+		t.Fatalf("Expected key 10.0.0.1:8080, got %s", key)
+	}
+
+	// Must match the stable_server_name template filter
+	if got := StableServerName(key); got != "srv_120e9814c44c" {
+		t.Errorf("Expected srv_120e9814c44c, got %s", got)
+	}
+
+	if ServerEndpointKey("web.svc", nil) != "web.svc" {
+		t.Errorf("Expected key without port to be the address")
+	}
+}
+
+// renderIndexedServers renders a backend whose servers are named by index,
+// the way templates looping over endpoints do.
+func renderIndexedServers(addresses ...string) string {
+	config := "\nglobal\n    daemon\n\nbackend web\n"
+	for i, address := range addresses {
+		config += fmt.Sprintf("    server srv%d %s:8080\n", i+1, address)
+	}
+	return config
+}
+
+func TestCompare_StableServerNames(t *testing.T) {
+	// Servers as stored by a previous sync with stable names
+	current := "\nglobal\n    daemon\n\nbackend web\n"
+	for _, address := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		current += fmt.Sprintf("    server %s %s:8080\n", StableServerName(address+":8080"), address)
+	}
+
+	t.Run("reordered endpoints produce no operations", func(t *testing.T) {
+		currentConfig, desiredConfig := parseTestConfigs(t, current, renderIndexedServers("10.0.0.3", "10.0.0.1", "10.0.0.2"))
+
+		diff, err := New().WithStableServerNames().Compare(currentConfig, desiredConfig)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 0 {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Expected no operations, got %d", len(diff.Operations))
+		}
+	})
+
+	t.Run("removed endpoint deletes only its server", func(t *testing.T) {
+		currentConfig, desiredConfig := parseTestConfigs(t, current, renderIndexedServers("10.0.0.3", "10.0.0.1"))
+
+		diff, err := New().WithStableServerNames().Compare(currentConfig, desiredConfig)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 1 || diff.Summary.TotalDeletes != 1 {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Expected a single delete, got %d operations", len(diff.Operations))
+		}
+	})
+
+	t.Run("shared endpoint is rejected", func(t *testing.T) {
+		currentConfig, desiredConfig := parseTestConfigs(t, current, renderIndexedServers("10.0.0.1", "10.0.0.1"))
+
+		_, err := New().WithStableServerNames().Compare(currentConfig, desiredConfig)
+
+		var collision *ServerNameCollisionError
+		if !errors.As(err, &collision) {
+			t.Fatalf("Expected *ServerNameCollisionError, got %v", err)
+		}
+		if collision.Backend != "web" || collision.Endpoint != "10.0.0.1:8080" {
+			t.Errorf("Unexpected collision: %+v", *collision)
+		}
+	})
+
+	t.Run("references follow renamed servers", func(t *testing.T) {
+		desired := `
+global
+    daemon
+
+backend web
+    use-server srv2 if { path_beg /admin }
+    server srv1 10.0.0.1:8080 track srv2
+    server srv2 10.0.0.2:8080 check
+
+backend other
+    server srv1 10.0.0.9:8080 track web/srv1
+`
+		srv1, srv2 := StableServerName("10.0.0.1:8080"), StableServerName("10.0.0.2:8080")
+		stored := fmt.Sprintf(`
+global
+    daemon
+
+backend web
+    use-server %[2]s if { path_beg /admin }
+    server %[1]s 10.0.0.1:8080 track %[2]s
+    server %[2]s 10.0.0.2:8080 check
+
+backend other
+    server %[3]s 10.0.0.9:8080 track web/%[1]s
+`, srv1, srv2, StableServerName("10.0.0.9:8080"))
+		currentConfig, desiredConfig := parseTestConfigs(t, stored, desired)
+
+		diff, err := New().WithStableServerNames().Compare(currentConfig, desiredConfig)
+		if err != nil {
+			t.Fatalf("Compare() failed: %v", err)
+		}
+		if len(diff.Operations) != 0 {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Expected no operations, got %d", len(diff.Operations))
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package comparator
+
+import (
+	"testing"
+
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+const userlistBase = `
+global
+    daemon
+
+userlist auth_users
+    group admins
+    user alice password $6$hash1 groups admins
+`
+
+func TestCompare_UserlistAddUserOnlyCreatesUser(t *testing.T) {
+	desiredConfig := userlistBase + "    user bob password $6$hash2\n"
+
+	current, desired := parseTestConfigs(t, userlistBase, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 1 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected only a user create, got %d operations", len(diff.Operations))
+	}
+
+	op := diff.Operations[0]
+	if op.Section() != "user" || op.Type() != sections.OperationCreate {
+		t.Errorf("Expected user create, got %s", op.Describe())
+	}
+	if got := op.Describe(); got != "Create user 'bob' in userlist 'auth_users'" {
+		t.Errorf("Unexpected operation: %s", got)
+	}
+}
+
+func TestCompare_UserlistGroupChangeRecreatesWithUsers(t *testing.T) {
+	desiredConfig := `
+global
+    daemon
+
+userlist auth_users
+    group admins
+    group operators
+    user alice password $6$hash1 groups admins
+`
+
+	current, desired := parseTestConfigs(t, userlistBase, desiredConfig)
+
+	diff, err := New().Compare(current, desired)
+	if err != nil {
+		t.Fatalf("Compare() failed: %v", err)
+	}
+
+	if len(diff.Operations) != 3 {
+		logOperations(t, diff.Operations)
+		t.Fatalf("Expected userlist delete, userlist create and user create, got %d operations", len(diff.Operations))
+	}
+
+	expected := []struct {
+		opType  sections.OperationType
+		section string
+	}{
+		{sections.OperationDelete, "userlist"},
+		{sections.OperationCreate, "userlist"},
+		{sections.OperationCreate, "user"},
+	}
+	for i, want := range expected {
+		op := diff.Operations[i]
+		if op.Type() != want.opType || op.Section() != want.section {
+			logOperations(t, diff.Operations)
+			t.Fatalf("Operation %d: expected %v %s, got %s", i, want.opType, want.section, op.Describe())
+		}
+	}
+}
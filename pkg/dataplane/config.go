@@ -1,9 +1,16 @@
 package dataplane
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
 	"time"
 
 	"haproxy-template-ic/pkg/dataplane/auxiliaryfiles"
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
 )
 
 // Endpoint represents HAProxy Dataplane API connection information.
@@ -17,6 +24,19 @@ type Endpoint struct {
 	// Password for basic authentication
 	Password string
 
+	// UsernameFile and PasswordFile read the basic auth credentials from files,
+	// e.g. mounted Kubernetes secrets (optional). They take precedence over
+	// Username and Password, must exist when the client is created, and are
+	// re-read when they change so rotated credentials apply without recreating
+	// the client. Trailing newlines are ignored.
+	UsernameFile string
+	PasswordFile string
+
+	// TLS configures a client certificate and CA for HTTPS endpoints (optional).
+	// With a client certificate set, Username and Password may be left empty to
+	// authenticate with mutual TLS instead of basic auth.
+	TLS *TLSConfig
+
 	// PodName is the Kubernetes pod name (for observability)
 	PodName string
 
@@ -28,6 +48,27 @@ type Endpoint struct {
 	DetectedMajorVersion int    // Major version (e.g., 3)
 	DetectedMinorVersion int    // Minor version (e.g., 2)
 	DetectedFullVersion  string // Full version string (e.g., "v3.2.6 87ad0bcf")
+
+	// Recorder captures all Dataplane API interactions for debugging (optional)
+	// See NewRecorder for details.
+	Recorder *Recorder
+
+	// Transport overrides the HTTP transport used for requests (optional)
+	// Use a ReplayTransport to serve a recording instead of contacting the Dataplane API.
+	Transport http.RoundTripper
+
+	// CircuitBreaker stops syncs after repeated failures (optional)
+	// While the circuit is open, Sync fails immediately with a CircuitOpenError
+	// instead of contacting the Dataplane API. See Client.CircuitState.
+	CircuitBreaker *CircuitBreakerOptions
+
+	// CloseTimeout is how long Client.Close waits for in-flight operations to
+	// finish before closing connections anyway (default: 30 seconds)
+	CloseTimeout time.Duration
+
+	// SubscribeInterval is how often Client.Subscribe polls the configuration
+	// version (default: 5 seconds)
+	SubscribeInterval time.Duration
 }
 
 // HasCachedVersion returns true if version info has been cached on this endpoint.
@@ -35,15 +76,86 @@ func (e *Endpoint) HasCachedVersion() bool {
 	return e.DetectedMajorVersion > 0
 }
 
+// TLSConfig holds PEM-encoded TLS material for connecting to a Dataplane API.
+type TLSConfig struct {
+	// CertPEM is the client certificate presented for mutual TLS (optional, requires KeyPEM)
+	CertPEM []byte
+
+	// KeyPEM is the private key of the client certificate (optional, requires CertPEM)
+	KeyPEM []byte
+
+	// CAPEM contains the CA certificates used to verify the Dataplane API
+	// server certificate (optional, defaults to the system roots)
+	CAPEM []byte
+}
+
+// tlsConfig converts the endpoint's PEM material into a tls.Config, checking
+// that the client certificate and key form a pair.
+// Returns nil if the endpoint has no TLS configuration.
+func (e *Endpoint) tlsConfig() (*tls.Config, error) {
+	if e.TLS == nil {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(e.TLS.CertPEM) > 0 || len(e.TLS.KeyPEM) > 0 {
+		if len(e.TLS.CertPEM) == 0 || len(e.TLS.KeyPEM) == 0 {
+			return nil, fmt.Errorf("client certificate and key must be set together")
+		}
+		cert, err := tls.X509KeyPair(e.TLS.CertPEM, e.TLS.KeyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate/key pair: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if len(e.TLS.CAPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(e.TLS.CAPEM) {
+			return nil, fmt.Errorf("no valid certificates found in CA PEM")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// httpClient builds the HTTP client for this endpoint from Transport and Recorder.
+// Without a Transport, requests use the default transport configured with
+// tlsConfig. Returns nil if neither is set so the default client is used.
+func (e *Endpoint) httpClient(tlsConfig *tls.Config) *http.Client {
+	if e.Transport == nil && e.Recorder == nil {
+		return nil
+	}
+
+	transport := e.Transport
+	if transport == nil && tlsConfig != nil {
+		transport = client.NewTLSTransport(tlsConfig)
+	}
+	if e.Recorder != nil {
+		transport = e.Recorder.Wrap(transport)
+	}
+
+	return &http.Client{Transport: transport}
+}
+
 // Redacted returns a redacted version of the endpoint for safe logging.
 // Credentials are masked to prevent exposure in logs.
 func (e *Endpoint) Redacted() map[string]string {
-	return map[string]string{
+	redacted := map[string]string{
 		"url":      e.URL,
 		"username": e.Username,
 		"password": "***REDACTED***",
 		"pod":      e.PodName,
 	}
+	if e.UsernameFile != "" {
+		redacted["username_file"] = e.UsernameFile
+	}
+	if e.PasswordFile != "" {
+		redacted["password_file"] = e.PasswordFile
+	}
+	return redacted
 }
 
 // AuxiliaryFiles contains files to synchronize before configuration changes.
@@ -70,17 +182,246 @@ type SyncOptions struct {
 	// These are always retried as they're recoverable errors.
 	MaxRetries int
 
+	// RetryBudget caps the total number of retries of the whole sync (default: 0, unlimited)
+	// Fetching the current configuration after connection errors, version
+	// conflicts when starting or committing a transaction, and the retry after
+	// deleting stale transactions all take from the same budget, on top of
+	// their own limits. Once it is used up, the sync fails with a SyncError
+	// wrapping client.ErrRetryBudgetExhausted and is not retried as raw push.
+	RetryBudget int
+
 	// Timeout for the entire sync operation (default: 2 minutes)
 	Timeout time.Duration
 
 	// ContinueOnError continues applying operations even if some fail (default: false)
-	// When false, the first error stops execution.
+	// When false, the first error stops execution. When true, failed operations
+	// are reported in SyncResult.FailedOperations, operations depending on them
+	// (e.g. the servers of a backend that failed to be created) are skipped, and
+	// the sync fails with a PartialSyncError after the remaining operations were
+	// committed. The raw fallback is skipped, since it would apply every change.
 	ContinueOnError bool
 
 	// FallbackToRaw enables automatic fallback to raw config push on non-409 errors (default: true)
 	// When enabled, if fine-grained sync fails with non-recoverable errors,
 	// the library automatically falls back to pushing the complete raw configuration.
+	// Options a raw push cannot honor skip the fallback, which is reported as a
+	// WarningRawFallbackSkipped in the result returned along with the error.
 	FallbackToRaw bool
+
+	// BindCollisionPolicy controls how binds sharing an address:port within a
+	// frontend are handled (default: BindCollisionIgnore, no detection)
+	// BindCollisionReject fails the sync on colliding binds and
+	// BindCollisionMerge drops duplicates that differ only in their name. The
+	// raw fallback is skipped for syncs that merged binds, since the raw
+	// configuration still contains the duplicates.
+	BindCollisionPolicy BindCollisionPolicy
+
+	// PriorityOverrides replaces the default ordering priority of section types
+	// (e.g. "cache", "http_errors") keyed by operation section name (default: none)
+	// Overrides that would order a section before one it depends on are rejected.
+	PriorityOverrides map[string]int
+
+	// OrderProfile selects the table of section priorities
+	// (default: OrderProfileRelaxed, the built-in priorities)
+	// PriorityOverrides apply on top of the profile's priorities.
+	OrderProfile OrderProfile
+
+	// RuntimeVars declares process-scoped variables (e.g. "proc.rate_limit")
+	// and the sample expressions they are set to (e.g. "int(100)") (default: none)
+	// They are written as global set-var directives so HAProxy sets them again
+	// on every reload instead of losing values set through the Runtime API.
+	RuntimeVars map[string]string
+
+	// FailOnDrift refuses to sync if the current configuration contains resources
+	// the controller does not manage (default: false)
+	// A resource is drifted if the diff would delete it and IsManaged returns false
+	// for it. The sync then fails with a DriftError before anything is applied.
+	FailOnDrift bool
+
+	// IsManaged reports whether the controller produces a resource, given the
+	// operation section (e.g. "backend", "server") and the resource name
+	// Child resources are passed by their own name, e.g. "srv1" for a server,
+	// and rules, which have no names, by their index.
+	// Required with FailOnDrift, which SyncOptions.Validate rejects without it.
+	IsManaged func(section, name string) bool
+
+	// NamespacePrefix restricts deletes to resources whose name starts with
+	// this prefix (default: none, any resource may be deleted)
+	// In HAProxy instances shared between tenants, this keeps the sync from
+	// removing resources of other tenants that are missing from the desired
+	// configuration. Child resources such as servers are matched by the name of
+	// their parent section. Deletes outside the prefix are dropped before the
+	// FailOnDrift check, so they are neither applied nor reported as drift. The
+	// raw fallback is skipped while a prefix is set, since a raw push would
+	// delete them as well.
+	NamespacePrefix string
+
+	// RollbackOnPartialFailure restores the configuration fetched at the start
+	// of the sync if the sync fails after changes were already committed
+	// (default: false)
+	// Changes applied through the Runtime API are committed one at a time, so a
+	// failure midway leaves HAProxy with part of the changes. The rollback pushes
+	// the previous configuration as raw config.
+	RollbackOnPartialFailure bool
+
+	// NormalizeFieldDefaults treats optional fields the desired configuration
+	// omits as equal to the same fields set to their HAProxy default in the
+	// current configuration, e.g. an omitted server weight and "weight 1"
+	// (default: false)
+	// This avoids perpetual updates for defaults the Dataplane API writes
+	// explicitly.
+	NormalizeFieldDefaults bool
+
+	// OperationTypeFilter restricts the sync to operations of the listed types
+	// (default: none, all operations are applied)
+	// Running a sync per type, e.g. creates first, then updates, then deletes,
+	// rolls a change out in stages. Operations keep their dependency order
+	// within the filtered set. Index-based rule operations following a filtered
+	// rule create or delete of the same list are skipped too, since their
+	// positions would not line up. The raw fallback is skipped while a filter is
+	// set, since a raw push would apply the excluded operations as well.
+	OperationTypeFilter []OperationType
+
+	// DrainBeforeDelete sets servers the sync removes to the drain state through
+	// the Runtime API and waits DrainGracePeriod before deleting them (default: false)
+	// Draining servers accept no new connections but finish the ones in flight.
+	// Servers that cannot be drained, e.g. because the Runtime API is
+	// unavailable, are deleted anyway and reported as a warning.
+	DrainBeforeDelete bool
+
+	// DrainGracePeriod is how long to wait between draining and deleting
+	// servers with DrainBeforeDelete (default: 30 seconds)
+	DrainGracePeriod time.Duration
+
+	// Overlays are configuration fragments merged into the desired
+	// configuration before it is compared (default: none)
+	// A section in an overlay replaces the desired section of the same type and
+	// name as a whole; new sections are appended. Overlays are applied in order
+	// and must not define the same section twice. The raw fallback is skipped
+	// while overlays are set, since it pushes the desired configuration alone.
+	Overlays []string
+
+	// CleanupStaleTransactions deletes stale transactions and retries once when
+	// the Dataplane API refuses to start a transaction because too many are
+	// open (default: false)
+	// Stale transactions were started on an older configuration version, e.g.
+	// by syncs that were interrupted, and can no longer be committed.
+	// Transactions on the current version are kept, since another sync may
+	// still be using them.
+	CleanupStaleTransactions bool
+
+	// StableServerNames renames the servers of the desired configuration to a
+	// name derived from their address and port before comparing (default: false)
+	// Templates that name servers by index (srv1, srv2, ...) would otherwise
+	// rename servers whenever the endpoint list changes order. use-server
+	// targets and "track" parameters referring to renamed servers are renamed
+	// with them; other references should use the stable_server_name filter,
+	// which yields the same names. The raw fallback is skipped while
+	// this is set, since it pushes the servers under their rendered names.
+	StableServerNames bool
+
+	// IncrementalMaps updates changed map files through Runtime API entry
+	// changes instead of replacing them (default: false)
+	// Both versions of a map file are diffed as sorted streams, so templates
+	// should render map entries sorted by key. A map file is replaced as a
+	// whole if it is not sorted, more than MaxMapEntryChanges entries change,
+	// or the Runtime API cannot apply the changes. Entry changes are applied
+	// after the configuration transaction committed.
+	IncrementalMaps bool
+
+	// MaxMapEntryChanges is the number of entry changes above which a map file
+	// is replaced as a whole despite IncrementalMaps (default: 1000)
+	MaxMapEntryChanges int
+
+	// AddBeforeRemoveBinds creates the new bind before deleting the old one
+	// when a bind moves to another address or port (default: false)
+	// Such binds are named after their address and port, so the change is a
+	// delete and a create, and deletes normally come first. Creating first
+	// keeps the frontend listening while the change is applied. Deletes of
+	// binds whose address:port a new bind uses stay first.
+	AddBeforeRemoveBinds bool
+
+	// ReturnAppliedConfig returns the desired configuration the sync applied
+	// in SyncResult.AppliedConfig (default: false)
+	// Off by default, since it keeps a copy of the whole configuration in the
+	// result.
+	ReturnAppliedConfig bool
+
+	// PreserveServerState keeps servers in the drain or maint admin state
+	// across reloads (default: false)
+	// Admin states set through the Runtime API, e.g. by an operator putting a
+	// server into maintenance, are lost when HAProxy reloads. With this set,
+	// the sync reads them through the Runtime API before applying changes and,
+	// if the sync reloaded HAProxy, waits for the reload and sets them again
+	// on the servers that still exist. Health check results and weights are
+	// not preserved; use the server-state-file and load-server-state-from-file
+	// directives for those.
+	PreserveServerState bool
+
+	// CaptureRollbackSnapshot returns the configuration fetched at the start of
+	// the sync in SyncResult.RollbackSnapshot (default: false)
+	// Passing the snapshot to Client.Rollback later restores the configuration
+	// the sync replaced, e.g. when the new configuration turns out to be broken
+	// after it was committed. Not set on no-op syncs, which change nothing.
+	CaptureRollbackSnapshot bool
+}
+
+// BindCollisionPolicy determines how binds sharing an address:port are handled.
+// This type is re-exported from pkg/dataplane/comparator for convenience.
+type BindCollisionPolicy = comparator.BindCollisionPolicy
+
+const (
+	// BindCollisionIgnore skips bind collision detection.
+	BindCollisionIgnore = comparator.BindCollisionIgnore
+
+	// BindCollisionReject fails the sync when binds collide.
+	BindCollisionReject = comparator.BindCollisionReject
+
+	// BindCollisionMerge merges colliding binds that differ only in their name.
+	BindCollisionMerge = comparator.BindCollisionMerge
+)
+
+// OrderProfile selects a predefined table of section priorities.
+// This type is re-exported from pkg/dataplane/comparator for convenience.
+type OrderProfile = comparator.OrderProfile
+
+const (
+	// OrderProfileRelaxed uses the built-in section priorities.
+	OrderProfileRelaxed = comparator.OrderProfileRelaxed
+
+	// OrderProfileStrict creates every section before the sections that can reference it.
+	OrderProfileStrict = comparator.OrderProfileStrict
+)
+
+// OperationType is the kind of change an operation makes (create, update or delete).
+// This type is re-exported from pkg/dataplane/comparator/sections for convenience.
+type OperationType = sections.OperationType
+
+const (
+	// OperationCreate adds a resource that is not in the current configuration.
+	OperationCreate = sections.OperationCreate
+
+	// OperationUpdate changes a resource that exists in both configurations.
+	OperationUpdate = sections.OperationUpdate
+
+	// OperationDelete removes a resource that is not in the desired configuration.
+	OperationDelete = sections.OperationDelete
+)
+
+// Validate checks that the options can be used together. Sync calls it
+// before fetching the current configuration.
+func (o *SyncOptions) Validate() error {
+	if o.FailOnDrift && o.IsManaged == nil {
+		return &SyncError{
+			Stage:   "options",
+			Message: "FailOnDrift requires an IsManaged predicate",
+			Hints: []string{
+				"Set IsManaged to match the names of the resources the templates produce",
+				"Disable FailOnDrift to let the sync remove unmanaged resources",
+			},
+		}
+	}
+	return nil
 }
 
 // DefaultSyncOptions returns sensible default sync options.
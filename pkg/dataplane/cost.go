@@ -0,0 +1,91 @@
+package dataplane
+
+// CostModel defines the weights used to score a planned configuration change.
+//
+// The score lets automation gate risky changes, for example by requiring manual
+// approval when DiffResult.Cost() exceeds a threshold. Each planned operation
+// contributes its type weight multiplied by either ReloadMultiplier or
+// RuntimeMultiplier, depending on how the plan would be applied.
+type CostModel struct {
+	// CreateWeight is the base cost of a create operation (default: 1)
+	CreateWeight float64
+
+	// UpdateWeight is the base cost of an update operation (default: 1)
+	UpdateWeight float64
+
+	// DeleteWeight is the base cost of a delete operation (default: 3)
+	// Deletes weigh more because they can drop traffic that is still routed to the resource.
+	DeleteWeight float64
+
+	// ReloadMultiplier scales operations applied through a transaction that reloads HAProxy (default: 2)
+	ReloadMultiplier float64
+
+	// RuntimeMultiplier scales operations applied through the Runtime API without reload (default: 1)
+	RuntimeMultiplier float64
+}
+
+// DefaultCostModel returns the cost model used by DiffResult.Cost.
+func DefaultCostModel() CostModel {
+	return CostModel{
+		CreateWeight:      1,
+		UpdateWeight:      1,
+		DeleteWeight:      3,
+		ReloadMultiplier:  2,
+		RuntimeMultiplier: 1,
+	}
+}
+
+// Cost returns the weighted score of the planned operations using DefaultCostModel.
+// A diff without changes has a cost of 0.
+func (r *DiffResult) Cost() float64 {
+	return r.CostWithModel(DefaultCostModel())
+}
+
+// CostWithModel returns the weighted score of the planned operations using the given model.
+//
+// Plans consisting only of server updates are applied through the Runtime API
+// without reload (see Sync), so their operations use RuntimeMultiplier. All
+// operations of any other plan use ReloadMultiplier.
+func (r *DiffResult) CostWithModel(model CostModel) float64 {
+	multiplier := model.ReloadMultiplier
+	if isRuntimeOnlyPlan(r.PlannedOperations) {
+		multiplier = model.RuntimeMultiplier
+	}
+
+	var cost float64
+	for i := range r.PlannedOperations {
+		cost += model.typeWeight(r.PlannedOperations[i].Type) * multiplier
+	}
+
+	return cost
+}
+
+// typeWeight returns the base weight for an operation type.
+func (m CostModel) typeWeight(opType string) float64 {
+	switch opType {
+	case "create":
+		return m.CreateWeight
+	case "update":
+		return m.UpdateWeight
+	case "delete":
+		return m.DeleteWeight
+	default:
+		return 0
+	}
+}
+
+// isRuntimeOnlyPlan checks if all planned operations are server updates.
+// This mirrors orchestrator.areAllOperationsRuntimeEligible for public result types.
+func isRuntimeOnlyPlan(ops []PlannedOperation) bool {
+	if len(ops) == 0 {
+		return false
+	}
+
+	for i := range ops {
+		if ops[i].Section != "server" || ops[i].Type != "update" {
+			return false
+		}
+	}
+
+	return true
+}
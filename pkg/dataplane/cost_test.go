@@ -0,0 +1,51 @@
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffResult_Cost(t *testing.T) {
+	backendOps := func(opType string) []PlannedOperation {
+		return []PlannedOperation{
+			{Type: opType, Section: "backend", Resource: "web"},
+			{Type: opType, Section: "server", Resource: "srv1"},
+			{Type: opType, Section: "server", Resource: "srv2"},
+		}
+	}
+
+	t.Run("delete-heavy plan scores higher than create-heavy plan", func(t *testing.T) {
+		creates := &DiffResult{HasChanges: true, PlannedOperations: backendOps("create")}
+		deletes := &DiffResult{HasChanges: true, PlannedOperations: backendOps("delete")}
+
+		assert.Equal(t, 6.0, creates.Cost())
+		assert.Equal(t, 18.0, deletes.Cost())
+		assert.Greater(t, deletes.Cost(), creates.Cost())
+	})
+
+	t.Run("runtime plan scores lower than reload plan", func(t *testing.T) {
+		runtime := &DiffResult{HasChanges: true, PlannedOperations: []PlannedOperation{
+			{Type: "update", Section: "server", Resource: "srv1"},
+			{Type: "update", Section: "server", Resource: "srv2"},
+		}}
+		reload := &DiffResult{HasChanges: true, PlannedOperations: []PlannedOperation{
+			{Type: "update", Section: "server", Resource: "srv1"},
+			{Type: "update", Section: "backend", Resource: "web"},
+		}}
+
+		assert.Equal(t, 2.0, runtime.Cost())
+		assert.Equal(t, 4.0, reload.Cost())
+	})
+
+	t.Run("no changes cost nothing", func(t *testing.T) {
+		assert.Zero(t, (&DiffResult{}).Cost())
+	})
+
+	t.Run("custom model", func(t *testing.T) {
+		diff := &DiffResult{HasChanges: true, PlannedOperations: backendOps("create")}
+		model := CostModel{CreateWeight: 10, ReloadMultiplier: 1}
+
+		assert.Equal(t, 30.0, diff.CostWithModel(model))
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsFunc returns a function providing the endpoint's basic auth
+// credentials, read from UsernameFile and PasswordFile, for use as
+// client.Endpoint.Credentials. Returns nil if the endpoint has no credential
+// files, and an error if a file cannot be read or a credential is empty.
+func (e *Endpoint) CredentialsFunc() (func() (username, password string), error) {
+	if e.UsernameFile == "" && e.PasswordFile == "" {
+		return nil, nil
+	}
+
+	w := &credentialWatcher{
+		username: credentialFile{path: e.UsernameFile, value: e.Username},
+		password: credentialFile{path: e.PasswordFile, value: e.Password},
+		logger:   slog.Default().With("pod", e.PodName),
+	}
+	for _, f := range []*credentialFile{&w.username, &w.password} {
+		if f.path == "" {
+			continue
+		}
+		if err := f.read(); err != nil {
+			return nil, err
+		}
+	}
+	if w.username.value == "" || w.password.value == "" {
+		return nil, fmt.Errorf("username and password must not be empty")
+	}
+	return w.credentials, nil
+}
+
+// credentialWatcher keeps credentials read from files up to date. Before each
+// request, the files are checked for changes (modification time and size) and
+// re-read if they changed. Checking the files themselves rather than watching
+// for events also covers Kubernetes secret volumes, which swap a symlinked
+// directory instead of writing to the files.
+type credentialWatcher struct {
+	mu       sync.Mutex
+	username credentialFile
+	password credentialFile
+	logger   *slog.Logger
+}
+
+// credentials returns the current credentials, re-reading changed files.
+// If a changed file cannot be read, the previous credentials are kept.
+func (w *credentialWatcher) credentials() (username, password string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, f := range []*credentialFile{&w.username, &w.password} {
+		if f.path == "" || !f.changed() {
+			continue
+		}
+		if err := f.read(); err != nil {
+			w.logger.Warn("Failed to re-read Dataplane API credentials, keeping previous credentials",
+				"error", err)
+			continue
+		}
+		w.logger.Info("Reloaded Dataplane API credentials", "file", f.path)
+	}
+	return w.username.value, w.password.value
+}
+
+// credentialFile is a credential read from a file (path "" for a static value).
+type credentialFile struct {
+	path    string
+	value   string
+	modTime time.Time
+	size    int64
+}
+
+// changed reports whether the file differs from when it was last read.
+func (f *credentialFile) changed() bool {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return true
+	}
+	return !info.ModTime().Equal(f.modTime) || info.Size() != f.size
+}
+
+// read reads the file, ignoring trailing newlines.
+func (f *credentialFile) read() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read credential file: %w", err)
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to read credential file: %w", err)
+	}
+	value := strings.TrimRight(string(data), "\r\n")
+	if value == "" {
+		return fmt.Errorf("credential file %s is empty", f.path)
+	}
+
+	f.value = value
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
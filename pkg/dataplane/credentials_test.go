@@ -0,0 +1,78 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClient_CredentialFiles(t *testing.T) {
+	dir := t.TempDir()
+	usernameFile := filepath.Join(dir, "username")
+	passwordFile := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(usernameFile, []byte("admin\n"), 0o600))
+	require.NoError(t, os.WriteFile(passwordFile, []byte("old-secret\n"), 0o600))
+
+	api := newFakeDataplaneAPI(t, baseTestConfig)
+	api.setPassword("old-secret")
+
+	endpoint := api.endpoint()
+	endpoint.Username = ""
+	endpoint.Password = ""
+	endpoint.UsernameFile = usernameFile
+	endpoint.PasswordFile = passwordFile
+
+	c, err := NewClient(context.Background(), endpoint)
+	require.NoError(t, err)
+
+	desired := baseTestConfig + `
+backend api
+    balance roundrobin
+`
+	_, err = c.Sync(context.Background(), desired, nil, nil)
+	require.NoError(t, err)
+
+	// Rotate the password; the next sync must pick up the new one
+	require.NoError(t, os.WriteFile(passwordFile, []byte("new-secret\n"), 0o600))
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(passwordFile, later, later))
+	api.setPassword("new-secret")
+
+	desired += `
+backend web
+    balance roundrobin
+`
+	_, err = c.Sync(context.Background(), desired, nil, nil)
+	require.NoError(t, err)
+}
+
+func TestNewClient_MissingCredentialFile(t *testing.T) {
+	api := newFakeDataplaneAPI(t, baseTestConfig)
+
+	endpoint := api.endpoint()
+	endpoint.PasswordFile = filepath.Join(t.TempDir(), "missing")
+
+	_, err := NewClient(context.Background(), endpoint)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid credentials")
+	assert.Empty(t, api.Requests(), "no request must be sent with invalid credentials")
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"time"
+
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// Operation is a single step of a sync plan.
+// This type is re-exported from pkg/dataplane/comparator for convenience.
+type Operation = comparator.Operation
+
+// RollbackOperation is a custom operation that can undo its effect. It is
+// rolled back when the configuration changes it was executed with are
+// discarded.
+type RollbackOperation interface {
+	Operation
+
+	// Rollback undoes a successful Execute.
+	Rollback(ctx context.Context) error
+}
+
+// customRegistration is an operation registered with RegisterCustomOperation.
+type customRegistration struct {
+	priority int
+	op       Operation
+}
+
+// RegisterCustomOperation adds op to the plan of every sync of this client
+// that applies configuration changes, e.g. to call an external API as part of
+// the sync.
+//
+// The operation runs in the configuration transaction, among the generated
+// operations of its Type(). Deletes and creates are ordered by priority like
+// them, after the generated operations of the same priority (see the
+// sections.Priority* constants). Generated updates run in no particular
+// order, so custom updates run after all of them, ordered by priority among
+// themselves. Effects outside the Dataplane API are not discarded with the
+// transaction, so operations implementing RollbackOperation are rolled back,
+// in reverse order, when the transaction is retried after a version conflict
+// or fails. The raw fallback is skipped for syncs running custom operations,
+// since a raw push cannot run them.
+//
+// Custom operations only run if the sync opens a configuration transaction,
+// i.e. if the desired configuration differs from the current one. Syncs
+// without configuration changes, including syncs that only update auxiliary
+// files, do not run them.
+//
+// Register custom operations before syncing; operations registered during a
+// sync take effect from the next one.
+func (c *Client) RegisterCustomOperation(priority int, op Operation) {
+	c.orch.customMu.Lock()
+	defer c.orch.customMu.Unlock()
+
+	c.orch.customOps = append(c.orch.customOps, customRegistration{priority: priority, op: op})
+}
+
+// customOperation runs a registered operation at its registered priority and
+// records successful executions for rollback.
+type customOperation struct {
+	Operation
+	priority int
+	state    *syncState
+}
+
+func (op *customOperation) Priority() int { return op.priority }
+
+func (op *customOperation) ParentSection() string { return sections.ParentSectionOf(op.Operation) }
+
+func (op *customOperation) Name() string { return sections.NameOf(op.Operation) }
+
+func (op *customOperation) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
+	if err := op.Operation.Execute(ctx, c, txID); err != nil {
+		return err
+	}
+	if rollback, ok := op.Operation.(RollbackOperation); ok {
+		op.state.customExecuted = append(op.state.customExecuted, rollback)
+	}
+	return nil
+}
+
+// insertCustomOperations adds the registered custom operations to ops, which
+// must be ordered like comparator.OrderOperations orders them, and sets
+// state.customOperations. Nothing is added if ops is empty, since no
+// transaction is opened for the custom operations to run in.
+func (o *orchestrator) insertCustomOperations(ops []comparator.Operation, state *syncState) []comparator.Operation {
+	o.customMu.Lock()
+	registered := o.customOps
+	o.customMu.Unlock()
+
+	if len(registered) == 0 {
+		return ops
+	}
+	if len(ops) == 0 {
+		o.logger.Debug("No configuration changes, skipping custom operations",
+			"custom_operations", len(registered))
+		return ops
+	}
+
+	for _, reg := range registered {
+		custom := &customOperation{Operation: reg.op, priority: reg.priority, state: state}
+		ops = insertByPriority(ops, custom)
+	}
+	state.customOperations = true
+	return ops
+}
+
+// insertByPriority inserts op after the operations that run before it:
+// deletes first, by descending priority, then creates, by ascending priority,
+// then updates. Generated updates are not ordered by priority, so a custom
+// update goes after all of them and after the custom updates of lower or
+// equal priority. The order of the other operations is kept.
+func insertByPriority(ops []comparator.Operation, op comparator.Operation) []comparator.Operation {
+	rank := func(o comparator.Operation) int {
+		switch o.Type() {
+		case sections.OperationDelete:
+			return 0
+		case sections.OperationCreate:
+			return 1
+		default:
+			return 2
+		}
+	}
+	runsBefore := func(o comparator.Operation) bool {
+		if rank(o) != rank(op) {
+			return rank(o) < rank(op)
+		}
+		switch op.Type() {
+		case sections.OperationDelete:
+			return o.Priority() >= op.Priority()
+		case sections.OperationCreate:
+			return o.Priority() <= op.Priority()
+		}
+		if _, custom := o.(*customOperation); !custom {
+			return true
+		}
+		return o.Priority() <= op.Priority()
+	}
+
+	i := len(ops)
+	for j, o := range ops {
+		if !runsBefore(o) {
+			i = j
+			break
+		}
+	}
+	return append(ops[:i:i], append([]comparator.Operation{op}, ops[i:]...)...)
+}
+
+// rollbackCustomOperations rolls back the custom operations executed so far,
+// in reverse order. Rollbacks also run if ctx was canceled, since the sync
+// failing that way is one reason to roll back. Failures are recorded as warnings.
+func (o *orchestrator) rollbackCustomOperations(ctx context.Context, state *syncState) {
+	executed := state.customExecuted
+	state.customExecuted = nil
+	if len(executed) == 0 {
+		return
+	}
+
+	rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	for i := len(executed) - 1; i >= 0; i-- {
+		op := executed[i]
+		if err := op.Rollback(rollbackCtx); err != nil {
+			o.logger.Warn("Failed to roll back custom operation",
+				"operation", op.Describe(),
+				"error", err)
+			state.warn(WarningRollbackFailed, "failed to roll back custom operation %q: %v", op.Describe(), err)
+		}
+	}
+}
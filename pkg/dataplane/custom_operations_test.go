@@ -0,0 +1,164 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/dataplane/auxiliaryfiles"
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// recordingOperation is a custom create operation that records the requests
+// the fake Dataplane API received before it ran and whether it was rolled back.
+type recordingOperation struct {
+	api        *fakeDataplaneAPI
+	seen       []string
+	executed   int
+	rolledBack int
+}
+
+func (op *recordingOperation) Type() sections.OperationType { return sections.OperationCreate }
+func (op *recordingOperation) Section() string              { return "external" }
+func (op *recordingOperation) Priority() int                { return 0 }
+func (op *recordingOperation) Describe() string             { return "Notify external system" }
+func (op *recordingOperation) Target() string               { return "notify" }
+func (op *recordingOperation) Model() any                   { return nil }
+
+func (op *recordingOperation) Execute(context.Context, *client.DataplaneClient, string) error {
+	op.executed++
+	op.seen = op.api.Requests()
+	return nil
+}
+
+func (op *recordingOperation) Rollback(context.Context) error {
+	op.rolledBack++
+	return nil
+}
+
+// stubOperation is an operation that only reports its type, section and
+// priority.
+type stubOperation struct {
+	typ      sections.OperationType
+	section  string
+	priority int
+}
+
+func (op *stubOperation) Type() sections.OperationType { return op.typ }
+func (op *stubOperation) Section() string              { return op.section }
+func (op *stubOperation) Priority() int                { return op.priority }
+func (op *stubOperation) Describe() string             { return op.section }
+func (op *stubOperation) Target() string               { return op.section }
+func (op *stubOperation) Model() any                   { return nil }
+
+func (op *stubOperation) Execute(context.Context, *client.DataplaneClient, string) error {
+	return nil
+}
+
+func TestInsertByPriority(t *testing.T) {
+	ops := []comparator.Operation{
+		&stubOperation{typ: sections.OperationDelete, section: "server", priority: sections.PriorityServer},
+		&stubOperation{typ: sections.OperationCreate, section: "backend", priority: sections.PriorityBackend},
+		&stubOperation{typ: sections.OperationUpdate, section: "server", priority: sections.PriorityServer},
+		&stubOperation{typ: sections.OperationUpdate, section: "global", priority: sections.PriorityGlobal},
+	}
+	custom := func(typ sections.OperationType, section string, priority int) comparator.Operation {
+		return &customOperation{Operation: &stubOperation{typ: typ, section: section, priority: priority}, priority: priority}
+	}
+
+	ops = insertByPriority(ops, custom(sections.OperationUpdate, "custom-late", sections.PriorityServer))
+	ops = insertByPriority(ops, custom(sections.OperationUpdate, "custom-early", sections.PriorityGlobal))
+	ops = insertByPriority(ops, custom(sections.OperationCreate, "custom-create", sections.PriorityBackend+1))
+
+	order := make([]string, 0, len(ops))
+	for _, op := range ops {
+		order = append(order, op.Section())
+	}
+	assert.Equal(t, []string{"server", "backend", "custom-create", "server", "global", "custom-early", "custom-late"}, order)
+}
+
+func TestClient_RegisterCustomOperation(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	t.Run("runs between the operations of lower and higher priority", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		op := &recordingOperation{api: api}
+		c.RegisterCustomOperation(sections.PriorityBackend+1, op)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, op.executed)
+		assert.Zero(t, op.rolledBack)
+		assert.Contains(t, op.seen, "POST /services/haproxy/configuration/backends")
+		assert.NotContains(t, op.seen, "POST /services/haproxy/configuration/backends/api/servers")
+		assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/backends/api/servers")
+
+		sectionsApplied := make([]string, 0, len(result.AppliedOperations))
+		for _, applied := range result.AppliedOperations {
+			sectionsApplied = append(sectionsApplied, applied.Section)
+		}
+		assert.Equal(t, []string{"backend", "external", "server"}, sectionsApplied)
+	})
+
+	t.Run("is rolled back when the transaction fails", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.failRequest = "POST /services/haproxy/configuration/backends/api/servers"
+		op := &recordingOperation{api: api}
+		c.RegisterCustomOperation(sections.PriorityBackend+1, op)
+
+		opts := DefaultSyncOptions()
+		opts.FallbackToRaw = false
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+
+		assert.Equal(t, 1, op.executed)
+		assert.Equal(t, 1, op.rolledBack)
+	})
+
+	t.Run("does not run without configuration changes", func(t *testing.T) {
+		c, api := newTestClient(t, desired)
+		op := &recordingOperation{api: api}
+		c.RegisterCustomOperation(sections.PriorityBackend+1, op)
+
+		_, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+		assert.Zero(t, op.executed)
+	})
+
+	t.Run("does not run when only auxiliary files change", func(t *testing.T) {
+		c, api := newTestClient(t, desired)
+		api.mapFiles = map[string]string{"hosts.map": "a.example.com 1\n"}
+		op := &recordingOperation{api: api}
+		c.RegisterCustomOperation(sections.PriorityBackend+1, op)
+
+		auxFiles := &AuxiliaryFiles{MapFiles: []auxiliaryfiles.MapFile{{Path: "hosts.map", Content: "a.example.com 2\n"}}}
+		_, err := c.Sync(context.Background(), desired, auxFiles, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "a.example.com 2\n", api.mapFiles["hosts.map"])
+		assert.Zero(t, op.executed)
+	})
+}
@@ -153,8 +153,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"sync"
+	"time"
 
 	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/parser"
 )
 
 // Client manages a persistent connection to the HAProxy Dataplane API.
@@ -181,6 +185,20 @@ type Client struct {
 
 	// orchestrator handles internal sync logic
 	orch *orchestrator
+
+	// breaker short-circuits syncs after repeated failures (nil if disabled)
+	breaker *circuitBreaker
+
+	// httpClient is the HTTP client of the endpoint (nil if the default client is used)
+	httpClient *http.Client
+
+	// mu guards closed and inFlight
+	mu       sync.Mutex
+	closed   bool
+	inFlight int
+
+	// drained is closed once Close was called and no operation is in flight
+	drained chan struct{}
 }
 
 // NewClient creates a new Client for the given endpoint.
@@ -205,16 +223,31 @@ func NewClient(ctx context.Context, endpoint *Endpoint) (*Client, error) {
 	// Create logger with pod context
 	logger := slog.Default().With("pod", endpoint.PodName)
 
+	tlsConfig, err := endpoint.tlsConfig()
+	if err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration for %s: %w", endpoint.URL, err)
+	}
+
+	credentials, err := endpoint.CredentialsFunc()
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for %s: %w", endpoint.URL, err)
+	}
+
+	httpClient := endpoint.httpClient(tlsConfig)
+
 	// Create dataplane client
 	// Pass cached version info to avoid redundant /v3/info calls
 	c, err := client.NewFromEndpoint(ctx, &client.Endpoint{
 		URL:                endpoint.URL,
 		Username:           endpoint.Username,
 		Password:           endpoint.Password,
+		Credentials:        credentials,
 		PodName:            endpoint.PodName,
 		CachedMajorVersion: endpoint.DetectedMajorVersion,
 		CachedMinorVersion: endpoint.DetectedMinorVersion,
 		CachedFullVersion:  endpoint.DetectedFullVersion,
+		HTTPClient:         httpClient,
+		TLSConfig:          tlsConfig,
 	}, logger)
 	if err != nil {
 		return nil, NewConnectionError(endpoint.URL, err)
@@ -226,19 +259,93 @@ func NewClient(ctx context.Context, endpoint *Endpoint) (*Client, error) {
 		return nil, fmt.Errorf("failed to create orchestrator: %w", err)
 	}
 
+	var breaker *circuitBreaker
+	if endpoint.CircuitBreaker != nil {
+		breaker = newCircuitBreaker(endpoint.CircuitBreaker)
+	}
+
 	return &Client{
-		Endpoint: *endpoint,
-		orch:     orch,
+		Endpoint:   *endpoint,
+		orch:       orch,
+		breaker:    breaker,
+		httpClient: httpClient,
+		drained:    make(chan struct{}),
 	}, nil
 }
 
-// Close cleans up client resources.
-// Currently a no-op, but provided for future resource cleanup needs.
+// CircuitState returns the state of the client's circuit breaker.
+// Clients without a circuit breaker are always CircuitClosed.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.currentState()
+}
+
+// Close stops the client from starting new operations, waits for the ones in
+// flight and closes idle connections.
+//
+// Operations started after Close fail with ErrClientClosed. Close waits up to
+// Endpoint.CloseTimeout (default: 30 seconds) and returns an error if
+// operations are still running by then. Calling Close again is a no-op.
 func (c *Client) Close() error {
-	// Future: close HTTP connections, cleanup resources
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	if c.inFlight == 0 {
+		close(c.drained)
+	}
+	c.mu.Unlock()
+
+	timeout := c.Endpoint.CloseTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-c.drained:
+	case <-timer.C:
+		c.mu.Lock()
+		err = fmt.Errorf("%d operations still in flight after %v", c.inFlight, timeout)
+		c.mu.Unlock()
+	}
+
+	if c.httpClient != nil {
+		c.httpClient.CloseIdleConnections()
+	}
+	return err
+}
+
+// begin registers an operation with the client, failing once it is closed.
+// Every successful call must be paired with a call to end.
+func (c *Client) begin() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return ErrClientClosed
+	}
+	c.inFlight++
 	return nil
 }
 
+// end unregisters an operation registered with begin.
+func (c *Client) end() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.inFlight--
+	if c.closed && c.inFlight == 0 {
+		close(c.drained)
+	}
+}
+
 // Sync synchronizes the desired HAProxy configuration using this client.
 //
 // This method:
@@ -256,9 +363,14 @@ func (c *Client) Close() error {
 //   - opts: Sync options (use nil for defaults)
 //
 // Returns:
-//   - *SyncResult: Detailed information about the sync operation
+//   - *SyncResult: Detailed information about the sync operation; also returned
+//     alongside the error if the sync failed after changes were committed
 //   - error: Detailed error with actionable hints if the sync fails
 //
+// If the endpoint configures a CircuitBreaker, repeated failures open the
+// circuit and further syncs fail with a CircuitOpenError until the cooldown
+// has elapsed.
+//
 // Example:
 //
 //	client, err := dataplane.NewClient(ctx, endpoint)
@@ -274,6 +386,11 @@ func (c *Client) Close() error {
 //
 //	fmt.Printf("Applied %d operations in %v\n", len(result.AppliedOperations), result.Duration)
 func (c *Client) Sync(ctx context.Context, desiredConfig string, auxFiles *AuxiliaryFiles, opts *SyncOptions) (*SyncResult, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	// Use default options if none provided
 	if opts == nil {
 		opts = DefaultSyncOptions()
@@ -291,8 +408,42 @@ func (c *Client) Sync(ctx context.Context, desiredConfig string, auxFiles *Auxil
 		defer cancel()
 	}
 
-	// Execute sync
-	return c.orch.sync(ctx, desiredConfig, opts, auxFiles)
+	if c.breaker == nil {
+		return c.orch.sync(ctx, desiredConfig, opts, auxFiles)
+	}
+
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.orch.sync(ctx, desiredConfig, opts, auxFiles)
+	c.breaker.record(err)
+	return result, err
+}
+
+// SyncFromModel synchronizes a configuration built in Go, e.g. with the
+// builder package, instead of a rendered configuration string.
+//
+// The model is rendered with parser.Serialize and then synced like with Sync,
+// so it is limited to the sections Serialize supports.
+//
+// Example:
+//
+//	desired, err := builder.New().
+//	    WithGlobal(global).
+//	    AddBackend("web", "http").
+//	    AddServer("web", "s1", "10.0.0.1", 80).
+//	    Build()
+//	if err != nil {
+//	    return err
+//	}
+//	result, err := client.SyncFromModel(ctx, desired, nil, nil)
+func (c *Client) SyncFromModel(ctx context.Context, desired *parser.StructuredConfig, auxFiles *AuxiliaryFiles, opts *SyncOptions) (*SyncResult, error) {
+	desiredConfig, err := parser.Serialize(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render configuration model: %w", err)
+	}
+
+	return c.Sync(ctx, desiredConfig, auxFiles, opts)
 }
 
 // DryRun previews what changes would be applied without actually applying them.
@@ -336,6 +487,11 @@ func (c *Client) Sync(ctx context.Context, desiredConfig string, auxFiles *Auxil
 //	    }
 //	}
 func (c *Client) DryRun(ctx context.Context, desiredConfig string) (*DiffResult, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.end()
+
 	return c.orch.diff(ctx, desiredConfig)
 }
 
@@ -372,6 +528,139 @@ func (c *Client) Diff(ctx context.Context, desiredConfig string) (*DiffResult, e
 	return c.DryRun(ctx, desiredConfig)
 }
 
+// ValidateRemote checks the desired configuration against the live Dataplane API
+// without applying it.
+//
+// Unlike DryRun, which only compares configurations locally, it stages the
+// planned operations in a transaction, lets the Dataplane API validate the
+// resulting configuration with HAProxy and then deletes the transaction. This
+// catches errors that only the API or HAProxy detect, such as unknown
+// directives or invalid references.
+//
+// Returns the planned changes if the configuration is valid. A rejected
+// configuration is reported as a *SyncError wrapping a *ValidationError whose
+// Phase is "operation" (an operation was rejected) or "semantic" (HAProxy
+// rejected the resulting configuration).
+func (c *Client) ValidateRemote(ctx context.Context, desiredConfig string) (*DiffResult, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	return c.orch.validateRemote(ctx, desiredConfig)
+}
+
+// ReloadStatus describes the state of an HAProxy reload.
+// This type is re-exported from pkg/dataplane/client for convenience.
+type ReloadStatus = client.ReloadStatus
+
+// Capabilities returns the features supported by the connected Dataplane API.
+func (c *Client) Capabilities() Capabilities {
+	return c.orch.client.Capabilities()
+}
+
+// GetConfigVersion returns the current configuration version of the Dataplane API.
+//
+// The version increases with every committed change, so callers can snapshot it
+// and compare it later to detect changes made outside of this client, e.g.
+// before a subsequent Sync.
+//
+// Example:
+//
+//	before, err := client.GetConfigVersion(ctx)
+//	// ... inspect the configuration ...
+//	after, err := client.GetConfigVersion(ctx)
+//	if after != before {
+//	    // the configuration was changed in the meantime
+//	}
+func (c *Client) GetConfigVersion(ctx context.Context) (int64, error) {
+	if err := c.begin(); err != nil {
+		return 0, err
+	}
+	defer c.end()
+
+	return c.orch.client.GetVersion(ctx)
+}
+
+// reloadPollInterval is how often WaitForReload checks the reload state.
+const reloadPollInterval = 100 * time.Millisecond
+
+// WaitForReload blocks until the reload with the given ID (SyncResult.ReloadID)
+// has finished, and returns its final state.
+//
+// Returns an error if the reload failed or ctx is done first.
+func (c *Client) WaitForReload(ctx context.Context, reloadID string) (*ReloadStatus, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	return c.orch.client.WaitForReload(ctx, reloadID, reloadPollInterval)
+}
+
+// Rollback restores the configuration captured in snapshot by syncing it as
+// the desired configuration with default options.
+//
+// Only the differences to the live configuration are applied, so rolling back
+// right after the sync that captured the snapshot undoes just its changes.
+// Auxiliary files are not part of the snapshot and are left as they are.
+//
+// Example:
+//
+//	opts := dataplane.DefaultSyncOptions()
+//	opts.CaptureRollbackSnapshot = true
+//	result, err := client.Sync(ctx, desiredConfig, nil, opts)
+//	...
+//	if !healthy() && result.RollbackSnapshot != nil {
+//	    _, err = client.Rollback(ctx, result.RollbackSnapshot)
+//	}
+func (c *Client) Rollback(ctx context.Context, snapshot *ConfigSnapshot) (*SyncResult, error) {
+	if snapshot == nil || snapshot.Config == "" {
+		return nil, fmt.Errorf("rollback requires a configuration snapshot")
+	}
+
+	return c.Sync(ctx, snapshot.Config, nil, nil)
+}
+
+// Reload forces an HAProxy reload without a configuration change, e.g. after
+// certificates were rotated outside of Sync.
+//
+// The current configuration is pushed back unchanged, which makes the Dataplane
+// API reload HAProxy. With wait set, Reload blocks until the reload finished
+// and returns an error if it failed; otherwise it returns as soon as the reload
+// is scheduled. If the Dataplane API did not need to reload, the result has
+// ReloadTriggered set to false and there is nothing to wait for.
+//
+// Example:
+//
+//	result, err := client.Reload(ctx, true)
+//	if err != nil {
+//	    return fmt.Errorf("reload failed: %w", err)
+//	}
+func (c *Client) Reload(ctx context.Context, wait bool) (*ReloadResult, error) {
+	if err := c.begin(); err != nil {
+		return nil, err
+	}
+	defer c.end()
+
+	reloadID, err := c.orch.client.Reload(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReloadResult{
+		ReloadTriggered: reloadID != "",
+		ReloadID:        reloadID,
+	}
+	if !wait || !result.ReloadTriggered {
+		return result, nil
+	}
+
+	status, err := c.orch.client.WaitForReload(ctx, reloadID, reloadPollInterval)
+	result.Status = status
+	return result, err
+}
+
 // Package-level convenience functions for simple one-off operations.
 // These create a client internally for each call.
 // For multiple operations, create a Client explicitly to reuse connections.
@@ -448,3 +737,43 @@ func DryRun(ctx context.Context, endpoint *Endpoint, desiredConfig string) (*Dif
 func Diff(ctx context.Context, endpoint *Endpoint, desiredConfig string) (*DiffResult, error) {
 	return DryRun(ctx, endpoint, desiredConfig)
 }
+
+// DiffConfigs compares two configuration strings offline and returns the operations
+// needed to transform currentConfig into desiredConfig.
+//
+// Unlike DryRun, no Dataplane API is contacted: both configurations are parsed
+// locally and compared with the same comparator used by Sync. This is useful for
+// reviewing configuration changes, e.g. in pull requests.
+//
+// Parameters:
+//   - currentConfig: The baseline HAProxy configuration
+//   - desiredConfig: The HAProxy configuration to compare against the baseline
+//
+// Returns:
+//   - *DiffResult: Detailed information about the differences
+//   - error: Error if parsing or comparison fails
+//
+// Example:
+//
+//	diff, err := dataplane.DiffConfigs(oldConfig, newConfig)
+//	if err != nil {
+//	    return fmt.Errorf("diff failed: %w", err)
+//	}
+//
+//	for _, op := range diff.PlannedOperations {
+//	    fmt.Printf("  - %s\n", op.Description)
+//	}
+func DiffConfigs(currentConfig, desiredConfig string) (*DiffResult, error) {
+	// Parsing and comparison never use the Dataplane client
+	orch, err := newOrchestrator(nil, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create orchestrator: %w", err)
+	}
+
+	diff, err := orch.parseAndCompareConfigs(currentConfig, desiredConfig, DryRunOptions(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDiffResult(diff), nil
+}
@@ -0,0 +1,90 @@
+package dataplane
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToDOT renders the planned operations as a Graphviz DOT graph.
+//
+// Operations are grouped into stages that execute one after another: deletes
+// by descending priority, creates by ascending priority, then all updates.
+// Each stage is a cluster containing one node per operation, and edges between
+// consecutive clusters show the execution order. The output is derived purely
+// from the diff and can be rendered with e.g. `dot -Tsvg`.
+func (r *DiffResult) ToDOT() string {
+	var b strings.Builder
+
+	b.WriteString("digraph operations {\n")
+	b.WriteString("  rankdir=TB;\n")
+	b.WriteString("  compound=true;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+
+	stages := planStages(r.PlannedOperations)
+	for i, stage := range stages {
+		fmt.Fprintf(&b, "\n  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%s;\n", dotQuote(stage.label))
+		for _, idx := range stage.operations {
+			op := &r.PlannedOperations[idx]
+			fmt.Fprintf(&b, "    op%d [label=%s, color=%s];\n", idx, dotQuote(op.Description), dotColor(op.Type))
+		}
+		b.WriteString("  }\n")
+	}
+
+	if len(stages) > 1 {
+		b.WriteString("\n")
+	}
+	for i := 1; i < len(stages); i++ {
+		fmt.Fprintf(&b, "  op%d -> op%d [ltail=cluster_%d, lhead=cluster_%d];\n",
+			stages[i-1].operations[0], stages[i].operations[0], i-1, i)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// planStage is a group of planned operations without ordering constraints between them.
+type planStage struct {
+	label      string
+	operations []int // indexes into PlannedOperations
+}
+
+// planStages groups planned operations, which are already in execution order,
+// into stages of the same type and priority. Updates form a single stage since
+// they run after all creates in any order.
+func planStages(ops []PlannedOperation) []planStage {
+	var stages []planStage
+
+	for i := range ops {
+		label := ops[i].Type
+		if ops[i].Type != "update" {
+			label = fmt.Sprintf("%s (priority %d)", ops[i].Type, ops[i].Priority)
+		}
+
+		if len(stages) == 0 || stages[len(stages)-1].label != label {
+			stages = append(stages, planStage{label: label})
+		}
+		last := &stages[len(stages)-1]
+		last.operations = append(last.operations, i)
+	}
+
+	return stages
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// dotColor returns the node color for an operation type.
+func dotColor(opType string) string {
+	switch opType {
+	case "create":
+		return "darkgreen"
+	case "delete":
+		return "red"
+	default:
+		return "blue"
+	}
+}
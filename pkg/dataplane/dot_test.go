@@ -0,0 +1,105 @@
+package dataplane
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertValidDOT performs a structural check of a DOT document: it must be a
+// single digraph with balanced braces, terminated strings and statements.
+func assertValidDOT(t *testing.T, dot string) {
+	t.Helper()
+
+	require.True(t, strings.HasPrefix(dot, "digraph operations {\n"), "missing digraph header")
+	require.True(t, strings.HasSuffix(dot, "}\n"), "missing closing brace")
+
+	depth := 0
+	inString := false
+	escaped := false
+	for _, c := range dot {
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case !inString && c == '{':
+			depth++
+		case !inString && c == '}':
+			depth--
+			require.GreaterOrEqual(t, depth, 0, "unbalanced braces")
+		}
+	}
+	assert.False(t, inString, "unterminated string")
+	assert.Equal(t, 0, depth, "unbalanced braces")
+
+	for _, line := range strings.Split(strings.TrimSpace(dot), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasSuffix(line, "{") || line == "}" {
+			continue
+		}
+		assert.True(t, strings.HasSuffix(line, ";"), "statement not terminated: %q", line)
+	}
+}
+
+func TestDiffResult_ToDOT(t *testing.T) {
+	diff := &DiffResult{
+		HasChanges: true,
+		PlannedOperations: []PlannedOperation{
+			{Type: "delete", Section: "acl", Resource: "old", Priority: 50, Description: "Delete ACL 'old' from frontend 'http'"},
+			{Type: "delete", Section: "backend", Resource: "legacy", Priority: 30, Description: "Delete backend 'legacy'"},
+			{Type: "create", Section: "backend", Resource: "api", Priority: 30, Description: "Create backend 'api'"},
+			{Type: "create", Section: "backend", Resource: "web", Priority: 30, Description: "Create backend 'web'"},
+			{Type: "create", Section: "server", Resource: "srv1", Priority: 40, Description: "Create server 'srv1' in backend 'api'"},
+			{Type: "update", Section: "global", Priority: 10, Description: "Update global section"},
+		},
+	}
+
+	dot := diff.ToDOT()
+	assertValidDOT(t, dot)
+
+	for i, op := range diff.PlannedOperations {
+		assert.Equal(t, 1, strings.Count(dot, fmt.Sprintf("    op%d [label=", i)), "expected one node for operation %d", i)
+		assert.Contains(t, dot, `"`+strings.ReplaceAll(op.Description, `"`, `\"`)+`"`)
+	}
+	assert.Equal(t, len(diff.PlannedOperations), strings.Count(dot, " [label="))
+
+	// Operations of the same type and priority share a stage
+	assert.Equal(t, 5, strings.Count(dot, "subgraph cluster_"))
+	assert.Contains(t, dot, `label="delete (priority 50)";`)
+	assert.Contains(t, dot, `label="create (priority 30)";`)
+	assert.Contains(t, dot, `label="update";`)
+
+	// Stages are chained in execution order
+	assert.Contains(t, dot, "op0 -> op1 [ltail=cluster_0, lhead=cluster_1];")
+	assert.Contains(t, dot, "op1 -> op2 [ltail=cluster_1, lhead=cluster_2];")
+	assert.Contains(t, dot, "op2 -> op4 [ltail=cluster_2, lhead=cluster_3];")
+	assert.Contains(t, dot, "op4 -> op5 [ltail=cluster_3, lhead=cluster_4];")
+	assert.Equal(t, 4, strings.Count(dot, " -> "))
+}
+
+func TestDiffResult_ToDOT_EscapesLabels(t *testing.T) {
+	diff := &DiffResult{
+		HasChanges: true,
+		PlannedOperations: []PlannedOperation{
+			{Type: "create", Section: "http_request_rule", Priority: 60, Description: `Create rule "set-header X-Path \path"`},
+		},
+	}
+
+	dot := diff.ToDOT()
+	assertValidDOT(t, dot)
+	assert.Contains(t, dot, `label="Create rule \"set-header X-Path \\path\""`)
+}
+
+func TestDiffResult_ToDOT_NoChanges(t *testing.T) {
+	dot := (&DiffResult{}).ToDOT()
+
+	assertValidDOT(t, dot)
+	assert.NotContains(t, dot, "subgraph")
+	assert.NotContains(t, dot, "->")
+}
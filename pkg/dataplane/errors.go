@@ -1,16 +1,22 @@
 package dataplane
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
+// ErrClientClosed is returned by operations started after Client.Close or
+// PoolClient.Close.
+var ErrClientClosed = errors.New("dataplane client is closed")
+
 // SyncError represents a synchronization failure with actionable context.
 // It provides detailed information about what stage failed and suggestions
 // for how to fix the problem.
 type SyncError struct {
 	// Stage indicates where the failure occurred:
-	// "connect", "fetch", "parse-current", "parse-desired", "compare", "apply", "commit", "fallback"
+	// "options", "connect", "circuit", "fetch", "parse-current", "parse-desired", "normalize", "compare", "drift", "apply", "commit", "fallback", "validate"
 	Stage string
 
 	// Message provides a detailed error description
@@ -154,6 +160,61 @@ func (e *OperationError) Unwrap() error {
 	return e.Cause
 }
 
+// PartialSyncError represents operations that failed or were skipped during
+// a sync with ContinueOnError, after the remaining operations were applied.
+type PartialSyncError struct {
+	// Failed lists the failed and skipped operations in execution order
+	Failed []FailedOperation
+}
+
+// Error implements the error interface.
+func (e *PartialSyncError) Error() string {
+	parts := make([]string, 0, len(e.Failed))
+	for _, op := range e.Failed {
+		parts = append(parts, fmt.Sprintf("%s: %v", op.Description, op.Error))
+	}
+	return fmt.Sprintf("%d operations failed: %s", len(e.Failed), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the failed operations for error unwrapping.
+func (e *PartialSyncError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, op := range e.Failed {
+		errs = append(errs, op.Error)
+	}
+	return errs
+}
+
+// DriftError represents resources in the HAProxy configuration that the
+// controller does not manage and a sync would delete.
+type DriftError struct {
+	// Resources describes the drifted resources (e.g. "backend 'legacy'")
+	Resources []string
+}
+
+// Error implements the error interface.
+func (e *DriftError) Error() string {
+	return fmt.Sprintf("unmanaged configuration found: %s", strings.Join(e.Resources, ", "))
+}
+
+// CircuitOpenError represents a sync rejected by an open circuit breaker.
+type CircuitOpenError struct {
+	// Failures is the number of failed syncs that opened the circuit
+	Failures int
+
+	// RetryAfter is the remaining cooldown before a probe sync is let through
+	// (zero while a probe is in flight)
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("circuit breaker open after %d failed syncs, retry in %v", e.Failures, e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("circuit breaker open after %d failed syncs, probe sync in progress", e.Failures)
+}
+
 // FallbackError represents a failure during raw config fallback.
 type FallbackError struct {
 	// OriginalError is the error that triggered the fallback
@@ -227,6 +288,38 @@ func NewValidationError(message string, cause error) *SyncError {
 	}
 }
 
+// NewRemoteValidationError creates a ValidationError for a configuration the
+// Dataplane API rejected during remote validation.
+//
+// Phase is "operation" if an operation was rejected while staging the changes,
+// or "semantic" if HAProxy rejected the resulting configuration.
+func NewRemoteValidationError(phase, message string, cause error) *SyncError {
+	return &SyncError{
+		Stage:   "validate",
+		Message: "Dataplane API rejected the configuration",
+		Cause:   &ValidationError{Phase: phase, Message: message, Err: cause},
+		Hints: []string{
+			"Review the validation error message from the Dataplane API",
+			"Check for references to non-existent backends or servers",
+			"Verify all directives are compatible with your HAProxy version",
+		},
+	}
+}
+
+// NewDriftError creates a DriftError for the given drifted resources.
+func NewDriftError(resources []string) *SyncError {
+	return &SyncError{
+		Stage:   "drift",
+		Message: fmt.Sprintf("%d unmanaged resources would be deleted", len(resources)),
+		Cause:   &DriftError{Resources: resources},
+		Hints: []string{
+			"Remove the listed resources from HAProxy or add them to the templates",
+			"Check whether the IsManaged predicate matches all resources the controller produces",
+			"Disable FailOnDrift to let the sync remove unmanaged resources",
+		},
+	}
+}
+
 // NewConflictError creates a ConflictError.
 func NewConflictError(retries int, expectedVersion int64, actualVersion string) *SyncError {
 	return &SyncError{
@@ -242,6 +335,36 @@ func NewConflictError(retries int, expectedVersion int64, actualVersion string)
 	}
 }
 
+// NewRetryBudgetError creates an error for a sync that used up its retry
+// budget of budget retries in the given stage.
+func NewRetryBudgetError(stage string, budget int, cause error) *SyncError {
+	return &SyncError{
+		Stage:   stage,
+		Message: fmt.Sprintf("retry budget of %d retries exhausted", budget),
+		Cause:   cause,
+		Hints: []string{
+			"Check if other processes are modifying the HAProxy configuration concurrently",
+			"Verify the dataplane API is reachable and not overloaded",
+			"Consider increasing RetryBudget in SyncOptions",
+		},
+	}
+}
+
+// NewTransactionLimitError creates an error for a transaction the Dataplane
+// API refused to start because too many transactions are open.
+func NewTransactionLimitError(cause error) *SyncError {
+	return &SyncError{
+		Stage:   "apply",
+		Message: "too many open transactions in the Dataplane API",
+		Cause:   cause,
+		Hints: []string{
+			"Enable CleanupStaleTransactions in SyncOptions to delete transactions left behind by interrupted syncs",
+			"Raise max_open_transactions in the Dataplane API configuration",
+			"Check if other clients leave transactions open",
+		},
+	}
+}
+
 // NewOperationError creates an OperationError.
 func NewOperationError(opType, section, resource string, cause error) *SyncError {
 	return &SyncError{
@@ -256,6 +379,33 @@ func NewOperationError(opType, section, resource string, cause error) *SyncError
 	}
 }
 
+// NewPartialSyncError creates a PartialSyncError for the given failed operations.
+func NewPartialSyncError(failed []FailedOperation) *SyncError {
+	return &SyncError{
+		Stage:   "apply",
+		Message: fmt.Sprintf("%d configuration operations failed, the others were applied", len(failed)),
+		Cause:   &PartialSyncError{Failed: failed},
+		Hints: []string{
+			"Review SyncResult.FailedOperations for the cause of each failure",
+			"Skipped operations depend on a failed one and are retried on the next sync",
+		},
+	}
+}
+
+// NewCircuitOpenError creates a CircuitOpenError.
+func NewCircuitOpenError(failures int, retryAfter time.Duration) *SyncError {
+	return &SyncError{
+		Stage:   "circuit",
+		Message: "sync skipped because previous syncs kept failing",
+		Cause:   &CircuitOpenError{Failures: failures, RetryAfter: retryAfter},
+		Hints: []string{
+			"Review the errors of the previous syncs to find the cause",
+			"The next sync after the cooldown is attempted as a probe",
+			"Adjust Endpoint.CircuitBreaker to change the threshold or cooldown",
+		},
+	}
+}
+
 // NewFallbackError creates a FallbackError.
 func NewFallbackError(originalErr, fallbackCause error) *SyncError {
 	return &SyncError{
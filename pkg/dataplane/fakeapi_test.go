@@ -0,0 +1,406 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeDataplaneAPI is a minimal in-memory Dataplane API for sync tests.
+//
+// It serves a fixed raw configuration, accepts every transaction and
+// fine-grained operation, and records the requests it receives.
+type fakeDataplaneAPI struct {
+	server *httptest.Server
+
+	mu            sync.Mutex
+	currentConfig string
+	requests      []string
+	bodies        map[string]string
+	queries       map[string]string
+
+	// runtimeUnavailable makes changes outside of a transaction fail the way the
+	// Dataplane API does when the Runtime API socket is missing.
+	runtimeUnavailable bool
+
+	// validationError makes configuration validation requests fail with this
+	// HAProxy output.
+	validationError string
+
+	// failRequest makes requests with this "METHOD /path" line fail with a 500.
+	failRequest string
+
+	// runtimeChangeLimit makes changes outside of a transaction fail with a 500
+	// once this many have succeeded (0 means no limit).
+	runtimeChangeLimit int
+	runtimeChanges     int
+
+	// rawPushWithoutReload makes raw configuration pushes succeed without
+	// scheduling a reload.
+	rawPushWithoutReload bool
+
+	// configVersion is the reported configuration version (1 if unset).
+	configVersion int64
+
+	// staleTransactions are open transactions on version 0. While any are
+	// open, starting a transaction fails with the transaction limit error.
+	staleTransactions []string
+
+	// transactionConflicts and commitConflicts are the number of transaction
+	// starts and commits that fail with a version conflict.
+	transactionConflicts int
+	commitConflicts      int
+
+	// mapFiles are the stored map files by name. Storage updates replace them,
+	// and force-synced runtime entry changes rewrite them the way HAProxy
+	// writes its in-memory map back: without comments, added keys last.
+	mapFiles map[string]string
+
+	// password makes requests without this basic auth password fail with a 401
+	// (any password is accepted if unset).
+	password string
+
+	// runtimeServers are the Runtime API server lists by backend, as JSON.
+	runtimeServers map[string]string
+}
+
+// newFakeDataplaneAPI starts a fake Dataplane API serving currentConfig.
+func newFakeDataplaneAPI(t *testing.T, currentConfig string) *fakeDataplaneAPI {
+	t.Helper()
+
+	api := &fakeDataplaneAPI{currentConfig: currentConfig, bodies: make(map[string]string), queries: make(map[string]string)}
+	api.server = httptest.NewServer(http.HandlerFunc(api.handle))
+	t.Cleanup(api.server.Close)
+
+	return api
+}
+
+// endpoint returns an Endpoint pointing at the fake API.
+func (f *fakeDataplaneAPI) endpoint() *Endpoint {
+	return &Endpoint{
+		URL:      f.server.URL,
+		Username: "admin",
+		Password: "password",
+		PodName:  "haproxy-0",
+	}
+}
+
+// setPassword changes the basic auth password the fake API accepts.
+func (f *fakeDataplaneAPI) setPassword(password string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.password = password
+}
+
+// setConfig replaces the configuration the fake API serves, e.g. to simulate
+// fine-grained changes, which the fake API does not apply.
+func (f *fakeDataplaneAPI) setConfig(config string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.currentConfig = config
+}
+
+// Requests returns the "METHOD /path" lines of all requests received so far.
+func (f *fakeDataplaneAPI) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make([]string, len(f.requests))
+	copy(result, f.requests)
+	return result
+}
+
+// RequestBody returns the body of the last request with the given "METHOD /path" line.
+func (f *fakeDataplaneAPI) RequestBody(request string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.bodies[request]
+}
+
+// RequestQuery returns the raw query of the last request with the given "METHOD /path" line.
+func (f *fakeDataplaneAPI) RequestQuery(request string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.queries[request]
+}
+
+func (f *fakeDataplaneAPI) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	f.bodies[r.Method+" "+r.URL.Path] = string(body)
+	f.queries[r.Method+" "+r.URL.Path] = r.URL.RawQuery
+	runtimeChangeRejected := false
+	if r.Method == http.MethodPut && r.URL.Query().Get("transaction_id") == "" &&
+		strings.HasPrefix(r.URL.Path, "/services/haproxy/configuration/") && f.runtimeChangeLimit > 0 {
+		runtimeChangeRejected = f.runtimeChanges >= f.runtimeChangeLimit
+		f.runtimeChanges++
+	}
+	_, password, _ := r.BasicAuth()
+	unauthorized := f.password != "" && password != f.password
+	f.mu.Unlock()
+
+	switch {
+	case unauthorized:
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"code":401,"message":"invalid credentials"}`)
+
+	case f.failRequest == r.Method+" "+r.URL.Path || runtimeChangeRejected:
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"code":500,"message":"injected failure"}`)
+
+	case r.URL.Path == "/v3/info":
+		fmt.Fprintln(w, `{"api":{"version":"v3.2.6 87ad0bcf"}}`)
+
+	case r.URL.Path == "/services/haproxy/configuration/version":
+		f.mu.Lock()
+		version := max(f.configVersion, 1)
+		f.mu.Unlock()
+		fmt.Fprintln(w, version)
+
+	case r.URL.Path == "/services/haproxy/configuration/raw" && r.Method == http.MethodGet:
+		f.mu.Lock()
+		config := f.currentConfig
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, config)
+
+	case r.URL.Path == "/services/haproxy/configuration/raw" && r.Method == http.MethodPost &&
+		r.URL.Query().Get("only_validate") == "true":
+		if f.validationError != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"code":400,"message":%q}`, f.validationError)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+
+	case r.URL.Path == "/services/haproxy/configuration/raw" && r.Method == http.MethodPost:
+		f.mu.Lock()
+		f.currentConfig = string(body)
+		f.mu.Unlock()
+		if f.rawPushWithoutReload {
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Header().Set("Reload-ID", "reload-raw")
+		w.WriteHeader(http.StatusAccepted)
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/reloads/") && r.Method == http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":%q,"status":"succeeded"}`, strings.TrimPrefix(r.URL.Path, "/services/haproxy/reloads/"))
+
+	case r.URL.Path == "/services/haproxy/storage/maps" && r.Method == http.MethodGet:
+		f.mu.Lock()
+		names := make([]string, 0, len(f.mapFiles))
+		for _, name := range slices.Sorted(maps.Keys(f.mapFiles)) {
+			names = append(names, fmt.Sprintf(`{"storage_name":%q}`, name))
+		}
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(names, ","))
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/storage/maps/") && r.Method == http.MethodGet:
+		f.mu.Lock()
+		content, ok := f.mapFiles[strings.TrimPrefix(r.URL.Path, "/services/haproxy/storage/maps/")]
+		f.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, content)
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/storage/maps/") && r.Method == http.MethodPut &&
+		!f.runtimeUnavailable:
+		f.mu.Lock()
+		if f.mapFiles != nil {
+			f.mapFiles[strings.TrimPrefix(r.URL.Path, "/services/haproxy/storage/maps/")] = string(body)
+		}
+		f.mu.Unlock()
+		fmt.Fprint(w, string(body))
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/runtime/maps/") && !f.runtimeUnavailable:
+		if r.URL.Query().Get("force_sync") == "true" {
+			f.syncRuntimeMapEntry(r.Method, r.URL.Path, body)
+		}
+		switch r.Method {
+		case http.MethodPost:
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, string(body))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			fmt.Fprint(w, string(body))
+		}
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/runtime/backends/") && strings.HasSuffix(r.URL.Path, "/servers") &&
+		r.Method == http.MethodGet:
+		backend := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/services/haproxy/runtime/backends/"), "/servers")
+		f.mu.Lock()
+		servers, ok := f.runtimeServers[backend]
+		f.mu.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, servers)
+
+	case r.URL.Path == "/services/haproxy/transactions" && r.Method == http.MethodGet:
+		f.mu.Lock()
+		transactions := make([]string, 0, len(f.staleTransactions))
+		for _, id := range f.staleTransactions {
+			transactions = append(transactions, fmt.Sprintf(`{"id":%q,"_version":0,"status":"in_progress"}`, id))
+		}
+		f.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(transactions, ","))
+
+	case r.URL.Path == "/services/haproxy/transactions" && r.Method == http.MethodPost && f.transactionLimitReached():
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"code":500,"message":"max number of opened transactions reached: 20"}`)
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/transactions/") && r.Method == http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Path, "/services/haproxy/transactions/")
+		f.mu.Lock()
+		f.staleTransactions = slices.DeleteFunc(f.staleTransactions, func(s string) bool { return s == id })
+		f.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.URL.Path == "/services/haproxy/transactions" && r.Method == http.MethodPost && f.takeConflict(&f.transactionConflicts):
+		w.Header().Set("Configuration-Version", "2")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"code":409,"message":"version mismatch"}`)
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/transactions/") && r.Method == http.MethodPut && f.takeConflict(&f.commitConflicts):
+		w.Header().Set("Configuration-Version", "2")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"code":409,"message":"version mismatch"}`)
+
+	case r.URL.Path == "/services/haproxy/transactions" && r.Method == http.MethodPost:
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"tx-1","version":1,"status":"in_progress"}`)
+
+	case strings.HasPrefix(r.URL.Path, "/services/haproxy/transactions/") && r.Method == http.MethodPut:
+		w.Header().Set("Reload-ID", "reload-1")
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, `{"id":"tx-1","status":"success"}`)
+
+	case r.Method == http.MethodPost:
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, string(body))
+
+	case r.Method == http.MethodPut && f.runtimeUnavailable && r.URL.Query().Get("transaction_id") == "":
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"code":503,"message":"runtime API not available"}`)
+
+	case r.Method == http.MethodPut:
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, string(body))
+
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// syncRuntimeMapEntry applies a runtime map entry change to the stored map
+// file, which HAProxy rewrites from its in-memory map.
+func (f *fakeDataplaneAPI) syncRuntimeMapEntry(method, path string, body []byte) {
+	name, key, _ := strings.Cut(strings.TrimPrefix(path, "/services/haproxy/runtime/maps/"), "/entries")
+	key = strings.TrimPrefix(key, "/")
+
+	var entry struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	_ = json.Unmarshal(body, &entry)
+	if entry.Key != "" {
+		key = entry.Key
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	content, ok := f.mapFiles[name]
+	if !ok {
+		return
+	}
+
+	var keys []string
+	values := make(map[string]string)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		k, v, _ := strings.Cut(line, " ")
+		keys = append(keys, k)
+		values[k] = strings.TrimSpace(v)
+	}
+
+	switch method {
+	case http.MethodPost:
+		keys = append(keys, key)
+		values[key] = entry.Value
+	case http.MethodPut:
+		values[key] = entry.Value
+	case http.MethodDelete:
+		keys = slices.DeleteFunc(keys, func(k string) bool { return k == key })
+	}
+
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s %s\n", k, values[k])
+	}
+	f.mapFiles[name] = b.String()
+}
+
+// transactionLimitReached reports whether stale transactions block new ones.
+func (f *fakeDataplaneAPI) transactionLimitReached() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return len(f.staleTransactions) > 0
+}
+
+// takeConflict reports whether a request fails with a version conflict,
+// counting down the remaining conflicts in *remaining.
+func (f *fakeDataplaneAPI) takeConflict(remaining *int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if *remaining <= 0 {
+		return false
+	}
+	*remaining--
+	return true
+}
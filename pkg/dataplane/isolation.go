@@ -0,0 +1,132 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// operationIsolation executes operations for ContinueOnError: a failing
+// operation is recorded instead of stopping the sync, and the operations that
+// depend on it are skipped.
+//
+// Dependencies are derived from operation targets ("section" or
+// "parent/child") along with the section type of the parent:
+//   - children of a section that failed to be created or deleted are skipped
+//   - index-based children (rules) of the same section and parent are skipped
+//     after one of them failed to be created or deleted, since their
+//     positions no longer line up
+type operationIsolation struct {
+	// blocked maps dependency keys to the description of the failed operation
+	blocked map[string]string
+
+	failed []FailedOperation
+}
+
+func newOperationIsolation() *operationIsolation {
+	return &operationIsolation{blocked: make(map[string]string)}
+}
+
+// execute runs ops in order within transaction txID ("" for none) and returns
+// the operations that succeeded. stop reports errors that must abort the run
+// instead of being isolated; the aborting error is returned.
+func (iso *operationIsolation) execute(ctx context.Context, c *client.DataplaneClient, ops []comparator.Operation, txID string, stop func(error) bool) ([]comparator.Operation, error) {
+	applied := make([]comparator.Operation, 0, len(ops))
+	for _, op := range ops {
+		if cause, blocked := iso.blockedBy(op); blocked {
+			iso.record(op, fmt.Errorf("skipped because %s failed", cause), true)
+			continue
+		}
+
+		if err := op.Execute(ctx, c, txID); err != nil {
+			if stop != nil && stop(err) {
+				return applied, err
+			}
+			iso.record(op, err, false)
+			continue
+		}
+		applied = append(applied, op)
+	}
+	return applied, nil
+}
+
+// blockedBy reports whether op depends on a failed operation and returns that
+// operation's description.
+func (iso *operationIsolation) blockedBy(op comparator.Operation) (string, bool) {
+	for _, key := range dependencyKeys(op) {
+		if cause, ok := iso.blocked[key]; ok {
+			return cause, true
+		}
+	}
+	return "", false
+}
+
+// record adds a failed or skipped operation and blocks its dependents.
+func (iso *operationIsolation) record(op comparator.Operation, err error, skipped bool) {
+	iso.failed = append(iso.failed, FailedOperation{
+		Type:        operationTypeToString(op.Type()),
+		Section:     op.Section(),
+		Resource:    extractResourceName(op),
+		Description: op.Describe(),
+		Error:       err,
+		Skipped:     skipped,
+	})
+
+	for _, key := range dependentKeys(op) {
+		if _, exists := iso.blocked[key]; !exists {
+			iso.blocked[key] = op.Describe()
+		}
+	}
+}
+
+// dependencyKeys returns the keys of the operations op depends on.
+func dependencyKeys(op comparator.Operation) []string {
+	parent, child, isChild := strings.Cut(op.Target(), "/")
+	if !isChild {
+		return nil
+	}
+
+	parent = sections.ParentSectionOf(op) + "/" + parent
+	keys := []string{"section:" + parent}
+	if _, err := strconv.Atoi(child); err == nil {
+		keys = append(keys, "index:"+op.Section()+":"+parent)
+	}
+	return keys
+}
+
+// dependentKeys returns the keys blocked when op fails. Failed updates block
+// nothing, since they neither leave a section missing nor shift positions.
+func dependentKeys(op comparator.Operation) []string {
+	if op.Type() == sections.OperationUpdate {
+		return nil
+	}
+
+	parent, child, isChild := strings.Cut(op.Target(), "/")
+	if !isChild {
+		return []string{"section:" + op.Section() + "/" + parent}
+	}
+
+	if _, err := strconv.Atoi(child); err == nil {
+		return []string{"index:" + op.Section() + ":" + sections.ParentSectionOf(op) + "/" + parent}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+// LintFinding is a likely mistake in a configuration that is valid syntax but
+// would probably fail to load or behave unexpectedly.
+type LintFinding struct {
+	// Section is the section the finding is in, e.g. "global"
+	Section string
+
+	// Directive is the keyword of the offending line, e.g. "ssl-default-bind-ciphers"
+	Directive string
+
+	// Message describes the problem
+	Message string
+}
+
+// String returns the finding as "section: directive: message".
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s: %s: %s", f.Section, f.Directive, f.Message)
+}
+
+// LintConfiguration checks a configuration for likely mistakes that the
+// syntax and schema validation do not catch.
+//
+// It currently checks the cipher lists of the global section
+// (ssl-default-bind-ciphers, ssl-default-bind-ciphersuites and their server
+// counterparts) against the OpenSSL cipher string grammar, e.g. for stray
+// separators. Cipher names are not checked against a cipher database.
+//
+// Returns an error only if the configuration cannot be parsed.
+func LintConfiguration(mainConfig string) ([]LintFinding, error) {
+	parsed, err := validateSyntax(mainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if parsed.Global == nil || parsed.Global.SslOptions == nil {
+		return nil, nil
+	}
+	return lintGlobalCiphers(parsed.Global.SslOptions), nil
+}
+
+// lintGlobalCiphers checks the cipher lists of the global SSL options.
+func lintGlobalCiphers(ssl *models.SslOptions) []LintFinding {
+	checks := []struct {
+		directive string
+		value     string
+		lint      func(string) []string
+	}{
+		{"ssl-default-bind-ciphers", ssl.DefaultBindCiphers, lintCipherList},
+		{"ssl-default-bind-ciphersuites", ssl.DefaultBindCiphersuites, lintCipherSuites},
+		{"ssl-default-server-ciphers", ssl.DefaultServerCiphers, lintCipherList},
+		{"ssl-default-server-ciphersuites", ssl.DefaultServerCiphersuites, lintCipherSuites},
+	}
+
+	var findings []LintFinding
+	for _, check := range checks {
+		if check.value == "" {
+			continue
+		}
+		for _, message := range check.lint(check.value) {
+			findings = append(findings, LintFinding{Section: "global", Directive: check.directive, Message: message})
+		}
+	}
+	return findings
+}
+
+// cipherNamePattern matches OpenSSL cipher names and aliases such as
+// "ECDHE-RSA-AES128-GCM-SHA256", "kEECDH" or "HIGH".
+var cipherNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// cipherSuitePattern matches TLSv1.3 cipher suite names such as "TLS_AES_128_GCM_SHA256".
+var cipherSuitePattern = regexp.MustCompile(`^TLS_[A-Z0-9_]+$`)
+
+// cipherDirectivePattern matches the special elements @STRENGTH and @SECLEVEL=n.
+var cipherDirectivePattern = regexp.MustCompile(`^@(STRENGTH|SECLEVEL=[0-5])$`)
+
+// lintCipherList checks a TLSv1.2 cipher list: elements separated by colons,
+// each an optionally prefixed (!, - or +) cipher name or combination of names
+// joined by + (e.g. "ECDHE+AESGCM"), or a directive such as @STRENGTH.
+func lintCipherList(list string) []string {
+	var problems []string
+	for i, element := range strings.Split(list, ":") {
+		switch {
+		case element == "":
+			problems = append(problems, fmt.Sprintf("empty element %d, check for stray colons in %q", i+1, list))
+		case strings.HasPrefix(element, "@"):
+			if !cipherDirectivePattern.MatchString(element) {
+				problems = append(problems, fmt.Sprintf("invalid directive %q", element))
+			}
+		default:
+			name := strings.TrimLeft(element, "!-+")
+			if len(element)-len(name) > 1 {
+				problems = append(problems, fmt.Sprintf("element %q has more than one prefix", element))
+				continue
+			}
+			for _, part := range strings.Split(name, "+") {
+				if !cipherNamePattern.MatchString(part) {
+					problems = append(problems, fmt.Sprintf("invalid cipher %q in element %q", part, element))
+					break
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// lintCipherSuites checks a TLSv1.3 cipher suite list: TLS_* names separated
+// by colons, without prefixes or aliases.
+func lintCipherSuites(list string) []string {
+	var problems []string
+	for i, suite := range strings.Split(list, ":") {
+		switch {
+		case suite == "":
+			problems = append(problems, fmt.Sprintf("empty element %d, check for stray colons in %q", i+1, list))
+		case !cipherSuitePattern.MatchString(suite):
+			problems = append(problems, fmt.Sprintf("invalid cipher suite %q, expected a TLSv1.3 name such as TLS_AES_128_GCM_SHA256", suite))
+		}
+	}
+	return problems
+}
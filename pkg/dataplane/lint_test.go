@@ -0,0 +1,63 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintConfiguration_Ciphers(t *testing.T) {
+	lint := func(t *testing.T, globals string) []LintFinding {
+		t.Helper()
+		findings, err := LintConfiguration("global\n" + globals + "\ndefaults\n    mode http\n")
+		require.NoError(t, err)
+		return findings
+	}
+
+	t.Run("accepts valid cipher strings", func(t *testing.T) {
+		findings := lint(t, `
+    ssl-default-bind-ciphers ECDHE-ECDSA-AES128-GCM-SHA256:ECDHE+AESGCM:!aNULL:-MD5:@STRENGTH
+    ssl-default-bind-ciphersuites TLS_AES_128_GCM_SHA256:TLS_CHACHA20_POLY1305_SHA256
+`)
+		assert.Empty(t, findings)
+	})
+
+	t.Run("flags a stray separator", func(t *testing.T) {
+		findings := lint(t, `
+    ssl-default-bind-ciphers ECDHE-RSA-AES128-GCM-SHA256::ECDHE-RSA-AES256-GCM-SHA384:
+`)
+		require.Len(t, findings, 2)
+		assert.Equal(t, "global", findings[0].Section)
+		assert.Equal(t, "ssl-default-bind-ciphers", findings[0].Directive)
+		assert.Contains(t, findings[0].Message, "empty element 2")
+		assert.Contains(t, findings[1].Message, "empty element 4")
+	})
+
+	t.Run("flags malformed elements", func(t *testing.T) {
+		findings := lint(t, `
+    ssl-default-bind-ciphers !!aNULL:ECDHE+:@FAST
+    ssl-default-server-ciphersuites TLS_AES_128_GCM_SHA256:!TLS_AES_256_GCM_SHA384
+`)
+		require.Len(t, findings, 4)
+		assert.Contains(t, findings[0].String(), `global: ssl-default-bind-ciphers: element "!!aNULL" has more than one prefix`)
+		assert.Contains(t, findings[1].Message, `invalid cipher "" in element "ECDHE+"`)
+		assert.Contains(t, findings[2].Message, `invalid directive "@FAST"`)
+		assert.Equal(t, "ssl-default-server-ciphersuites", findings[3].Directive)
+		assert.Contains(t, findings[3].Message, `invalid cipher suite "!TLS_AES_256_GCM_SHA384"`)
+	})
+}
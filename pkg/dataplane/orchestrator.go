@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -23,6 +26,12 @@ type orchestrator struct {
 	parser     *parser.Parser
 	comparator *comparator.Comparator
 	logger     *slog.Logger
+
+	// customMu guards customOps
+	customMu sync.Mutex
+
+	// customOps are the operations added to every plan (see RegisterCustomOperation)
+	customOps []customRegistration
 }
 
 // newOrchestrator creates a new orchestrator instance.
@@ -42,7 +51,12 @@ func newOrchestrator(c *client.DataplaneClient, logger *slog.Logger) (*orchestra
 
 // sync implements the complete sync workflow with automatic fallback.
 func (o *orchestrator) sync(ctx context.Context, desiredConfig string, opts *SyncOptions, auxFiles *AuxiliaryFiles) (*SyncResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
 	startTime := time.Now()
+	state := &syncState{retryBudget: client.NewRetryBudget(opts.RetryBudget)}
 
 	// Step 1: Fetch current configuration from dataplane API (with retry for transient connection errors)
 	o.logger.Info("Fetching current configuration from dataplane API",
@@ -55,50 +69,237 @@ func (o *orchestrator) sync(ctx context.Context, desiredConfig string, opts *Syn
 		Backoff:     client.BackoffExponential,
 		BaseDelay:   100 * time.Millisecond,
 		Logger:      o.logger.With("operation", "fetch_config"),
+		Budget:      state.retryBudget,
 	}
 
+	fetchStart := time.Now()
 	currentConfigStr, err := client.WithRetry(ctx, retryConfig, func(attempt int) (string, error) {
 		return o.client.GetRawConfiguration(ctx)
 	})
+	state.timings.Fetch = time.Since(fetchStart)
 
+	if errors.Is(err, client.ErrRetryBudgetExhausted) {
+		return nil, NewRetryBudgetError("fetch", opts.RetryBudget, err)
+	}
 	if err != nil {
 		return nil, NewConnectionError(o.client.Endpoint.URL, err)
 	}
 
+	// Keep the configuration as it was before this sync for Client.Rollback
+	var snapshot *ConfigSnapshot
+	if opts.CaptureRollbackSnapshot {
+		snapshot = &ConfigSnapshot{Config: currentConfigStr, CapturedAt: time.Now()}
+	}
+
+	// Declare runtime variables so they survive reloads (also covers the raw fallback)
+	desiredConfig, err = applyRuntimeVars(desiredConfig, opts.RuntimeVars)
+	if err != nil {
+		return nil, &SyncError{
+			Stage:   "normalize",
+			Message: "invalid runtime variables",
+			Cause:   err,
+			Hints: []string{
+				"Runtime variables must use the proc scope, e.g. proc.rate_limit",
+				"Values are HAProxy sample expressions, e.g. int(100) or str(blue)",
+			},
+		}
+	}
+
 	// Step 2-4: Parse and compare configurations
-	diff, err := o.parseAndCompareConfigs(currentConfigStr, desiredConfig)
+	diff, err := o.parseAndCompareConfigs(currentConfigStr, desiredConfig, opts, state)
 	if err != nil {
 		return nil, err
 	}
 
+	// Refuse to remove resources the controller did not produce
+	if opts.FailOnDrift {
+		if drifted := findDrift(diff.Operations, opts.IsManaged); len(drifted) > 0 {
+			return nil, NewDriftError(drifted)
+		}
+	}
+
 	// Step 5: Compare auxiliary files and check if sync is needed
+	auxCompareStart := time.Now()
 	auxDiffs, err := o.checkForChanges(ctx, diff, auxFiles)
+	state.timings.Diff += time.Since(auxCompareStart)
 	if err != nil {
 		return nil, err
 	}
 
 	// Early return if no changes
 	if !auxDiffs.hasChanges {
-		return o.createNoChangesResult(startTime, &diff.Summary), nil
+		result := o.createNoChangesResult(startTime, &diff.Summary, state)
+		setAppliedConfig(result, desiredConfig, opts)
+		return result, nil
+	}
+
+	diff.Operations = o.insertCustomOperations(diff.Operations, state)
+
+	// Remember admin states set through the Runtime API, a reload resets them
+	var serverStates []ServerState
+	if opts.PreserveServerState {
+		serverStates = o.captureServerState(ctx, state)
 	}
 
 	// Step 7: Attempt fine-grained sync with retry logic (pass pre-computed diffs)
-	result, err := o.attemptFineGrainedSyncWithDiffs(ctx, diff, opts, auxDiffs.fileDiff, auxDiffs.sslDiff, auxDiffs.mapDiff, auxDiffs.crtlistDiff, startTime)
+	result, err := o.attemptFineGrainedSyncWithDiffs(ctx, diff, opts, auxDiffs.fileDiff, auxDiffs.sslDiff, auxDiffs.mapDiff, auxDiffs.crtlistDiff, startTime, state)
 
 	// Step 7: If fine-grained sync failed and fallback is enabled, try raw config push
-	if err != nil && opts.FallbackToRaw {
+	if err != nil && rawFallbackAllowed(opts, state, err) {
 		o.logger.Warn("Fine-grained sync failed, attempting fallback to raw config push",
 			"error", err)
 
-		fallbackResult, fallbackErr := o.attemptRawFallback(ctx, desiredConfig, diff, auxFiles, startTime)
-		if fallbackErr != nil {
-			return nil, NewFallbackError(err, fallbackErr)
+		fallbackResult, fallbackErr := o.attemptRawFallback(ctx, desiredConfig, diff, auxFiles, startTime, state)
+		if fallbackErr == nil {
+			state.warn(WarningRawFallback, "fine-grained sync failed, configuration was pushed as raw config: %v", err)
+			fallbackResult.FallbackSections = fallbackSections(diff, state)
+			fallbackResult.Warnings = state.warnings
+			fallbackResult.Timings = state.timings
+			fallbackResult.RetriesUsed = state.retryBudget.Used()
+			fallbackResult.CommittedTransactions = state.committed
+			fallbackResult.TransactionID = state.transactionID()
+			fallbackResult.RollbackSnapshot = snapshot
+			o.restoreServerStateAfterReload(ctx, fallbackResult, serverStates, diff.Operations, state)
+			setAppliedConfig(fallbackResult, desiredConfig, opts)
+			return fallbackResult, nil
 		}
+		err = NewFallbackError(err, fallbackErr)
+	}
+
+	if err != nil && len(state.committed) > 0 {
+		result := o.partialFailureResult(ctx, currentConfigStr, diff, opts, startTime, state)
+		result.RollbackSnapshot = snapshot
+		return result, err
+	}
+
+	if err != nil && len(state.failed) > 0 {
+		return &SyncResult{
+			Success:           false,
+			Duration:          time.Since(startTime),
+			Details:           convertDiffSummary(&diff.Summary),
+			Message:           fmt.Sprintf("No configuration changes applied, %d operations failed or were skipped", len(state.failed)),
+			Warnings:          state.warnings,
+			Timings:           state.timings,
+			RetriesUsed:       state.retryBudget.Used(),
+			FailedOperations:  state.failed,
+			SkippedOperations: state.skippedOperations(),
+		}, err
+	}
+
+	// Report why the raw fallback was skipped
+	if err != nil && state.hasWarning(WarningRawFallbackSkipped) {
+		return &SyncResult{
+			Success:     false,
+			Duration:    time.Since(startTime),
+			Details:     convertDiffSummary(&diff.Summary),
+			Message:     "Fine-grained sync failed and the raw fallback was skipped",
+			Warnings:    state.warnings,
+			Timings:     state.timings,
+			RetriesUsed: state.retryBudget.Used(),
+		}, err
+	}
+
+	if err == nil {
+		result.RollbackSnapshot = snapshot
+		o.restoreServerStateAfterReload(ctx, result, serverStates, diff.Operations, state)
+		setAppliedConfig(result, desiredConfig, opts)
+	}
+	return result, err
+}
 
-		return fallbackResult, nil
+// countBinds returns the number of frontend binds in config.
+func countBinds(config *parser.StructuredConfig) int {
+	count := 0
+	for _, frontend := range config.Frontends {
+		count += len(frontend.Binds)
 	}
+	return count
+}
 
-	return result, err
+// rawFallbackAllowed reports whether a sync that failed with err may fall back
+// to pushing the raw configuration. A raw push applies the desired
+// configuration as a whole, so it is skipped for options it cannot honor; the
+// reason is recorded as a WarningRawFallbackSkipped.
+func rawFallbackAllowed(opts *SyncOptions, state *syncState, err error) bool {
+	if !opts.FallbackToRaw {
+		return false
+	}
+
+	var reason string
+	switch {
+	case len(opts.OperationTypeFilter) > 0:
+		reason = "an operation type filter is set, a raw push would apply the excluded operations as well"
+	case opts.NamespacePrefix != "":
+		reason = "a namespace prefix is set, a raw push would delete resources outside of it"
+	case len(opts.Overlays) > 0:
+		reason = "overlays are set, a raw push would not include them"
+	case opts.StableServerNames:
+		reason = "stable server names are enabled, a raw push would use the rendered server names"
+	case opts.ContinueOnError:
+		reason = "ContinueOnError is set, a raw push would apply the failed operations as well"
+	case state.customOperations:
+		reason = "custom operations are registered, a raw push cannot run them"
+	case state.mergedBinds:
+		reason = "colliding binds were merged, a raw push would contain them again"
+	case errors.Is(err, client.ErrRetryBudgetExhausted):
+		reason = "the retry budget is exhausted, a raw push would overwrite the concurrent changes"
+	default:
+		return true
+	}
+
+	state.warn(WarningRawFallbackSkipped, "fine-grained sync failed, raw fallback skipped: %s", reason)
+	return false
+}
+
+// setAppliedConfig records the applied configuration in a successful result
+// if opts.ReturnAppliedConfig is set.
+func setAppliedConfig(result *SyncResult, appliedConfig string, opts *SyncOptions) {
+	if result != nil && opts.ReturnAppliedConfig {
+		result.AppliedConfig = appliedConfig
+	}
+}
+
+// partialFailureResult builds the result of a sync that failed after changes
+// were committed, restoring previousConfig first if RollbackOnPartialFailure is set.
+func (o *orchestrator) partialFailureResult(ctx context.Context, previousConfig string, diff *comparator.ConfigDiff, opts *SyncOptions, startTime time.Time, state *syncState) *SyncResult {
+	o.logger.Warn("Sync failed after committing changes, HAProxy runs a partially applied configuration",
+		"committed", len(state.committed))
+
+	result := &SyncResult{
+		Success:               false,
+		Details:               convertDiffSummary(&diff.Summary),
+		Message:               fmt.Sprintf("Sync failed after %d committed changes", len(state.committed)),
+		CommittedTransactions: state.committed,
+		TransactionID:         state.transactionID(),
+		FailedOperations:      state.failed,
+		SkippedOperations:     state.skippedOperations(),
+	}
+
+	if opts.RollbackOnPartialFailure {
+		// Restore even if ctx is done, the partial state is worse than a late rollback
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+
+		pushStart := time.Now()
+		reloadID, err := o.client.PushRawConfiguration(rollbackCtx, previousConfig)
+		state.timings.Commit += time.Since(pushStart)
+		if err != nil {
+			o.logger.Error("Failed to restore previous configuration", "error", err)
+			state.warn(WarningRollbackFailed, "failed to restore the previous configuration: %v", err)
+		} else {
+			o.logger.Info("Restored previous configuration", "reload_id", reloadID)
+			result.RolledBack = true
+			result.ReloadTriggered = true
+			result.ReloadID = reloadID
+			result.Message = fmt.Sprintf("Sync failed after %d committed changes, previous configuration restored", len(state.committed))
+		}
+	}
+
+	result.Duration = time.Since(startTime)
+	result.Warnings = state.warnings
+	result.Timings = state.timings
+	result.RetriesUsed = state.retryBudget.Used()
+	return result
 }
 
 // attemptFineGrainedSyncWithDiffs attempts fine-grained sync with pre-computed auxiliary file diffs.
@@ -112,20 +313,43 @@ func (o *orchestrator) attemptFineGrainedSyncWithDiffs(
 	mapDiff *auxiliaryfiles.MapFileDiff,
 	crtlistDiff *auxiliaryfiles.CRTListDiff,
 	startTime time.Time,
+	state *syncState,
 ) (*SyncResult, error) {
 	// Phase 1: Sync auxiliary files (pre-config) using pre-computed diffs
-	if err := o.syncAuxiliaryFilesPreConfig(ctx, fileDiff, sslDiff, mapDiff, crtlistDiff); err != nil {
+	auxStart := time.Now()
+	mapEntryUpdates, err := o.syncAuxiliaryFilesPreConfig(ctx, opts, fileDiff, sslDiff, mapDiff, crtlistDiff)
+	state.timings.AuxiliaryFiles += time.Since(auxStart)
+	if err != nil {
 		return nil, err
 	}
 
+	// Let removed servers finish their connections before deleting them
+	if opts.DrainBeforeDelete {
+		drainStart := time.Now()
+		err = o.drainDeletedServers(ctx, diff.Operations, opts.DrainGracePeriod, state)
+		state.timings.Drain += time.Since(drainStart)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Phase 2: Execute configuration sync with retry logic
-	appliedOps, reloadTriggered, reloadID, retries, err := o.executeConfigOperations(ctx, diff, opts)
+	o.warnAboutOperations(diff.Operations, state)
+	appliedOps, reloadTriggered, reloadID, retries, err := o.executeConfigOperations(ctx, diff, opts, state)
 	if err != nil {
 		return nil, err
 	}
 
-	// Phase 3: Delete obsolete files AFTER successful config sync
-	o.deleteObsoleteFilesPostConfig(ctx, fileDiff, sslDiff, mapDiff, crtlistDiff)
+	// Phase 3: Apply map entry changes and delete obsolete files AFTER successful config sync
+	cleanupStart := time.Now()
+	err = o.applyMapEntryUpdatesPostConfig(ctx, mapEntryUpdates)
+	if err == nil {
+		o.deleteObsoleteFilesPostConfig(ctx, fileDiff, sslDiff, mapDiff, crtlistDiff, state)
+	}
+	state.timings.AuxiliaryFiles += time.Since(cleanupStart)
+	if err != nil {
+		return nil, err
+	}
 
 	o.logger.Info("Fine-grained sync completed successfully",
 		"operations", len(appliedOps),
@@ -134,24 +358,86 @@ func (o *orchestrator) attemptFineGrainedSyncWithDiffs(
 		"duration", time.Since(startTime))
 
 	return &SyncResult{
-		Success:           true,
-		AppliedOperations: appliedOps,
-		ReloadTriggered:   reloadTriggered,
-		ReloadID:          reloadID,
-		FallbackToRaw:     false,
-		Duration:          time.Since(startTime),
-		Retries:           max(0, retries-1),
-		Details:           convertDiffSummary(&diff.Summary),
-		Message:           fmt.Sprintf("Successfully applied %d configuration changes", len(appliedOps)),
+		Success:            true,
+		AppliedOperations:  appliedOps,
+		ReloadTriggered:    reloadTriggered,
+		ReloadID:           reloadID,
+		FallbackToRaw:      false,
+		RuntimeUnavailable: state.runtimeUnavailable,
+		Duration:           time.Since(startTime),
+		Retries:            max(0, retries-1),
+		Details:            convertDiffSummary(&diff.Summary),
+		Message:            fmt.Sprintf("Successfully applied %d configuration changes", len(appliedOps)),
+		Warnings:           state.warnings,
+		Timings:            state.timings,
+		RetriesUsed:        state.retryBudget.Used(),
+
+		CommittedTransactions: state.committed,
+		TransactionID:         state.transactionID(),
+		SkippedOperations:     state.skippedOperations(),
 	}, nil
 }
 
+// defaultDrainGracePeriod is the DrainGracePeriod used when none is set.
+const defaultDrainGracePeriod = 30 * time.Second
+
+// drainDeletedServers sets the servers deleted by ops to the drain state and
+// waits gracePeriod, so they finish in-flight connections before the deletion
+// is committed. Servers that cannot be drained are reported as warnings.
+// Returns an error only if ctx is done while waiting.
+func (o *orchestrator) drainDeletedServers(ctx context.Context, ops []comparator.Operation, gracePeriod time.Duration, state *syncState) error {
+	drained := 0
+	for _, op := range ops {
+		if op.Type() != sections.OperationDelete || op.Section() != "server" {
+			continue
+		}
+
+		backend, server, _ := strings.Cut(op.Target(), "/")
+		if err := o.client.SetServerAdminState(ctx, backend, server, client.ServerAdminStateDrain); err != nil {
+			o.logger.Warn("Failed to drain server before deletion",
+				"backend", backend,
+				"server", server,
+				"error", err)
+			state.warn(WarningDrainFailed, "server '%s' in backend '%s' was deleted without draining: %v", server, backend, err)
+			continue
+		}
+		drained++
+	}
+
+	if drained == 0 {
+		return nil
+	}
+
+	if gracePeriod <= 0 {
+		gracePeriod = defaultDrainGracePeriod
+	}
+	o.logger.Info("Draining servers before deletion",
+		"servers", drained,
+		"grace_period", gracePeriod)
+
+	select {
+	case <-time.After(gracePeriod):
+		return nil
+	case <-ctx.Done():
+		return &SyncError{
+			Stage:   "apply",
+			Message: "sync canceled while draining servers",
+			Cause:   ctx.Err(),
+			Hints: []string{
+				"Drained servers stay in the drain state until the next sync deletes them",
+				"Use a DrainGracePeriod shorter than the sync timeout",
+			},
+		}
+	}
+}
+
 // attemptRawFallback attempts to sync using raw configuration push.
-func (o *orchestrator) attemptRawFallback(ctx context.Context, desiredConfig string, diff *comparator.ConfigDiff, auxFiles *AuxiliaryFiles, startTime time.Time) (*SyncResult, error) {
+func (o *orchestrator) attemptRawFallback(ctx context.Context, desiredConfig string, diff *comparator.ConfigDiff, auxFiles *AuxiliaryFiles, startTime time.Time, state *syncState) (*SyncResult, error) {
 	o.logger.Warn("Falling back to raw configuration push")
 
 	// Phase 1: Sync auxiliary files BEFORE pushing raw config (same as fine-grained sync)
 	// Files must exist before HAProxy validates the configuration
+	auxStart := time.Now()
 	g, gCtx := errgroup.WithContext(ctx)
 
 	// Sync general files
@@ -179,12 +465,16 @@ func (o *orchestrator) attemptRawFallback(ctx context.Context, desiredConfig str
 	})
 
 	// Wait for all auxiliary file syncs to complete
-	if err := g.Wait(); err != nil {
+	err := g.Wait()
+	state.timings.AuxiliaryFiles += time.Since(auxStart)
+	if err != nil {
 		return nil, err
 	}
 
 	// Phase 2: Push raw configuration (now that auxiliary files exist)
+	pushStart := time.Now()
 	reloadID, err := o.client.PushRawConfiguration(ctx, desiredConfig)
+	state.timings.Commit += time.Since(pushStart)
 	if err != nil {
 		return nil, &SyncError{
 			Stage:   "fallback",
@@ -205,6 +495,7 @@ func (o *orchestrator) attemptRawFallback(ctx context.Context, desiredConfig str
 	// Preserve detailed operation information from diff
 	// Even though we used raw config push, we still know what changes were applied
 	appliedOps := convertOperationsToApplied(diff.Operations)
+	state.committed = append(state.committed, CommittedTransaction{Operations: appliedOps})
 
 	return &SyncResult{
 		Success:           true,
@@ -227,44 +518,76 @@ func (o *orchestrator) diff(ctx context.Context, desiredConfig string) (*DiffRes
 		return nil, NewConnectionError(o.client.Endpoint.URL, err)
 	}
 
-	// Step 2: Parse current configuration
-	currentConfig, err := o.parser.ParseFromString(currentConfigStr)
+	// Step 2-4: Parse and compare configurations
+	diff, err := o.parseAndCompareConfigs(currentConfigStr, desiredConfig, DryRunOptions(), nil)
 	if err != nil {
-		snippet := currentConfigStr
-		if len(snippet) > 200 {
-			snippet = snippet[:200]
-		}
-		return nil, NewParseError("current", snippet, err)
+		return nil, err
 	}
 
-	// Step 3: Parse desired configuration
-	desiredParsed, err := o.parser.ParseFromString(desiredConfig)
+	return newDiffResult(diff), nil
+}
+
+// validateRemote stages the operations for desiredConfig in a transaction and
+// has the Dataplane API validate the resulting configuration with HAProxy.
+// The transaction is always deleted, so nothing is ever committed.
+func (o *orchestrator) validateRemote(ctx context.Context, desiredConfig string) (*DiffResult, error) {
+	currentConfigStr, err := o.client.GetRawConfiguration(ctx)
 	if err != nil {
-		snippet := desiredConfig
-		if len(snippet) > 200 {
-			snippet = snippet[:200]
+		return nil, NewConnectionError(o.client.Endpoint.URL, err)
+	}
+
+	diff, err := o.parseAndCompareConfigs(currentConfigStr, desiredConfig, DryRunOptions(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := o.client.GetVersion(ctx)
+	if err != nil {
+		return nil, NewConnectionError(o.client.Endpoint.URL, err)
+	}
+
+	tx, err := o.client.CreateTransaction(ctx, version)
+	if err != nil {
+		return nil, NewConnectionError(o.client.Endpoint.URL, err)
+	}
+	defer func() {
+		// Use a fresh context so the transaction is deleted even if ctx is done
+		abortCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if abortErr := tx.Abort(abortCtx); abortErr != nil {
+			o.logger.Warn("Failed to delete validation transaction",
+				"transaction_id", tx.ID,
+				"error", abortErr)
 		}
-		return nil, NewParseError("desired", snippet, err)
+	}()
+
+	if _, err := synchronizer.SyncOperations(ctx, o.client, diff.Operations, tx); err != nil {
+		return nil, NewRemoteValidationError("operation", SimplifyValidationError(err), err)
 	}
 
-	// Step 4: Compare configurations
-	diff, err := o.comparator.Compare(currentConfig, desiredParsed)
+	stagedConfig, err := o.client.GetTransactionConfiguration(ctx, tx.ID)
 	if err != nil {
-		return nil, &SyncError{
-			Stage:   "compare",
-			Message: "failed to compare configurations",
-			Cause:   err,
+		return nil, NewConnectionError(o.client.Endpoint.URL, err)
+	}
+
+	if err := o.client.ValidateRawConfiguration(ctx, stagedConfig); err != nil {
+		var invalidErr *client.ConfigValidationError
+		if errors.As(err, &invalidErr) {
+			return nil, NewRemoteValidationError("semantic", invalidErr.Message, err)
 		}
+		return nil, NewConnectionError(o.client.Endpoint.URL, err)
 	}
 
-	// Convert to DiffResult
-	plannedOps := convertOperationsToPlanned(diff.Operations)
+	return newDiffResult(diff), nil
+}
 
+// newDiffResult converts a comparator diff into the public DiffResult.
+func newDiffResult(diff *comparator.ConfigDiff) *DiffResult {
 	return &DiffResult{
 		HasChanges:        diff.Summary.HasChanges(),
-		PlannedOperations: plannedOps,
+		PlannedOperations: convertOperationsToPlanned(diff.Operations, diff.Current),
 		Details:           convertDiffSummary(&diff.Summary),
-	}, nil
+	}
 }
 
 // Helper functions to convert internal types to public API types
@@ -282,7 +605,7 @@ func convertOperationsToApplied(ops []comparator.Operation) []AppliedOperation {
 	return applied
 }
 
-func convertOperationsToPlanned(ops []comparator.Operation) []PlannedOperation {
+func convertOperationsToPlanned(ops []comparator.Operation, current *parser.StructuredConfig) []PlannedOperation {
 	planned := make([]PlannedOperation, 0, len(ops))
 	for _, op := range ops {
 		planned = append(planned, PlannedOperation{
@@ -291,6 +614,8 @@ func convertOperationsToPlanned(ops []comparator.Operation) []PlannedOperation {
 			Resource:    extractResourceName(op),
 			Description: op.Describe(),
 			Priority:    op.Priority(),
+			fingerprint: operationFingerprint(op),
+			fieldDiffs:  comparator.FieldDiffs(op, current),
 		})
 	}
 	return planned
@@ -326,6 +651,23 @@ func extractResourceName(op comparator.Operation) string {
 	return "unknown"
 }
 
+// findDrift describes the resources deleted by ops that isManaged does not
+// claim.
+func findDrift(ops []comparator.Operation, isManaged func(section, name string) bool) []string {
+	var drifted []string
+	for _, op := range ops {
+		if op.Type() != sections.OperationDelete {
+			continue
+		}
+		name := sections.NameOf(op)
+		if isManaged(op.Section(), name) {
+			continue
+		}
+		drifted = append(drifted, fmt.Sprintf("%s '%s'", op.Section(), name))
+	}
+	return drifted
+}
+
 func convertDiffSummary(summary *comparator.DiffSummary) DiffDetails {
 	return DiffDetails{
 		TotalOperations:   summary.TotalOperations(),
@@ -592,6 +934,11 @@ func (o *orchestrator) areAllOperationsRuntimeEligible(operations []comparator.O
 	}
 
 	for _, op := range operations {
+		// Custom operations run in the transaction so they can be rolled back with it
+		if _, custom := op.(*customOperation); custom {
+			return false
+		}
+
 		// Only server UPDATE operations are runtime-eligible
 		// Server creates/deletes require transaction, other sections require transaction
 		if op.Section() != "server" || op.Type() != sections.OperationUpdate {
@@ -603,8 +950,8 @@ func (o *orchestrator) areAllOperationsRuntimeEligible(operations []comparator.O
 }
 
 // deleteObsoleteFilesPostConfig deletes obsolete auxiliary files AFTER successful config sync.
-// Errors are logged as warnings but do not fail the sync since config is already applied.
-func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDiff *auxiliaryfiles.FileDiff, sslDiff *auxiliaryfiles.SSLCertificateDiff, mapDiff *auxiliaryfiles.MapFileDiff, crtlistDiff *auxiliaryfiles.CRTListDiff) {
+// Errors are recorded as warnings but do not fail the sync since config is already applied.
+func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDiff *auxiliaryfiles.FileDiff, sslDiff *auxiliaryfiles.SSLCertificateDiff, mapDiff *auxiliaryfiles.MapFileDiff, crtlistDiff *auxiliaryfiles.CRTListDiff, state *syncState) {
 	// Delete general files
 	if fileDiff != nil && len(fileDiff.ToDelete) > 0 {
 		o.logger.Info("Deleting obsolete general files", "count", len(fileDiff.ToDelete))
@@ -617,6 +964,7 @@ func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDi
 
 		if err := auxiliaryfiles.SyncGeneralFiles(ctx, o.client, postConfigDiff); err != nil {
 			o.logger.Warn("Failed to delete obsolete general files", "error", err, "files", fileDiff.ToDelete)
+			state.warn(WarningFileCleanupFailed, "failed to delete obsolete general files %v: %v", fileDiff.ToDelete, err)
 		} else {
 			o.logger.Info("Obsolete general files deleted successfully")
 		}
@@ -634,6 +982,7 @@ func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDi
 
 		if err := auxiliaryfiles.SyncSSLCertificates(ctx, o.client, postConfigSSL); err != nil {
 			o.logger.Warn("Failed to delete obsolete SSL certificates", "error", err, "certificates", sslDiff.ToDelete)
+			state.warn(WarningFileCleanupFailed, "failed to delete obsolete SSL certificates %v: %v", sslDiff.ToDelete, err)
 		} else {
 			o.logger.Info("Obsolete SSL certificates deleted successfully")
 		}
@@ -651,6 +1000,7 @@ func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDi
 
 		if err := auxiliaryfiles.SyncMapFiles(ctx, o.client, postConfigMap); err != nil {
 			o.logger.Warn("Failed to delete obsolete map files", "error", err, "maps", mapDiff.ToDelete)
+			state.warn(WarningFileCleanupFailed, "failed to delete obsolete map files %v: %v", mapDiff.ToDelete, err)
 		} else {
 			o.logger.Info("Obsolete map files deleted successfully")
 		}
@@ -668,6 +1018,7 @@ func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDi
 
 		if err := auxiliaryfiles.SyncCRTLists(ctx, o.client, postConfigCRTList); err != nil {
 			o.logger.Warn("Failed to delete obsolete crt-list files", "error", err, "crtlists", crtlistDiff.ToDelete)
+			state.warn(WarningFileCleanupFailed, "failed to delete obsolete crt-list files %v: %v", crtlistDiff.ToDelete, err)
 		} else {
 			o.logger.Info("Obsolete crt-list files deleted successfully")
 		}
@@ -675,8 +1026,17 @@ func (o *orchestrator) deleteObsoleteFilesPostConfig(ctx context.Context, fileDi
 }
 
 // parseAndCompareConfigs parses both current and desired configurations and compares them.
+// Bind collisions in the desired configuration are handled according to opts.BindCollisionPolicy.
+// Parse and comparison durations and filtered operations are recorded in state
+// if it is not nil.
 // Returns the configuration diff or an error if parsing or comparison fails.
-func (o *orchestrator) parseAndCompareConfigs(currentConfigStr, desiredConfig string) (*comparator.ConfigDiff, error) {
+func (o *orchestrator) parseAndCompareConfigs(currentConfigStr, desiredConfig string, opts *SyncOptions, state *syncState) (*comparator.ConfigDiff, error) {
+	if state == nil {
+		state = &syncState{}
+	}
+	timings := &state.timings
+	parseStart := time.Now()
+
 	// Parse current configuration
 	o.logger.Debug("Parsing current configuration")
 	currentConfig, err := o.parser.ParseFromString(currentConfigStr)
@@ -699,9 +1059,85 @@ func (o *orchestrator) parseAndCompareConfigs(currentConfigStr, desiredConfig st
 		return nil, NewParseError("desired", snippet, err)
 	}
 
+	// Merge overlay fragments into the desired configuration
+	if len(opts.Overlays) > 0 {
+		overlays := make([]*parser.StructuredConfig, 0, len(opts.Overlays))
+		for _, overlay := range opts.Overlays {
+			overlayParsed, err := o.parser.ParseFromString(overlay)
+			if err != nil {
+				snippet := overlay
+				if len(snippet) > 200 {
+					snippet = snippet[:200]
+				}
+				return nil, NewParseError("overlay", snippet, err)
+			}
+			overlays = append(overlays, overlayParsed)
+		}
+
+		desiredParsed, err = parser.MergeOverlays(desiredParsed, overlays...)
+		if err != nil {
+			return nil, &SyncError{
+				Stage:   "normalize",
+				Message: "overlays define the same section",
+				Cause:   err,
+				Hints: []string{
+					"Ensure each section is overridden by at most one overlay",
+				},
+			}
+		}
+	}
+
+	// Detect binds sharing the same address:port before they reach the Dataplane API
+	bindCount := countBinds(desiredParsed)
+	if err := comparator.NormalizeBinds(desiredParsed, opts.BindCollisionPolicy); err != nil {
+		return nil, &SyncError{
+			Stage:   "normalize",
+			Message: "desired configuration contains colliding binds",
+			Cause:   err,
+			Hints: []string{
+				"Ensure templates emit each address:port only once per frontend",
+				"Use BindCollisionMerge to merge binds that differ only in their name",
+			},
+		}
+	}
+	state.mergedBinds = countBinds(desiredParsed) < bindCount
+
+	// Catch "from" references to missing defaults sections before HAProxy does
+	if err := comparator.ValidateDefaultsReferences(desiredParsed); err != nil {
+		return nil, &SyncError{
+			Stage:   "normalize",
+			Message: "desired configuration references a missing defaults section",
+			Cause:   err,
+			Hints: []string{
+				"Check the name after 'from' in frontend and backend sections",
+				"Ensure templates render the referenced defaults section",
+			},
+		}
+	}
+
+	timings.Parse = time.Since(parseStart)
+
+	if opts.PreserveServerState {
+		for _, backend := range currentConfig.Backends {
+			state.backends = append(state.backends, backend.Name)
+		}
+	}
+
 	// Compare configurations
 	o.logger.Info("Comparing configurations")
-	diff, err := o.comparator.Compare(currentConfig, desiredParsed)
+	compareStart := time.Now()
+	defer func() { timings.Diff += time.Since(compareStart) }()
+	cmp := o.comparator
+	if opts.NormalizeFieldDefaults || opts.StableServerNames {
+		cmp = comparator.New()
+		if opts.NormalizeFieldDefaults {
+			cmp.WithFieldDefaults()
+		}
+		if opts.StableServerNames {
+			cmp.WithStableServerNames()
+		}
+	}
+	diff, err := cmp.Compare(currentConfig, desiredParsed)
 	if err != nil {
 		return nil, &SyncError{
 			Stage:   "compare",
@@ -714,9 +1150,162 @@ func (o *orchestrator) parseAndCompareConfigs(currentConfigStr, desiredConfig st
 		}
 	}
 
+	priorities, err := comparator.ProfilePriorities(opts.OrderProfile, opts.PriorityOverrides)
+	if err != nil {
+		return nil, &SyncError{
+			Stage:   "compare",
+			Message: "invalid order profile",
+			Cause:   err,
+			Hints: []string{
+				"Use OrderProfileRelaxed or OrderProfileStrict",
+			},
+		}
+	}
+
+	if len(priorities) > 0 {
+		ordered, err := comparator.OrderOperationsWithOverrides(diff.Operations, priorities)
+		if err != nil {
+			return nil, &SyncError{
+				Stage:   "compare",
+				Message: "invalid priority overrides",
+				Cause:   err,
+				Hints: []string{
+					"Only override section types listed in the supported configuration docs",
+					"Child sections must keep a higher priority than their parents",
+				},
+			}
+		}
+		diff.Operations = ordered
+	}
+
+	if opts.AddBeforeRemoveBinds {
+		diff.Operations = comparator.AddBindsBeforeRemoval(diff.Operations)
+	}
+
+	if len(opts.OperationTypeFilter) > 0 {
+		dropped, blocked := filterOperationTypes(diff, opts.OperationTypeFilter)
+		for _, op := range dropped {
+			state.skip(op, SkipReasonFiltered)
+		}
+		for _, op := range blocked {
+			state.skip(op, SkipReasonFilteredDependency)
+		}
+	}
+
+	if opts.NamespacePrefix != "" {
+		for _, op := range filterForeignDeletes(diff, opts.NamespacePrefix) {
+			state.skip(op, SkipReasonNotOwned)
+		}
+	}
+
 	return diff, nil
 }
 
+// filterOperationTypes drops the operations whose type is not listed in types
+// and updates the summary to match. The order of the remaining operations is kept.
+//
+// Rule operations are addressed by index, computed on the assumption that all
+// earlier creates and deletes of the same list are applied. Once one of them is
+// dropped, the later index-based operations of that list would land at the
+// wrong positions, so they are dropped as well, the same way operationIsolation
+// skips them after a failure.
+// Returns the dropped operations and the index-based operations blocked by them.
+func filterOperationTypes(diff *comparator.ConfigDiff, types []OperationType) (dropped, blocked []comparator.Operation) {
+	allowed := make(map[OperationType]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	blockedKeys := make(map[string]bool)
+	isBlocked := func(op comparator.Operation) bool {
+		for _, key := range dependencyKeys(op) {
+			if blockedKeys[key] {
+				return true
+			}
+		}
+		return false
+	}
+
+	filtered := make([]comparator.Operation, 0, len(diff.Operations))
+	for _, op := range diff.Operations {
+		switch {
+		case !allowed[op.Type()]:
+			dropped = append(dropped, op)
+		case isBlocked(op):
+			blocked = append(blocked, op)
+		default:
+			filtered = append(filtered, op)
+			continue
+		}
+		for _, key := range dependentKeys(op) {
+			blockedKeys[key] = true
+		}
+	}
+	diff.Operations = filtered
+
+	summary := &diff.Summary
+	for _, op := range blocked {
+		switch op.Type() {
+		case sections.OperationCreate:
+			summary.TotalCreates--
+		case sections.OperationUpdate:
+			summary.TotalUpdates--
+		case sections.OperationDelete:
+			summary.TotalDeletes--
+		}
+	}
+	if !allowed[sections.OperationCreate] {
+		summary.TotalCreates = 0
+		summary.FrontendsAdded = nil
+		summary.BackendsAdded = nil
+		summary.ServersAdded = map[string][]string{}
+	}
+	if !allowed[sections.OperationUpdate] {
+		summary.TotalUpdates = 0
+		summary.GlobalChanged = false
+		summary.DefaultsChanged = false
+		summary.FrontendsModified = nil
+		summary.BackendsModified = nil
+		summary.ServersModified = map[string][]string{}
+	}
+	if !allowed[sections.OperationDelete] {
+		summary.TotalDeletes = 0
+		summary.FrontendsDeleted = nil
+		summary.BackendsDeleted = nil
+		summary.ServersDeleted = map[string][]string{}
+	}
+	return dropped, blocked
+}
+
+// filterForeignDeletes drops the delete operations of resources whose name does
+// not start with prefix and updates the summary to match. Child resources are
+// matched by the name of their parent section. Returns the dropped operations.
+func filterForeignDeletes(diff *comparator.ConfigDiff, prefix string) []comparator.Operation {
+	owned := func(name string) bool { return strings.HasPrefix(name, prefix) }
+
+	filtered := make([]comparator.Operation, 0, len(diff.Operations))
+	var dropped []comparator.Operation
+	for _, op := range diff.Operations {
+		if op.Type() == sections.OperationDelete && !owned(op.Target()) {
+			diff.Summary.TotalDeletes--
+			dropped = append(dropped, op)
+			continue
+		}
+		filtered = append(filtered, op)
+	}
+	diff.Operations = filtered
+
+	summary := &diff.Summary
+	summary.FrontendsDeleted = slices.DeleteFunc(summary.FrontendsDeleted, func(name string) bool { return !owned(name) })
+	summary.BackendsDeleted = slices.DeleteFunc(summary.BackendsDeleted, func(name string) bool { return !owned(name) })
+	for backend := range summary.ServersDeleted {
+		if !owned(backend) {
+			delete(summary.ServersDeleted, backend)
+		}
+	}
+	return dropped
+}
+
 // compareAuxiliaryFiles compares all auxiliary file types in parallel.
 // Returns file diffs for general files, SSL certificates, map files, and crt-list files.
 func (o *orchestrator) compareAuxiliaryFiles(
@@ -862,12 +1451,194 @@ func (o *orchestrator) compareCRTListFiles(ctx context.Context, crtlistFiles []a
 	return crtlistDiff, nil
 }
 
+// syncState tracks non-fatal conditions encountered during a single sync.
+type syncState struct {
+	// runtimeUnavailable is set on the first runtime operation failing because the
+	// Runtime API is unreachable, so the remaining operations go straight to a
+	// configuration transaction.
+	runtimeUnavailable bool
+
+	// warnings are returned to the caller in SyncResult.Warnings.
+	warnings []SyncWarning
+
+	// timings are returned to the caller in SyncResult.Timings.
+	timings SyncTimings
+
+	// failedSections are the sections of operations that failed, reported in
+	// SyncResult.FallbackSections when the raw fallback is used.
+	failedSections []string
+
+	// committed are the changes committed so far, returned in
+	// SyncResult.CommittedTransactions.
+	committed []CommittedTransaction
+
+	// failed are the operations that failed or were skipped with
+	// ContinueOnError, returned in SyncResult.FailedOperations.
+	failed []FailedOperation
+
+	// skipped are the operations skipped before execution, returned in
+	// SyncResult.SkippedOperations along with the skipped operations of failed.
+	skipped []SkippedOperation
+
+	// customOperations is set if the plan contains custom operations.
+	customOperations bool
+
+	// mergedBinds is set if BindCollisionMerge removed binds from the desired
+	// configuration, which the raw configuration still contains.
+	mergedBinds bool
+
+	// customExecuted are the custom operations executed in the current
+	// transaction that can be rolled back, in execution order.
+	customExecuted []RollbackOperation
+
+	// backends are the backends of the current configuration, whose server
+	// states are captured with PreserveServerState.
+	backends []string
+
+	// retryBudget is shared by all retries of the sync, its use is returned
+	// in SyncResult.RetriesUsed.
+	retryBudget *client.RetryBudget
+}
+
+// warn records a non-fatal issue for the caller.
+func (s *syncState) warn(code, format string, args ...any) {
+	s.warnings = append(s.warnings, SyncWarning{Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// hasWarning reports whether a warning with the given code was recorded.
+func (s *syncState) hasWarning(code string) bool {
+	for _, warning := range s.warnings {
+		if warning.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// skip records an operation that will not be applied.
+func (s *syncState) skip(op comparator.Operation, reason SkipReason) {
+	s.skipped = append(s.skipped, SkippedOperation{
+		Type:        operationTypeToString(op.Type()),
+		Section:     op.Section(),
+		Resource:    extractResourceName(op),
+		Description: op.Describe(),
+		Reason:      reason,
+	})
+}
+
+// skippedOperations returns the operations skipped before execution followed
+// by those skipped because an operation they depend on failed.
+func (s *syncState) skippedOperations() []SkippedOperation {
+	skipped := slices.Clone(s.skipped)
+	for _, failed := range s.failed {
+		if failed.Skipped {
+			skipped = append(skipped, SkippedOperation{
+				Type:        failed.Type,
+				Section:     failed.Section,
+				Resource:    failed.Resource,
+				Description: failed.Description,
+				Reason:      SkipReasonDependencyFailed,
+			})
+		}
+	}
+	return skipped
+}
+
+// transactionID returns the ID of the last transaction committed so far,
+// or "" if none was.
+func (s *syncState) transactionID() string {
+	for i := len(s.committed) - 1; i >= 0; i-- {
+		if s.committed[i].ID != "" {
+			return s.committed[i].ID
+		}
+	}
+	return ""
+}
+
+// timedOperation records the execution time of an operation in the sync timings
+// and the section of the operation if it fails.
+type timedOperation struct {
+	comparator.Operation
+	state *syncState
+}
+
+func (op *timedOperation) ParentSection() string { return sections.ParentSectionOf(op.Operation) }
+
+func (op *timedOperation) Name() string { return sections.NameOf(op.Operation) }
+
+func (op *timedOperation) Execute(ctx context.Context, c *client.DataplaneClient, txID string) error {
+	start := time.Now()
+	err := op.Operation.Execute(ctx, c, txID)
+	elapsed := time.Since(start)
+
+	timings := &op.state.timings
+	timings.Execute += elapsed
+	if timings.ExecuteBySection == nil {
+		timings.ExecuteBySection = make(map[string]time.Duration)
+	}
+	timings.ExecuteBySection[op.Section()] += elapsed
+
+	// An unreachable Runtime API is retried through a transaction, not a failure of the section
+	if err != nil && !errors.Is(err, client.ErrRuntimeUnavailable) && !slices.Contains(op.state.failedSections, op.Section()) {
+		op.state.failedSections = append(op.state.failedSections, op.Section())
+	}
+	return err
+}
+
+// timeOperations wraps operations so their execution is recorded in state.
+func timeOperations(ops []comparator.Operation, state *syncState) []comparator.Operation {
+	timed := make([]comparator.Operation, len(ops))
+	for i, op := range ops {
+		timed[i] = &timedOperation{Operation: op, state: state}
+	}
+	return timed
+}
+
+// fallbackSections returns the sections that caused the fine-grained sync to fail.
+// If no operation failed, the transaction was rejected as a whole (e.g. on commit),
+// so every section with changes is reported.
+func fallbackSections(diff *comparator.ConfigDiff, state *syncState) []string {
+	if len(state.failedSections) > 0 {
+		return slices.Clone(state.failedSections)
+	}
+
+	var result []string
+	for _, op := range diff.Operations {
+		if !slices.Contains(result, op.Section()) {
+			result = append(result, op.Section())
+		}
+	}
+	return result
+}
+
+// warnAboutOperations records warnings for sections that cannot be updated in
+// place and are recreated through a delete and create.
+func (o *orchestrator) warnAboutOperations(ops []comparator.Operation, state *syncState) {
+	deleted := make(map[string]bool)
+	for _, op := range ops {
+		if op.Type() == sections.OperationDelete {
+			deleted[op.Section()+"/"+extractResourceName(op)] = true
+		}
+	}
+
+	for _, op := range ops {
+		if op.Type() == sections.OperationCreate && deleted[op.Section()+"/"+extractResourceName(op)] {
+			state.warn(WarningSectionRecreated, "%s '%s' cannot be updated in place and is recreated", op.Section(), extractResourceName(op))
+		}
+	}
+}
+
 // executeConfigOperations executes configuration operations with retry logic.
 // Returns applied operations, reload status, reload ID, retry count, and error.
+//
+// Runtime-eligible operations are applied without a transaction. If the Runtime API
+// turns out to be unavailable, they are applied through a transaction instead and
+// state.runtimeUnavailable is set.
 func (o *orchestrator) executeConfigOperations(
 	ctx context.Context,
 	diff *comparator.ConfigDiff,
 	opts *SyncOptions,
+	state *syncState,
 ) (appliedOps []AppliedOperation, reloadTriggered bool, reloadID string, retries int, err error) {
 	// If there are no config operations, skip sync entirely (no reload needed)
 	// This happens when only auxiliary files changed
@@ -877,23 +1648,53 @@ func (o *orchestrator) executeConfigOperations(
 	}
 
 	// Execute configuration operations
-	adapter := client.NewVersionAdapter(o.client, opts.MaxRetries)
+	adapter := client.NewVersionAdapter(o.client, opts.MaxRetries).
+		WithStaleTransactionCleanup(opts.CleanupStaleTransactions).
+		WithRetryBudget(state.retryBudget)
+	timedOps := timeOperations(diff.Operations, state)
 
 	// Check if all operations are runtime-eligible (server UPDATE only)
 	// Runtime-eligible operations can be executed without reload via Runtime API
-	allRuntimeEligible := o.areAllOperationsRuntimeEligible(diff.Operations)
+	useRuntime := !state.runtimeUnavailable && o.areAllOperationsRuntimeEligible(diff.Operations)
 
 	var commitResult *client.CommitResult
 
-	if allRuntimeEligible {
+	if useRuntime {
 		// Execute runtime-eligible operations without transaction (no reload)
 		o.logger.Info("All operations are runtime-eligible, executing without transaction")
 
 		// Execute operations directly using runtime API (empty transactionID)
-		for _, op := range diff.Operations {
-			if execErr := op.Execute(ctx, o.client, ""); execErr != nil {
-				err = fmt.Errorf("runtime operation failed: %w", execErr)
-				break
+		if opts.ContinueOnError {
+			iso := newOperationIsolation()
+			var applied []comparator.Operation
+			applied, err = iso.execute(ctx, o.client, timedOps, "", func(err error) bool {
+				return errors.Is(err, client.ErrRuntimeUnavailable)
+			})
+			// Each runtime operation is committed on its own
+			for _, op := range applied {
+				state.committed = append(state.committed, CommittedTransaction{
+					Operations: convertOperationsToApplied([]comparator.Operation{op}),
+				})
+			}
+			if err == nil {
+				appliedOps = convertOperationsToApplied(applied)
+				state.failed = iso.failed
+			} else {
+				err = fmt.Errorf("runtime operation failed: %w", err)
+			}
+		} else {
+			for i, op := range timedOps {
+				if execErr := op.Execute(ctx, o.client, ""); execErr != nil {
+					err = fmt.Errorf("runtime operation failed: %w", execErr)
+					break
+				}
+				// Each runtime operation is committed on its own
+				state.committed = append(state.committed, CommittedTransaction{
+					Operations: convertOperationsToApplied(diff.Operations[i : i+1]),
+				})
+			}
+			if err == nil {
+				appliedOps = convertOperationsToApplied(diff.Operations)
 			}
 		}
 
@@ -901,20 +1702,46 @@ func (o *orchestrator) executeConfigOperations(
 		reloadTriggered = false // Runtime API doesn't trigger reload
 		reloadID = ""           // No reload ID
 
-		if err == nil {
-			appliedOps = convertOperationsToApplied(diff.Operations)
+		// Degrade to a transaction if the Runtime API cannot be reached
+		if errors.Is(err, client.ErrRuntimeUnavailable) {
+			o.logger.Warn("Runtime API unavailable, falling back to configuration transaction",
+				"error", err)
+			state.runtimeUnavailable = true
+			state.warn(WarningRuntimeUnavailable, "Runtime API unavailable, server changes were applied through a transaction with reload: %v", err)
+			useRuntime = false
+			retries = 0
+			err = nil
 		}
-	} else {
+	}
+
+	if !useRuntime {
 		// Execute with transaction (triggers reload)
+		txStart := time.Now()
+		executeBefore := state.timings.Execute
+		var txID string
 		commitResult, err = adapter.ExecuteTransaction(ctx, func(ctx context.Context, tx *client.Transaction) error {
 			retries++
+			txID = tx.ID
+
+			// The previous attempt's transaction was discarded
+			o.rollbackCustomOperations(ctx, state)
+
 			o.logger.Info("Executing fine-grained sync",
 				"attempt", retries,
 				"transaction_id", tx.ID,
 				"version", tx.Version)
 
-			// Execute operations within the transaction
-			_, err := synchronizer.SyncOperations(ctx, o.client, diff.Operations, tx)
+			// Execute operations within the transaction, isolating failures
+			// if requested so the other operations are still committed
+			if opts.ContinueOnError {
+				iso := newOperationIsolation()
+				applied, _ := iso.execute(ctx, o.client, timedOps, tx.ID, nil)
+				appliedOps = convertOperationsToApplied(applied)
+				state.failed = iso.failed
+				return nil
+			}
+
+			_, err := synchronizer.SyncOperations(ctx, o.client, timedOps, tx)
 			if err != nil {
 				return err
 			}
@@ -925,21 +1752,35 @@ func (o *orchestrator) executeConfigOperations(
 			return nil
 			// VersionAdapter will commit the transaction after this callback returns
 		})
+		state.timings.Commit += time.Since(txStart) - (state.timings.Execute - executeBefore)
 
 		// Extract reload information from commit result (if successful)
 		if err == nil && commitResult != nil {
 			reloadTriggered = commitResult.StatusCode == 202
 			reloadID = commitResult.ReloadID
 		}
+		if err == nil && len(appliedOps) > 0 {
+			state.committed = append(state.committed, CommittedTransaction{ID: txID, Operations: appliedOps})
+		}
 	}
 
 	if err != nil {
+		o.rollbackCustomOperations(ctx, state)
+
+		if errors.Is(err, client.ErrRetryBudgetExhausted) {
+			return nil, false, "", retries, NewRetryBudgetError("apply", opts.RetryBudget, err)
+		}
+
 		// Check if it's a version conflict error
 		var conflictErr *client.VersionConflictError
 		if errors.As(err, &conflictErr) {
 			return nil, false, "", retries, NewConflictError(retries, conflictErr.ExpectedVersion, conflictErr.ActualVersion)
 		}
 
+		if errors.Is(err, client.ErrTransactionLimit) {
+			return nil, false, "", retries, NewTransactionLimitError(err)
+		}
+
 		// Other errors - return with details
 		return nil, false, "", retries, &SyncError{
 			Stage:   "apply",
@@ -953,6 +1794,10 @@ func (o *orchestrator) executeConfigOperations(
 		}
 	}
 
+	if len(state.failed) > 0 {
+		return appliedOps, reloadTriggered, reloadID, retries, NewPartialSyncError(state.failed)
+	}
+
 	return appliedOps, reloadTriggered, reloadID, retries, nil
 }
 
@@ -1022,10 +1867,11 @@ func (o *orchestrator) checkForChanges(
 }
 
 // createNoChangesResult creates a SyncResult for when no changes are detected.
-func (o *orchestrator) createNoChangesResult(startTime time.Time, summary *comparator.DiffSummary) *SyncResult {
+func (o *orchestrator) createNoChangesResult(startTime time.Time, summary *comparator.DiffSummary, state *syncState) *SyncResult {
 	o.logger.Info("No configuration or auxiliary file changes detected")
 	return &SyncResult{
 		Success:           true,
+		NoOp:              true,
 		AppliedOperations: nil,
 		ReloadTriggered:   false,
 		FallbackToRaw:     false,
@@ -1033,6 +1879,9 @@ func (o *orchestrator) createNoChangesResult(startTime time.Time, summary *compa
 		Retries:           0,
 		Details:           convertDiffSummary(summary),
 		Message:           "No configuration or auxiliary file changes detected",
+		Timings:           state.timings,
+		RetriesUsed:       state.retryBudget.Used(),
+		SkippedOperations: state.skippedOperations(),
 	}
 }
 
@@ -1071,14 +1920,20 @@ func (o *orchestrator) syncAuxiliaryFileType(ctx context.Context, params *auxili
 
 // syncAuxiliaryFilesPreConfig syncs all auxiliary files before config sync (Phase 1).
 // Only creates and updates are synced; deletions are deferred until post-config phase.
+//
+// With IncrementalMaps, map updates applied as Runtime API entry changes are
+// returned instead of synced, since entry changes take effect at once and are
+// not discarded if the configuration transaction fails.
 func (o *orchestrator) syncAuxiliaryFilesPreConfig(
 	ctx context.Context,
+	opts *SyncOptions,
 	fileDiff *auxiliaryfiles.FileDiff,
 	sslDiff *auxiliaryfiles.SSLCertificateDiff,
 	mapDiff *auxiliaryfiles.MapFileDiff,
 	crtlistDiff *auxiliaryfiles.CRTListDiff,
-) error {
+) ([]auxiliaryfiles.MapEntryUpdate, error) {
 	g, gCtx := errgroup.WithContext(ctx)
+	var mapEntryUpdates []auxiliaryfiles.MapEntryUpdate
 
 	// Sync general files if there are changes
 	if fileDiff != nil && fileDiff.HasChanges() {
@@ -1155,6 +2010,13 @@ func (o *orchestrator) syncAuxiliaryFilesPreConfig(
 						ToUpdate: mapDiff.ToUpdate,
 						ToDelete: nil,
 					}
+					if opts.IncrementalMaps {
+						files, updates, err := auxiliaryfiles.PlanMapEntryUpdates(ctx, o.client, preConfigMap, opts.MaxMapEntryChanges)
+						if err != nil {
+							return err
+						}
+						preConfigMap, mapEntryUpdates = files, updates
+					}
 					return auxiliaryfiles.SyncMapFiles(ctx, o.client, preConfigMap)
 				},
 			})
@@ -1189,5 +2051,35 @@ func (o *orchestrator) syncAuxiliaryFilesPreConfig(
 	}
 
 	// Wait for all auxiliary file syncs to complete
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return mapEntryUpdates, nil
+}
+
+// applyMapEntryUpdatesPostConfig applies map entry changes through the Runtime
+// API after the configuration was committed, so that entries referring to
+// new backends go live once those backends exist.
+func (o *orchestrator) applyMapEntryUpdatesPostConfig(ctx context.Context, updates []auxiliaryfiles.MapEntryUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	o.logger.Info("Applying map entry changes", "maps", len(updates))
+
+	if err := auxiliaryfiles.ApplyMapEntryUpdates(ctx, o.client, updates); err != nil {
+		return &SyncError{
+			Stage:   "sync_maps_post",
+			Message: "failed to apply map entry changes after config sync",
+			Cause:   err,
+			Hints: []string{
+				"Check the Runtime API is reachable",
+				"Check map storage permissions",
+				"Review error message for specific map failures",
+			},
+		}
+	}
+
+	o.logger.Info("Map entry changes applied successfully (post-config phase)")
+	return nil
 }
@@ -0,0 +1,1781 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haproxytech/client-native/v6/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/dataplane/auxiliaryfiles"
+	"haproxy-template-ic/pkg/dataplane/builder"
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/parser"
+)
+
+const baseTestConfig = `
+global
+    daemon
+
+defaults
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+`
+
+// newTestClient creates a Client connected to a fake Dataplane API serving currentConfig.
+func newTestClient(t *testing.T, currentConfig string) (*Client, *fakeDataplaneAPI) {
+	t.Helper()
+
+	api := newFakeDataplaneAPI(t, currentConfig)
+	c, err := NewClient(context.Background(), api.endpoint())
+	require.NoError(t, err)
+
+	return c, api
+}
+
+func TestSync_BindCollision(t *testing.T) {
+	desired := baseTestConfig + `
+frontend https
+    bind *:443 name example-com
+    bind *:443 name example-org
+`
+
+	t.Run("not checked by default", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, countBindCreates(result))
+	})
+
+	t.Run("rejected when configured", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.BindCollisionPolicy = BindCollisionReject
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+
+		var syncErr *SyncError
+		require.True(t, errors.As(err, &syncErr))
+		assert.Equal(t, "normalize", syncErr.Stage)
+
+		var collision *comparator.BindCollisionError
+		require.True(t, errors.As(err, &collision))
+		assert.Equal(t, "*:443", collision.Address)
+	})
+
+	t.Run("per-thread binds sync when rejecting", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.BindCollisionPolicy = BindCollisionReject
+
+		result, err := c.Sync(context.Background(), baseTestConfig+`
+frontend http
+    bind :80 name a thread 1
+    bind :80 name b thread 2
+`, nil, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 2, countBindCreates(result))
+	})
+
+	t.Run("merged when configured", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.BindCollisionPolicy = BindCollisionMerge
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.Equal(t, 1, countBindCreates(result))
+	})
+
+	t.Run("merged binds skip the raw fallback", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.failRequest = "PUT /services/haproxy/transactions/tx-1"
+
+		opts := DefaultSyncOptions()
+		opts.BindCollisionPolicy = BindCollisionMerge
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+		require.NotNil(t, result)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningRawFallbackSkipped, result.Warnings[0].Code)
+		assert.Equal(t, "Fine-grained sync failed and the raw fallback was skipped", result.Message)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/configuration/raw")
+	})
+
+	t.Run("not checked by dry run", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		diff, err := c.DryRun(context.Background(), desired)
+		require.NoError(t, err)
+		assert.True(t, diff.HasChanges)
+	})
+}
+
+// countBindCreates returns the number of bind create operations a sync applied.
+func countBindCreates(result *SyncResult) int {
+	var creates int
+	for _, op := range result.AppliedOperations {
+		if op.Section == "bind" && op.Type == "create" {
+			creates++
+		}
+	}
+	return creates
+}
+
+func TestDiffConfigs(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+	desired := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+`
+
+	diff, err := DiffConfigs(current, desired)
+	require.NoError(t, err)
+
+	assert.True(t, diff.HasChanges)
+	require.Len(t, diff.PlannedOperations, 1)
+	assert.Equal(t, "create", diff.PlannedOperations[0].Type)
+	assert.Equal(t, "server", diff.PlannedOperations[0].Section)
+	assert.Equal(t, "srv2", diff.PlannedOperations[0].Resource)
+	assert.Equal(t, []string{"srv2"}, diff.Details.ServersAdded["web"])
+
+	t.Run("identical configs", func(t *testing.T) {
+		diff, err := DiffConfigs(current, current)
+		require.NoError(t, err)
+		assert.False(t, diff.HasChanges)
+		assert.Empty(t, diff.PlannedOperations)
+	})
+
+	t.Run("colliding binds in desired config", func(t *testing.T) {
+		diff, err := DiffConfigs(current, current+`
+frontend https
+    bind *:443 name a
+    bind *:443 name b
+`)
+		require.NoError(t, err)
+		assert.True(t, diff.HasChanges)
+	})
+}
+
+func TestDiffConfigs_FieldDiffs(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    balance roundrobin
+    server srv1 10.0.0.1:80 weight 10
+`
+
+	t.Run("server weight", func(t *testing.T) {
+		diff, err := DiffConfigs(current, strings.Replace(current, "weight 10", "weight 20", 1))
+		require.NoError(t, err)
+
+		require.Len(t, diff.PlannedOperations, 1)
+		op := diff.PlannedOperations[0]
+		assert.Equal(t, "update", op.Type)
+		assert.Equal(t, "server", op.Section)
+		assert.Equal(t, []FieldDiff{{Field: "weight", Old: "10", New: "20"}}, op.FieldDiffs())
+	})
+
+	t.Run("backend attributes", func(t *testing.T) {
+		desired := strings.Replace(current, "balance roundrobin", "balance leastconn\n    retries 3", 1)
+		diff, err := DiffConfigs(current, desired)
+		require.NoError(t, err)
+
+		require.Len(t, diff.PlannedOperations, 1)
+		assert.Equal(t, []FieldDiff{
+			{Field: "balance.algorithm", Old: "roundrobin", New: "leastconn"},
+			{Field: "retries", Old: "", New: "3"},
+		}, diff.PlannedOperations[0].FieldDiffs())
+	})
+
+	t.Run("not reported for creates", func(t *testing.T) {
+		diff, err := DiffConfigs(current, current+"    server srv2 10.0.0.2:80\n")
+		require.NoError(t, err)
+
+		require.Len(t, diff.PlannedOperations, 1)
+		assert.Nil(t, diff.PlannedOperations[0].FieldDiffs())
+	})
+}
+
+func TestSync_RuntimeUnavailableFallsBackToTransaction(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80 weight 10
+`
+	desired := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80 weight 20
+`
+
+	t.Run("runtime available", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.False(t, result.RuntimeUnavailable)
+		assert.False(t, result.ReloadTriggered)
+		assert.Empty(t, result.Warnings)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+
+	t.Run("runtime unavailable", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		api.runtimeUnavailable = true
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.True(t, result.RuntimeUnavailable)
+		assert.False(t, result.FallbackToRaw)
+		assert.True(t, result.ReloadTriggered)
+		assert.Equal(t, "reload-1", result.ReloadID)
+		require.Len(t, result.AppliedOperations, 1)
+		assert.Equal(t, "server", result.AppliedOperations[0].Section)
+		assert.Contains(t, api.Requests(), "POST /services/haproxy/transactions")
+
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningRuntimeUnavailable, result.Warnings[0].Code)
+		assert.Contains(t, result.Warnings[0].Message, "Runtime API unavailable")
+	})
+}
+
+func TestSync_WarnsAboutRecreatedSections(t *testing.T) {
+	current := baseTestConfig + `
+userlist auth
+    group admins
+    user alice password $6$hash1 groups admins
+`
+	desired := baseTestConfig + `
+userlist auth
+    group admins
+    group operators
+    user alice password $6$hash1 groups admins
+`
+
+	c, _ := newTestClient(t, current)
+
+	result, err := c.Sync(context.Background(), desired, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, WarningSectionRecreated, result.Warnings[0].Code)
+	assert.Equal(t, "userlist 'auth' cannot be updated in place and is recreated", result.Warnings[0].Message)
+}
+
+func TestSync_PeersTableCreateAndDelete(t *testing.T) {
+	peers := baseTestConfig + `
+peers mypeers
+    peer haproxy1 10.0.0.1:10000
+`
+	withTable := peers + `    table sessions type ip size 100k expire 30m
+`
+
+	t.Run("create", func(t *testing.T) {
+		c, api := newTestClient(t, peers)
+
+		result, err := c.Sync(context.Background(), withTable, nil, nil)
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.False(t, result.FallbackToRaw)
+		require.Len(t, result.AppliedOperations, 1)
+		assert.Equal(t, "create", result.AppliedOperations[0].Type)
+		assert.Equal(t, "table", result.AppliedOperations[0].Section)
+		assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/peers/mypeers/tables")
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		c, api := newTestClient(t, withTable)
+
+		result, err := c.Sync(context.Background(), peers, nil, nil)
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.False(t, result.FallbackToRaw)
+		require.Len(t, result.AppliedOperations, 1)
+		assert.Equal(t, "delete", result.AppliedOperations[0].Type)
+		assert.Contains(t, api.Requests(), "DELETE /services/haproxy/configuration/peers/mypeers/tables/sessions")
+	})
+}
+
+func TestSync_MailersTimeoutChangeKeepsEntries(t *testing.T) {
+	mailers := func(timeout string) string {
+		return baseTestConfig + `
+mailers alerts
+    timeout mail ` + timeout + `
+    mailer smtp1 192.168.1.100:25
+    mailer smtp2 192.168.1.101:25
+`
+	}
+
+	c, api := newTestClient(t, mailers("10s"))
+
+	result, err := c.Sync(context.Background(), mailers("20s"), nil, nil)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	assert.False(t, result.FallbackToRaw)
+	require.Len(t, result.AppliedOperations, 1)
+	assert.Equal(t, "update", result.AppliedOperations[0].Type)
+	assert.Equal(t, "mailers", result.AppliedOperations[0].Section)
+
+	update := "PUT /services/haproxy/configuration/mailers_section/alerts"
+	require.Contains(t, api.Requests(), update)
+	assert.Contains(t, api.RequestBody(update), `"timeout":20000`)
+	assert.NotContains(t, api.RequestBody(update), "mailer_entries")
+	for _, req := range api.Requests() {
+		assert.NotContains(t, req, "mailer_entries")
+	}
+}
+
+func TestValidateRemote(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	t.Run("valid", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		diff, err := c.ValidateRemote(context.Background(), desired)
+		require.NoError(t, err)
+
+		assert.True(t, diff.HasChanges)
+		assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/backends")
+		assert.Contains(t, api.Requests(), "DELETE /services/haproxy/transactions/tx-1")
+		assert.NotContains(t, api.Requests(), "PUT /services/haproxy/transactions/tx-1")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.validationError = "[ALERT] config : parsing [haproxy.cfg:12] : unknown keyword 'foo'"
+
+		_, err := c.ValidateRemote(context.Background(), desired)
+		require.Error(t, err)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "semantic", validationErr.Phase)
+		assert.Contains(t, validationErr.Message, "unknown keyword 'foo'")
+
+		assert.Contains(t, api.Requests(), "DELETE /services/haproxy/transactions/tx-1")
+		assert.NotContains(t, api.Requests(), "PUT /services/haproxy/transactions/tx-1")
+		assert.Equal(t, baseTestConfig, api.currentConfig, "validation must not change the configuration")
+	})
+}
+
+func TestSync_HTTPRequestRuleInsertedInMiddle(t *testing.T) {
+	rules := func(headers ...string) string {
+		config := baseTestConfig + `
+frontend http
+    bind :80
+`
+		for _, header := range headers {
+			config += "    http-request set-header " + header + " 1\n"
+		}
+		return config
+	}
+
+	c, api := newTestClient(t, rules("X-A", "X-B", "X-C", "X-D"))
+
+	result, err := c.Sync(context.Background(), rules("X-A", "X-B", "X-New", "X-C", "X-D"), nil, nil)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	require.Len(t, result.AppliedOperations, 1)
+	assert.Equal(t, "create", result.AppliedOperations[0].Type)
+	assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/frontends/http/http_request_rules/2")
+}
+
+func TestSync_FrontendHTTPAfterResponseRule(t *testing.T) {
+	frontend := baseTestConfig + `
+frontend http
+    bind :80
+`
+	rule := func(value string) string {
+		return frontend + "    http-after-response set-header X-Served-By " + value + "\n"
+	}
+	const rulePath = "/services/haproxy/configuration/frontends/http/http_after_response_rules/0"
+
+	tests := []struct {
+		name    string
+		current string
+		desired string
+		opType  string
+		request string
+	}{
+		{name: "create", current: frontend, desired: rule("haproxy"), opType: "create", request: "POST " + rulePath},
+		{name: "update", current: rule("haproxy"), desired: rule("edge"), opType: "update", request: "PUT " + rulePath},
+		{name: "delete", current: rule("haproxy"), desired: frontend, opType: "delete", request: "DELETE " + rulePath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, api := newTestClient(t, tt.current)
+
+			result, err := c.Sync(context.Background(), tt.desired, nil, nil)
+			require.NoError(t, err)
+
+			assert.True(t, result.Success)
+			assert.False(t, result.FallbackToRaw)
+			require.Len(t, result.AppliedOperations, 1)
+			assert.Equal(t, tt.opType, result.AppliedOperations[0].Type)
+			assert.Equal(t, "http_after_response_rule", result.AppliedOperations[0].Section)
+			assert.Contains(t, api.Requests(), tt.request)
+		})
+	}
+}
+
+func TestSync_RuntimeVarsSurviveReload(t *testing.T) {
+	withBackend := baseTestConfig + `
+backend web
+    balance roundrobin
+`
+	opts := DefaultSyncOptions()
+	opts.RuntimeVars = map[string]string{"proc.rate_limit": "int(100)"}
+
+	t.Run("declared with reload-triggering changes", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		result, err := c.Sync(context.Background(), withBackend, nil, opts)
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.True(t, result.ReloadTriggered)
+		assert.Contains(t, api.Requests(), "PUT /services/haproxy/configuration/global")
+		assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/backends")
+	})
+
+	t.Run("no changes once declared", func(t *testing.T) {
+		current, err := applyRuntimeVars(withBackend, opts.RuntimeVars)
+		require.NoError(t, err)
+		c, _ := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), withBackend, nil, opts)
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, result.AppliedOperations)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		invalid := DefaultSyncOptions()
+		invalid.RuntimeVars = map[string]string{"rate_limit": "int(100)"}
+
+		_, err := c.Sync(context.Background(), withBackend, nil, invalid)
+		var syncErr *SyncError
+		require.ErrorAs(t, err, &syncErr)
+		assert.Equal(t, "normalize", syncErr.Stage)
+	})
+}
+
+func TestSync_Timings(t *testing.T) {
+	desired := baseTestConfig + `
+backend web
+    server web1 10.0.0.1:80
+    server web2 10.0.0.2:80
+`
+	c, _ := newTestClient(t, baseTestConfig)
+
+	result, err := c.Sync(context.Background(), desired, nil, nil)
+	require.NoError(t, err)
+
+	timings := result.Timings
+	assert.Positive(t, timings.Fetch)
+	assert.Positive(t, timings.Parse)
+	assert.Positive(t, timings.Diff)
+	assert.Positive(t, timings.Execute)
+	assert.Positive(t, timings.Commit)
+	assert.Positive(t, timings.ExecuteBySection["backend"])
+	assert.Positive(t, timings.ExecuteBySection["server"])
+
+	var bySection time.Duration
+	for _, d := range timings.ExecuteBySection {
+		bySection += d
+	}
+	assert.Equal(t, timings.Execute, bySection)
+
+	// Stages do not overlap and cover nearly the whole sync
+	assert.LessOrEqual(t, timings.Total(), result.Duration)
+	assert.InDelta(t, result.Duration, timings.Total(), float64(result.Duration/10+5*time.Millisecond))
+}
+
+func TestSync_PriorityOverrides(t *testing.T) {
+	desired := baseTestConfig + `
+cache static
+    total-max-size 4
+
+http-errors site
+    errorfile 503 /etc/haproxy/errors/503.http
+`
+
+	t.Run("reorders independent sections", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.PriorityOverrides = map[string]int{"http_errors": 12}
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.AppliedOperations, 2)
+		assert.Equal(t, "http_errors", result.AppliedOperations[0].Section)
+		assert.Equal(t, "cache", result.AppliedOperations[1].Section)
+	})
+
+	t.Run("rejects overrides violating dependencies", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.PriorityOverrides = map[string]int{"server": 20}
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+
+		var syncErr *SyncError
+		require.True(t, errors.As(err, &syncErr))
+		assert.Equal(t, "compare", syncErr.Stage)
+		assert.Contains(t, err.Error(), "server (20) must have a higher priority than backend (30)")
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+}
+
+func TestSync_RawFallbackPreservesDesiredConfig(t *testing.T) {
+	desired := "# Generated by haproxy-template-ic - do not edit\n" + baseTestConfig + `
+# API backends
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	c, api := newTestClient(t, baseTestConfig)
+	api.failRequest = "POST /services/haproxy/configuration/backends"
+
+	opts := DefaultSyncOptions()
+	opts.FallbackToRaw = true
+
+	result, err := c.Sync(context.Background(), desired, nil, opts)
+	require.NoError(t, err)
+
+	assert.True(t, result.FallbackToRaw)
+	assert.Equal(t, []string{"backend"}, result.FallbackSections)
+	assert.Equal(t, desired, api.RequestBody("POST /services/haproxy/configuration/raw"))
+}
+
+func TestSync_FailOnDrift(t *testing.T) {
+	current := baseTestConfig + `
+backend ing_api
+    server srv1 10.0.0.1:8080
+
+backend legacy
+    server old 10.0.0.9:8080
+`
+	desired := baseTestConfig + `
+backend ing_web
+    server srv1 10.0.0.2:8080
+`
+
+	opts := DefaultSyncOptions()
+	opts.FailOnDrift = true
+	opts.IsManaged = func(section, name string) bool {
+		return strings.HasPrefix(name, "ing_")
+	}
+
+	t.Run("manually added backend", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+
+		var driftErr *DriftError
+		require.True(t, errors.As(err, &driftErr))
+		assert.Equal(t, []string{"backend 'legacy'"}, driftErr.Resources)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+
+	t.Run("managed resources only", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig+`
+backend ing_api
+    server srv1 10.0.0.1:8080
+`)
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+
+	t.Run("predicate gets section and resource name", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig+`
+backend ing_api
+    http-request deny if { path_beg /admin }
+    server ing_srv1 10.0.0.1:8080
+    server manual 10.0.0.9:8080
+`)
+
+		var checked []string
+		recordOpts := DefaultSyncOptions()
+		recordOpts.FailOnDrift = true
+		recordOpts.IsManaged = func(section, name string) bool {
+			checked = append(checked, section+" "+name)
+			return true
+		}
+
+		_, err := c.Sync(context.Background(), baseTestConfig+`
+backend ing_api
+    server ing_srv1 10.0.0.1:8080
+`, nil, recordOpts)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"http_request_rule 0", "server manual"}, checked)
+	})
+
+	t.Run("rejected without IsManaged", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		noPredicate := DefaultSyncOptions()
+		noPredicate.FailOnDrift = true
+
+		_, err := c.Sync(context.Background(), desired, nil, noPredicate)
+		require.Error(t, err)
+
+		var syncErr *SyncError
+		require.True(t, errors.As(err, &syncErr))
+		assert.Equal(t, "options", syncErr.Stage)
+		assert.NotContains(t, api.Requests(), "GET /services/haproxy/configuration/raw")
+	})
+}
+
+func TestSync_RollbackOnPartialFailure(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80 weight 10
+    server srv2 10.0.0.2:80 weight 10
+`
+	desired := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80 weight 20
+    server srv2 10.0.0.2:80 weight 20
+`
+	const rawPush = "POST /services/haproxy/configuration/raw"
+
+	for _, rollback := range []bool{true, false} {
+		t.Run(fmt.Sprintf("rollback %t", rollback), func(t *testing.T) {
+			c, api := newTestClient(t, current)
+			// Server updates are committed one by one through the Runtime API;
+			// the second one fails
+			api.runtimeChangeLimit = 1
+
+			opts := DefaultSyncOptions()
+			opts.FallbackToRaw = false
+			opts.RollbackOnPartialFailure = rollback
+
+			result, err := c.Sync(context.Background(), desired, nil, opts)
+			require.Error(t, err)
+			require.NotNil(t, result)
+
+			assert.False(t, result.Success)
+			require.Len(t, result.CommittedTransactions, 1)
+			require.Len(t, result.CommittedTransactions[0].Operations, 1)
+			assert.Equal(t, "update", result.CommittedTransactions[0].Operations[0].Type)
+			assert.Empty(t, result.CommittedTransactions[0].ID)
+
+			assert.Equal(t, rollback, result.RolledBack)
+			if rollback {
+				assert.Equal(t, current, api.RequestBody(rawPush))
+				assert.Equal(t, "reload-raw", result.ReloadID)
+			} else {
+				assert.NotContains(t, api.Requests(), rawPush)
+			}
+		})
+	}
+
+	t.Run("no rollback without committed changes", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		api.failRequest = "POST /services/haproxy/transactions"
+
+		opts := DefaultSyncOptions()
+		opts.FallbackToRaw = false
+		opts.RollbackOnPartialFailure = true
+
+		result, err := c.Sync(context.Background(), baseTestConfig, nil, opts)
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.NotContains(t, api.Requests(), rawPush)
+	})
+}
+
+func TestSync_RecordsCommittedTransaction(t *testing.T) {
+	c, _ := newTestClient(t, baseTestConfig)
+
+	result, err := c.Sync(context.Background(), baseTestConfig+`
+backend api
+    server srv1 10.0.0.1:8080
+`, nil, nil)
+	require.NoError(t, err)
+
+	require.Len(t, result.CommittedTransactions, 1)
+	assert.Equal(t, "tx-1", result.CommittedTransactions[0].ID)
+	assert.Equal(t, result.AppliedOperations, result.CommittedTransactions[0].Operations)
+	assert.False(t, result.RolledBack)
+}
+
+func TestSync_ReportsTransactionID(t *testing.T) {
+	c, _ := newTestClient(t, baseTestConfig)
+
+	result, err := c.Sync(context.Background(), baseTestConfig+`
+backend api
+    server srv1 10.0.0.1:8080
+`, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "tx-1", result.TransactionID)
+	assert.Contains(t, result.String(), "Transaction: tx-1")
+
+	t.Run("empty without changes", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		result, err := c.Sync(context.Background(), baseTestConfig, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.TransactionID)
+	})
+}
+
+func TestSync_ReturnAppliedConfig(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	t.Run("returns the applied config when enabled", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		opts := DefaultSyncOptions()
+		opts.ReturnAppliedConfig = true
+		opts.RuntimeVars = map[string]string{"proc.rate_limit": "int(100)"}
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+
+		expected, err := applyRuntimeVars(desired, opts.RuntimeVars)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result.AppliedConfig)
+	})
+
+	t.Run("returns the config without changes", func(t *testing.T) {
+		c, _ := newTestClient(t, desired)
+
+		opts := DefaultSyncOptions()
+		opts.ReturnAppliedConfig = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.Equal(t, desired, result.AppliedConfig)
+	})
+
+	t.Run("empty when disabled", func(t *testing.T) {
+		c, _ := newTestClient(t, baseTestConfig)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.AppliedConfig)
+	})
+}
+
+func TestSync_PeersBindSurvivesServerChange(t *testing.T) {
+	peers := baseTestConfig + `
+peers mypeers
+    bind 10.0.0.1:10000
+    server haproxy2 10.0.0.2:10000
+`
+
+	c, api := newTestClient(t, peers)
+
+	result, err := c.Sync(context.Background(), peers+`    server haproxy3 10.0.0.3:10000
+`, nil, nil)
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	assert.False(t, result.FallbackToRaw)
+	require.Len(t, result.AppliedOperations, 1)
+	assert.Equal(t, "create", result.AppliedOperations[0].Type)
+	assert.Equal(t, "peer_server", result.AppliedOperations[0].Section)
+	assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/peers/mypeers/servers")
+	for _, req := range api.Requests() {
+		assert.NotContains(t, req, "/binds")
+	}
+}
+
+func TestSync_OperationTypeFilter(t *testing.T) {
+	oldBackend := `
+backend old
+    server srv1 10.0.0.1:80
+`
+	newBackend := `
+backend new
+    server srv1 10.0.0.2:80
+`
+	filtered := func(types ...OperationType) *SyncOptions {
+		opts := DefaultSyncOptions()
+		opts.OperationTypeFilter = types
+		return opts
+	}
+
+	// Creates-only pass: the new backend is added, the old one is kept
+	c, api := newTestClient(t, baseTestConfig+oldBackend)
+
+	result, err := c.Sync(context.Background(), baseTestConfig+newBackend, nil, filtered(OperationCreate))
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	require.Len(t, result.AppliedOperations, 2)
+	assert.Equal(t, "backend", result.AppliedOperations[0].Section)
+	assert.Equal(t, "server", result.AppliedOperations[1].Section)
+	for _, op := range result.AppliedOperations {
+		assert.Equal(t, "create", op.Type)
+	}
+	assert.Equal(t, 2, result.Details.TotalOperations)
+	for _, req := range api.Requests() {
+		assert.NotContains(t, req, "DELETE")
+	}
+
+	// Deletes pass against the configuration left by the creates pass
+	c, api = newTestClient(t, baseTestConfig+oldBackend+newBackend)
+
+	result, err = c.Sync(context.Background(), baseTestConfig+newBackend, nil, filtered(OperationDelete))
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	require.Len(t, result.AppliedOperations, 1)
+	assert.Equal(t, "delete", result.AppliedOperations[0].Type)
+	assert.Equal(t, "backend", result.AppliedOperations[0].Section)
+	assert.Equal(t, "old", result.AppliedOperations[0].Resource)
+	assert.Contains(t, api.Requests(), "DELETE /services/haproxy/configuration/backends/old")
+
+	t.Run("no matching operations", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig+newBackend)
+
+		result, err := c.Sync(context.Background(), baseTestConfig+newBackend+oldBackend, nil, filtered(OperationDelete))
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, result.AppliedOperations)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+
+	t.Run("rule create after a filtered rule delete is held back", func(t *testing.T) {
+		current := baseTestConfig + `
+frontend web
+    bind *:80 name http
+    http-request deny if { path_beg /old }
+    http-request allow if { src 10.0.0.0/8 }
+`
+		desired := baseTestConfig + `
+frontend web
+    bind *:80 name http
+    http-request allow if { src 10.0.0.0/8 }
+    http-request deny if { path_beg /admin }
+`
+		c, api := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), desired, nil, filtered(OperationCreate))
+		require.NoError(t, err)
+
+		assert.True(t, result.Success)
+		assert.Empty(t, result.AppliedOperations)
+		assert.Equal(t, 0, result.Details.TotalOperations)
+		require.Len(t, result.SkippedOperations, 2)
+		assert.Equal(t, "delete", result.SkippedOperations[0].Type)
+		assert.Equal(t, SkipReasonFiltered, result.SkippedOperations[0].Reason)
+		assert.Equal(t, "create", result.SkippedOperations[1].Type)
+		assert.Equal(t, SkipReasonFilteredDependency, result.SkippedOperations[1].Reason)
+		for _, request := range api.Requests() {
+			assert.NotContains(t, request, "http_request_rules")
+		}
+	})
+}
+
+func TestSync_NoOp(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+
+	t.Run("unchanged config opens no transaction", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), current, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.True(t, result.NoOp)
+		assert.Empty(t, result.AppliedOperations)
+		assert.False(t, result.ReloadTriggered)
+		assert.Empty(t, result.TransactionID)
+
+		for _, request := range api.Requests() {
+			assert.NotContains(t, request, "/services/haproxy/transactions", "no transaction may be opened")
+			assert.True(t, strings.HasPrefix(request, "GET "), "unexpected change request %s", request)
+		}
+	})
+
+	t.Run("changed config is not a no-op", func(t *testing.T) {
+		c, _ := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), current+"    server srv2 10.0.0.2:80\n", nil, nil)
+		require.NoError(t, err)
+		assert.False(t, result.NoOp)
+		assert.NotEmpty(t, result.AppliedOperations)
+	})
+}
+
+func TestSync_RollbackSnapshot(t *testing.T) {
+	previous := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+	desired := previous + "    server srv2 10.0.0.2:80\n"
+	const restoreRequest = "DELETE /services/haproxy/configuration/backends/web/servers/srv2"
+
+	t.Run("rollback restores the captured config", func(t *testing.T) {
+		c, api := newTestClient(t, previous)
+		opts := DefaultSyncOptions()
+		opts.CaptureRollbackSnapshot = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		require.NotNil(t, result.RollbackSnapshot)
+		assert.Equal(t, previous, result.RollbackSnapshot.Config)
+		assert.False(t, result.RollbackSnapshot.CapturedAt.IsZero())
+
+		// The fake API does not apply fine-grained changes itself
+		api.setConfig(desired)
+
+		rollback, err := c.Rollback(context.Background(), result.RollbackSnapshot)
+		require.NoError(t, err)
+		assert.True(t, rollback.Success)
+		assert.False(t, rollback.NoOp)
+		assert.Contains(t, api.Requests(), restoreRequest)
+		require.Len(t, rollback.AppliedOperations, 1)
+		assert.Equal(t, "delete", rollback.AppliedOperations[0].Type)
+
+		// Once restored, rolling back again changes nothing
+		api.setConfig(previous)
+		rollback, err = c.Rollback(context.Background(), result.RollbackSnapshot)
+		require.NoError(t, err)
+		assert.True(t, rollback.NoOp)
+	})
+
+	t.Run("not captured by default", func(t *testing.T) {
+		c, _ := newTestClient(t, previous)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, result.RollbackSnapshot)
+	})
+
+	t.Run("rollback requires a snapshot", func(t *testing.T) {
+		c, api := newTestClient(t, previous)
+
+		_, err := c.Rollback(context.Background(), nil)
+		require.Error(t, err)
+		assert.NotContains(t, api.Requests(), "GET /services/haproxy/configuration/raw")
+	})
+}
+
+func TestSync_DrainBeforeDelete(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+`
+	desired := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+	const drainRequest = "PUT /services/haproxy/runtime/backends/web/servers/srv2"
+	const deleteRequest = "DELETE /services/haproxy/configuration/backends/web/servers/srv2"
+
+	drainOptions := func() *SyncOptions {
+		opts := DefaultSyncOptions()
+		opts.DrainBeforeDelete = true
+		opts.DrainGracePeriod = 50 * time.Millisecond
+		return opts
+	}
+
+	t.Run("drains before deleting", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), desired, nil, drainOptions())
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+
+		requests := api.Requests()
+		drainIdx := slices.Index(requests, drainRequest)
+		deleteIdx := slices.Index(requests, deleteRequest)
+		require.NotEqual(t, -1, drainIdx, "server must be drained: %v", requests)
+		require.NotEqual(t, -1, deleteIdx, "server must be deleted: %v", requests)
+		assert.Less(t, drainIdx, deleteIdx)
+		assert.Contains(t, api.RequestBody(drainRequest), `"admin_state":"drain"`)
+		assert.GreaterOrEqual(t, result.Timings.Drain, 50*time.Millisecond)
+	})
+
+	t.Run("deletes immediately when disabled", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		_, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.NotContains(t, api.Requests(), drainRequest)
+		assert.Contains(t, api.Requests(), deleteRequest)
+	})
+
+	t.Run("deletes undrainable servers with a warning", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		api.failRequest = drainRequest
+
+		result, err := c.Sync(context.Background(), desired, nil, drainOptions())
+		require.NoError(t, err)
+
+		assert.Contains(t, api.Requests(), deleteRequest)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningDrainFailed, result.Warnings[0].Code)
+	})
+}
+
+func TestSync_PreserveServerState(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+    server srv3 10.0.0.3:80
+`
+	// Changing the balance algorithm needs a reload, srv2 is removed
+	desired := baseTestConfig + `
+backend web
+    balance leastconn
+    server srv1 10.0.0.1:80
+    server srv3 10.0.0.3:80
+`
+	const captureRequest = "GET /services/haproxy/runtime/backends/web/servers"
+	const restoreRequest = "PUT /services/haproxy/runtime/backends/web/servers/srv1"
+	const commitRequest = "PUT /services/haproxy/transactions/tx-1"
+
+	newAPI := func(t *testing.T) (*Client, *fakeDataplaneAPI) {
+		c, api := newTestClient(t, current)
+		api.runtimeServers = map[string]string{"web": `[
+			{"name":"srv1","admin_state":"maint","operational_state":"down"},
+			{"name":"srv2","admin_state":"drain","operational_state":"up"},
+			{"name":"srv3","admin_state":"ready","operational_state":"up"}]`}
+		return c, api
+	}
+
+	t.Run("restores states after reload", func(t *testing.T) {
+		c, api := newAPI(t)
+		opts := DefaultSyncOptions()
+		opts.PreserveServerState = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		require.True(t, result.ReloadTriggered)
+
+		requests := api.Requests()
+		captureIdx := slices.Index(requests, captureRequest)
+		commitIdx := slices.Index(requests, commitRequest)
+		restoreIdx := slices.Index(requests, restoreRequest)
+		require.NotEqual(t, -1, captureIdx, "states must be captured: %v", requests)
+		require.NotEqual(t, -1, restoreIdx, "srv1 must be restored: %v", requests)
+		assert.Less(t, captureIdx, commitIdx)
+		assert.Less(t, commitIdx, restoreIdx)
+		assert.Contains(t, requests, "GET /services/haproxy/reloads/reload-1", "restore must wait for the reload")
+		assert.Contains(t, api.RequestBody(restoreRequest), `"admin_state":"maint"`)
+
+		// The removed srv2 and the ready srv3 are left alone
+		assert.NotContains(t, requests, "PUT /services/haproxy/runtime/backends/web/servers/srv2")
+		assert.NotContains(t, requests, "PUT /services/haproxy/runtime/backends/web/servers/srv3")
+		assert.Equal(t, []ServerState{{Backend: "web", Server: "srv1", AdminState: "maint"}}, result.RestoredServerStates)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		c, api := newAPI(t)
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.NotContains(t, api.Requests(), captureRequest)
+		assert.NotContains(t, api.Requests(), restoreRequest)
+		assert.Empty(t, result.RestoredServerStates)
+	})
+
+	t.Run("warns when states cannot be captured", func(t *testing.T) {
+		c, api := newAPI(t)
+		api.failRequest = captureRequest
+		opts := DefaultSyncOptions()
+		opts.PreserveServerState = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+
+		assert.NotContains(t, api.Requests(), restoreRequest)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningServerStateFailed, result.Warnings[0].Code)
+	})
+}
+
+func TestSync_NamespacePrefix(t *testing.T) {
+	current := baseTestConfig + `
+backend tenant-a_web
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+
+backend tenant-a_old
+    server srv1 10.0.0.3:80
+
+backend tenant-b_web
+    server srv1 10.0.0.4:80
+`
+	desired := baseTestConfig + `
+backend tenant-a_web
+    server srv1 10.0.0.1:80
+`
+	opts := DefaultSyncOptions()
+	opts.NamespacePrefix = "tenant-a_"
+
+	t.Run("deletes only resources with the prefix", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+
+		requests := api.Requests()
+		assert.Contains(t, requests, "DELETE /services/haproxy/configuration/backends/tenant-a_old")
+		assert.Contains(t, requests, "DELETE /services/haproxy/configuration/backends/tenant-a_web/servers/srv2")
+		for _, req := range requests {
+			assert.NotContains(t, req, "tenant-b_")
+		}
+		assert.Equal(t, 2, result.Details.Deletes)
+		assert.Equal(t, []string{"tenant-a_old"}, result.Details.BackendsDeleted)
+	})
+
+	t.Run("resources of other tenants are not drift", func(t *testing.T) {
+		c, _ := newTestClient(t, current)
+
+		driftOpts := *opts
+		driftOpts.FailOnDrift = true
+		driftOpts.IsManaged = func(section, name string) bool {
+			return name != "tenant-a_old"
+		}
+
+		_, err := c.Sync(context.Background(), desired, nil, &driftOpts)
+		require.Error(t, err)
+
+		var driftErr *DriftError
+		require.True(t, errors.As(err, &driftErr))
+		assert.Equal(t, []string{"backend 'tenant-a_old'"}, driftErr.Resources)
+	})
+
+	t.Run("no changes within the prefix", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig+`
+backend tenant-a_web
+    server srv1 10.0.0.1:80
+
+backend tenant-b_web
+    server srv1 10.0.0.4:80
+`)
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+
+	t.Run("reports the skipped raw fallback", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		api.failRequest = "PUT /services/haproxy/transactions/tx-1"
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+		require.NotNil(t, result)
+		assert.False(t, result.Success)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningRawFallbackSkipped, result.Warnings[0].Code)
+		assert.Contains(t, result.Warnings[0].Message, "namespace prefix")
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/configuration/raw")
+	})
+}
+
+func TestSync_Overlays(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    server srv1 10.0.0.1:80
+`
+	desired := current
+
+	t.Run("overlay replaces a backend", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		opts := DefaultSyncOptions()
+		opts.Overlays = []string{`
+backend web
+    server canary 10.0.1.1:80
+`}
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+
+		requests := api.Requests()
+		assert.Contains(t, requests, "POST /services/haproxy/configuration/backends/web/servers")
+		assert.Contains(t, requests, "DELETE /services/haproxy/configuration/backends/web/servers/srv1")
+	})
+
+	t.Run("conflicting overlays fail the sync", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		opts := DefaultSyncOptions()
+		opts.Overlays = []string{
+			"backend web\n    server a 10.0.1.1:80\n",
+			"backend web\n    server b 10.0.1.2:80\n",
+		}
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+
+		var syncErr *SyncError
+		require.True(t, errors.As(err, &syncErr))
+		assert.Equal(t, "normalize", syncErr.Stage)
+
+		var conflict *parser.OverlayConflictError
+		require.True(t, errors.As(err, &conflict))
+		assert.Equal(t, "web", conflict.Name)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+	})
+}
+
+func TestSync_AddBeforeRemoveBinds(t *testing.T) {
+	frontend := func(port string) string {
+		return baseTestConfig + `
+frontend http
+    bind *:` + port + `
+`
+	}
+
+	// bindRequests returns the bind requests in the order they were made.
+	bindRequests := func(api *fakeDataplaneAPI) []string {
+		var binds []string
+		for _, request := range api.Requests() {
+			if strings.Contains(request, "/frontends/http/binds") {
+				method, _, _ := strings.Cut(request, " ")
+				binds = append(binds, method)
+			}
+		}
+		return binds
+	}
+
+	t.Run("creates the new bind first when enabled", func(t *testing.T) {
+		c, api := newTestClient(t, frontend("80"))
+
+		opts := DefaultSyncOptions()
+		opts.AddBeforeRemoveBinds = true
+
+		_, err := c.Sync(context.Background(), frontend("8080"), nil, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"POST", "DELETE"}, bindRequests(api))
+	})
+
+	t.Run("deletes the old bind first by default", func(t *testing.T) {
+		c, api := newTestClient(t, frontend("80"))
+
+		_, err := c.Sync(context.Background(), frontend("8080"), nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"DELETE", "POST"}, bindRequests(api))
+	})
+}
+
+func TestSync_TransactionLimit(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	t.Run("cleans up stale transactions and retries when enabled", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.staleTransactions = []string{"stale-1", "stale-2"}
+
+		opts := DefaultSyncOptions()
+		opts.FallbackToRaw = false
+		opts.CleanupStaleTransactions = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+
+		requests := api.Requests()
+		assert.Contains(t, requests, "DELETE /services/haproxy/transactions/stale-1")
+		assert.Contains(t, requests, "DELETE /services/haproxy/transactions/stale-2")
+		assert.Contains(t, requests, "PUT /services/haproxy/transactions/tx-1")
+	})
+
+	t.Run("reports the limit when disabled", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.staleTransactions = []string{"stale-1"}
+
+		opts := DefaultSyncOptions()
+		opts.FallbackToRaw = false
+
+		_, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, client.ErrTransactionLimit)
+		assert.NotContains(t, api.Requests(), "GET /services/haproxy/transactions")
+		assert.NotContains(t, api.Requests(), "DELETE /services/haproxy/transactions/stale-1")
+	})
+}
+
+func TestSync_RetryBudget(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+
+	t.Run("counts retries across version conflicts and commits", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.transactionConflicts = 2
+		api.commitConflicts = 1
+
+		opts := DefaultSyncOptions()
+		opts.MaxRetries = 5
+		opts.RetryBudget = 3
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, 3, result.RetriesUsed)
+	})
+
+	t.Run("fails once the budget is used up", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.transactionConflicts = 2
+		api.commitConflicts = 2
+
+		// Each limit alone would allow the sync to succeed
+		opts := DefaultSyncOptions()
+		opts.MaxRetries = 5
+		opts.RetryBudget = 3
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, client.ErrRetryBudgetExhausted)
+		require.NotNil(t, result)
+		assert.Equal(t, 3, result.RetriesUsed)
+		require.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningRawFallbackSkipped, result.Warnings[0].Code)
+
+		var syncErr *SyncError
+		require.ErrorAs(t, err, &syncErr)
+		assert.Equal(t, "apply", syncErr.Stage)
+		assert.Equal(t, "retry budget of 3 retries exhausted", syncErr.Message)
+
+		// Two conflicting starts and two conflicting commits, no raw fallback
+		commits := 0
+		for _, request := range api.Requests() {
+			if request == "PUT /services/haproxy/transactions/tx-1" {
+				commits++
+			}
+		}
+		assert.Equal(t, 2, commits)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/configuration/raw")
+	})
+
+	t.Run("is unlimited by default", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.transactionConflicts = 1
+		api.commitConflicts = 1
+
+		result, err := c.Sync(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.RetriesUsed)
+		assert.Equal(t, 1, result.Retries)
+	})
+}
+
+func TestSync_IncrementalMaps(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+	currentMap := "a.example.com 1\nb.example.com 2\nc.example.com 3\n"
+	desiredMap := "a.example.com 1\nb.example.com 20\nd.example.com 4\n"
+
+	resync := func(t *testing.T, api *fakeDataplaneAPI, c *Client, content string) []string {
+		t.Helper()
+
+		opts := DefaultSyncOptions()
+		opts.IncrementalMaps = true
+
+		auxFiles := &AuxiliaryFiles{MapFiles: []auxiliaryfiles.MapFile{{Path: "hosts.map", Content: content}}}
+		result, err := c.Sync(context.Background(), desired, auxFiles, opts)
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+		return api.Requests()
+	}
+
+	sync := func(t *testing.T, api *fakeDataplaneAPI, c *Client, content string) []string {
+		t.Helper()
+		api.mapFiles = map[string]string{"hosts.map": currentMap}
+		return resync(t, api, c, content)
+	}
+
+	t.Run("applies entry changes through the runtime API", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		requests := sync(t, api, c, desiredMap)
+		assert.Contains(t, requests, "PUT /services/haproxy/runtime/maps/hosts.map/entries/b.example.com")
+		assert.Contains(t, requests, "DELETE /services/haproxy/runtime/maps/hosts.map/entries/c.example.com")
+		assert.Contains(t, requests, "POST /services/haproxy/runtime/maps/hosts.map/entries")
+		assert.Contains(t, api.RequestBody("POST /services/haproxy/runtime/maps/hosts.map/entries"), `"key":"d.example.com"`)
+	})
+
+	t.Run("stores the desired content without a reload", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		requests := sync(t, api, c, desiredMap)
+		entry := slices.Index(requests, "POST /services/haproxy/runtime/maps/hosts.map/entries")
+		store := slices.Index(requests, "PUT /services/haproxy/storage/maps/hosts.map")
+		require.NotEqual(t, -1, store)
+		assert.Greater(t, store, entry)
+		assert.Contains(t, api.RequestQuery("PUT /services/haproxy/storage/maps/hosts.map"), "skip_reload=true")
+		assert.Equal(t, desiredMap, api.mapFiles["hosts.map"])
+	})
+
+	t.Run("leaves the map alone on the next sync", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		commented := "# hosts\n" + desiredMap
+
+		first := len(sync(t, api, c, commented))
+		requests := resync(t, api, c, commented)[first:]
+		assert.NotContains(t, requests, "PUT /services/haproxy/storage/maps/hosts.map")
+		for _, request := range requests {
+			assert.NotContains(t, request, "/runtime/maps/")
+		}
+	})
+
+	t.Run("applies entry changes after the transaction is committed", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		requests := sync(t, api, c, desiredMap)
+		commit := slices.Index(requests, "PUT /services/haproxy/transactions/tx-1")
+		entry := slices.Index(requests, "POST /services/haproxy/runtime/maps/hosts.map/entries")
+		require.NotEqual(t, -1, commit)
+		assert.Greater(t, entry, commit)
+	})
+
+	t.Run("leaves runtime entries unchanged when the transaction fails", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.mapFiles = map[string]string{"hosts.map": currentMap}
+		api.failRequest = "POST /services/haproxy/configuration/backends/api/servers"
+
+		opts := DefaultSyncOptions()
+		opts.IncrementalMaps = true
+		opts.FallbackToRaw = false
+
+		auxFiles := &AuxiliaryFiles{MapFiles: []auxiliaryfiles.MapFile{{Path: "hosts.map", Content: desiredMap}}}
+		_, err := c.Sync(context.Background(), desired, auxFiles, opts)
+		require.Error(t, err)
+
+		for _, request := range api.Requests() {
+			assert.NotContains(t, request, "/runtime/maps/")
+		}
+		assert.Equal(t, currentMap, api.mapFiles["hosts.map"])
+	})
+
+	t.Run("replaces the file when the runtime API rejects a change", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+		api.failRequest = "POST /services/haproxy/runtime/maps/hosts.map/entries"
+
+		requests := sync(t, api, c, desiredMap)
+		assert.Contains(t, requests, "PUT /services/haproxy/storage/maps/hosts.map")
+	})
+
+	t.Run("replaces the file when it is not sorted", func(t *testing.T) {
+		c, api := newTestClient(t, baseTestConfig)
+
+		requests := sync(t, api, c, "d.example.com 4\na.example.com 1\n")
+		assert.Contains(t, requests, "PUT /services/haproxy/storage/maps/hosts.map")
+		assert.NotContains(t, requests, "POST /services/haproxy/runtime/maps/hosts.map/entries")
+	})
+}
+
+func TestSync_ContinueOnError(t *testing.T) {
+	current := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+
+backend old
+    server srv1 10.0.0.9:8080
+`
+
+	sync := func(t *testing.T, desired, failRequest string) (*fakeDataplaneAPI, *SyncResult, error) {
+		t.Helper()
+		c, api := newTestClient(t, current)
+		api.failRequest = failRequest
+
+		opts := DefaultSyncOptions()
+		opts.ContinueOnError = true
+
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		return api, result, err
+	}
+
+	t.Run("skips dependents and applies independent operations", func(t *testing.T) {
+		desired := current + `
+backend web
+    server srv1 10.0.1.1:8080
+`
+		desired = strings.Replace(desired, "    server srv1 10.0.0.1:8080\n", "    server srv1 10.0.0.1:8080\n    server srv2 10.0.0.2:8080\n", 1)
+
+		api, result, err := sync(t, desired, "POST /services/haproxy/configuration/backends")
+		require.Error(t, err)
+
+		var partial *PartialSyncError
+		require.True(t, errors.As(err, &partial))
+		require.NotNil(t, result)
+		assert.False(t, result.Success)
+		assert.Equal(t, partial.Failed, result.FailedOperations)
+
+		require.Len(t, result.FailedOperations, 2)
+		assert.Equal(t, "backend", result.FailedOperations[0].Section)
+		assert.False(t, result.FailedOperations[0].Skipped)
+		assert.Equal(t, "server", result.FailedOperations[1].Section)
+		assert.True(t, result.FailedOperations[1].Skipped)
+		assert.Contains(t, result.FailedOperations[1].Error.Error(), result.FailedOperations[0].Description)
+
+		requests := api.Requests()
+		assert.Contains(t, requests, "POST /services/haproxy/configuration/backends/api/servers")
+		assert.NotContains(t, requests, "POST /services/haproxy/configuration/backends/web/servers")
+		assert.Contains(t, requests, "PUT /services/haproxy/transactions/tx-1")
+		assert.NotContains(t, requests, "POST /services/haproxy/configuration/raw")
+
+		require.Len(t, result.CommittedTransactions, 1)
+		assert.Len(t, result.CommittedTransactions[0].Operations, 1)
+	})
+
+	t.Run("skips later rules of a list after a rule failed", func(t *testing.T) {
+		rules := func(headers ...string) string {
+			config := current + `
+frontend http
+    bind :80
+`
+			for _, header := range headers {
+				config += "    http-request set-header " + header + " 1\n"
+			}
+			return config
+		}
+
+		c, api := newTestClient(t, rules("X-A"))
+		api.failRequest = "POST /services/haproxy/configuration/frontends/http/http_request_rules/1"
+
+		opts := DefaultSyncOptions()
+		opts.ContinueOnError = true
+
+		result, err := c.Sync(context.Background(), rules("X-A", "X-B", "X-C"), nil, opts)
+		require.Error(t, err)
+		require.NotNil(t, result)
+
+		require.Len(t, result.FailedOperations, 2)
+		assert.False(t, result.FailedOperations[0].Skipped)
+		assert.True(t, result.FailedOperations[1].Skipped)
+		assert.NotContains(t, api.Requests(), "POST /services/haproxy/configuration/frontends/http/http_request_rules/2")
+	})
+
+	t.Run("does not skip children of a backend sharing the failed frontend's name", func(t *testing.T) {
+		desired := current + `
+frontend web
+    bind :80
+    default_backend web
+
+backend web
+    server srv1 10.0.1.1:8080
+`
+
+		api, result, err := sync(t, desired, "POST /services/haproxy/configuration/frontends")
+		require.Error(t, err)
+		require.NotNil(t, result)
+
+		for _, failed := range result.FailedOperations {
+			assert.NotEqual(t, "server", failed.Section, failed.Description)
+		}
+		requests := api.Requests()
+		assert.Contains(t, requests, "POST /services/haproxy/configuration/backends/web/servers")
+		assert.NotContains(t, requests, "POST /services/haproxy/configuration/frontends/web/binds")
+	})
+}
+
+func TestSync_SkippedOperations(t *testing.T) {
+	current := baseTestConfig + `
+backend tenant-a_old
+    server srv1 10.0.0.1:80
+
+backend tenant-b_web
+    server srv1 10.0.0.2:80
+`
+
+	t.Run("filtered", func(t *testing.T) {
+		c, _ := newTestClient(t, current)
+
+		opts := DefaultSyncOptions()
+		opts.OperationTypeFilter = []OperationType{OperationCreate}
+
+		result, err := c.Sync(context.Background(), baseTestConfig, nil, opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.SkippedOperations, 2)
+		for _, skipped := range result.SkippedOperations {
+			assert.Equal(t, "delete", skipped.Type)
+			assert.Equal(t, "backend", skipped.Section)
+			assert.Equal(t, SkipReasonFiltered, skipped.Reason)
+			assert.NotEmpty(t, skipped.Description)
+		}
+	})
+
+	t.Run("not owned", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+
+		opts := DefaultSyncOptions()
+		opts.NamespacePrefix = "tenant-a_"
+
+		result, err := c.Sync(context.Background(), baseTestConfig, nil, opts)
+		require.NoError(t, err)
+
+		require.Len(t, result.SkippedOperations, 1)
+		skipped := result.SkippedOperations[0]
+		assert.Equal(t, "delete", skipped.Type)
+		assert.Equal(t, "tenant-b_web", skipped.Resource)
+		assert.Equal(t, SkipReasonNotOwned, skipped.Reason)
+		assert.Contains(t, api.Requests(), "DELETE /services/haproxy/configuration/backends/tenant-a_old")
+	})
+
+	t.Run("dependency failed", func(t *testing.T) {
+		c, api := newTestClient(t, current)
+		api.failRequest = "POST /services/haproxy/configuration/backends"
+
+		opts := DefaultSyncOptions()
+		opts.ContinueOnError = true
+
+		desired := current + `
+backend web
+    server srv1 10.0.1.1:8080
+`
+		result, err := c.Sync(context.Background(), desired, nil, opts)
+		require.Error(t, err)
+		require.NotNil(t, result)
+
+		require.Len(t, result.SkippedOperations, 1)
+		skipped := result.SkippedOperations[0]
+		assert.Equal(t, "create", skipped.Type)
+		assert.Equal(t, "server", skipped.Section)
+		assert.Equal(t, SkipReasonDependencyFailed, skipped.Reason)
+
+		// The failed operation itself is not skipped
+		require.Len(t, result.FailedOperations, 2)
+		assert.Equal(t, result.FailedOperations[1].Description, skipped.Description)
+	})
+
+	t.Run("nothing skipped", func(t *testing.T) {
+		c, _ := newTestClient(t, current)
+
+		result, err := c.Sync(context.Background(), baseTestConfig, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, result.SkippedOperations)
+	})
+}
+
+func TestSync_DanglingDefaultsReference(t *testing.T) {
+	c, api := newTestClient(t, baseTestConfig)
+
+	_, err := c.Sync(context.Background(), baseTestConfig+`
+backend web from missing-defaults
+    server srv1 10.0.0.1:80
+`, nil, nil)
+	require.Error(t, err)
+
+	var syncErr *SyncError
+	require.True(t, errors.As(err, &syncErr))
+	assert.Equal(t, "normalize", syncErr.Stage)
+
+	var danglingErr *comparator.DanglingDefaultsError
+	require.True(t, errors.As(err, &danglingErr))
+	assert.Equal(t, "missing-defaults", danglingErr.Defaults)
+	assert.NotContains(t, api.Requests(), "POST /services/haproxy/transactions")
+}
+
+func TestSyncFromModel(t *testing.T) {
+	current := baseTestConfig + `
+backend web
+    mode http
+    server srv1 10.0.0.1:80
+`
+	c, api := newTestClient(t, current)
+
+	timeout := func(d int64) *int64 { return &d }
+	desired, err := builder.New().
+		WithGlobal(&models.Global{GlobalBase: models.GlobalBase{Daemon: true}}).
+		AddDefaults(&models.Defaults{DefaultsBase: models.DefaultsBase{
+			Name:           "unnamed_defaults_1",
+			Mode:           "http",
+			ConnectTimeout: timeout(5000),
+			ClientTimeout:  timeout(30000),
+			ServerTimeout:  timeout(30000),
+		}}).
+		AddBackend("web", "http").
+		AddServer("web", "srv1", "10.0.0.1", 80).
+		AddServer("web", "srv2", "10.0.0.2", 80).
+		Build()
+	require.NoError(t, err)
+
+	result, err := c.SyncFromModel(context.Background(), desired, nil, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	require.Len(t, result.AppliedOperations, 1)
+	assert.Equal(t, "Create server 'srv2' in backend 'web'", result.AppliedOperations[0].Description)
+	assert.Contains(t, api.Requests(), "POST /services/haproxy/configuration/backends/web/servers")
+
+	t.Run("unsupported model", func(t *testing.T) {
+		desired.Backends[0].HTTPRequestRuleList = models.HTTPRequestRules{{Type: "deny"}}
+
+		_, err := c.SyncFromModel(context.Background(), desired, nil, nil)
+		assert.ErrorContains(t, err, "only section attributes and servers can be serialized")
+	})
+}
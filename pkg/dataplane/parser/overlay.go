@@ -0,0 +1,178 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+// OverlayConflictError reports a named section defined by more than one overlay.
+type OverlayConflictError struct {
+	// Section is the section type (e.g., "backend")
+	Section string
+
+	// Name is the name of the section
+	Name string
+
+	// Overlays are the indexes of the two conflicting overlays
+	Overlays [2]int
+}
+
+// Error implements the error interface.
+func (e *OverlayConflictError) Error() string {
+	return fmt.Sprintf("%s '%s' is defined by overlays %d and %d",
+		e.Section, e.Name, e.Overlays[0], e.Overlays[1])
+}
+
+// MergeOverlays merges the named sections of overlays into base and returns
+// the result as a new configuration.
+//
+// An overlay section replaces the base section of the same type and name as a
+// whole, including children such as servers. Sections that only overlays
+// define are appended after the base sections, in overlay order. The global
+// section always comes from base. Two overlays defining the same section are a
+// conflict and returned as *OverlayConflictError, since the result would
+// otherwise depend on the overlay order.
+//
+// The result shares section models with base and overlays, so they must not
+// be modified afterwards.
+func MergeOverlays(base *StructuredConfig, overlays ...*StructuredConfig) (*StructuredConfig, error) {
+	if base == nil {
+		return nil, fmt.Errorf("base configuration is nil")
+	}
+
+	merged := &StructuredConfig{Global: base.Global}
+	var err error
+
+	if merged.Defaults, err = mergeSections("defaults", base, overlays,
+		func(c *StructuredConfig) []*models.Defaults { return c.Defaults },
+		func(d *models.Defaults) string { return d.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Frontends, err = mergeSections("frontend", base, overlays,
+		func(c *StructuredConfig) []*models.Frontend { return c.Frontends },
+		func(f *models.Frontend) string { return f.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Backends, err = mergeSections("backend", base, overlays,
+		func(c *StructuredConfig) []*models.Backend { return c.Backends },
+		func(b *models.Backend) string { return b.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Peers, err = mergeSections("peers", base, overlays,
+		func(c *StructuredConfig) []*models.PeerSection { return c.Peers },
+		func(p *models.PeerSection) string { return p.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Resolvers, err = mergeSections("resolvers", base, overlays,
+		func(c *StructuredConfig) []*models.Resolver { return c.Resolvers },
+		func(r *models.Resolver) string { return r.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Mailers, err = mergeSections("mailers", base, overlays,
+		func(c *StructuredConfig) []*models.MailersSection { return c.Mailers },
+		func(m *models.MailersSection) string { return m.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Caches, err = mergeSections("cache", base, overlays,
+		func(c *StructuredConfig) []*models.Cache { return c.Caches },
+		func(c *models.Cache) string {
+			if c.Name == nil {
+				return ""
+			}
+			return *c.Name
+		}); err != nil {
+		return nil, err
+	}
+	if merged.Rings, err = mergeSections("ring", base, overlays,
+		func(c *StructuredConfig) []*models.Ring { return c.Rings },
+		func(r *models.Ring) string { return r.Name }); err != nil {
+		return nil, err
+	}
+	if merged.HTTPErrors, err = mergeSections("http-errors", base, overlays,
+		func(c *StructuredConfig) []*models.HTTPErrorsSection { return c.HTTPErrors },
+		func(h *models.HTTPErrorsSection) string { return h.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Userlists, err = mergeSections("userlist", base, overlays,
+		func(c *StructuredConfig) []*models.Userlist { return c.Userlists },
+		func(u *models.Userlist) string { return u.Name }); err != nil {
+		return nil, err
+	}
+	if merged.Programs, err = mergeSections("program", base, overlays,
+		func(c *StructuredConfig) []*models.Program { return c.Programs },
+		func(p *models.Program) string { return p.Name }); err != nil {
+		return nil, err
+	}
+	if merged.LogForwards, err = mergeSections("log-forward", base, overlays,
+		func(c *StructuredConfig) []*models.LogForward { return c.LogForwards },
+		func(l *models.LogForward) string { return l.Name }); err != nil {
+		return nil, err
+	}
+	if merged.FCGIApps, err = mergeSections("fcgi-app", base, overlays,
+		func(c *StructuredConfig) []*models.FCGIApp { return c.FCGIApps },
+		func(f *models.FCGIApp) string { return f.Name }); err != nil {
+		return nil, err
+	}
+	if merged.CrtStores, err = mergeSections("crt-store", base, overlays,
+		func(c *StructuredConfig) []*models.CrtStore { return c.CrtStores },
+		func(c *models.CrtStore) string { return c.Name }); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// mergeSections merges the sections of one type returned by list, as
+// described in MergeOverlays.
+func mergeSections[T any](
+	section string,
+	base *StructuredConfig,
+	overlays []*StructuredConfig,
+	list func(*StructuredConfig) []*T,
+	name func(*T) string,
+) ([]*T, error) {
+	// Collect the overlay sections, rejecting names defined twice
+	replacements := make(map[string]*T)
+	definedBy := make(map[string]int)
+	var added []string
+	for i, overlay := range overlays {
+		if overlay == nil {
+			continue
+		}
+		for _, s := range list(overlay) {
+			if s == nil {
+				continue
+			}
+			n := name(s)
+			if first, exists := definedBy[n]; exists {
+				return nil, &OverlayConflictError{Section: section, Name: n, Overlays: [2]int{first, i}}
+			}
+			definedBy[n] = i
+			replacements[n] = s
+			added = append(added, n)
+		}
+	}
+
+	baseSections := list(base)
+	merged := make([]*T, 0, len(baseSections)+len(added))
+	inBase := make(map[string]bool, len(baseSections))
+	for _, s := range baseSections {
+		if s == nil {
+			continue
+		}
+		n := name(s)
+		inBase[n] = true
+		if replacement, ok := replacements[n]; ok {
+			s = replacement
+		}
+		merged = append(merged, s)
+	}
+	for _, n := range added {
+		if !inBase[n] {
+			merged = append(merged, replacements[n])
+		}
+	}
+
+	return merged, nil
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+// parseOverlayTestConfig parses config, failing the test on error.
+func parseOverlayTestConfig(t *testing.T, config string) *StructuredConfig {
+	t.Helper()
+
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	parsed, err := p.ParseFromString(config)
+	if err != nil {
+		t.Fatalf("ParseFromString() failed: %v", err)
+	}
+	return parsed
+}
+
+// TestMergeOverlays_OverridesBackendServers verifies an overlay backend replaces
+// the base backend including its servers.
+func TestMergeOverlays_OverridesBackendServers(t *testing.T) {
+	base := parseOverlayTestConfig(t, `
+global
+    daemon
+
+backend api
+    server api1 10.0.0.1:8080
+
+backend web
+    server web1 10.0.0.2:80
+    server web2 10.0.0.3:80
+`)
+	overlay := parseOverlayTestConfig(t, `
+backend web
+    server web-canary 10.0.1.2:80
+
+backend extra
+    server extra1 10.0.2.1:80
+`)
+
+	merged, err := MergeOverlays(base, overlay)
+	if err != nil {
+		t.Fatalf("MergeOverlays() failed: %v", err)
+	}
+
+	if !merged.Global.Daemon {
+		t.Error("Expected global section from base")
+	}
+
+	if len(merged.Backends) != 3 {
+		t.Fatalf("Expected 3 backends, got %d", len(merged.Backends))
+	}
+	if merged.Backends[2].Name != "extra" {
+		t.Errorf("Expected new backend extra to be appended, got %s", merged.Backends[2].Name)
+	}
+
+	var web *models.Backend
+	for i, backend := range merged.Backends {
+		if backend.Name == "web" {
+			web = backend
+			if base.Backends[i].Name != "web" {
+				t.Errorf("Expected overlaid backend to keep its base position")
+			}
+		}
+	}
+	if web == nil {
+		t.Fatal("Expected backend web in merged configuration")
+	}
+	if len(web.Servers) != 1 {
+		t.Fatalf("Expected 1 server in overlaid backend, got %d", len(web.Servers))
+	}
+	if _, ok := web.Servers["web-canary"]; !ok {
+		t.Errorf("Expected server web-canary from overlay, got %v", web.Servers)
+	}
+
+	for _, backend := range base.Backends {
+		if backend.Name == "web" && len(backend.Servers) != 2 {
+			t.Error("Base configuration must not be modified")
+		}
+	}
+}
+
+// TestMergeOverlays_Conflict verifies two overlays defining the same section are rejected.
+func TestMergeOverlays_Conflict(t *testing.T) {
+	base := parseOverlayTestConfig(t, `
+backend web
+    server web1 10.0.0.2:80
+`)
+	first := parseOverlayTestConfig(t, `
+backend web
+    server web2 10.0.0.3:80
+`)
+	second := parseOverlayTestConfig(t, `
+backend web
+    server web3 10.0.0.4:80
+`)
+
+	_, err := MergeOverlays(base, first, second)
+
+	var conflict *OverlayConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Expected *OverlayConflictError, got %v", err)
+	}
+	if conflict.Section != "backend" || conflict.Name != "web" || conflict.Overlays != [2]int{0, 1} {
+		t.Errorf("Unexpected conflict: %+v", *conflict)
+	}
+}
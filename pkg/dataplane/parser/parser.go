@@ -15,6 +15,9 @@ import (
 	"sync"
 
 	parser "github.com/haproxytech/client-native/v6/config-parser"
+	"github.com/haproxytech/client-native/v6/config-parser/parsers/actions"
+	tcptypes "github.com/haproxytech/client-native/v6/config-parser/parsers/tcp/types"
+	"github.com/haproxytech/client-native/v6/config-parser/types"
 	"github.com/haproxytech/client-native/v6/configuration"
 	"github.com/haproxytech/client-native/v6/models"
 )
@@ -359,7 +362,7 @@ func (p *Parser) extractFrontends() ([]*models.Frontend, error) {
 		}
 		fe.HTTPRequestRuleList, _ = configuration.ParseHTTPRequestRules(string(parser.Frontends), sectionName, p.parser)
 		fe.HTTPResponseRuleList, _ = configuration.ParseHTTPResponseRules(string(parser.Frontends), sectionName, p.parser)
-		fe.TCPRequestRuleList, _ = configuration.ParseTCPRequestRules(string(parser.Frontends), sectionName, p.parser)
+		fe.TCPRequestRuleList = p.parseTCPRequestRules(parser.Frontends, sectionName)
 		fe.HTTPAfterResponseRuleList, _ = configuration.ParseHTTPAfterRules(string(parser.Frontends), sectionName, p.parser)
 		fe.HTTPErrorRuleList, _ = configuration.ParseHTTPErrorRules(string(parser.Frontends), sectionName, p.parser)
 		fe.FilterList, _ = configuration.ParseFilters(string(parser.Frontends), sectionName, p.parser)
@@ -439,7 +442,7 @@ func (p *Parser) parseBackendServers(sectionName string, be *models.Backend) {
 func (p *Parser) parseBackendRules(sectionName string, be *models.Backend) {
 	be.HTTPRequestRuleList, _ = configuration.ParseHTTPRequestRules(string(parser.Backends), sectionName, p.parser)
 	be.HTTPResponseRuleList, _ = configuration.ParseHTTPResponseRules(string(parser.Backends), sectionName, p.parser)
-	be.TCPRequestRuleList, _ = configuration.ParseTCPRequestRules(string(parser.Backends), sectionName, p.parser)
+	be.TCPRequestRuleList = p.parseTCPRequestRules(parser.Backends, sectionName)
 	be.TCPResponseRuleList, _ = configuration.ParseTCPResponseRules(string(parser.Backends), sectionName, p.parser)
 	be.HTTPAfterResponseRuleList, _ = configuration.ParseHTTPAfterRules(string(parser.Backends), sectionName, p.parser)
 	be.HTTPErrorRuleList, _ = configuration.ParseHTTPErrorRules(string(parser.Backends), sectionName, p.parser)
@@ -447,6 +450,38 @@ func (p *Parser) parseBackendRules(sectionName string, be *models.Backend) {
 	be.StickRuleList, _ = configuration.ParseStickRules(sectionName, p.parser)
 }
 
+// parseTCPRequestRules parses the tcp-request rules of a frontend or backend.
+//
+// configuration.ParseTCPRequestRules turns "tcp-request session reject" into an
+// accept rule, which would hide the difference between the two from the
+// comparator and push the wrong action. The action is taken from the
+// config-parser rule instead.
+func (p *Parser) parseTCPRequestRules(sectionType parser.Section, sectionName string) models.TCPRequestRules {
+	data, err := p.parser.Get(sectionType, sectionName, "tcp-request", false)
+	if err != nil {
+		return nil
+	}
+	parsed, ok := data.([]types.TCPType)
+	if !ok {
+		return nil
+	}
+
+	var rules models.TCPRequestRules
+	for _, r := range parsed {
+		rule, err := configuration.ParseTCPRequestRule(r)
+		if err != nil {
+			continue
+		}
+		if session, ok := r.(*tcptypes.Session); ok {
+			if _, reject := session.Action.(*actions.Reject); reject {
+				rule.Action = models.TCPRequestRuleActionReject
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
 // parseBackendFiltersAndChecks parses filters, log targets, and health checks for a backend.
 func (p *Parser) parseBackendFiltersAndChecks(sectionName string, be *models.Backend) {
 	be.FilterList, _ = configuration.ParseFilters(string(parser.Backends), sectionName, p.parser)
@@ -497,6 +532,37 @@ func (p *Parser) extractPeers() ([]*models.PeerSection, error) {
 			}
 		}
 
+		// Convert Tables slice to map
+		tables, _ := configuration.ParseTables(sectionName, p.parser)
+		if tables != nil {
+			peer.Tables = make(map[string]models.Table)
+			for _, table := range tables {
+				if table != nil {
+					peer.Tables[table.Name] = *table
+				}
+			}
+		}
+
+		// Parse the local peer's binds and servers; ParseSection only fills the section attributes
+		binds, _ := configuration.ParseBinds(configuration.PeersParentName, sectionName, p.parser)
+		if binds != nil {
+			peer.Binds = make(map[string]models.Bind)
+			for _, bind := range binds {
+				if bind != nil {
+					peer.Binds[bind.Name] = *bind
+				}
+			}
+		}
+		servers, _ := configuration.ParseServers(configuration.PeersParentName, sectionName, p.parser)
+		if servers != nil {
+			peer.Servers = make(map[string]models.Server)
+			for _, server := range servers {
+				if server != nil {
+					peer.Servers[server.Name] = *server
+				}
+			}
+		}
+
 		peers = append(peers, peer)
 	}
 
@@ -616,6 +682,17 @@ func (p *Parser) extractRings() ([]*models.Ring, error) {
 			continue
 		}
 
+		// Parse servers the ring forwards its events to
+		servers, _ := configuration.ParseServers(configuration.RingParentName, sectionName, p.parser)
+		if servers != nil {
+			ring.Servers = make(map[string]models.Server)
+			for _, server := range servers {
+				if server != nil {
+					ring.Servers[server.Name] = *server
+				}
+			}
+		}
+
 		rings = append(rings, ring)
 	}
 
@@ -747,6 +824,26 @@ func (p *Parser) extractLogForwards() ([]*models.LogForward, error) {
 			continue
 		}
 
+		// Parse nested binds; ParseLogForward only fills the section attributes
+		binds, _ := configuration.ParseBinds(configuration.LogForwardParentName, sectionName, p.parser)
+		if binds != nil {
+			logForward.Binds = make(map[string]models.Bind)
+			for _, bind := range binds {
+				if bind != nil {
+					logForward.Binds[bind.Name] = *bind
+				}
+			}
+		}
+		dgramBinds, _ := configuration.ParseDgramBinds(sectionName, p.parser)
+		if dgramBinds != nil {
+			logForward.DgramBinds = make(map[string]models.DgramBind)
+			for _, dgramBind := range dgramBinds {
+				if dgramBind != nil {
+					logForward.DgramBinds[dgramBind.Name] = *dgramBind
+				}
+			}
+		}
+
 		logForwards = append(logForwards, logForward)
 	}
 
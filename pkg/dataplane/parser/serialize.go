@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+
+	parser "github.com/haproxytech/client-native/v6/config-parser"
+	"github.com/haproxytech/client-native/v6/configuration"
+	"github.com/haproxytech/client-native/v6/configuration/options"
+	"github.com/haproxytech/client-native/v6/models"
+)
+
+// Serialize renders a structured configuration as HAProxy configuration text,
+// the inverse of ParseFromString.
+//
+// Only the sections needed to describe a basic proxy setup are supported: the
+// global section, defaults sections, and frontends and backends with their
+// binds and servers. A configuration using any other section or any other
+// section child (ACLs, rules, checks, ...) returns an error instead of being
+// rendered without it. Binds and servers are rendered sorted by name.
+func Serialize(conf *StructuredConfig) (string, error) {
+	if conf == nil {
+		return "", fmt.Errorf("configuration is nil")
+	}
+	if err := checkSerializable(conf); err != nil {
+		return "", err
+	}
+
+	// Lock to prevent concurrent access to client-native parser
+	parserMutex.Lock()
+	defer parserMutex.Unlock()
+
+	p, err := parser.New()
+	if err != nil {
+		return "", fmt.Errorf("failed to create parser: %w", err)
+	}
+	opt := &options.ConfigurationOptions{}
+
+	if conf.Global != nil {
+		if err := configuration.SerializeGlobalSection(p, conf.Global, opt); err != nil {
+			return "", fmt.Errorf("failed to serialize global section: %w", err)
+		}
+		for i, log := range conf.Global.LogTargetList {
+			if err := p.Insert(parser.Global, parser.GlobalSectionName, "log", configuration.SerializeLogTarget(*log), i); err != nil {
+				return "", fmt.Errorf("failed to serialize global log target %d: %w", i, err)
+			}
+		}
+	}
+
+	for i, defaults := range conf.Defaults {
+		name := defaults.Name
+		if name == "" {
+			name = fmt.Sprintf("unnamed_defaults_%d", i+1)
+		}
+		if err := createSection(p, parser.Defaults, name, &defaults.DefaultsBase, opt); err != nil {
+			return "", fmt.Errorf("failed to serialize defaults section '%s': %w", name, err)
+		}
+	}
+
+	for _, frontend := range conf.Frontends {
+		if err := createSection(p, parser.Frontends, frontend.Name, &frontend.FrontendBase, opt); err != nil {
+			return "", fmt.Errorf("failed to serialize frontend '%s': %w", frontend.Name, err)
+		}
+		for _, name := range sortedKeys(frontend.Binds) {
+			if err := p.Insert(parser.Frontends, frontend.Name, "bind", configuration.SerializeBind(frontend.Binds[name], opt), -1); err != nil {
+				return "", fmt.Errorf("failed to serialize bind '%s' of frontend '%s': %w", name, frontend.Name, err)
+			}
+		}
+	}
+
+	for _, backend := range conf.Backends {
+		if err := createSection(p, parser.Backends, backend.Name, &backend.BackendBase, opt); err != nil {
+			return "", fmt.Errorf("failed to serialize backend '%s': %w", backend.Name, err)
+		}
+		for _, name := range sortedKeys(backend.Servers) {
+			if err := p.Insert(parser.Backends, backend.Name, "server", configuration.SerializeServer(backend.Servers[name], opt), -1); err != nil {
+				return "", fmt.Errorf("failed to serialize server '%s' of backend '%s': %w", name, backend.Name, err)
+			}
+		}
+	}
+
+	return p.String(), nil
+}
+
+// createSection creates a section and sets its attributes from base.
+func createSection(p parser.Parser, section parser.Section, name string, base interface{}, opt *options.ConfigurationOptions) error {
+	if err := p.SectionsCreate(section, name); err != nil {
+		return err
+	}
+	return configuration.CreateEditSection(base, section, name, p, opt)
+}
+
+// checkSerializable returns an error naming the first part of conf that
+// Serialize does not support.
+func checkSerializable(conf *StructuredConfig) error {
+	unsupported := map[string]int{
+		"peers":       len(conf.Peers),
+		"resolvers":   len(conf.Resolvers),
+		"mailers":     len(conf.Mailers),
+		"cache":       len(conf.Caches),
+		"ring":        len(conf.Rings),
+		"http-errors": len(conf.HTTPErrors),
+		"userlist":    len(conf.Userlists),
+		"program":     len(conf.Programs),
+		"log-forward": len(conf.LogForwards),
+		"fcgi-app":    len(conf.FCGIApps),
+		"crt-store":   len(conf.CrtStores),
+	}
+	for _, section := range sortedKeys(unsupported) {
+		if unsupported[section] > 0 {
+			return fmt.Errorf("serializing %s sections is not supported", section)
+		}
+	}
+
+	for _, defaults := range conf.Defaults {
+		if !defaults.Equal(models.Defaults{DefaultsBase: defaults.DefaultsBase}) {
+			return fmt.Errorf("defaults section '%s': only section attributes can be serialized", defaults.Name)
+		}
+	}
+	for _, frontend := range conf.Frontends {
+		if !frontend.Equal(models.Frontend{FrontendBase: frontend.FrontendBase, Binds: frontend.Binds}) {
+			return fmt.Errorf("frontend '%s': only section attributes and binds can be serialized", frontend.Name)
+		}
+	}
+	for _, backend := range conf.Backends {
+		if !backend.Equal(models.Backend{BackendBase: backend.BackendBase, Servers: backend.Servers}) {
+			return fmt.Errorf("backend '%s': only section attributes and servers can be serialized", backend.Name)
+		}
+	}
+
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
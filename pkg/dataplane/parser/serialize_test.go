@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSerialize_RoundTrip(t *testing.T) {
+	config := `
+global
+    daemon
+    maxconn 4096
+
+defaults base
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+
+frontend http
+    mode http
+    bind :80
+    bind :8080 name alt
+    default_backend web
+
+backend web
+    mode http
+    balance roundrobin
+    server s2 10.0.0.2:80 check
+    server s1 10.0.0.1:80 check weight 10
+`
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	original, err := p.ParseFromString(config)
+	if err != nil {
+		t.Fatalf("ParseFromString() failed: %v", err)
+	}
+
+	serialized, err := Serialize(original)
+	if err != nil {
+		t.Fatalf("Serialize() failed: %v", err)
+	}
+
+	// Servers are rendered sorted by name
+	if strings.Index(serialized, "server s1") > strings.Index(serialized, "server s2") {
+		t.Errorf("Expected servers sorted by name:\n%s", serialized)
+	}
+
+	reparsed, err := p.ParseFromString(serialized)
+	if err != nil {
+		t.Fatalf("ParseFromString() of serialized config failed: %v\n%s", err, serialized)
+	}
+
+	if !reparsed.Global.Equal(*original.Global) {
+		t.Errorf("Global section changed in round trip:\n%s", serialized)
+	}
+	if len(reparsed.Defaults) != 1 || !reparsed.Defaults[0].Equal(*original.Defaults[0]) {
+		t.Errorf("Defaults section changed in round trip:\n%s", serialized)
+	}
+	if len(reparsed.Frontends) != 1 || !reparsed.Frontends[0].Equal(*original.Frontends[0]) {
+		t.Errorf("Frontend changed in round trip:\n%s", serialized)
+	}
+	if len(reparsed.Backends) != 1 || !reparsed.Backends[0].Equal(*original.Backends[0]) {
+		t.Errorf("Backend changed in round trip:\n%s", serialized)
+	}
+}
+
+func TestSerialize_Unsupported(t *testing.T) {
+	p, err := New()
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	conf, err := p.ParseFromString(`
+global
+    daemon
+
+backend web
+    http-request deny
+    server s1 10.0.0.1:80
+`)
+	if err != nil {
+		t.Fatalf("ParseFromString() failed: %v", err)
+	}
+
+	_, err = Serialize(conf)
+	if err == nil || !strings.Contains(err.Error(), "backend 'web'") {
+		t.Fatalf("Expected error for unsupported http-request rule, got: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package dataplane
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"haproxy-template-ic/pkg/dataplane/comparator"
+)
+
+// Hash returns a stable hash of the planned operations.
+//
+// Two diffs hash equally when they plan the same operations on the same
+// resources with the same models, regardless of the order the comparator
+// produced them in. Descriptions are not part of the hash. This lets callers
+// remember the hash of the last applied plan and skip a sync when the next
+// diff produces the same plan again. A diff without operations hashes to the
+// hash of the empty plan, so HasChanges should be checked separately.
+func (r *DiffResult) Hash() string {
+	entries := make([]string, 0, len(r.PlannedOperations))
+	for i := range r.PlannedOperations {
+		op := &r.PlannedOperations[i]
+
+		identity := op.fingerprint
+		if identity == "" {
+			identity = op.Resource
+		}
+		entries = append(entries, strings.Join([]string{
+			op.Type,
+			op.Section,
+			strconv.Itoa(op.Priority),
+			identity,
+		}, "\x00"))
+	}
+
+	// Operations with equal priority may be listed in any order
+	sort.Strings(entries)
+
+	h := sha256.New()
+	for _, entry := range entries {
+		h.Write([]byte(entry))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// operationFingerprint identifies the resource an operation targets and the
+// model it applies. Models that cannot be serialized fall back to the target.
+func operationFingerprint(op comparator.Operation) string {
+	model, err := json.Marshal(op.Model())
+	if err != nil {
+		return op.Target()
+	}
+
+	return op.Target() + "\x00" + string(model)
+}
@@ -0,0 +1,81 @@
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResult_Hash(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+    server srv2 10.0.0.2:8080
+
+backend web
+    server srv1 10.0.1.1:8080
+`
+	c, _ := newTestClient(t, baseTestConfig)
+	defer c.Close()
+
+	diff := func(t *testing.T, config string) *DiffResult {
+		t.Helper()
+		result, err := c.Diff(context.Background(), config)
+		require.NoError(t, err)
+		require.True(t, result.HasChanges)
+		return result
+	}
+
+	t.Run("equivalent diffs hash equally", func(t *testing.T) {
+		first := diff(t, desired)
+		second := diff(t, desired)
+		assert.Equal(t, first.Hash(), second.Hash())
+	})
+
+	t.Run("operation order does not matter", func(t *testing.T) {
+		result := diff(t, desired)
+		want := result.Hash()
+
+		ops := result.PlannedOperations
+		for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+			ops[i], ops[j] = ops[j], ops[i]
+		}
+		assert.Equal(t, want, result.Hash())
+	})
+
+	t.Run("descriptions do not matter", func(t *testing.T) {
+		result := diff(t, desired)
+		want := result.Hash()
+
+		for i := range result.PlannedOperations {
+			result.PlannedOperations[i].Description = "changed"
+		}
+		assert.Equal(t, want, result.Hash())
+	})
+
+	t.Run("changed model changes hash", func(t *testing.T) {
+		changed := diff(t, baseTestConfig+`
+backend api
+    server srv1 10.0.0.1:8080
+    server srv2 10.0.0.3:8080
+
+backend web
+    server srv1 10.0.1.1:8080
+`)
+		assert.NotEqual(t, diff(t, desired).Hash(), changed.Hash())
+	})
+
+	t.Run("changed parent changes hash", func(t *testing.T) {
+		moved := diff(t, baseTestConfig+`
+backend api
+    server srv1 10.0.1.1:8080
+    server srv2 10.0.0.2:8080
+
+backend web
+    server srv1 10.0.0.1:8080
+`)
+		assert.NotEqual(t, diff(t, desired).Hash(), moved.Hash())
+	})
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// PoolOptions configures a PoolClient.
+type PoolOptions struct {
+	// ContinueOnError keeps syncing the remaining endpoints after one fails.
+	// When false, SyncAll stops at the first failed endpoint and reports the
+	// endpoints after it as skipped.
+	ContinueOnError bool
+}
+
+// PoolClient synchronizes the same configuration to a pool of HAProxy
+// instances, such as the replicas of one HAProxy deployment.
+//
+// Endpoints are synced one after another in the order they were given, so with
+// ContinueOnError disabled the first endpoint acts as a canary for the rest.
+type PoolClient struct {
+	endpoints []Endpoint
+	opts      PoolOptions
+
+	mu      sync.Mutex
+	clients map[string]*Client // keyed by endpoint URL
+	closed  bool
+}
+
+// PoolSyncResult contains the outcome of a PoolClient.SyncAll call.
+// All maps are keyed by endpoint URL.
+type PoolSyncResult struct {
+	// Results contains the sync results of endpoints that synced successfully
+	Results map[string]*SyncResult
+
+	// Errors contains the errors of endpoints that failed to sync
+	Errors map[string]error
+
+	// Skipped lists endpoints that were not synced because an earlier
+	// endpoint failed and ContinueOnError is disabled
+	Skipped []string
+}
+
+// Succeeded returns the number of endpoints that synced successfully.
+func (r *PoolSyncResult) Succeeded() int {
+	return len(r.Results)
+}
+
+// Failed returns the number of endpoints that failed to sync.
+func (r *PoolSyncResult) Failed() int {
+	return len(r.Errors)
+}
+
+// NewPoolClient creates a PoolClient for the given endpoints.
+//
+// A client is connected to every endpoint up front. Endpoints that cannot be
+// reached yet do not fail the pool; connecting to them is retried on each
+// SyncAll and reported as that endpoint's sync error if it fails again.
+//
+// Example:
+//
+//	pool, err := dataplane.NewPoolClient(ctx, endpoints, &dataplane.PoolOptions{
+//	    ContinueOnError: true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer pool.Close()
+//
+//	result, err := pool.SyncAll(ctx, desiredConfig, nil, nil)
+func NewPoolClient(ctx context.Context, endpoints []Endpoint, opts *PoolOptions) (*PoolClient, error) {
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("pool requires at least one endpoint")
+	}
+
+	seen := make(map[string]bool, len(endpoints))
+	for i := range endpoints {
+		if seen[endpoints[i].URL] {
+			return nil, fmt.Errorf("duplicate endpoint %q in pool", endpoints[i].URL)
+		}
+		seen[endpoints[i].URL] = true
+	}
+
+	p := &PoolClient{
+		endpoints: append([]Endpoint(nil), endpoints...),
+		clients:   make(map[string]*Client, len(endpoints)),
+	}
+	if opts != nil {
+		p.opts = *opts
+	}
+
+	for i := range p.endpoints {
+		if _, err := p.client(ctx, &p.endpoints[i]); err != nil {
+			slog.Default().Warn("Dataplane endpoint not reachable, will retry on sync",
+				"url", p.endpoints[i].URL,
+				"pod", p.endpoints[i].PodName,
+				"error", err)
+		}
+	}
+
+	return p, nil
+}
+
+// Endpoints returns the endpoints of the pool in sync order.
+func (p *PoolClient) Endpoints() []Endpoint {
+	return append([]Endpoint(nil), p.endpoints...)
+}
+
+// SyncAll synchronizes desiredConfig to every endpoint of the pool.
+//
+// The returned PoolSyncResult is always non-nil and contains the per-endpoint
+// results. The error is non-nil if any endpoint failed and joins the errors of
+// all failed endpoints.
+func (p *PoolClient) SyncAll(ctx context.Context, desiredConfig string, auxFiles *AuxiliaryFiles, opts *SyncOptions) (*PoolSyncResult, error) {
+	result := &PoolSyncResult{
+		Results: make(map[string]*SyncResult),
+		Errors:  make(map[string]error),
+	}
+
+	var errs []error
+	for i := range p.endpoints {
+		endpoint := &p.endpoints[i]
+
+		if len(errs) > 0 && !p.opts.ContinueOnError {
+			result.Skipped = append(result.Skipped, endpoint.URL)
+			continue
+		}
+
+		syncResult, err := p.syncEndpoint(ctx, endpoint, desiredConfig, auxFiles, opts)
+		if err != nil {
+			result.Errors[endpoint.URL] = err
+			errs = append(errs, fmt.Errorf("endpoint %s: %w", endpoint.URL, err))
+			continue
+		}
+		result.Results[endpoint.URL] = syncResult
+	}
+
+	return result, errors.Join(errs...)
+}
+
+// Close closes the clients of all endpoints. Endpoints synced after Close
+// fail with ErrClientClosed.
+func (p *PoolClient) Close() error {
+	p.mu.Lock()
+	clients := p.clients
+	p.clients = make(map[string]*Client)
+	p.closed = true
+	p.mu.Unlock()
+
+	// Closing a client waits for its syncs in flight, so the lock is not held
+	var errs []error
+	for url, c := range clients {
+		if err := c.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("endpoint %s: %w", url, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// syncEndpoint syncs a single endpoint, connecting to it first if needed.
+func (p *PoolClient) syncEndpoint(ctx context.Context, endpoint *Endpoint, desiredConfig string, auxFiles *AuxiliaryFiles, opts *SyncOptions) (*SyncResult, error) {
+	c, err := p.client(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Sync(ctx, desiredConfig, auxFiles, opts)
+}
+
+// client returns the cached client for endpoint, creating it if necessary.
+func (p *PoolClient) client(ctx context.Context, endpoint *Endpoint) (*Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, ErrClientClosed
+	}
+	if c, ok := p.clients[endpoint.URL]; ok {
+		return c, nil
+	}
+
+	c, err := NewClient(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	p.clients[endpoint.URL] = c
+
+	return c, nil
+}
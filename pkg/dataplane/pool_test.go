@@ -0,0 +1,122 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unreachableEndpoint returns an Endpoint whose server has already been shut down.
+func unreachableEndpoint(t *testing.T) Endpoint {
+	t.Helper()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	return Endpoint{URL: server.URL, Username: "admin", Password: "password", PodName: "haproxy-down"}
+}
+
+func TestPoolClient_SyncAll(t *testing.T) {
+	desired := baseTestConfig + `
+backend api
+    server srv1 10.0.0.1:8080
+`
+	const commit = "PUT /services/haproxy/transactions/tx-1"
+
+	t.Run("continue on error", func(t *testing.T) {
+		down := unreachableEndpoint(t)
+		api := newFakeDataplaneAPI(t, baseTestConfig)
+
+		pool, err := NewPoolClient(context.Background(), []Endpoint{down, *api.endpoint()}, &PoolOptions{ContinueOnError: true})
+		require.NoError(t, err)
+		defer pool.Close()
+
+		result, err := pool.SyncAll(context.Background(), desired, nil, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), down.URL)
+
+		assert.Equal(t, 1, result.Failed())
+		assert.Contains(t, result.Errors, down.URL)
+		assert.Equal(t, 1, result.Succeeded())
+		require.Contains(t, result.Results, api.server.URL)
+		assert.True(t, result.Results[api.server.URL].Success)
+		assert.Empty(t, result.Skipped)
+		assert.Contains(t, api.Requests(), commit)
+	})
+
+	t.Run("stop on first error", func(t *testing.T) {
+		down := unreachableEndpoint(t)
+		api := newFakeDataplaneAPI(t, baseTestConfig)
+
+		pool, err := NewPoolClient(context.Background(), []Endpoint{down, *api.endpoint()}, nil)
+		require.NoError(t, err)
+		defer pool.Close()
+
+		result, err := pool.SyncAll(context.Background(), desired, nil, nil)
+		require.Error(t, err)
+
+		assert.Equal(t, 1, result.Failed())
+		assert.Equal(t, 0, result.Succeeded())
+		assert.Equal(t, []string{api.server.URL}, result.Skipped)
+		assert.NotContains(t, api.Requests(), commit)
+	})
+
+	t.Run("all endpoints synced", func(t *testing.T) {
+		api1 := newFakeDataplaneAPI(t, baseTestConfig)
+		api2 := newFakeDataplaneAPI(t, baseTestConfig)
+
+		pool, err := NewPoolClient(context.Background(), []Endpoint{*api1.endpoint(), *api2.endpoint()}, nil)
+		require.NoError(t, err)
+		defer pool.Close()
+
+		result, err := pool.SyncAll(context.Background(), desired, nil, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, result.Succeeded())
+		assert.Contains(t, api1.Requests(), commit)
+		assert.Contains(t, api2.Requests(), commit)
+	})
+
+	t.Run("closed pool", func(t *testing.T) {
+		api := newFakeDataplaneAPI(t, baseTestConfig)
+
+		pool, err := NewPoolClient(context.Background(), []Endpoint{*api.endpoint()}, nil)
+		require.NoError(t, err)
+		require.NoError(t, pool.Close())
+		requests := len(api.Requests())
+
+		result, err := pool.SyncAll(context.Background(), desired, nil, nil)
+		require.ErrorIs(t, err, ErrClientClosed)
+		assert.Equal(t, 1, result.Failed())
+		assert.Len(t, api.Requests(), requests, "no client is connected after Close")
+		require.NoError(t, pool.Close())
+	})
+}
+
+func TestNewPoolClient_InvalidEndpoints(t *testing.T) {
+	_, err := NewPoolClient(context.Background(), nil, nil)
+	require.Error(t, err)
+
+	api := newFakeDataplaneAPI(t, baseTestConfig)
+	_, err = NewPoolClient(context.Background(), []Endpoint{*api.endpoint(), *api.endpoint()}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate endpoint")
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import "haproxy-template-ic/pkg/dataplane/client"
+
+// Recorder captures Dataplane API interactions to a file for later replay.
+// This type is re-exported from pkg/dataplane/client for convenience.
+type Recorder = client.Recorder
+
+// Interaction is a single recorded Dataplane API request/response pair.
+// This type is re-exported from pkg/dataplane/client for convenience.
+type Interaction = client.Interaction
+
+// ReplayTransport serves recorded interactions instead of contacting a Dataplane API.
+// This type is re-exported from pkg/dataplane/client for convenience.
+type ReplayTransport = client.ReplayTransport
+
+// NewRecorder creates a Recorder writing interactions to the given file.
+//
+// Set it on Endpoint.Recorder to capture every request a Client makes:
+//
+//	recorder, err := dataplane.NewRecorder("/tmp/sync.jsonl")
+//	if err != nil {
+//	    return err
+//	}
+//	defer recorder.Close()
+//
+//	endpoint.Recorder = recorder
+//	client, err := dataplane.NewClient(ctx, endpoint)
+func NewRecorder(path string) (*Recorder, error) {
+	return client.NewRecorder(path)
+}
+
+// NewReplayTransportFromFile loads a recording and returns a transport serving it.
+//
+// Set it on Endpoint.Transport to reproduce a recorded sync without a Dataplane API:
+//
+//	replay, err := dataplane.NewReplayTransportFromFile("/tmp/sync.jsonl")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	endpoint.Transport = replay
+//	client, err := dataplane.NewClient(ctx, endpoint)
+func NewReplayTransportFromFile(path string) (*ReplayTransport, error) {
+	interactions, err := client.LoadInteractions(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.NewReplayTransport(interactions), nil
+}
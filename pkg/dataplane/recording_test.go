@@ -0,0 +1,202 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/dataplane/auxiliaryfiles"
+	"haproxy-template-ic/pkg/dataplane/client"
+)
+
+const recordingCurrentConfig = `
+global
+    daemon
+
+defaults
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+
+backend web
+    balance roundrobin
+    server srv1 10.0.0.1:80
+`
+
+const recordingDesiredConfig = `
+global
+    daemon
+
+defaults
+    mode http
+    timeout connect 5s
+    timeout client 30s
+    timeout server 30s
+
+backend web
+    balance leastconn
+    server srv1 10.0.0.1:80
+    server srv2 10.0.0.2:80
+
+backend api
+    server api1 10.0.1.1:8080
+`
+
+func TestSync_RecordAndReplay(t *testing.T) {
+	ctx := context.Background()
+	api := newFakeDataplaneAPI(t, recordingCurrentConfig)
+	recordingPath := filepath.Join(t.TempDir(), "sync.jsonl")
+
+	// Record a sync against the fake API
+	recorder, err := NewRecorder(recordingPath)
+	require.NoError(t, err)
+
+	endpoint := api.endpoint()
+	endpoint.Recorder = recorder
+
+	recordedClient, err := NewClient(ctx, endpoint)
+	require.NoError(t, err)
+
+	recordedResult, err := recordedClient.Sync(ctx, recordingDesiredConfig, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+	require.NotEmpty(t, recordedResult.AppliedOperations)
+
+	recorded, err := client.LoadInteractions(recordingPath)
+	require.NoError(t, err)
+	require.NotEmpty(t, recorded)
+	for _, interaction := range recorded {
+		assert.NotContains(t, interaction.RequestBody, "password")
+	}
+
+	// Replay the recording without contacting the fake API
+	replay, err := NewReplayTransportFromFile(recordingPath)
+	require.NoError(t, err)
+
+	replayPath := filepath.Join(t.TempDir(), "replay.jsonl")
+	replayRecorder, err := NewRecorder(replayPath)
+	require.NoError(t, err)
+	defer replayRecorder.Close()
+
+	requestsBeforeReplay := len(api.Requests())
+
+	replayedClient, err := NewClient(ctx, &Endpoint{
+		URL:       "http://replay.invalid",
+		Username:  "admin",
+		Password:  "password",
+		Transport: replay,
+		Recorder:  replayRecorder,
+	})
+	require.NoError(t, err)
+
+	replayedResult, err := replayedClient.Sync(ctx, recordingDesiredConfig, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, recordedResult.AppliedOperations, replayedResult.AppliedOperations)
+	assert.Equal(t, recordedResult.ReloadID, replayedResult.ReloadID)
+	replayed, err := client.LoadInteractions(replayPath)
+	require.NoError(t, err)
+	assert.Equal(t, recorded, replayed, "replay must issue the identical request sequence")
+	assert.Zero(t, replay.Remaining())
+	assert.Len(t, api.Requests(), requestsBeforeReplay, "replay must not contact the API")
+}
+
+func TestSync_RecordAndReplayAuxiliaryFiles(t *testing.T) {
+	ctx := context.Background()
+	api := newFakeDataplaneAPI(t, recordingCurrentConfig)
+	api.mapFiles = map[string]string{"hosts.map": "a.example.com web\n"}
+	recordingPath := filepath.Join(t.TempDir(), "sync.jsonl")
+
+	auxFiles := &AuxiliaryFiles{MapFiles: []auxiliaryfiles.MapFile{
+		{Path: "hosts.map", Content: "a.example.com web\nb.example.com api\n"},
+		{Path: "paths.map", Content: "/api api\n"},
+	}}
+
+	recorder, err := NewRecorder(recordingPath)
+	require.NoError(t, err)
+
+	endpoint := api.endpoint()
+	endpoint.Recorder = recorder
+
+	recordedClient, err := NewClient(ctx, endpoint)
+	require.NoError(t, err)
+
+	_, err = recordedClient.Sync(ctx, recordingDesiredConfig, auxFiles, nil)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+	require.Contains(t, api.Requests(), "POST /services/haproxy/storage/maps")
+
+	// Storage uploads use a new multipart boundary on every request
+	replay, err := NewReplayTransportFromFile(recordingPath)
+	require.NoError(t, err)
+
+	requestsBeforeReplay := len(api.Requests())
+
+	replayedClient, err := NewClient(ctx, &Endpoint{
+		URL:       "http://replay.invalid",
+		Username:  "admin",
+		Password:  "password",
+		Transport: replay,
+	})
+	require.NoError(t, err)
+
+	_, err = replayedClient.Sync(ctx, recordingDesiredConfig, auxFiles, nil)
+	require.NoError(t, err)
+	assert.Zero(t, replay.Remaining())
+	assert.Len(t, api.Requests(), requestsBeforeReplay, "replay must not contact the API")
+}
+
+func TestSync_ReplayRejectsDivergingRequests(t *testing.T) {
+	ctx := context.Background()
+	api := newFakeDataplaneAPI(t, recordingCurrentConfig)
+	recordingPath := filepath.Join(t.TempDir(), "sync.jsonl")
+
+	recorder, err := NewRecorder(recordingPath)
+	require.NoError(t, err)
+
+	endpoint := api.endpoint()
+	endpoint.Recorder = recorder
+
+	recordedClient, err := NewClient(ctx, endpoint)
+	require.NoError(t, err)
+
+	_, err = recordedClient.Sync(ctx, recordingDesiredConfig, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, recorder.Close())
+
+	replay, err := NewReplayTransportFromFile(recordingPath)
+	require.NoError(t, err)
+
+	replayedClient, err := NewClient(ctx, &Endpoint{
+		URL:       "http://replay.invalid",
+		Username:  "admin",
+		Password:  "password",
+		Transport: replay,
+	})
+	require.NoError(t, err)
+
+	// A different desired config produces requests that were never recorded
+	opts := DefaultSyncOptions()
+	opts.FallbackToRaw = false
+	_, err = replayedClient.Sync(ctx, recordingCurrentConfig+"\nbackend other\n", nil, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded interaction matches")
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Reload(t *testing.T) {
+	const rawPush = "POST /services/haproxy/configuration/raw"
+
+	t.Run("pushes the current configuration unchanged", func(t *testing.T) {
+		client, api := newTestClient(t, baseTestConfig)
+
+		result, err := client.Reload(context.Background(), false)
+		require.NoError(t, err)
+
+		assert.True(t, result.ReloadTriggered)
+		assert.Equal(t, "reload-raw", result.ReloadID)
+		assert.Nil(t, result.Status)
+		assert.Equal(t, baseTestConfig, api.RequestBody(rawPush))
+		assert.NotContains(t, api.Requests(), "GET /services/haproxy/reloads/reload-raw")
+	})
+
+	t.Run("waits for the reload", func(t *testing.T) {
+		client, api := newTestClient(t, baseTestConfig)
+
+		result, err := client.Reload(context.Background(), true)
+		require.NoError(t, err)
+
+		assert.True(t, result.ReloadTriggered)
+		require.NotNil(t, result.Status)
+		assert.Equal(t, "reload-raw", result.Status.ID)
+		assert.Equal(t, "succeeded", result.Status.Status)
+		assert.Contains(t, api.Requests(), "GET /services/haproxy/reloads/reload-raw")
+	})
+
+	t.Run("no reload needed", func(t *testing.T) {
+		client, api := newTestClient(t, baseTestConfig)
+		api.rawPushWithoutReload = true
+
+		result, err := client.Reload(context.Background(), true)
+		require.NoError(t, err)
+
+		assert.False(t, result.ReloadTriggered)
+		assert.Empty(t, result.ReloadID)
+		assert.Nil(t, result.Status)
+		for _, request := range api.Requests() {
+			assert.NotContains(t, request, "/services/haproxy/reloads/")
+		}
+	})
+
+	t.Run("push failure", func(t *testing.T) {
+		client, api := newTestClient(t, baseTestConfig)
+		api.failRequest = rawPush
+
+		_, err := client.Reload(context.Background(), false)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to trigger reload")
+	})
+}
+
+func TestClient_GetConfigVersion(t *testing.T) {
+	c, api := newTestClient(t, baseTestConfig)
+
+	version, err := c.GetConfigVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+
+	// A change made outside of the client is visible as a new version
+	api.mu.Lock()
+	api.configVersion = 42
+	api.mu.Unlock()
+
+	version, err = c.GetConfigVersion(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), version)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"haproxy-template-ic/pkg/dataplane/comparator"
 )
 
 // SyncResult contains detailed information about a sync operation.
@@ -11,6 +13,11 @@ type SyncResult struct {
 	// Success indicates whether the sync completed successfully
 	Success bool
 
+	// NoOp indicates that the desired configuration and auxiliary files matched
+	// the live ones, so the sync returned without opening a transaction,
+	// changing the configuration version, or reloading HAProxy
+	NoOp bool
+
 	// AppliedOperations contains structured information about operations that were applied
 	AppliedOperations []AppliedOperation
 
@@ -22,24 +29,250 @@ type SyncResult struct {
 	// Only set when ReloadTriggered is true
 	ReloadID string
 
+	// TransactionID is the ID of the Dataplane API transaction whose commit
+	// applied the configuration changes, for correlation with the Dataplane
+	// API's logs. Empty if no transaction was committed, e.g. when all changes
+	// went through the Runtime API or a raw configuration push.
+	TransactionID string
+
 	// FallbackToRaw indicates whether we had to fall back to raw config push
 	// This happens when fine-grained sync encounters non-recoverable errors
 	FallbackToRaw bool
 
+	// FallbackSections lists the sections (e.g. "backend", "server") whose
+	// operations failed and triggered the raw fallback, in the order they failed.
+	// If the transaction was rejected as a whole, all sections with changes are listed.
+	// Only set when FallbackToRaw is true
+	FallbackSections []string
+
+	// RuntimeUnavailable indicates that runtime-eligible operations could not reach
+	// the Runtime API and were applied through a configuration transaction instead
+	RuntimeUnavailable bool
+
 	// Duration of the sync operation
 	Duration time.Duration
 
 	// Retries indicates how many times operations were retried (for 409 conflicts)
 	Retries int
 
+	// RetriesUsed is the number of retries of any kind taken from the sync's
+	// retry budget, see SyncOptions.RetryBudget
+	RetriesUsed int
+
 	// Details contains detailed diff information
 	// This field is always populated, even when FallbackToRaw is true
 	Details DiffDetails
 
 	// Message provides additional context about the result
 	Message string
+
+	// Warnings lists non-fatal issues encountered during the sync, such as
+	// falling back from the Runtime API or recreating a section
+	Warnings []SyncWarning
+
+	// Timings breaks Duration down into the stages of the sync
+	Timings SyncTimings
+
+	// CommittedTransactions records the changes committed to HAProxy, in order.
+	// On a failed sync it shows what remained applied before any rollback.
+	CommittedTransactions []CommittedTransaction
+
+	// RolledBack indicates that the sync failed after committing changes and
+	// the previous configuration was restored (see SyncOptions.RollbackOnPartialFailure)
+	RolledBack bool
+
+	// FailedOperations lists the operations that failed or were skipped, in
+	// execution order. Only set with SyncOptions.ContinueOnError.
+	FailedOperations []FailedOperation
+
+	// SkippedOperations lists the planned operations that were not applied and
+	// why, in plan order. Resources that did not change produce no operations
+	// and are not listed. Not set when the configuration was pushed raw.
+	SkippedOperations []SkippedOperation
+
+	// AppliedConfig is the desired configuration as the sync applied it, after
+	// normalization such as declaring SyncOptions.RuntimeVars. Overlays and
+	// stable server names are applied to the parsed configuration and are not
+	// part of it. Only set on successful syncs with
+	// SyncOptions.ReturnAppliedConfig.
+	AppliedConfig string
+
+	// RestoredServerStates lists the servers whose admin state was set again
+	// after the reload, with SyncOptions.PreserveServerState
+	RestoredServerStates []ServerState
+
+	// RollbackSnapshot is the configuration before the sync, for
+	// Client.Rollback. Only set with SyncOptions.CaptureRollbackSnapshot on
+	// syncs that applied changes, including ones that failed after committing.
+	RollbackSnapshot *ConfigSnapshot
+}
+
+// ConfigSnapshot is the configuration of an instance captured before a sync
+// changed it (see SyncOptions.CaptureRollbackSnapshot).
+type ConfigSnapshot struct {
+	// Config is the raw HAProxy configuration
+	Config string
+
+	// CapturedAt is when the configuration was fetched
+	CapturedAt time.Time
 }
 
+// FailedOperation is an operation that failed, or was skipped because an
+// operation it depends on failed, during a sync with ContinueOnError.
+type FailedOperation struct {
+	// Type is the operation type: "create", "update", or "delete"
+	Type string
+
+	// Section is the configuration section: "backend", "server", "frontend", "acl", "http-rule", etc.
+	Section string
+
+	// Resource is the resource name or identifier (e.g., backend name, server name)
+	Resource string
+
+	// Description is a human-readable description of the operation
+	Description string
+
+	// Error is the cause of the failure. For skipped operations it names the
+	// failed operation they depend on.
+	Error error
+
+	// Skipped indicates that the operation was not attempted
+	Skipped bool
+}
+
+// SkippedOperation is a planned operation that a sync did not apply.
+type SkippedOperation struct {
+	// Type is the operation type: "create", "update", or "delete"
+	Type string
+
+	// Section is the configuration section: "backend", "server", "frontend", "acl", "http-rule", etc.
+	Section string
+
+	// Resource is the resource name or identifier (e.g., backend name, server name)
+	Resource string
+
+	// Description is a human-readable description of the operation
+	Description string
+
+	// Reason is why the operation was skipped
+	Reason SkipReason
+}
+
+// SkipReason is why an operation was skipped.
+type SkipReason string
+
+// Reasons reported in SkippedOperation.Reason.
+const (
+	// SkipReasonFiltered indicates the operation type is not listed in
+	// SyncOptions.OperationTypeFilter.
+	SkipReasonFiltered SkipReason = "filtered"
+
+	// SkipReasonFilteredDependency indicates an index-based rule operation
+	// whose position depends on a rule operation filtered out by
+	// SyncOptions.OperationTypeFilter.
+	SkipReasonFilteredDependency SkipReason = "filtered_dependency"
+
+	// SkipReasonNotOwned indicates a delete of a resource outside
+	// SyncOptions.NamespacePrefix.
+	SkipReasonNotOwned SkipReason = "not_owned"
+
+	// SkipReasonDependencyFailed indicates an operation the operation depends
+	// on failed (SyncOptions.ContinueOnError).
+	SkipReasonDependencyFailed SkipReason = "dependency_failed"
+)
+
+// CommittedTransaction is a change committed to HAProxy during a sync.
+type CommittedTransaction struct {
+	// ID is the Dataplane API transaction ID
+	// Empty for changes applied without a transaction, e.g. through the Runtime API
+	// or a raw configuration push.
+	ID string
+
+	// Operations are the operations the commit applied
+	Operations []AppliedOperation
+}
+
+// SyncTimings breaks down where the time of a sync was spent.
+//
+// Stages that did not run are zero. The stages do not overlap, so their sum
+// is close to SyncResult.Duration; the remainder is bookkeeping and logging.
+type SyncTimings struct {
+	// Fetch is the time spent fetching the current configuration
+	Fetch time.Duration
+
+	// Parse is the time spent parsing the current and desired configurations
+	Parse time.Duration
+
+	// Diff is the time spent comparing configurations and auxiliary files
+	Diff time.Duration
+
+	// AuxiliaryFiles is the time spent uploading and deleting auxiliary files
+	AuxiliaryFiles time.Duration
+
+	// Execute is the time spent executing configuration operations
+	Execute time.Duration
+
+	// ExecuteBySection splits Execute by operation section (e.g. "backend", "server")
+	ExecuteBySection map[string]time.Duration
+
+	// Commit is the time spent opening and committing transactions, or pushing
+	// the raw configuration when falling back
+	Commit time.Duration
+
+	// Drain is the time spent draining removed servers, including the grace
+	// period, with SyncOptions.DrainBeforeDelete
+	Drain time.Duration
+}
+
+// Total returns the sum of all stages.
+func (t SyncTimings) Total() time.Duration {
+	return t.Fetch + t.Parse + t.Diff + t.AuxiliaryFiles + t.Drain + t.Execute + t.Commit
+}
+
+// SyncWarning describes a non-fatal issue encountered during a sync.
+type SyncWarning struct {
+	// Code identifies the kind of warning (one of the Warning* constants)
+	Code string
+
+	// Message is a human-readable description of the issue
+	Message string
+}
+
+// Warning codes reported in SyncResult.Warnings.
+const (
+	// WarningRuntimeUnavailable indicates runtime-eligible changes were applied
+	// through a transaction because the Runtime API was unavailable.
+	WarningRuntimeUnavailable = "runtime_unavailable"
+
+	// WarningRawFallback indicates the fine-grained sync failed and the
+	// configuration was pushed as raw config instead.
+	WarningRawFallback = "raw_fallback"
+
+	// WarningRawFallbackSkipped indicates the fine-grained sync failed and the
+	// raw fallback was skipped because SyncOptions it cannot honor are set.
+	WarningRawFallbackSkipped = "raw_fallback_skipped"
+
+	// WarningSectionRecreated indicates a section without an update endpoint
+	// was deleted and created again.
+	WarningSectionRecreated = "section_recreated"
+
+	// WarningFileCleanupFailed indicates obsolete auxiliary files could not be
+	// deleted after the configuration was applied.
+	WarningFileCleanupFailed = "file_cleanup_failed"
+
+	// WarningRollbackFailed indicates the previous configuration could not be
+	// restored after a sync failed with changes already committed.
+	WarningRollbackFailed = "rollback_failed"
+
+	// WarningDrainFailed indicates a removed server could not be drained
+	// before it was deleted.
+	WarningDrainFailed = "drain_failed"
+
+	// WarningServerStateFailed indicates server admin states could not be
+	// captured or restored with SyncOptions.PreserveServerState.
+	WarningServerStateFailed = "server_state_failed"
+)
+
 // AppliedOperation represents a single applied configuration change.
 type AppliedOperation struct {
 	// Type is the operation type: "create", "update", or "delete"
@@ -55,6 +288,21 @@ type AppliedOperation struct {
 	Description string
 }
 
+// ReloadResult contains the outcome of Client.Reload.
+type ReloadResult struct {
+	// ReloadTriggered is false if the Dataplane API accepted the unchanged
+	// configuration without scheduling a reload
+	ReloadTriggered bool
+
+	// ReloadID is the reload identifier from the Reload-ID response header
+	// Only set when ReloadTriggered is true
+	ReloadID string
+
+	// Status is the final state of the reload
+	// Only set when Client.Reload waited for the reload to finish
+	Status *ReloadStatus
+}
+
 // DiffResult contains comparison results without applying changes.
 type DiffResult struct {
 	// HasChanges indicates whether any differences were detected
@@ -83,6 +331,27 @@ type PlannedOperation struct {
 
 	// Priority indicates execution order (lower = earlier for creates, higher = earlier for deletes)
 	Priority int
+
+	// fingerprint identifies the target resource and the applied model for
+	// DiffResult.Hash; it is empty for operations not created by a diff
+	fingerprint string
+
+	// fieldDiffs are the changed fields of backend and server updates
+	fieldDiffs []FieldDiff
+}
+
+// FieldDiff is a single field changed by an update, with its current and
+// desired value.
+type FieldDiff = comparator.FieldDiff
+
+// FieldDiffs returns the fields an update changes, compared to the fetched
+// current configuration, sorted by field name.
+//
+// Available for backend and server updates; nil for other operations. For
+// backends, only the attributes of the section itself are included, since
+// changed servers, ACLs and rules are operations of their own.
+func (p *PlannedOperation) FieldDiffs() []FieldDiff {
+	return p.fieldDiffs
 }
 
 // DiffDetails contains detailed diff information about configuration changes.
@@ -154,9 +423,18 @@ func (r *SyncResult) String() string {
 	// Fallback indicator
 	if r.FallbackToRaw {
 		parts = append(parts, "Mode: Raw config push (fallback)")
+		if len(r.FallbackSections) > 0 {
+			parts = append(parts, fmt.Sprintf("Fallback triggered by: %s", strings.Join(r.FallbackSections, ", ")))
+		}
 	} else {
 		parts = append(parts, "Mode: Fine-grained sync")
 	}
+	if r.RuntimeUnavailable {
+		parts = append(parts, "Runtime API: Unavailable (applied via transaction)")
+	}
+	if r.RolledBack {
+		parts = append(parts, "Rollback: Previous configuration restored")
+	}
 
 	// Reload info
 	if r.ReloadTriggered {
@@ -168,6 +446,9 @@ func (r *SyncResult) String() string {
 	} else {
 		parts = append(parts, "Reload: Not triggered (runtime API used)")
 	}
+	if r.TransactionID != "" {
+		parts = append(parts, fmt.Sprintf("Transaction: %s", r.TransactionID))
+	}
 
 	// Operations summary
 	if len(r.AppliedOperations) > 0 {
@@ -187,6 +468,14 @@ func (r *SyncResult) String() string {
 		parts = append(parts, fmt.Sprintf("\nMessage: %s", r.Message))
 	}
 
+	// Warnings
+	if len(r.Warnings) > 0 {
+		parts = append(parts, fmt.Sprintf("\nWarnings: %d", len(r.Warnings)))
+		for _, w := range r.Warnings {
+			parts = append(parts, fmt.Sprintf("  [%s] %s", w.Code, w.Message))
+		}
+	}
+
 	return strings.Join(parts, "\n")
 }
 
@@ -0,0 +1,116 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// runtimeVarNamePattern matches process-scoped HAProxy variable names.
+// Only the proc scope exists while the global section is evaluated.
+var runtimeVarNamePattern = regexp.MustCompile(`^proc\.[A-Za-z0-9_.]+$`)
+
+// applyRuntimeVars declares the given runtime variables in the global section
+// of config so HAProxy sets them again on every reload.
+//
+// The Dataplane API has no endpoint for the Runtime API "set var" command, so
+// instead of re-applying variables after a reload they are emitted as global
+// "set-var" directives, which HAProxy evaluates each time it (re)loads the
+// configuration. Directives are appended at the end of the global section,
+// overriding values the template may set for the same variables, and a global
+// section is added after any leading comments if the configuration has none.
+//
+// Keys are variable names in the proc scope, values are sample expressions
+// such as "int(100)" or "str(blue)".
+func applyRuntimeVars(config string, vars map[string]string) (string, error) {
+	if len(vars) == 0 {
+		return config, nil
+	}
+
+	names := make([]string, 0, len(vars))
+	for name, expr := range vars {
+		if !runtimeVarNamePattern.MatchString(name) {
+			return "", fmt.Errorf("invalid runtime variable name %q: must be in the proc scope (e.g. proc.rate_limit)", name)
+		}
+		if strings.TrimSpace(expr) == "" || strings.ContainsAny(expr, "\r\n") {
+			return "", fmt.Errorf("invalid expression for runtime variable %q: must be a single-line sample expression", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	directives := make([]string, 0, len(names))
+	for _, name := range names {
+		directives = append(directives, fmt.Sprintf("    set-var %s %s", name, strings.TrimSpace(vars[name])))
+	}
+
+	lines := strings.Split(config, "\n")
+	globalStart := -1
+	for i, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "global" && !isIndented(line) {
+			globalStart = i
+			break
+		}
+	}
+
+	if globalStart == -1 {
+		// Add the section after leading comments so a banner stays at the top
+		header := 0
+		for header < len(lines) {
+			trimmed := strings.TrimSpace(lines[header])
+			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+				break
+			}
+			header++
+		}
+
+		result := make([]string, 0, len(lines)+len(directives)+1)
+		result = append(result, lines[:header]...)
+		result = append(result, "global")
+		result = append(result, directives...)
+		result = append(result, lines[header:]...)
+
+		return strings.Join(result, "\n"), nil
+	}
+
+	// The global section ends before the next unindented keyword; insert after
+	// its last directive so trailing blank lines stay between the sections.
+	insertAt := globalStart + 1
+	for i := globalStart + 1; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !isIndented(lines[i]) {
+			break
+		}
+		insertAt = i + 1
+	}
+
+	result := make([]string, 0, len(lines)+len(directives))
+	result = append(result, lines[:insertAt]...)
+	result = append(result, directives...)
+	result = append(result, lines[insertAt:]...)
+
+	return strings.Join(result, "\n"), nil
+}
+
+// isIndented reports whether a configuration line starts with whitespace.
+func isIndented(line string) bool {
+	return line != "" && (line[0] == ' ' || line[0] == '\t')
+}
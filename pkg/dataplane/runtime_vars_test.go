@@ -0,0 +1,63 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyRuntimeVars(t *testing.T) {
+	vars := map[string]string{
+		"proc.rate_limit": "int(100)",
+		"proc.color":      "str(blue)",
+	}
+
+	t.Run("appends to global section", func(t *testing.T) {
+		config := "global\n    daemon\n    # tuning\n\ndefaults\n    mode http\n"
+
+		result, err := applyRuntimeVars(config, vars)
+		require.NoError(t, err)
+		assert.Equal(t, "global\n    daemon\n    set-var proc.color str(blue)\n    set-var proc.rate_limit int(100)\n    # tuning\n\ndefaults\n    mode http\n", result)
+	})
+
+	t.Run("adds missing global section", func(t *testing.T) {
+		result, err := applyRuntimeVars("defaults\n    mode http\n", map[string]string{"proc.rate_limit": "int(100)"})
+		require.NoError(t, err)
+		assert.Equal(t, "global\n    set-var proc.rate_limit int(100)\ndefaults\n    mode http\n", result)
+	})
+
+	t.Run("keeps leading comments above added global section", func(t *testing.T) {
+		result, err := applyRuntimeVars("# Managed by the controller\n\ndefaults\n    mode http\n", map[string]string{"proc.rate_limit": "int(100)"})
+		require.NoError(t, err)
+		assert.Equal(t, "# Managed by the controller\n\nglobal\n    set-var proc.rate_limit int(100)\ndefaults\n    mode http\n", result)
+	})
+
+	t.Run("no variables leaves config untouched", func(t *testing.T) {
+		result, err := applyRuntimeVars(baseTestConfig, nil)
+		require.NoError(t, err)
+		assert.Equal(t, baseTestConfig, result)
+	})
+
+	t.Run("rejects invalid declarations", func(t *testing.T) {
+		_, err := applyRuntimeVars(baseTestConfig, map[string]string{"txn.rate_limit": "int(100)"})
+		assert.ErrorContains(t, err, "must be in the proc scope")
+
+		_, err = applyRuntimeVars(baseTestConfig, map[string]string{"proc.rate_limit": "int(1)\n    daemon"})
+		assert.ErrorContains(t, err, "single-line sample expression")
+	})
+}
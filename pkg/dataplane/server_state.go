@@ -0,0 +1,107 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+
+	"haproxy-template-ic/pkg/dataplane/client"
+	"haproxy-template-ic/pkg/dataplane/comparator"
+	"haproxy-template-ic/pkg/dataplane/comparator/sections"
+)
+
+// ServerState is the admin state of a server, preserved across reloads with
+// SyncOptions.PreserveServerState.
+type ServerState struct {
+	// Backend is the name of the server's backend
+	Backend string
+
+	// Server is the server name
+	Server string
+
+	// AdminState is "drain" or "maint"
+	AdminState string
+}
+
+// captureServerState reads the admin state of the servers in the backends of
+// the current configuration and returns the servers that are not ready.
+// Backends whose servers cannot be read are reported as warnings.
+func (o *orchestrator) captureServerState(ctx context.Context, state *syncState) []ServerState {
+	var captured []ServerState
+	for _, backend := range state.backends {
+		servers, err := o.client.GetRuntimeServers(ctx, backend)
+		if err != nil {
+			o.logger.Warn("Failed to capture server states",
+				"backend", backend,
+				"error", err)
+			state.warn(WarningServerStateFailed, "server states of backend '%s' were not captured: %v", backend, err)
+			continue
+		}
+
+		for _, server := range servers {
+			if server.AdminState == client.ServerAdminStateDrain || server.AdminState == client.ServerAdminStateMaint {
+				captured = append(captured, ServerState{Backend: backend, Server: server.Name, AdminState: server.AdminState})
+			}
+		}
+	}
+
+	return captured
+}
+
+// restoreServerStateAfterReload waits for the reload of a successful sync and
+// sets the captured admin states again on the servers ops did not delete.
+// Does nothing if the sync did not reload HAProxy.
+func (o *orchestrator) restoreServerStateAfterReload(ctx context.Context, result *SyncResult, captured []ServerState, ops []comparator.Operation, state *syncState) {
+	if result == nil || !result.ReloadTriggered || len(captured) == 0 {
+		return
+	}
+	defer func() { result.Warnings = state.warnings }()
+
+	// States set before the new process is up would be lost with the old one
+	if result.ReloadID != "" {
+		if _, err := o.client.WaitForReload(ctx, result.ReloadID, reloadPollInterval); err != nil {
+			o.logger.Warn("Failed to wait for reload, server states not restored", "error", err)
+			state.warn(WarningServerStateFailed, "server states were not restored, waiting for reload %s failed: %v", result.ReloadID, err)
+			return
+		}
+	}
+
+	deleted := make(map[string]bool)
+	for _, op := range ops {
+		if op.Type() == sections.OperationDelete && (op.Section() == "backend" || op.Section() == "server") {
+			deleted[op.Target()] = true
+		}
+	}
+
+	for _, s := range captured {
+		if deleted[s.Backend] || deleted[s.Backend+"/"+s.Server] {
+			continue
+		}
+
+		if err := o.client.SetServerAdminState(ctx, s.Backend, s.Server, s.AdminState); err != nil {
+			o.logger.Warn("Failed to restore server state",
+				"backend", s.Backend,
+				"server", s.Server,
+				"error", err)
+			state.warn(WarningServerStateFailed, "server '%s' in backend '%s' was not set to %s again: %v", s.Server, s.Backend, s.AdminState, err)
+			continue
+		}
+		result.RestoredServerStates = append(result.RestoredServerStates, s)
+	}
+
+	if len(result.RestoredServerStates) > 0 {
+		o.logger.Info("Restored server states after reload", "servers", len(result.RestoredServerStates))
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// defaultSubscribeInterval is how often Subscribe polls by default.
+const defaultSubscribeInterval = 5 * time.Second
+
+// Subscribe notifies about configuration version changes, e.g. to resync after
+// the configuration was changed by another Dataplane API client.
+//
+// The Dataplane API has no change notifications, so the version is polled
+// every Endpoint.SubscribeInterval (default: 5 seconds). The version at the
+// first successful poll is the baseline and is not sent; every later change,
+// including those made by Sync on this client, sends the new version. If the
+// receiver falls behind, only the latest version is kept. Failed polls are
+// logged and retried at the next interval.
+//
+// The channel is closed once ctx is done or the client is closed.
+//
+// Example:
+//
+//	for version := range client.Subscribe(ctx) {
+//	    log.Printf("configuration changed to version %d, resyncing", version)
+//	    client.Sync(ctx, desiredConfig, auxFiles, opts)
+//	}
+func (c *Client) Subscribe(ctx context.Context) <-chan int64 {
+	interval := c.Endpoint.SubscribeInterval
+	if interval <= 0 {
+		interval = defaultSubscribeInterval
+	}
+
+	ch := make(chan int64, 1)
+	go c.pollConfigVersion(ctx, interval, ch)
+	return ch
+}
+
+// pollConfigVersion sends version changes to ch until ctx is done or the
+// client is closed, then closes ch.
+func (c *Client) pollConfigVersion(ctx context.Context, interval time.Duration, ch chan int64) {
+	defer close(ch)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last int64
+	known := false
+	for {
+		version, err := c.GetConfigVersion(ctx)
+		switch {
+		case errors.Is(err, ErrClientClosed) || ctx.Err() != nil:
+			return
+		case err != nil:
+			c.orch.logger.Debug("Failed to poll configuration version", "error", err)
+		case !known:
+			last, known = version, true
+		case version != last:
+			last = version
+			// Replace a version the receiver has not picked up yet
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- version
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
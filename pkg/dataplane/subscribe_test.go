@@ -0,0 +1,121 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSubscribeTestClient creates a client polling the fake API every 10ms.
+func newSubscribeTestClient(t *testing.T) (*Client, *fakeDataplaneAPI) {
+	t.Helper()
+
+	api := newFakeDataplaneAPI(t, baseTestConfig)
+	endpoint := api.endpoint()
+	endpoint.SubscribeInterval = 10 * time.Millisecond
+
+	c, err := NewClient(context.Background(), endpoint)
+	require.NoError(t, err)
+	return c, api
+}
+
+// setConfigVersion simulates a change made by another Dataplane API client.
+func setConfigVersion(api *fakeDataplaneAPI, version int64) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.configVersion = version
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	t.Run("emits version changes", func(t *testing.T) {
+		c, api := newSubscribeTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		versions := c.Subscribe(ctx)
+
+		// The baseline version is not sent
+		select {
+		case version := <-versions:
+			t.Fatalf("unexpected version %d without a change", version)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		setConfigVersion(api, 2)
+		select {
+		case version := <-versions:
+			assert.Equal(t, int64(2), version)
+		case <-time.After(time.Second):
+			t.Fatal("version change was not sent")
+		}
+	})
+
+	t.Run("keeps only the latest version", func(t *testing.T) {
+		c, api := newSubscribeTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		versions := c.Subscribe(ctx)
+		time.Sleep(30 * time.Millisecond)
+
+		setConfigVersion(api, 2)
+		time.Sleep(30 * time.Millisecond)
+		setConfigVersion(api, 3)
+		time.Sleep(30 * time.Millisecond)
+
+		// Version 2 was replaced by 3 while nobody was receiving
+		select {
+		case version := <-versions:
+			assert.Equal(t, int64(3), version)
+		case <-time.After(time.Second):
+			t.Fatal("version change was not sent")
+		}
+	})
+
+	t.Run("closes on context cancellation", func(t *testing.T) {
+		c, _ := newSubscribeTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		versions := c.Subscribe(ctx)
+		cancel()
+
+		select {
+		case _, ok := <-versions:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel was not closed after cancellation")
+		}
+	})
+
+	t.Run("closes when the client is closed", func(t *testing.T) {
+		c, _ := newSubscribeTestClient(t)
+
+		versions := c.Subscribe(context.Background())
+		require.NoError(t, c.Close())
+
+		select {
+		case _, ok := <-versions:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("channel was not closed after Close")
+		}
+	})
+}
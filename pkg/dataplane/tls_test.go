@@ -0,0 +1,175 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dataplane
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"haproxy-template-ic/pkg/dataplane/client"
+)
+
+// generateClientCertificate returns a self-signed client certificate and its
+// key as PEM, along with the parsed certificate.
+func generateClientCertificate(t *testing.T) (certPEM, keyPEM []byte, cert *x509.Certificate) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "haproxy-template-ic"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, cert
+}
+
+// newMutualTLSFakeDataplaneAPI starts a fake Dataplane API that requires a
+// client certificate signed by clientCA.
+func newMutualTLSFakeDataplaneAPI(t *testing.T, currentConfig string, clientCA *x509.Certificate) *fakeDataplaneAPI {
+	t.Helper()
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+
+	api := &fakeDataplaneAPI{currentConfig: currentConfig, bodies: make(map[string]string), queries: make(map[string]string)}
+	api.server = httptest.NewUnstartedServer(http.HandlerFunc(api.handle))
+	api.server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	api.server.StartTLS()
+	t.Cleanup(api.server.Close)
+
+	return api
+}
+
+func TestNewClient_MutualTLS(t *testing.T) {
+	certPEM, keyPEM, cert := generateClientCertificate(t)
+	api := newMutualTLSFakeDataplaneAPI(t, baseTestConfig, cert)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: api.server.Certificate().Raw})
+
+	t.Run("client certificate instead of basic auth", func(t *testing.T) {
+		c, err := NewClient(context.Background(), &Endpoint{
+			URL: api.server.URL,
+			TLS: &TLSConfig{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: caPEM},
+		})
+		require.NoError(t, err)
+		defer c.Close()
+
+		diff, err := c.Diff(context.Background(), baseTestConfig)
+		require.NoError(t, err)
+		assert.False(t, diff.HasChanges)
+	})
+
+	t.Run("with recorder", func(t *testing.T) {
+		recordingPath := filepath.Join(t.TempDir(), "sync.jsonl")
+		recorder, err := NewRecorder(recordingPath)
+		require.NoError(t, err)
+		defer recorder.Close()
+
+		c, err := NewClient(context.Background(), &Endpoint{
+			URL:      api.server.URL,
+			Username: "admin",
+			Password: "password",
+			TLS:      &TLSConfig{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: caPEM},
+			Recorder: recorder,
+		})
+		require.NoError(t, err)
+		defer c.Close()
+
+		recorded, err := client.LoadInteractions(recordingPath)
+		require.NoError(t, err)
+		assert.NotEmpty(t, recorded)
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		_, err := NewClient(context.Background(), &Endpoint{
+			URL:      api.server.URL,
+			Username: "admin",
+			Password: "password",
+			TLS:      &TLSConfig{CAPEM: caPEM},
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestNewClient_InvalidTLSConfig(t *testing.T) {
+	certPEM, keyPEM, _ := generateClientCertificate(t)
+	_, otherKeyPEM, _ := generateClientCertificate(t)
+
+	tests := []struct {
+		name    string
+		tls     *TLSConfig
+		wantErr string
+	}{
+		{
+			name:    "mismatched key",
+			tls:     &TLSConfig{CertPEM: certPEM, KeyPEM: otherKeyPEM},
+			wantErr: "invalid client certificate/key pair",
+		},
+		{
+			name:    "certificate without key",
+			tls:     &TLSConfig{CertPEM: certPEM},
+			wantErr: "client certificate and key must be set together",
+		},
+		{
+			name:    "invalid CA",
+			tls:     &TLSConfig{CertPEM: certPEM, KeyPEM: keyPEM, CAPEM: []byte("not a certificate")},
+			wantErr: "no valid certificates found in CA PEM",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewClient(context.Background(), &Endpoint{URL: "https://haproxy:5555", TLS: tt.tls})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid TLS configuration")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
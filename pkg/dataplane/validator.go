@@ -99,6 +99,31 @@ type ValidationPaths struct {
 //   - nil if validation succeeds
 //   - ValidationError with phase information if validation fails
 func ValidateConfiguration(mainConfig string, auxFiles *AuxiliaryFiles, paths *ValidationPaths, version *Version) error {
+	// Phase 1 and 1.5: Syntax and API schema validation
+	if err := ValidateStructure(mainConfig, version); err != nil {
+		return err
+	}
+
+	// Phase 2: Semantic validation with haproxy binary
+	if err := validateSemantics(mainConfig, auxFiles, paths); err != nil {
+		return &ValidationError{
+			Phase:   "semantic",
+			Message: "configuration has semantic errors",
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// ValidateStructure performs the syntax and API schema phases of ValidateConfiguration.
+//
+// It needs neither the haproxy binary nor auxiliary files, which makes it
+// suitable for checking rendered configurations in CI pipelines. Files
+// referenced by the configuration are not checked.
+//
+// Returns a ValidationError with phase "syntax" or "schema" if validation fails.
+func ValidateStructure(mainConfig string, version *Version) error {
 	// Phase 1: Syntax validation with client-native parser
 	// This also returns the parsed configuration for Phase 1.5
 	parsedConfig, err := validateSyntax(mainConfig)
@@ -119,15 +144,6 @@ func ValidateConfiguration(mainConfig string, auxFiles *AuxiliaryFiles, paths *V
 		}
 	}
 
-	// Phase 2: Semantic validation with haproxy binary
-	if err := validateSemantics(mainConfig, auxFiles, paths); err != nil {
-		return &ValidationError{
-			Phase:   "semantic",
-			Message: "configuration has semantic errors",
-			Err:     err,
-		}
-	}
-
 	return nil
 }
 
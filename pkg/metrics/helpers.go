@@ -192,6 +192,41 @@ func NewCounterVec(registry prometheus.Registerer, name, help string, labels []s
 	)
 }
 
+// NewHistogramVec creates and registers a histogram vector with labels and
+// custom buckets.
+//
+// A histogram vector is a collection of histograms with the same name and
+// buckets but different label dimensions.
+//
+// Parameters:
+//   - registry: The Prometheus registry to register with
+//   - name: Metric name
+//   - help: Human-readable description
+//   - labels: Label names (e.g., []string{"operation"})
+//   - buckets: Bucket boundaries (e.g., DurationBuckets())
+//
+// Example:
+//
+//	registry := prometheus.NewRegistry()
+//	latency := metrics.NewHistogramVec(
+//	    registry,
+//	    "operation_duration_seconds",
+//	    "Operation duration by operation",
+//	    []string{"operation"},
+//	    metrics.DurationBuckets(),
+//	)
+//	latency.WithLabelValues("render").Observe(0.25)
+func NewHistogramVec(registry prometheus.Registerer, name, help string, labels []string, buckets []float64) *prometheus.HistogramVec {
+	return promauto.With(registry).NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    name,
+			Help:    help,
+			Buckets: buckets,
+		},
+		labels,
+	)
+}
+
 // DurationBuckets returns histogram buckets suitable for duration metrics in seconds.
 //
 // The buckets cover a range from 10ms to 10s, which is appropriate for most
@@ -160,6 +160,28 @@ func TestNewCounterVec(t *testing.T) {
 	assert.Equal(t, 3.0, testutil.ToFloat64(get404))
 }
 
+func TestNewHistogramVec(t *testing.T) {
+	registry := prometheus.NewRegistry()
+
+	histogramVec := NewHistogramVec(
+		registry,
+		"test_operation_duration_seconds",
+		"Operation duration",
+		[]string{"operation"},
+		[]float64{0.1, 1.0},
+	)
+	assert.NotNil(t, histogramVec)
+
+	histogramVec.WithLabelValues("render").Observe(0.05)
+	histogramVec.WithLabelValues("render").Observe(0.5)
+	histogramVec.WithLabelValues("parse").Observe(2.0)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(histogramVec))
+	count, err := testutil.GatherAndCount(registry, "test_operation_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
 func TestDurationBuckets(t *testing.T) {
 	buckets := DurationBuckets()
 
@@ -15,16 +15,25 @@
 package templating
 
 import (
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"net"
 	"path/filepath"
 	"reflect"
+	"regexp"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/nikolalohinski/gonja/v2/builtins"
 	"github.com/nikolalohinski/gonja/v2/config"
@@ -34,6 +43,9 @@ import (
 	"github.com/nikolalohinski/gonja/v2/parser"
 	"github.com/nikolalohinski/gonja/v2/tokens"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"haproxy-template-ic/pkg/templating/metrics"
 )
 
 // FilterFunc is a custom filter function that can be registered with the template engine.
@@ -86,6 +98,12 @@ type TemplateEngine struct {
 
 	// tracing controls template execution tracing
 	tracing *tracingConfig
+
+	// metrics records rendering metrics (nil unless enabled with WithMetrics)
+	metrics *metrics.Metrics
+
+	// config is the Gonja configuration shared by all compiled templates
+	config *config.Config
 }
 
 // tracingConfig holds template tracing configuration.
@@ -245,6 +263,7 @@ func New(engineType EngineType, templates map[string]string, customFilters map[s
 	// Create template loader and config
 	loader := NewSimpleLoader(templates)
 	cfg := createGonjaConfig()
+	engine.config = cfg
 
 	// Build Gonja environment with custom extensions
 	environment := buildEnvironment(customFilters, customFunctions)
@@ -310,12 +329,31 @@ func buildFilters(customFilters map[string]FilterFunc) *exec.FilterSet {
 		"eval":       evalFilter,
 		"strip":      stripFilter,
 		"trim":       trimFilter, // Override builtin trim to pass through errors
+		"to_mapfile": toMapfileFilter,
+		"acl_or":     aclOrFilter,
+		"acl_and":    aclAndFilter,
+
+		"server_cookie": serverCookieFilter,
+		"wrap_comment":  wrapCommentFilter,
+		"sort_unique":   sortUniqueFilter,
+		"distribute":    distributeFilter,
+		"dns_safe":      dnsSafeFilter,
+
+		"server_resilience":  serverResilienceFilter,
+		"server_options":     serverOptionsFilter,
+		"stable_server_name": stableServerNameFilter,
+		"haproxy_size":       haproxySizeFilter,
+		"percentile":         percentileFilter,
+		"clamp":              clampFilter,
+		"flag":               flagFilter,
+		"healthcheck":        healthcheckFilter,
+		"server_dns":         serverDNSFilter,
 	}
 	genericFilterSet := exec.NewFilterSet(genericFilterMap)
 	return filters.Update(genericFilterSet)
 }
 
-// buildGlobalFunctions creates a context with builtin, fail, and custom global functions.
+// buildGlobalFunctions creates a context with builtin, fail, env, and custom global functions.
 func buildGlobalFunctions(customFunctions map[string]GlobalFunc) *exec.Context {
 	globalFunctions := builtins.GlobalFunctions
 
@@ -331,6 +369,14 @@ func buildGlobalFunctions(customFunctions map[string]GlobalFunc) *exec.Context {
 		}
 		return nil, fmt.Errorf("%s", message)
 	}
+	failFunctionMap["env"] = envFunction
+	failFunctionMap["server_cookie"] = serverCookieFunction
+	failFunctionMap["stable_id"] = stableIDFunction
+	failFunctionMap["haproxy_version"] = haproxyVersionFunction
+	failFunctionMap["is_leader"] = isLeaderFunction
+	failFunctionMap["list_resources"] = listResourcesFunction
+	failFunctionMap["effective_timeout"] = effectiveTimeoutFunction
+	failFunctionMap["referenced_backends"] = referencedBackendsFunction
 	failFunctionContext := exec.NewContext(failFunctionMap)
 	globalFunctions = globalFunctions.Update(failFunctionContext)
 
@@ -365,6 +411,7 @@ func buildEnvironment(customFilters map[string]FilterFunc, customFunctions map[s
 	// Register custom control structures (tags)
 	customControlStructures := map[string]parser.ControlStructureParser{
 		"compute_once": computeOnceParser,
+		"assert":       assertParser,
 	}
 	customControlStructureSet := exec.NewControlStructureSet(customControlStructures)
 	controlStructures := builtins.ControlStructures.Update(customControlStructureSet)
@@ -385,7 +432,7 @@ func compileTemplates(engine *TemplateEngine, templates map[string]string, cfg *
 
 		compiled, err := exec.NewTemplate(name, cfg, loader, environment)
 		if err != nil {
-			return NewCompilationError(name, content, err)
+			return NewTemplateCompileError(name, content, err)
 		}
 
 		engine.compiledTemplates[name] = compiled
@@ -530,6 +577,11 @@ func createCustomDictMethods() *exec.MethodSet[map[string]interface{}] {
 // rendered output. Returns an error if the template does not exist or if
 // rendering fails.
 //
+// Each render works on an isolated copy of the context's maps and lists, so
+// globals or filters that mutate them cannot leak state into other renders or
+// back into the caller's context. Other values (e.g., store wrappers, the file
+// registry) are shared by reference on purpose.
+//
 // Example:
 //
 //	context := map[string]interface{}{
@@ -552,8 +604,74 @@ func (e *TemplateEngine) Render(templateName string, context map[string]interfac
 		context = make(map[string]interface{})
 	}
 
-	ctx := exec.NewContext(context)
+	ctx := exec.NewContext(isolateContext(context))
+
+	if e.metrics == nil {
+		return e.execute(template, templateName, ctx)
+	}
+
+	// Make the metrics available to custom tags such as compute_once
+	ctx.Set("_render_metrics", &renderMetrics{metrics: e.metrics, template: templateName})
+
+	start := time.Now()
+	output, err := e.execute(template, templateName, ctx)
+	e.metrics.RecordRender(templateName, time.Since(start), len(output), err == nil)
+
+	return output, err
+}
+
+// RenderResult is the outcome of rendering one template with RenderAll.
+type RenderResult struct {
+	// TemplateName is the name of the rendered template
+	TemplateName string
+
+	// Output is the rendered output (empty if Err is set)
+	Output string
+
+	// Err is the error returned by Render for this template
+	Err error
+}
+
+// RenderAll renders several templates with the same context, using up to
+// concurrency goroutines (GOMAXPROCS if concurrency is not positive).
+//
+// The results are in the order of templateNames, whatever order the renders
+// finish in, so concatenating them gives the same output as rendering the
+// templates one after another. Every template is rendered even if others fail.
+// Each render gets its own copy of the context like with Render, so the
+// context must not be modified until RenderAll returns.
+func (e *TemplateEngine) RenderAll(templateNames []string, context map[string]interface{}, concurrency int) []RenderResult {
+	results := make([]RenderResult, len(templateNames))
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	concurrency = min(concurrency, len(templateNames))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				output, err := e.Render(templateNames[i], context)
+				results[i] = RenderResult{TemplateName: templateNames[i], Output: output, Err: err}
+			}
+		}()
+	}
+
+	for i := range templateNames {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
 
+// execute renders a compiled template with the given execution context and
+// applies its post-processors.
+func (e *TemplateEngine) execute(template *exec.Template, templateName string, ctx *exec.Context) (string, error) {
 	// Setup tracing if enabled
 	cleanup := e.setupTracing(ctx, templateName)
 	if cleanup != nil {
@@ -575,6 +693,23 @@ func (e *TemplateEngine) Render(templateName string, context map[string]interfac
 	return output, nil
 }
 
+// renderMetrics is the per-render metrics state stored in the execution context.
+type renderMetrics struct {
+	metrics  *metrics.Metrics
+	template string
+}
+
+// renderMetricsFrom returns the metrics state of the render ctx belongs to,
+// or nil if metrics are disabled.
+func renderMetricsFrom(ctx *exec.Context) *renderMetrics {
+	if value, ok := ctx.Get("_render_metrics"); ok {
+		if rm, ok := value.(*renderMetrics); ok {
+			return rm
+		}
+	}
+	return nil
+}
+
 // templateNotFoundError creates a TemplateNotFoundError with available template names.
 func (e *TemplateEngine) templateNotFoundError(templateName string) error {
 	availableNames := make([]string, 0, len(e.compiledTemplates))
@@ -762,9 +897,16 @@ func sortByFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec
 	return exec.AsValue(sortable.items)
 }
 
-// groupByFilter groups items by evaluated key expression.
-// Usage: items | group_by("$.hostname ~ '|' ~ $.path.type ~ '|' ~ $.path.value").
+// groupByFilter groups items by evaluated key expression into a dict of
+// key → list. Items keep their input order within each group, and items for
+// which the key is missing are grouped under the empty key "".
+// Usage: items | group_by("$.hostname ~ '|' ~ $.path.type ~ '|' ~ $.path.value")
+// or endpoints | group_by("zone").
 func groupByFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
 	// Extract items array
 	items := in.Interface()
 	itemsSlice, ok := convertToSlice(items)
@@ -796,7 +938,13 @@ func groupByFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exe
 	groups := make(map[string]interface{})
 	for _, item := range itemsSlice {
 		key := evaluateExpression(item, keyExprStr)
-		keyStr := fmt.Sprint(key)
+		if v, ok := key.(*exec.Value); ok {
+			key = v.Interface()
+		}
+		keyStr := ""
+		if key != nil {
+			keyStr = fmt.Sprint(key)
+		}
 		if existing, ok := groups[keyStr]; ok {
 			// Append to existing group
 			if existingSlice, ok := existing.([]interface{}); ok {
@@ -1043,822 +1191,2652 @@ func stripFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.
 	return exec.AsValue(stripped)
 }
 
-// trimFilter is a custom trim filter that passes through errors instead of masking them.
-// This is critical for proper error reporting when templates fail inside include_matching().
-//
-// The builtin Gonja trim filter wraps errors with "Wrong signature for 'trim'", which
-// hides the actual error (e.g., from fail() function). Our version checks if the input
-// is an error and returns it unchanged, allowing the real error to propagate.
+// wrapCommentFilter wraps text into "# "-prefixed HAProxy comment lines no
+// longer than width, breaking at word boundaries.
 //
-// Usage: {{ include_matching("pattern-*") | trim }}.
+// Line breaks in the input start a new paragraph and blank lines are kept as
+// a bare "#". Words longer than the available width are placed on their own
+// line rather than split.
 //
-// Supports optional 'chars' parameter like Gonja's trim:
-// {{ "  hello  " | trim }}  → "hello".
-// {{ "xxhelloxx" | trim(chars="x") }}  → "hello".
-func trimFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
-	// Pass through errors unchanged (don't mask them)
+// Usage: {{ description | wrap_comment(80) }}.
+func wrapCommentFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
 	if in.IsError() {
 		return in
 	}
 
-	// Extract 'chars' parameter (defaults to whitespace)
-	charsParam := exec.KwArg{
-		Name:    "chars",
-		Default: " \t\n\r",
+	widthArg := params.First()
+	if widthArg == nil || !widthArg.IsInteger() {
+		return exec.AsValue(fmt.Errorf("wrap_comment: width must be an integer"))
 	}
-	p := params.ExpectKwArgs([]*exec.KwArg{&charsParam})
-	if p.IsError() {
-		return exec.AsValue(errors.Wrap(p, "Wrong signature for 'trim'"))
+	width := widthArg.Integer()
+	if width <= len("# ") {
+		return exec.AsValue(fmt.Errorf("wrap_comment: width must be greater than %d, got %d", len("# "), width))
 	}
 
-	// Convert input to string
-	str := in.String()
+	return exec.AsValue(wrapComment(in.String(), width))
+}
 
-	// Get chars to trim
-	chars := p.GetKeywordArgument(charsParam.Name, charsParam.Default).String()
+// wrapComment implements wrap_comment for a single string.
+func wrapComment(text string, width int) string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "#")
+			continue
+		}
 
-	// Perform trim operation
-	trimmed := strings.Trim(str, chars)
+		line := "# " + words[0]
+		for _, word := range words[1:] {
+			if utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, line)
+				line = "# " + word
+				continue
+			}
+			line += " " + word
+		}
+		lines = append(lines, line)
+	}
 
-	return exec.AsValue(trimmed)
+	return strings.Join(lines, "\n")
 }
 
-// conflictsByTest detects conflicts when grouped by key.
-// Usage: routes | conflicts_by("$.path", "$.method ~ $.headers").
-func conflictsByTest(ctx *exec.Context, in *exec.Value, params *exec.VarArgs) (bool, error) {
-	// Extract items array
-	items := in.Interface()
-	itemsSlice, ok := convertToSlice(items)
+// sortUniqueFilter sorts a list of strings or numbers and removes duplicates,
+// so lists assembled from several sources render in a stable order.
+//
+// Strings sort lexicographically, numbers numerically. Mixing both is an error
+// because there is no ordering that is obviously right for it.
+//
+// Usage: {{ ["10.0.0.0/8", "192.168.0.0/16", "10.0.0.0/8"] | sort_unique }}
+// → ["10.0.0.0/8", "192.168.0.0/16"].
+func sortUniqueFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	items, ok := convertToSlice(in.Interface())
 	if !ok {
-		return false, fmt.Errorf("conflicts_by: expected array/slice, got %T", items)
+		return exec.AsValue(fmt.Errorf("sort_unique: expected a list, got %T", in.Interface()))
 	}
 
-	// Extract group expression and compare expression
-	args := params.Args
-	if len(args) < 2 {
-		return false, fmt.Errorf("conflicts_by: requires 2 arguments, got %d", len(args))
+	result, err := sortUnique(items)
+	if err != nil {
+		return exec.AsValue(err)
 	}
+	return exec.AsValue(result)
+}
 
-	// Get group expression (first arg)
-	groupExpr := args[0].String()
+// distributeFilter splits an integer total into parts proportional to weights,
+// e.g. to divide a global maxconn budget across frontends.
+//
+// The parts always sum to exactly the total. Shares are rounded down and the
+// remaining units go to the largest remainders (ties to the earlier weight;
+// map weights are processed in key order). Zero weights get nothing; all
+// weights being zero is an error unless the total is zero.
+//
+// Weights are a list, giving a list of parts, or a map, giving a map with the
+// same keys.
+//
+// Usage: {{ 10000 | distribute([3, 1]) }} → [7500, 2500]
+// or {% set maxconn = 10000 | distribute({"http": 1, "https": 3}) %} → maxconn.https = 7500.
+func distributeFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
 
-	// Get compare expression (second arg)
-	compareExpr := args[1].String()
+	total, ok := toFloat64(in.Interface())
+	if !ok || total != math.Trunc(total) || total < 0 {
+		return exec.AsValue(fmt.Errorf("distribute: total must be a non-negative integer, got %v", in.Interface()))
+	}
 
-	// Group items
-	groups := make(map[string][]interface{})
-	for _, item := range itemsSlice {
-		key := evaluateExpression(item, groupExpr)
-		keyStr := fmt.Sprint(key)
-		groups[keyStr] = append(groups[keyStr], item)
+	weightsArg := params.First()
+	if weightsArg == nil {
+		return exec.AsValue(fmt.Errorf("distribute: requires weights (list or map)"))
 	}
 
-	// Check for conflicts within each group
-	for _, group := range groups {
-		if len(group) <= 1 {
-			continue
+	if weightMap, ok := weightsArg.Interface().(map[string]interface{}); ok {
+		keys := make([]string, 0, len(weightMap))
+		for key := range weightMap {
+			keys = append(keys, key)
 		}
+		sort.Strings(keys)
 
-		// Check if items in group have different comparison values
-		firstValue := evaluateExpression(group[0], compareExpr)
-		firstStr := fmt.Sprint(firstValue)
+		weights := make([]interface{}, len(keys))
+		for i, key := range keys {
+			weights[i] = weightMap[key]
+		}
 
-		for _, item := range group[1:] {
-			value := evaluateExpression(item, compareExpr)
-			valueStr := fmt.Sprint(value)
-			if firstStr != valueStr {
-				return true, nil // Conflict found
-			}
+		parts, err := distribute(int(total), weights)
+		if err != nil {
+			return exec.AsValue(err)
 		}
-	}
 
-	return false, nil
-}
+		result := make(map[string]interface{}, len(keys))
+		for i, key := range keys {
+			result[key] = parts[i]
+		}
+		return exec.AsValue(result)
+	}
 
-// Helper types and functions for the generic functions
+	weights, ok := convertToSlice(weightsArg.Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("distribute: weights must be a list or map, got %T", weightsArg.Interface()))
+	}
 
-type sortableItems struct {
-	items    []interface{}
-	criteria []string
-	tracing  *tracingConfig // For filter debug logging
-}
+	parts, err := distribute(int(total), weights)
+	if err != nil {
+		return exec.AsValue(err)
+	}
 
-func (s *sortableItems) Len() int {
-	return len(s.items)
+	result := make([]interface{}, len(parts))
+	for i, part := range parts {
+		result[i] = part
+	}
+	return exec.AsValue(result)
 }
 
-func (s *sortableItems) Less(i, j int) bool {
-	for _, criterion := range s.criteria {
-		cmp := compareByExpressionWithDebug(s.items[i], s.items[j], criterion, s.tracing)
-		if cmp != 0 {
-			return cmp < 0
+// distribute implements distribute using the largest remainder method.
+func distribute(total int, weights []interface{}) ([]int, error) {
+	values := make([]float64, len(weights))
+	var sum float64
+	for i, weight := range weights {
+		value, ok := toFloat64(weight)
+		if !ok || value < 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+			return nil, fmt.Errorf("distribute: weight %d must be a non-negative number, got %v", i, weight)
 		}
+		values[i] = value
+		sum += value
 	}
-	return false
-}
-
-func (s *sortableItems) Swap(i, j int) {
-	s.items[i], s.items[j] = s.items[j], s.items[i]
-}
 
-// evaluateExpression evaluates a JSONPath-like expression against an item.
-// Supports:
-// - Basic paths: "$.field.subfield"
-// - Array access: "$.items[0]" or "$.items[*]"
-// - Length operator: "$.items | length"
-// - Concatenation: "$.field1 ~ '-' ~ $.field2"
-// - Existence check: "$.field:exists".
-func evaluateExpression(item interface{}, expr string) interface{} {
-	expr = strings.TrimSpace(expr)
-	if expr == "" {
-		return nil
+	parts := make([]int, len(weights))
+	if total == 0 {
+		return parts, nil
 	}
-
-	// Don't unwrap *exec.Value here - let navigateJSONPath handle it
-	// using Gonja's GetItem/GetAttribute methods for proper field access
-
-	// Handle concatenation operator ~
-	if strings.Contains(expr, " ~ ") {
-		return handleConcatenation(item, expr)
+	if sum == 0 {
+		return nil, fmt.Errorf("distribute: cannot distribute %d with all weights zero", total)
 	}
 
-	// Handle pipe operator |
-	if strings.Contains(expr, " | ") {
-		return handlePipeOperator(item, expr)
+	remainders := make([]float64, len(weights))
+	assigned := 0
+	for i, value := range values {
+		share := float64(total) * value / sum
+		parts[i] = int(math.Floor(share))
+		remainders[i] = share - float64(parts[i])
+		assigned += parts[i]
 	}
 
-	// Handle existence check
-	if strings.HasSuffix(expr, ":exists") {
-		return handleExistenceCheck(item, expr)
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
 	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
 
-	// Handle JSONPath-like navigation
-	if strings.HasPrefix(expr, "$.") || expr == "$" {
-		return navigateJSONPath(item, expr)
+	// With fractional weights, floating point error can leave a unit to hand out
+	// even to a weight with a zero remainder (never to a zero weight), or round a
+	// share up so that one unit too many was assigned.
+	for i := 0; assigned < total; i = (i + 1) % len(order) {
+		if values[order[i]] == 0 {
+			continue
+		}
+		parts[order[i]]++
+		assigned++
+	}
+	for i := len(order) - 1; assigned > total; i-- {
+		if parts[order[i]] > 0 {
+			parts[order[i]]--
+			assigned--
+		}
 	}
 
-	// Treat as field name without $. prefix
-	return navigateJSONPath(item, "$."+expr)
+	return parts, nil
 }
 
-// handleConcatenation processes the ~ concatenation operator.
-func handleConcatenation(item interface{}, expr string) interface{} {
-	parts := strings.Split(expr, " ~ ")
-	var result strings.Builder
+// percentileFilter returns the p-th percentile (0-100) of a list of numbers,
+// e.g. to derive a check interval from the spread of service sizes.
+//
+// The percentile is linearly interpolated between the two closest ranks, so
+// percentile(50) of [1, 2, 3, 4] is 2.5. Whole results are integers. Empty
+// lists, non-numeric values and p outside 0-100 are errors.
+//
+// Usage: slowstart {{ replica_counts | percentile(90) * 2 }}s.
+func percentileFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
 
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
+	pArg := params.First()
+	if pArg == nil {
+		return exec.AsValue(fmt.Errorf("percentile: requires p (0-100)"))
+	}
+	p, ok := toFloat64(pArg.Interface())
+	if !ok || p < 0 || p > 100 {
+		return exec.AsValue(fmt.Errorf("percentile: p must be a number between 0 and 100, got %v", pArg.Interface()))
+	}
 
-		// Check if it's a literal string
-		if (strings.HasPrefix(part, "'") && strings.HasSuffix(part, "'")) ||
-			(strings.HasPrefix(part, "\"") && strings.HasSuffix(part, "\"")) {
-			// Remove quotes and append literal
-			result.WriteString(strings.Trim(part, "'\""))
-		} else {
-			// Evaluate as expression
-			value := evaluateExpression(item, part)
-			if value != nil {
-				result.WriteString(fmt.Sprint(value))
-			}
+	items, ok := convertToSlice(in.Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("percentile: expected list of numbers, got %T", in.Interface()))
+	}
+	if len(items) == 0 {
+		return exec.AsValue(fmt.Errorf("percentile: list is empty"))
+	}
+
+	values := make([]float64, len(items))
+	for i, item := range items {
+		value, ok := toFloat64(item)
+		if !ok || math.IsNaN(value) {
+			return exec.AsValue(fmt.Errorf("percentile: item %d must be a number, got %v", i, item))
 		}
+		values[i] = value
 	}
+	sort.Float64s(values)
 
-	return result.String()
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	result := values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+
+	return exec.AsValue(wholeAsInt(result))
 }
 
-// handlePipeOperator processes the | pipe operator for filters.
-func handlePipeOperator(item interface{}, expr string) interface{} {
-	parts := strings.Split(expr, " | ")
-	value := evaluateExpression(item, strings.TrimSpace(parts[0]))
+// clampFilter limits a number to the range [min, max].
+//
+// Integers stay integers if the result is whole. Non-numeric values and a
+// minimum above the maximum are errors.
+//
+// Usage: inter {{ (1000 * backends | length) | clamp(500, 5000) }}.
+func clampFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
 
-	for i := 1; i < len(parts); i++ {
-		value = applyPipeOperation(value, strings.TrimSpace(parts[i]))
+	if len(params.Args) != 2 {
+		return exec.AsValue(fmt.Errorf("clamp: requires min and max, got %d arguments", len(params.Args)))
 	}
 
-	return value
+	value, ok := toFloat64(in.Interface())
+	if !ok || math.IsNaN(value) {
+		return exec.AsValue(fmt.Errorf("clamp: value must be a number, got %v", in.Interface()))
+	}
+	minValue, ok := toFloat64(params.Args[0].Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("clamp: min must be a number, got %v", params.Args[0].Interface()))
+	}
+	maxValue, ok := toFloat64(params.Args[1].Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("clamp: max must be a number, got %v", params.Args[1].Interface()))
+	}
+	if minValue > maxValue {
+		return exec.AsValue(fmt.Errorf("clamp: min %v is greater than max %v", minValue, maxValue))
+	}
+
+	return exec.AsValue(wholeAsInt(math.Min(math.Max(value, minValue), maxValue)))
 }
 
-// applyPipeOperation applies a single pipe operation to a value.
-func applyPipeOperation(value interface{}, operation string) interface{} {
-	// Handle length operator
-	if operation == "length" {
-		return calculateLength(value)
+// wholeAsInt returns f as an int if it is a whole number that fits, so that
+// templates render it without a fractional part.
+func wholeAsInt(f float64) interface{} {
+	if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+		return int(f)
 	}
+	return f
+}
 
-	// Handle default operator
-	if strings.HasPrefix(operation, "default(") && strings.HasSuffix(operation, ")") {
-		if value == nil || value == "" {
-			defaultValue := strings.TrimSuffix(strings.TrimPrefix(operation, "default("), ")")
-			return strings.Trim(defaultValue, "'\"")
+// sortUnique implements sort_unique for a list of scalars.
+func sortUnique(items []interface{}) ([]interface{}, error) {
+	var strs []string
+	var nums []float64
+	numValues := make(map[float64]interface{})
+
+	for i, item := range items {
+		if str, ok := item.(string); ok {
+			strs = append(strs, str)
+			continue
+		}
+
+		num, ok := toFloat64(item)
+		if !ok {
+			return nil, fmt.Errorf("sort_unique: item %d must be a string or number, got %T", i, item)
+		}
+		if _, seen := numValues[num]; !seen {
+			numValues[num] = item
+			nums = append(nums, num)
 		}
 	}
 
-	return value
-}
+	if len(strs) > 0 && len(nums) > 0 {
+		return nil, fmt.Errorf("sort_unique: cannot sort a list mixing strings and numbers")
+	}
 
-// calculateLength returns the length of a value.
-func calculateLength(value interface{}) int {
-	switch v := value.(type) {
-	case string:
-		return len(v)
-	default:
-		if slice, ok := convertToSlice(value); ok {
-			return len(slice)
+	result := make([]interface{}, 0, len(items))
+	if len(nums) > 0 {
+		sort.Float64s(nums)
+		for _, num := range nums {
+			result = append(result, numValues[num])
 		}
-		if m, ok := convertToMap(value); ok {
-			return len(m)
+		return result, nil
+	}
+
+	sort.Strings(strs)
+	for i, str := range strs {
+		if i > 0 && str == strs[i-1] {
+			continue
 		}
-		return 0
+		result = append(result, str)
 	}
+	return result, nil
 }
 
-// handleExistenceCheck checks if a value exists (is not nil).
-func handleExistenceCheck(item interface{}, expr string) interface{} {
-	expr = strings.TrimSuffix(expr, ":exists")
-	value := evaluateExpression(item, expr)
-	return value != nil
-}
+// dnsSafeMaxLength is the default maximum length of dns_safe names, the
+// length limit of a DNS label.
+const dnsSafeMaxLength = 63
 
-// navigateJSONPath navigates through an object using JSONPath-like syntax.
-func navigateJSONPath(item interface{}, path string) interface{} {
-	// Remove leading $. if present
-	if strings.HasPrefix(path, "$.") {
-		path = strings.TrimPrefix(path, "$.")
-	} else if path == "$" {
-		return item
+// dnsSafeFilter turns an arbitrary string, such as a label value, into a name
+// that is valid as a DNS label and as an HAProxy section or server name.
+//
+// The input is lowercased, every character other than a-z and 0-9 becomes "-",
+// repeated dashes collapse into one and leading and trailing dashes are removed.
+// The result is then cut to the maximum length (63 unless given) without
+// leaving a trailing dash. The same input always gives the same name, and
+// inputs differing only in case or punctuation give the same name too.
+//
+// Usage: backend {{ service.metadata.labels.app | dns_safe }}
+// or {{ name | dns_safe(32) }}.
+func dnsSafeFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
 	}
 
-	if path == "" {
-		return item
+	maxLength := dnsSafeMaxLength
+	if params != nil && len(params.Args) > 0 {
+		arg := params.Args[0]
+		if !arg.IsInteger() || arg.Integer() < 1 {
+			return exec.AsValue(fmt.Errorf("dns_safe: max length must be a positive integer, got %v", arg.Interface()))
+		}
+		maxLength = arg.Integer()
 	}
 
-	// Split path into segments, handling array indices
-	segments := parsePathSegments(path)
-	current := item
+	name := dnsSafe(in.String(), maxLength)
+	if name == "" {
+		return exec.AsValue(fmt.Errorf("dns_safe: %q contains no letters or digits", in.String()))
+	}
+	return exec.AsValue(name)
+}
 
-	for i, segment := range segments {
-		if current == nil {
-			return nil
+// dnsSafe implements dns_safe for a single string.
+func dnsSafe(s string, maxLength int) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
 		}
-
-		// Handle array index access
-		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
-			result, done := processArrayIndex(current, segment, segments, i)
-			if done {
-				return result
-			}
-			current = result
-		} else {
-			// Regular field access
-			current = processFieldAccess(current, segment)
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
 		}
 	}
 
-	// Unwrap final result if it's a Gonja Value
-	if execVal, ok := current.(*exec.Value); ok {
-		return execVal.Interface()
+	name := b.String()
+	if len(name) > maxLength {
+		name = name[:maxLength]
 	}
-
-	return current
+	return strings.TrimRight(name, "-")
 }
 
-// processArrayIndex handles array index access including wildcards and numeric indices.
-func processArrayIndex(current interface{}, segment string, segments []string, segmentIndex int) (interface{}, bool) {
-	indexStr := strings.TrimPrefix(strings.TrimSuffix(segment, "]"), "[")
+// serverResilienceParam is a parameter accepted by server_resilience.
+type serverResilienceParam struct {
+	// name is the key of the parameter in the input dict
+	name string
 
-	// Handle wildcard [*]
-	if indexStr == "*" {
-		return processWildcardIndex(current, segments, segmentIndex)
-	}
+	// keyword is the server option the parameter is emitted as
+	keyword string
 
-	// Handle numeric index
-	return processNumericIndex(current, indexStr)
+	// format validates the parameter value and returns the option argument
+	format func(v interface{}) (string, bool)
 }
 
-// processWildcardIndex handles wildcard array access [*].
-func processWildcardIndex(current interface{}, segments []string, segmentIndex int) (interface{}, bool) {
-	slice, ok := convertToSlice(current)
+// serverResilienceParams are the parameters of server_resilience in the order
+// their options are emitted.
+var serverResilienceParams = []serverResilienceParam{
+	{name: "observe", keyword: "observe", format: oneOf("layer4", "layer7")},
+	{name: "error_limit", keyword: "error-limit", format: positiveInteger},
+	{name: "on_error", keyword: "on-error", format: oneOf("fastinter", "fail-check", "sudden-death", "mark-down")},
+	{name: "on_marked_down", keyword: "on-marked-down", format: oneOf("shutdown-sessions")},
+	{name: "on_marked_up", keyword: "on-marked-up", format: oneOf("shutdown-backup-sessions")},
+	{name: "fastinter", keyword: "fastinter", format: haproxyDuration},
+	{name: "downinter", keyword: "downinter", format: haproxyDuration},
+}
+
+// haproxyDurationPattern matches HAProxy time values such as "500", "500ms" or "2s".
+var haproxyDurationPattern = regexp.MustCompile(`^[0-9]+(us|ms|s|m|h|d)?$`)
+
+// serverResilienceFilter turns a dict of resilience parameters into the
+// corresponding server options.
+//
+// Supported parameters are observe, error_limit, on_error, on_marked_down,
+// on_marked_up, fastinter and downinter; they map to the server options of the
+// same name with underscores replaced by dashes. Options are emitted in a fixed
+// order and absent or null parameters are omitted, so an empty dict yields an
+// empty string. Since HAProxy only counts errors of observed traffic,
+// error_limit and on_error require observe. Unknown parameters and invalid
+// values are errors.
+//
+// Usage: server {{ name }} {{ address }} check {{ resilience | server_resilience }}.
+func serverResilienceFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	opts, ok := convertToDict(in.Interface())
 	if !ok {
-		return nil, true
+		return exec.AsValue(fmt.Errorf("server_resilience: expected dict of parameters, got %T", in.Interface()))
 	}
 
-	// If there are more segments, collect results from all elements
-	if segmentIndex < len(segments)-1 {
-		remainingPath := strings.Join(segments[segmentIndex+1:], ".")
-		var results []interface{}
-		for _, elem := range slice {
-			result := navigateJSONPath(elem, "$."+remainingPath)
-			if result != nil {
-				if resultSlice, ok := convertToSlice(result); ok {
-					results = append(results, resultSlice...)
-				} else {
-					results = append(results, result)
-				}
-			}
+	known := make(map[string]bool, len(serverResilienceParams))
+	for _, param := range serverResilienceParams {
+		known[param.name] = true
+	}
+	for name := range opts {
+		if !known[name] {
+			return exec.AsValue(fmt.Errorf("server_resilience: unknown parameter %q", name))
 		}
-		return results, true
 	}
 
-	// Just return all elements
-	return slice, true
-}
+	set := func(name string) bool { return unwrapValue(opts[name]) != nil }
+	if (set("error_limit") || set("on_error")) && !set("observe") {
+		return exec.AsValue(fmt.Errorf("server_resilience: error_limit and on_error require observe"))
+	}
 
-// processNumericIndex handles numeric array index access.
-func processNumericIndex(current interface{}, indexStr string) (interface{}, bool) {
-	index, err := strconv.Atoi(indexStr)
-	if err != nil {
-		return current, false // Not a numeric index, continue
+	tokens := make([]string, 0, 2*len(serverResilienceParams))
+	for _, param := range serverResilienceParams {
+		value := unwrapValue(opts[param.name])
+		if value == nil {
+			continue
+		}
+		arg, ok := param.format(value)
+		if !ok {
+			return exec.AsValue(fmt.Errorf("server_resilience: invalid %s %v", param.name, value))
+		}
+		tokens = append(tokens, param.keyword, arg)
 	}
 
-	slice, ok := convertToSlice(current)
+	return exec.AsValue(strings.Join(tokens, " "))
+}
+
+// convertToDict converts context maps as well as dict literals from templates,
+// which Gonja stores as *exec.Dict, to a map.
+func convertToDict(v interface{}) (map[string]interface{}, bool) {
+	dict, ok := v.(*exec.Dict)
 	if !ok {
-		return nil, true
+		return convertToMap(v)
 	}
 
-	if index >= 0 && index < len(slice) {
-		return slice[index], false
+	m := make(map[string]interface{}, len(dict.Pairs))
+	for _, pair := range dict.Pairs {
+		m[pair.Key.String()] = pair.Value.Interface()
 	}
-
-	return nil, true
+	return m, true
 }
 
-// processFieldAccess handles regular field access.
-func processFieldAccess(current interface{}, segment string) interface{} {
-	// If current is a Gonja Value, use its GetItem method for proper access
-	if execVal, ok := current.(*exec.Value); ok {
-		if val, found := execVal.GetItem(segment); found {
-			return val
-		}
-		// Also try GetAttribute for struct fields
-		if val, found := execVal.GetAttribute(segment); found {
-			return val
-		}
-		return nil
+// oneOf returns a format function accepting only the given strings.
+func oneOf(allowed ...string) func(v interface{}) (string, bool) {
+	return func(v interface{}) (string, bool) {
+		s, ok := v.(string)
+		return s, ok && slices.Contains(allowed, s)
 	}
+}
 
-	if m, ok := convertToMap(current); ok {
-		return m[segment]
+// positiveInteger formats integers greater than zero.
+func positiveInteger(v interface{}) (string, bool) {
+	f, ok := toFloat64(v)
+	if !ok || f < 1 || f != math.Trunc(f) {
+		return "", false
 	}
+	return strconv.FormatInt(int64(f), 10), true
+}
 
-	// Try reflection for struct fields
-	return getFieldByReflection(current, segment)
+// haproxyDuration formats integers as milliseconds and passes through strings
+// that are valid HAProxy time values.
+func haproxyDuration(v interface{}) (string, bool) {
+	if s, ok := v.(string); ok {
+		return s, haproxyDurationPattern.MatchString(s)
+	}
+	f, ok := toFloat64(v)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return "", false
+	}
+	return strconv.FormatInt(int64(f), 10), true
 }
 
-// parsePathSegments parses a JSONPath into navigable segments.
-func parsePathSegments(path string) []string {
-	var segments []string
-	var current strings.Builder
-	inBracket := false
+// serverOption is an option accepted by server_options.
+type serverOption struct {
+	// name is the key of the option in the input dict
+	name string
 
-	for i, char := range path {
-		switch {
-		case char == '[':
-			segments, inBracket = processLeftBracket(segments, &current)
-		case char == ']':
-			segments, inBracket = processRightBracket(segments, &current, inBracket)
-		case char == '.' && !inBracket:
-			segments = processDot(segments, &current)
-		default:
-			current.WriteRune(char)
-		}
+	// keyword is the server option the key is emitted as
+	keyword string
 
-		// Handle last segment
-		if i == len(path)-1 && current.Len() > 0 {
-			segments = append(segments, current.String())
-		}
-	}
+	// flag marks options without an argument, which are emitted if their value
+	// is true and omitted if it is false
+	flag bool
 
-	return segments
+	// format validates the value of non-flag options and returns the option argument
+	format func(v interface{}) (string, bool)
 }
 
-// processLeftBracket handles '[' character when parsing path segments.
-func processLeftBracket(segments []string, current *strings.Builder) ([]string, bool) {
-	if current.Len() > 0 {
-		segments = append(segments, current.String())
-		current.Reset()
-	}
-	current.WriteByte('[')
-	return segments, true
+// serverOptions are the options of server_options in the order they are emitted.
+var serverOptions = []serverOption{
+	{name: "check", keyword: "check", flag: true},
+	{name: "check_ssl", keyword: "check-ssl", flag: true},
+	{name: "inter", keyword: "inter", format: haproxyDuration},
+	{name: "fastinter", keyword: "fastinter", format: haproxyDuration},
+	{name: "downinter", keyword: "downinter", format: haproxyDuration},
+	{name: "rise", keyword: "rise", format: positiveInteger},
+	{name: "fall", keyword: "fall", format: positiveInteger},
+	{name: "weight", keyword: "weight", format: integerInRange(0, 256)},
+	{name: "maxconn", keyword: "maxconn", format: positiveInteger},
+	{name: "maxqueue", keyword: "maxqueue", format: positiveInteger},
+	{name: "slowstart", keyword: "slowstart", format: haproxyDuration},
+	{name: "backup", keyword: "backup", flag: true},
+	{name: "ssl", keyword: "ssl", flag: true},
+	{name: "verify", keyword: "verify", format: oneOf("none", "required")},
+	{name: "send_proxy", keyword: "send-proxy", flag: true},
+	{name: "send_proxy_v2", keyword: "send-proxy-v2", flag: true},
 }
 
-// processRightBracket handles ']' character when parsing path segments.
-func processRightBracket(segments []string, current *strings.Builder, inBracket bool) ([]string, bool) {
-	current.WriteByte(']')
-	if inBracket {
-		segments = append(segments, current.String())
-		current.Reset()
-		return segments, false
+// WarningsContextKey is the rendering context key holding a WarningFunc that
+// receives the warnings of filters such as server_options. Without it,
+// warnings are logged through the default slog logger.
+const WarningsContextKey = "template_warnings"
+
+// WarningFunc receives a warning emitted while rendering a template.
+type WarningFunc func(message string)
+
+// warnf reports a rendering warning to the WarningFunc in the rendering context.
+func warnf(e *exec.Evaluator, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		if value, ok := e.Environment.Context.Get(WarningsContextKey); ok {
+			if warn, ok := value.(WarningFunc); ok && warn != nil {
+				warn(message)
+				return
+			}
+		}
 	}
-	return segments, inBracket
+	slog.Warn("template warning", "message", message)
 }
 
-// processDot handles '.' character when parsing path segments.
-func processDot(segments []string, current *strings.Builder) []string {
-	if current.Len() > 0 {
-		segments = append(segments, current.String())
-		current.Reset()
+// serverOptionsFilter turns a dict of per-service settings, such as the
+// values of Kubernetes annotations, into server options.
+//
+// Keys may use dashes or underscores ("send-proxy" or "send_proxy"). Flags
+// (check, check_ssl, backup, ssl, send_proxy, send_proxy_v2) take booleans or
+// the strings "true" and "false"; the other keys take the value of the server
+// option of the same name. Options are emitted in a fixed order regardless of
+// the order of the dict, and null values are omitted. Unknown keys are ignored
+// with a warning (see WarningsContextKey), so annotations meant for other
+// consumers can be passed through unfiltered. Invalid values are errors.
+//
+// Usage: server {{ name }} {{ address }} {{ service.metadata.annotations | server_options }}.
+func serverOptionsFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
 	}
-	return segments
-}
 
-// compareByExpressionWithDebug wraps compareByExpression and adds debug logging when enabled.
-func compareByExpressionWithDebug(a, b interface{}, criterion string, tracing *tracingConfig) int {
-	// Check if debug is enabled
-	var debugEnabled bool
-	if tracing != nil {
-		tracing.mu.Lock()
-		debugEnabled = tracing.debugFilters
-		tracing.mu.Unlock()
+	input, ok := convertToDict(in.Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("server_options: expected dict of options, got %T", in.Interface()))
 	}
 
-	// If debug not enabled, just call compareByExpression
-	if !debugEnabled {
-		return compareByExpression(a, b, criterion)
+	known := make(map[string]bool, len(serverOptions))
+	for _, option := range serverOptions {
+		known[option.name] = true
 	}
 
-	// Debug enabled - clean expression and evaluate for logging
-	// Parse modifiers to get clean expression (same logic as compareByExpression)
-	checkExists := false
-	checkLength := false
+	opts := make(map[string]interface{}, len(input))
+	var unknown []string
+	for key, value := range input {
+		name := strings.ReplaceAll(key, "-", "_")
+		if !known[name] {
+			unknown = append(unknown, key)
+			continue
+		}
+		if _, duplicate := opts[name]; duplicate {
+			return exec.AsValue(fmt.Errorf("server_options: %s is set more than once", name))
+		}
+		opts[name] = unwrapValue(value)
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		warnf(e, "server_options: ignoring unknown key %q", key)
+	}
 
-	parts := strings.Split(criterion, ":")
-	expr := parts[0]
+	tokens := make([]string, 0, 2*len(serverOptions))
+	for _, option := range serverOptions {
+		value := opts[option.name]
+		if value == nil {
+			continue
+		}
 
-	for i := 1; i < len(parts); i++ {
-		modifier := strings.TrimSpace(parts[i])
-		if modifier == "exists" {
-			checkExists = true
+		if option.flag {
+			enabled, ok := parseFlag(value)
+			if !ok {
+				return exec.AsValue(fmt.Errorf("server_options: invalid %s %v, expected true or false", option.name, value))
+			}
+			if enabled {
+				tokens = append(tokens, option.keyword)
+			}
+			continue
 		}
-	}
 
-	// Check for length operator
-	if strings.Contains(expr, " | length") {
-		checkLength = true
-		expr = strings.Split(expr, " | ")[0]
+		arg, ok := option.format(value)
+		if !ok {
+			return exec.AsValue(fmt.Errorf("server_options: invalid %s %v", option.name, value))
+		}
+		tokens = append(tokens, option.keyword, arg)
 	}
 
-	// Evaluate cleaned expression
-	valA := evaluateExpression(a, expr)
-	valB := evaluateExpression(b, expr)
+	return exec.AsValue(strings.Join(tokens, " "))
+}
 
-	// Apply modifiers for debug display
-	if checkExists {
-		valA = (valA != nil)
-		valB = (valB != nil)
-	} else if checkLength {
-		valA = getLength(valA)
-		valB = getLength(valB)
+// parseFlag parses booleans as well as the strings "true" and "false", which is
+// how annotations carry them.
+func parseFlag(v interface{}) (bool, bool) {
+	switch val := v.(type) {
+	case bool:
+		return val, true
+	case string:
+		b, err := strconv.ParseBool(val)
+		return b, err == nil
 	}
+	return false, false
+}
 
-	// Call comparison
-	result := compareByExpression(a, b, criterion)
+// flagFilter emits a bare HAProxy directive when enabled and nothing
+// otherwise, or the directive prefixed with "no" when disabled with
+// negate=true, for directives that are on by default.
+//
+// Enabled accepts booleans and their string forms (e.g. from annotations);
+// none counts as disabled.
+//
+// Usage: {{ "option httpchk" | flag(health_check) }}.
+func flagFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
 
-	// Log the comparison
-	slog.Info("SORT comparison",
-		"criterion", criterion,
-		"valA", valA,
-		"valA_type", fmt.Sprintf("%T", valA),
-		"valB", valB,
-		"valB_type", fmt.Sprintf("%T", valB),
-		"result", result)
+	if len(params.Args) != 1 {
+		return exec.AsValue(fmt.Errorf("flag: requires enabled, got %d arguments", len(params.Args)))
+	}
+	negate := false
+	for name, value := range params.KwArgs {
+		if name != "negate" {
+			return exec.AsValue(fmt.Errorf("flag: unknown argument %q", name))
+		}
+		negate = value.IsTrue()
+	}
 
-	return result
+	directive := strings.TrimSpace(in.String())
+	if directive == "" {
+		return exec.AsValue(fmt.Errorf("flag: directive must not be empty"))
+	}
+
+	enabled := false
+	if arg := params.Args[0]; !arg.IsNil() {
+		var ok bool
+		if enabled, ok = parseFlag(unwrapValue(arg.Interface())); !ok {
+			return exec.AsValue(fmt.Errorf("flag: enabled must be a boolean, got %v", arg.Interface()))
+		}
+	}
+
+	switch {
+	case enabled:
+		return exec.AsValue(directive)
+	case negate:
+		return exec.AsValue("no " + directive)
+	default:
+		return exec.AsValue("")
+	}
 }
 
-// compareByExpression compares two items based on an expression with optional modifiers.
-func compareByExpression(a, b interface{}, criterion string) int {
-	// Parse modifiers
-	descending := false
-	checkExists := false
-	checkLength := false
+// serverDNSHostnamePattern matches hostnames and SRV record names (e.g.
+// "_http._tcp.api.default.svc.cluster.local"), with an optional trailing dot.
+var serverDNSHostnamePattern = regexp.MustCompile(`^[A-Za-z0-9_]([A-Za-z0-9_-]*[A-Za-z0-9])?(\.[A-Za-z0-9_]([A-Za-z0-9_-]*[A-Za-z0-9])?)*\.?$`)
 
-	parts := strings.Split(criterion, ":")
-	expr := parts[0]
+// serverDNSInitAddrMethods are the init-addr methods server_dns accepts besides IP addresses.
+var serverDNSInitAddrMethods = []string{"last", "libc", "none"}
 
-	for i := 1; i < len(parts); i++ {
-		modifier := strings.TrimSpace(parts[i])
-		switch modifier {
-		case "desc":
-			descending = true
-		case "exists":
-			checkExists = true
+// serverDNSFilter emits the server options that make HAProxy resolve a
+// server's hostname at runtime through a resolvers section.
+//
+// The input is the hostname and the argument the name of the resolvers
+// section. The prefer keyword sets resolve-prefer ("ipv4" unless given) and
+// init_addr sets init-addr, the resolution methods tried at startup
+// ("last,libc,none" unless given, so HAProxy starts even if the name does not
+// resolve yet). IP addresses need no resolution and yield an empty string, so
+// the filter can be applied to every server address.
+//
+// Usage: server {{ name }} {{ host }}:{{ port }} {{ host | server_dns("kube-dns") }}
+// or {{ host | server_dns("kube-dns", prefer="ipv6", init_addr="none") }}.
+func serverDNSFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	if params == nil || len(params.Args) != 1 {
+		return exec.AsValue(fmt.Errorf("server_dns: requires the resolvers section name"))
+	}
+	resolvers := strings.TrimSpace(params.Args[0].String())
+	if resolvers == "" || strings.ContainsAny(resolvers, " \t") {
+		return exec.AsValue(fmt.Errorf("server_dns: invalid resolvers section name %q", params.Args[0].String()))
+	}
+
+	prefer := "ipv4"
+	initAddr := "last,libc,none"
+	for name, value := range params.KwArgs {
+		switch name {
+		case "prefer":
+			prefer = value.String()
+			if prefer != "ipv4" && prefer != "ipv6" {
+				return exec.AsValue(fmt.Errorf("server_dns: prefer must be ipv4 or ipv6, got %q", prefer))
+			}
+		case "init_addr":
+			initAddr = strings.ReplaceAll(value.String(), " ", "")
+			for _, method := range strings.Split(initAddr, ",") {
+				if !slices.Contains(serverDNSInitAddrMethods, method) && net.ParseIP(method) == nil {
+					return exec.AsValue(fmt.Errorf("server_dns: invalid init_addr method %q, expected last, libc, none or an IP address", method))
+				}
+			}
+		default:
+			return exec.AsValue(fmt.Errorf("server_dns: unknown argument %q", name))
 		}
 	}
 
-	// Check for length operator
-	if strings.Contains(expr, " | length") {
-		checkLength = true
-		expr = strings.Split(expr, " | ")[0]
+	host := strings.TrimSpace(in.String())
+	if net.ParseIP(strings.Trim(host, "[]")) != nil {
+		return exec.AsValue("")
+	}
+	if !serverDNSHostnamePattern.MatchString(host) {
+		return exec.AsValue(fmt.Errorf("server_dns: %q is not a hostname", host))
 	}
 
-	// Evaluate expressions
-	valA := evaluateExpression(a, expr)
-	valB := evaluateExpression(b, expr)
+	return exec.AsValue(fmt.Sprintf("resolvers %s resolve-prefer %s init-addr %s", resolvers, prefer, initAddr))
+}
 
-	// Handle special checks
-	if checkExists {
-		existsA := valA != nil
-		existsB := valB != nil
-		result := 0
-		if existsA && !existsB {
-			result = -1 // A exists, B doesn't → A comes first
-		} else if !existsA && existsB {
-			result = 1 // B exists, A doesn't → B comes first
+// healthcheckParams are the keys accepted by healthcheck.
+var healthcheckParams = []string{"detection_time", "tolerance", "recovery_time"}
+
+// minCheckInterval is the shortest check interval healthcheck computes.
+const minCheckInterval = 100 * time.Millisecond
+
+// healthcheckFilter converts a health check SLO into the server check
+// parameters inter, fall and rise.
+//
+// The SLO is a dict with detection_time, the longest a failed server may keep
+// receiving traffic; tolerance, the number of consecutive failed checks that
+// are ignored as transient (default 2); and recovery_time, how soon a
+// recovered server gets traffic again (default: detection_time). Times are
+// milliseconds or HAProxy time values.
+//
+// fall is tolerance + 1 and inter is detection_time / fall, rounded down to
+// whole seconds from 10s, to 100ms from 1s and to 10ms below that, so the
+// detection time is never exceeded. rise is the number of intervals that fit
+// into recovery_time, at least 1. Intervals below 100ms are an error.
+//
+// Usage: server {{ name }} {{ address }} check {{ {"detection_time": "10s"} | healthcheck }}.
+func healthcheckFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	slo, ok := convertToDict(in.Interface())
+	if !ok {
+		return exec.AsValue(fmt.Errorf("healthcheck: expected dict with detection_time, got %T", in.Interface()))
+	}
+	for name := range slo {
+		if !slices.Contains(healthcheckParams, name) {
+			return exec.AsValue(fmt.Errorf("healthcheck: unknown parameter %q", name))
 		}
-		// For :exists modifier, the descending flag means "items with field first"
-		// which is already the natural ordering above (exists = -1, not exists = 1).
-		// Don't negate - the semantics of :exists:desc are already captured.
-		return result
 	}
 
-	if checkLength {
-		lenA := getLength(valA)
-		lenB := getLength(valB)
-		result := lenA - lenB
-		if descending {
-			result = -result
+	detection, ok := parseHAProxyDuration(unwrapValue(slo["detection_time"]))
+	if !ok || detection <= 0 {
+		return exec.AsValue(fmt.Errorf("healthcheck: detection_time must be a positive time, got %v", unwrapValue(slo["detection_time"])))
+	}
+
+	tolerance := int64(2)
+	if value := unwrapValue(slo["tolerance"]); value != nil {
+		f, ok := toFloat64(value)
+		if !ok || f < 0 || f != math.Trunc(f) {
+			return exec.AsValue(fmt.Errorf("healthcheck: tolerance must be a non-negative integer, got %v", value))
 		}
-		return result
+		tolerance = int64(f)
 	}
 
-	// Standard comparison
-	result := compareValues(valA, valB)
-	if descending {
-		result = -result
+	recovery := detection
+	if value := unwrapValue(slo["recovery_time"]); value != nil {
+		if recovery, ok = parseHAProxyDuration(value); !ok || recovery <= 0 {
+			return exec.AsValue(fmt.Errorf("healthcheck: recovery_time must be a positive time, got %v", value))
+		}
 	}
-	return result
+
+	fall := tolerance + 1
+	inter := roundCheckInterval(detection / time.Duration(fall))
+	if inter < minCheckInterval {
+		return exec.AsValue(fmt.Errorf("healthcheck: detection_time %v is too short for tolerance %d, checks would run more often than every %v",
+			detection, tolerance, minCheckInterval))
+	}
+	rise := max(int64(recovery/inter), 1)
+
+	return exec.AsValue(fmt.Sprintf("inter %s fall %d rise %d", formatHAProxyDuration(inter), fall, rise))
 }
 
-// compareValues compares two values of potentially different types.
-func compareValues(a, b interface{}) int {
-	// Handle nil values
-	if a == nil && b == nil {
-		return 0
+// roundCheckInterval rounds d down to whole seconds from 10s, to 100ms from
+// 1s and to 10ms below that.
+func roundCheckInterval(d time.Duration) time.Duration {
+	switch {
+	case d >= 10*time.Second:
+		return d.Truncate(time.Second)
+	case d >= time.Second:
+		return d.Truncate(100 * time.Millisecond)
+	default:
+		return d.Truncate(10 * time.Millisecond)
 	}
-	if a == nil {
-		return 1 // nil is considered greater (sorts to end)
+}
+
+// haproxyTimeUnits maps HAProxy time units to their duration.
+var haproxyTimeUnits = map[string]time.Duration{
+	"us": time.Microsecond,
+	"ms": time.Millisecond,
+	"s":  time.Second,
+	"m":  time.Minute,
+	"h":  time.Hour,
+	"d":  24 * time.Hour,
+}
+
+// parseHAProxyDuration parses integers as milliseconds and strings as HAProxy
+// time values, whose default unit is also milliseconds.
+func parseHAProxyDuration(v interface{}) (time.Duration, bool) {
+	s, isString := v.(string)
+	if !isString {
+		f, ok := toFloat64(v)
+		if !ok || f < 0 || f != math.Trunc(f) {
+			return 0, false
+		}
+		return time.Duration(f) * time.Millisecond, true
 	}
-	if b == nil {
-		return -1
+
+	if !haproxyDurationPattern.MatchString(s) {
+		return 0, false
 	}
+	number := strings.TrimRight(s, "usmhd")
+	n, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	unit := haproxyTimeUnits[s[len(number):]]
+	if unit == 0 {
+		unit = time.Millisecond
+	}
+	return time.Duration(n) * unit, true
+}
 
-	// Try numeric comparison first
-	if numA, okA := toFloat64(a); okA {
-		if numB, okB := toFloat64(b); okB {
-			if numA < numB {
-				return -1
-			} else if numA > numB {
-				return 1
-			}
-			return 0
+// formatHAProxyDuration formats d in seconds if it is a whole number of
+// seconds and in milliseconds otherwise.
+func formatHAProxyDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return strconv.FormatInt(int64(d/time.Second), 10) + "s"
+	}
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
+
+// integerInRange returns a format function accepting integers from minValue
+// to maxValue.
+func integerInRange(minValue, maxValue int64) func(v interface{}) (string, bool) {
+	return func(v interface{}) (string, bool) {
+		f, ok := toFloat64(v)
+		if !ok || f != math.Trunc(f) || f < float64(minValue) || f > float64(maxValue) {
+			return "", false
 		}
+		return strconv.FormatInt(int64(f), 10), true
 	}
+}
 
-	// Fall back to string comparison
-	strA := fmt.Sprint(a)
-	strB := fmt.Sprint(b)
-	return strings.Compare(strA, strB)
+// haproxySizeUnits maps the size units accepted by haproxy_size to their
+// multiplier. Like HAProxy, k, m and g are binary multiples.
+var haproxySizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"k":   1 << 10,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1 << 20,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1 << 30,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
 }
 
-// Helper functions
+// haproxySizePattern splits a size into its number and unit, e.g. "1.5 MiB".
+var haproxySizePattern = regexp.MustCompile(`^([0-9]+(?:\.[0-9]+)?)\s*([a-zA-Z]*)$`)
 
-func convertToSlice(v interface{}) ([]interface{}, bool) {
-	if v == nil {
-		return nil, false
+// haproxySizeFilter converts a human-friendly size such as "16k", "16KiB" or
+// "1.5m" into the number of bytes, the form every HAProxy size setting accepts.
+//
+// Units are case-insensitive; k, m and g (optionally followed by b or ib) are
+// powers of 1024, matching HAProxy's own suffixes. Plain numbers are bytes.
+// Unknown units, negative values and sizes that are not a whole number of
+// bytes are errors.
+//
+// Usage: tune.bufsize {{ settings.bufsize | haproxy_size }}.
+func haproxySizeFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
 	}
 
-	// Direct slice conversion
-	if slice, ok := v.([]interface{}); ok {
-		return slice, true
+	if in.IsInteger() {
+		if in.Integer() < 0 {
+			return exec.AsValue(fmt.Errorf("haproxy_size: size must not be negative, got %d", in.Integer()))
+		}
+		return exec.AsValue(in.Integer())
 	}
 
-	// Use reflection for other slice types
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
-		result := make([]interface{}, val.Len())
-		for i := 0; i < val.Len(); i++ {
-			result[i] = val.Index(i).Interface()
+	size, err := parseHAProxySize(in.String())
+	if err != nil {
+		return exec.AsValue(fmt.Errorf("haproxy_size: %w", err))
+	}
+	return exec.AsValue(size)
+}
+
+// parseHAProxySize implements haproxy_size for a single string.
+func parseHAProxySize(s string) (int64, error) {
+	match := haproxySizePattern.FindStringSubmatch(strings.TrimSpace(s))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	multiplier, ok := haproxySizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q (use k, m or g)", s, match[2])
+	}
+
+	number, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+
+	bytes := number * float64(multiplier)
+	if bytes != math.Trunc(bytes) {
+		return 0, fmt.Errorf("invalid size %q: not a whole number of bytes", s)
+	}
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: too large", s)
+	}
+	return int64(bytes), nil
+}
+
+// toMapfileFilter serializes a dict or a list of [key, value] pairs into HAProxy
+// map file content.
+//
+// Entries are sorted by key and emitted as one "key value" line each, so the
+// output is stable across renders. HAProxy reads the key up to the first
+// whitespace and the value up to the end of the line, and it has no escape
+// syntax, so keys containing whitespace or starting with "#" are rejected while
+// values are written verbatim. Keys or values containing line breaks are
+// rejected as well.
+//
+// Usage: {{ hosts_to_backends | to_mapfile }}.
+func toMapfileFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	entries, err := mapfileEntries(in.Interface())
+	if err != nil {
+		return exec.AsValue(err)
+	}
+
+	// Stable sort keeps the input order of duplicate keys from pair lists
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i][0] < entries[j][0]
+	})
+
+	var b strings.Builder
+	for _, entry := range entries {
+		key, value := entry[0], entry[1]
+		if key == "" {
+			return exec.AsValue(fmt.Errorf("to_mapfile: empty key"))
 		}
-		return result, true
+		if strings.ContainsAny(key, "\r\n") || strings.ContainsAny(value, "\r\n") {
+			return exec.AsValue(fmt.Errorf("to_mapfile: entry %q contains a line break", key))
+		}
+		if strings.ContainsAny(key, " \t") {
+			return exec.AsValue(fmt.Errorf("to_mapfile: key %q contains whitespace", key))
+		}
+		if strings.HasPrefix(key, "#") {
+			return exec.AsValue(fmt.Errorf("to_mapfile: key %q starts with '#' and would be read as a comment", key))
+		}
+
+		b.WriteString(key)
+		if value != "" {
+			b.WriteByte(' ')
+			b.WriteString(value)
+		}
+		b.WriteByte('\n')
 	}
 
-	return nil, false
+	return exec.AsValue(b.String())
 }
 
-func convertToMap(v interface{}) (map[string]interface{}, bool) {
+// mapfileEntries converts the to_mapfile input into key/value string pairs.
+func mapfileEntries(input interface{}) ([][2]string, error) {
+	if m, ok := convertToMap(input); ok {
+		entries := make([][2]string, 0, len(m))
+		for key, value := range m {
+			entries = append(entries, [2]string{key, mapfileValue(value)})
+		}
+		return entries, nil
+	}
+
+	items, ok := convertToSlice(input)
+	if !ok {
+		return nil, fmt.Errorf("to_mapfile: expected dict or list of pairs, got %T", input)
+	}
+
+	entries := make([][2]string, 0, len(items))
+	for i, item := range items {
+		pair, ok := convertToSlice(unwrapValue(item))
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("to_mapfile: item %d is not a [key, value] pair", i)
+		}
+		entries = append(entries, [2]string{mapfileValue(pair[0]), mapfileValue(pair[1])})
+	}
+	return entries, nil
+}
+
+// unwrapValue returns the underlying Go value of list items created by template
+// literals, which Gonja stores as *exec.Value.
+func unwrapValue(v interface{}) interface{} {
+	if value, ok := v.(*exec.Value); ok {
+		return value.Interface()
+	}
+	return v
+}
+
+// mapfileValue renders a single key or value as a string; nil becomes empty.
+func mapfileValue(v interface{}) string {
+	v = unwrapValue(v)
 	if v == nil {
-		return nil, false
+		return ""
 	}
+	return fmt.Sprint(v)
+}
 
-	// Direct map conversion
-	if m, ok := v.(map[string]interface{}); ok {
-		return m, true
+// aclOrFilter joins ACL names or conditions into a condition matching any of them.
+//
+// Usage: {{ ["is_api", "is_admin"] | acl_or }} → "is_api || is_admin".
+func aclOrFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	conditions, err := aclConditions("acl_or", in.Interface())
+	if err != nil {
+		return exec.AsValue(err)
+	}
+
+	var result [][]string
+	for _, condition := range conditions {
+		result = append(result, condition...)
+	}
+
+	return exec.AsValue(formatACLCondition(result))
+}
+
+// aclAndFilter joins ACL names or conditions into a condition matching all of them.
+//
+// HAProxy conditions have no parentheses and AND binds tighter than OR, so
+// operands containing "||" are distributed: ["a || b", "c"] becomes "a c || b c".
+//
+// Usage: {{ ["is_api", "!is_admin"] | acl_and }} → "is_api !is_admin".
+func aclAndFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	conditions, err := aclConditions("acl_and", in.Interface())
+	if err != nil {
+		return exec.AsValue(err)
+	}
+
+	result := [][]string{{}}
+	for _, condition := range conditions {
+		product := make([][]string, 0, len(result)*len(condition))
+		for _, left := range result {
+			for _, right := range condition {
+				term := make([]string, 0, len(left)+len(right))
+				term = append(term, left...)
+				term = append(term, right...)
+				product = append(product, term)
+			}
+		}
+		result = product
+	}
+
+	return exec.AsValue(formatACLCondition(result))
+}
+
+// aclConditions parses the filter input into conditions in disjunctive normal
+// form: each condition is a list of OR-ed terms, each term a list of AND-ed operands.
+func aclConditions(filterName string, input interface{}) ([][][]string, error) {
+	items, ok := convertToSlice(input)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected list of ACL names, got %T", filterName, input)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%s: empty list of ACL names", filterName)
+	}
+
+	conditions := make([][][]string, 0, len(items))
+	for i, item := range items {
+		condition := parseACLCondition(mapfileValue(item))
+		if len(condition) == 0 {
+			return nil, fmt.Errorf("%s: item %d is empty", filterName, i)
+		}
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// parseACLCondition splits a condition into OR-ed terms of AND-ed operands.
+// Anonymous ACLs in braces are kept as single operands.
+func parseACLCondition(condition string) [][]string {
+	var terms [][]string
+	var term []string
+	var anonymous []string
+	depth := 0
+
+	for _, token := range strings.Fields(condition) {
+		switch {
+		case depth > 0 || token == "{":
+			if token == "{" {
+				depth++
+			} else if token == "}" {
+				depth--
+			}
+			anonymous = append(anonymous, token)
+			if depth == 0 {
+				term = append(term, strings.Join(anonymous, " "))
+				anonymous = nil
+			}
+		case token == "||" || token == "or":
+			if len(term) > 0 {
+				terms = append(terms, term)
+			}
+			term = nil
+		default:
+			term = append(term, token)
+		}
+	}
+
+	if len(anonymous) > 0 {
+		term = append(term, strings.Join(anonymous, " "))
+	}
+	if len(term) > 0 {
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+// formatACLCondition renders OR-ed terms of AND-ed operands as a HAProxy condition.
+func formatACLCondition(terms [][]string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = strings.Join(term, " ")
+	}
+	return strings.Join(parts, " || ")
+}
+
+// trimFilter is a custom trim filter that passes through errors instead of masking them.
+// This is critical for proper error reporting when templates fail inside include_matching().
+//
+// The builtin Gonja trim filter wraps errors with "Wrong signature for 'trim'", which
+// hides the actual error (e.g., from fail() function). Our version checks if the input
+// is an error and returns it unchanged, allowing the real error to propagate.
+//
+// Usage: {{ include_matching("pattern-*") | trim }}.
+//
+// Supports optional 'chars' parameter like Gonja's trim:
+// {{ "  hello  " | trim }}  → "hello".
+// {{ "xxhelloxx" | trim(chars="x") }}  → "hello".
+func trimFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	// Pass through errors unchanged (don't mask them)
+	if in.IsError() {
+		return in
+	}
+
+	// Extract 'chars' parameter (defaults to whitespace)
+	charsParam := exec.KwArg{
+		Name:    "chars",
+		Default: " \t\n\r",
+	}
+	p := params.ExpectKwArgs([]*exec.KwArg{&charsParam})
+	if p.IsError() {
+		return exec.AsValue(errors.Wrap(p, "Wrong signature for 'trim'"))
+	}
+
+	// Convert input to string
+	str := in.String()
+
+	// Get chars to trim
+	chars := p.GetKeywordArgument(charsParam.Name, charsParam.Default).String()
+
+	// Perform trim operation
+	trimmed := strings.Trim(str, chars)
+
+	return exec.AsValue(trimmed)
+}
+
+// conflictsByTest detects conflicts when grouped by key.
+// Usage: routes | conflicts_by("$.path", "$.method ~ $.headers").
+func conflictsByTest(ctx *exec.Context, in *exec.Value, params *exec.VarArgs) (bool, error) {
+	// Extract items array
+	items := in.Interface()
+	itemsSlice, ok := convertToSlice(items)
+	if !ok {
+		return false, fmt.Errorf("conflicts_by: expected array/slice, got %T", items)
+	}
+
+	// Extract group expression and compare expression
+	args := params.Args
+	if len(args) < 2 {
+		return false, fmt.Errorf("conflicts_by: requires 2 arguments, got %d", len(args))
+	}
+
+	// Get group expression (first arg)
+	groupExpr := args[0].String()
+
+	// Get compare expression (second arg)
+	compareExpr := args[1].String()
+
+	// Group items
+	groups := make(map[string][]interface{})
+	for _, item := range itemsSlice {
+		key := evaluateExpression(item, groupExpr)
+		keyStr := fmt.Sprint(key)
+		groups[keyStr] = append(groups[keyStr], item)
+	}
+
+	// Check for conflicts within each group
+	for _, group := range groups {
+		if len(group) <= 1 {
+			continue
+		}
+
+		// Check if items in group have different comparison values
+		firstValue := evaluateExpression(group[0], compareExpr)
+		firstStr := fmt.Sprint(firstValue)
+
+		for _, item := range group[1:] {
+			value := evaluateExpression(item, compareExpr)
+			valueStr := fmt.Sprint(value)
+			if firstStr != valueStr {
+				return true, nil // Conflict found
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Helper types and functions for the generic functions
+
+type sortableItems struct {
+	items    []interface{}
+	criteria []string
+	tracing  *tracingConfig // For filter debug logging
+}
+
+func (s *sortableItems) Len() int {
+	return len(s.items)
+}
+
+func (s *sortableItems) Less(i, j int) bool {
+	for _, criterion := range s.criteria {
+		cmp := compareByExpressionWithDebug(s.items[i], s.items[j], criterion, s.tracing)
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
+func (s *sortableItems) Swap(i, j int) {
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+}
+
+// evaluateExpression evaluates a JSONPath-like expression against an item.
+// Supports:
+// - Basic paths: "$.field.subfield"
+// - Array access: "$.items[0]" or "$.items[*]"
+// - Length operator: "$.items | length"
+// - Concatenation: "$.field1 ~ '-' ~ $.field2"
+// - Existence check: "$.field:exists".
+func evaluateExpression(item interface{}, expr string) interface{} {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil
+	}
+
+	// Don't unwrap *exec.Value here - let navigateJSONPath handle it
+	// using Gonja's GetItem/GetAttribute methods for proper field access
+
+	// Handle concatenation operator ~
+	if strings.Contains(expr, " ~ ") {
+		return handleConcatenation(item, expr)
+	}
+
+	// Handle pipe operator |
+	if strings.Contains(expr, " | ") {
+		return handlePipeOperator(item, expr)
+	}
+
+	// Handle existence check
+	if strings.HasSuffix(expr, ":exists") {
+		return handleExistenceCheck(item, expr)
+	}
+
+	// Handle JSONPath-like navigation
+	if strings.HasPrefix(expr, "$.") || expr == "$" {
+		return navigateJSONPath(item, expr)
+	}
+
+	// Treat as field name without $. prefix
+	return navigateJSONPath(item, "$."+expr)
+}
+
+// handleConcatenation processes the ~ concatenation operator.
+func handleConcatenation(item interface{}, expr string) interface{} {
+	parts := strings.Split(expr, " ~ ")
+	var result strings.Builder
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+
+		// Check if it's a literal string
+		if (strings.HasPrefix(part, "'") && strings.HasSuffix(part, "'")) ||
+			(strings.HasPrefix(part, "\"") && strings.HasSuffix(part, "\"")) {
+			// Remove quotes and append literal
+			result.WriteString(strings.Trim(part, "'\""))
+		} else {
+			// Evaluate as expression
+			value := evaluateExpression(item, part)
+			if value != nil {
+				result.WriteString(fmt.Sprint(value))
+			}
+		}
+	}
+
+	return result.String()
+}
+
+// handlePipeOperator processes the | pipe operator for filters.
+func handlePipeOperator(item interface{}, expr string) interface{} {
+	parts := strings.Split(expr, " | ")
+	value := evaluateExpression(item, strings.TrimSpace(parts[0]))
+
+	for i := 1; i < len(parts); i++ {
+		value = applyPipeOperation(value, strings.TrimSpace(parts[i]))
+	}
+
+	return value
+}
+
+// applyPipeOperation applies a single pipe operation to a value.
+func applyPipeOperation(value interface{}, operation string) interface{} {
+	// Handle length operator
+	if operation == "length" {
+		return calculateLength(value)
+	}
+
+	// Handle default operator
+	if strings.HasPrefix(operation, "default(") && strings.HasSuffix(operation, ")") {
+		if value == nil || value == "" {
+			defaultValue := strings.TrimSuffix(strings.TrimPrefix(operation, "default("), ")")
+			return strings.Trim(defaultValue, "'\"")
+		}
+	}
+
+	return value
+}
+
+// calculateLength returns the length of a value.
+func calculateLength(value interface{}) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	default:
+		if slice, ok := convertToSlice(value); ok {
+			return len(slice)
+		}
+		if m, ok := convertToMap(value); ok {
+			return len(m)
+		}
+		return 0
+	}
+}
+
+// handleExistenceCheck checks if a value exists (is not nil).
+func handleExistenceCheck(item interface{}, expr string) interface{} {
+	expr = strings.TrimSuffix(expr, ":exists")
+	value := evaluateExpression(item, expr)
+	return value != nil
+}
+
+// navigateJSONPath navigates through an object using JSONPath-like syntax.
+func navigateJSONPath(item interface{}, path string) interface{} {
+	// Remove leading $. if present
+	if strings.HasPrefix(path, "$.") {
+		path = strings.TrimPrefix(path, "$.")
+	} else if path == "$" {
+		return item
+	}
+
+	if path == "" {
+		return item
+	}
+
+	// Split path into segments, handling array indices
+	segments := parsePathSegments(path)
+	current := item
+
+	for i, segment := range segments {
+		if current == nil {
+			return nil
+		}
+
+		// Handle array index access
+		if strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+			result, done := processArrayIndex(current, segment, segments, i)
+			if done {
+				return result
+			}
+			current = result
+		} else {
+			// Regular field access
+			current = processFieldAccess(current, segment)
+		}
+	}
+
+	// Unwrap final result if it's a Gonja Value
+	if execVal, ok := current.(*exec.Value); ok {
+		return execVal.Interface()
+	}
+
+	return current
+}
+
+// processArrayIndex handles array index access including wildcards and numeric indices.
+func processArrayIndex(current interface{}, segment string, segments []string, segmentIndex int) (interface{}, bool) {
+	indexStr := strings.TrimPrefix(strings.TrimSuffix(segment, "]"), "[")
+
+	// Handle wildcard [*]
+	if indexStr == "*" {
+		return processWildcardIndex(current, segments, segmentIndex)
+	}
+
+	// Handle numeric index
+	return processNumericIndex(current, indexStr)
+}
+
+// processWildcardIndex handles wildcard array access [*].
+func processWildcardIndex(current interface{}, segments []string, segmentIndex int) (interface{}, bool) {
+	slice, ok := convertToSlice(current)
+	if !ok {
+		return nil, true
+	}
+
+	// If there are more segments, collect results from all elements
+	if segmentIndex < len(segments)-1 {
+		remainingPath := strings.Join(segments[segmentIndex+1:], ".")
+		var results []interface{}
+		for _, elem := range slice {
+			result := navigateJSONPath(elem, "$."+remainingPath)
+			if result != nil {
+				if resultSlice, ok := convertToSlice(result); ok {
+					results = append(results, resultSlice...)
+				} else {
+					results = append(results, result)
+				}
+			}
+		}
+		return results, true
+	}
+
+	// Just return all elements
+	return slice, true
+}
+
+// processNumericIndex handles numeric array index access.
+func processNumericIndex(current interface{}, indexStr string) (interface{}, bool) {
+	index, err := strconv.Atoi(indexStr)
+	if err != nil {
+		return current, false // Not a numeric index, continue
+	}
+
+	slice, ok := convertToSlice(current)
+	if !ok {
+		return nil, true
+	}
+
+	if index >= 0 && index < len(slice) {
+		return slice[index], false
+	}
+
+	return nil, true
+}
+
+// processFieldAccess handles regular field access.
+func processFieldAccess(current interface{}, segment string) interface{} {
+	// If current is a Gonja Value, use its GetItem method for proper access
+	if execVal, ok := current.(*exec.Value); ok {
+		if val, found := execVal.GetItem(segment); found {
+			return val
+		}
+		// Also try GetAttribute for struct fields
+		if val, found := execVal.GetAttribute(segment); found {
+			return val
+		}
+		return nil
+	}
+
+	if m, ok := convertToMap(current); ok {
+		return m[segment]
+	}
+
+	// Try reflection for struct fields
+	return getFieldByReflection(current, segment)
+}
+
+// parsePathSegments parses a JSONPath into navigable segments.
+func parsePathSegments(path string) []string {
+	var segments []string
+	var current strings.Builder
+	inBracket := false
+
+	for i, char := range path {
+		switch {
+		case char == '[':
+			segments, inBracket = processLeftBracket(segments, &current)
+		case char == ']':
+			segments, inBracket = processRightBracket(segments, &current, inBracket)
+		case char == '.' && !inBracket:
+			segments = processDot(segments, &current)
+		default:
+			current.WriteRune(char)
+		}
+
+		// Handle last segment
+		if i == len(path)-1 && current.Len() > 0 {
+			segments = append(segments, current.String())
+		}
+	}
+
+	return segments
+}
+
+// processLeftBracket handles '[' character when parsing path segments.
+func processLeftBracket(segments []string, current *strings.Builder) ([]string, bool) {
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+		current.Reset()
+	}
+	current.WriteByte('[')
+	return segments, true
+}
+
+// processRightBracket handles ']' character when parsing path segments.
+func processRightBracket(segments []string, current *strings.Builder, inBracket bool) ([]string, bool) {
+	current.WriteByte(']')
+	if inBracket {
+		segments = append(segments, current.String())
+		current.Reset()
+		return segments, false
+	}
+	return segments, inBracket
+}
+
+// processDot handles '.' character when parsing path segments.
+func processDot(segments []string, current *strings.Builder) []string {
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+		current.Reset()
+	}
+	return segments
+}
+
+// compareByExpressionWithDebug wraps compareByExpression and adds debug logging when enabled.
+func compareByExpressionWithDebug(a, b interface{}, criterion string, tracing *tracingConfig) int {
+	// Check if debug is enabled
+	var debugEnabled bool
+	if tracing != nil {
+		tracing.mu.Lock()
+		debugEnabled = tracing.debugFilters
+		tracing.mu.Unlock()
+	}
+
+	// If debug not enabled, just call compareByExpression
+	if !debugEnabled {
+		return compareByExpression(a, b, criterion)
+	}
+
+	// Debug enabled - clean expression and evaluate for logging
+	// Parse modifiers to get clean expression (same logic as compareByExpression)
+	checkExists := false
+	checkLength := false
+
+	parts := strings.Split(criterion, ":")
+	expr := parts[0]
+
+	for i := 1; i < len(parts); i++ {
+		modifier := strings.TrimSpace(parts[i])
+		if modifier == "exists" {
+			checkExists = true
+		}
+	}
+
+	// Check for length operator
+	if strings.Contains(expr, " | length") {
+		checkLength = true
+		expr = strings.Split(expr, " | ")[0]
+	}
+
+	// Evaluate cleaned expression
+	valA := evaluateExpression(a, expr)
+	valB := evaluateExpression(b, expr)
+
+	// Apply modifiers for debug display
+	if checkExists {
+		valA = (valA != nil)
+		valB = (valB != nil)
+	} else if checkLength {
+		valA = getLength(valA)
+		valB = getLength(valB)
+	}
+
+	// Call comparison
+	result := compareByExpression(a, b, criterion)
+
+	// Log the comparison
+	slog.Info("SORT comparison",
+		"criterion", criterion,
+		"valA", valA,
+		"valA_type", fmt.Sprintf("%T", valA),
+		"valB", valB,
+		"valB_type", fmt.Sprintf("%T", valB),
+		"result", result)
+
+	return result
+}
+
+// compareByExpression compares two items based on an expression with optional modifiers.
+func compareByExpression(a, b interface{}, criterion string) int {
+	// Parse modifiers
+	descending := false
+	checkExists := false
+	checkLength := false
+
+	parts := strings.Split(criterion, ":")
+	expr := parts[0]
+
+	for i := 1; i < len(parts); i++ {
+		modifier := strings.TrimSpace(parts[i])
+		switch modifier {
+		case "desc":
+			descending = true
+		case "exists":
+			checkExists = true
+		}
+	}
+
+	// Check for length operator
+	if strings.Contains(expr, " | length") {
+		checkLength = true
+		expr = strings.Split(expr, " | ")[0]
+	}
+
+	// Evaluate expressions
+	valA := evaluateExpression(a, expr)
+	valB := evaluateExpression(b, expr)
+
+	// Handle special checks
+	if checkExists {
+		existsA := valA != nil
+		existsB := valB != nil
+		result := 0
+		if existsA && !existsB {
+			result = -1 // A exists, B doesn't → A comes first
+		} else if !existsA && existsB {
+			result = 1 // B exists, A doesn't → B comes first
+		}
+		// For :exists modifier, the descending flag means "items with field first"
+		// which is already the natural ordering above (exists = -1, not exists = 1).
+		// Don't negate - the semantics of :exists:desc are already captured.
+		return result
+	}
+
+	if checkLength {
+		lenA := getLength(valA)
+		lenB := getLength(valB)
+		result := lenA - lenB
+		if descending {
+			result = -result
+		}
+		return result
+	}
+
+	// Standard comparison
+	result := compareValues(valA, valB)
+	if descending {
+		result = -result
+	}
+	return result
+}
+
+// compareValues compares two values of potentially different types.
+func compareValues(a, b interface{}) int {
+	// Handle nil values
+	if a == nil && b == nil {
+		return 0
+	}
+	if a == nil {
+		return 1 // nil is considered greater (sorts to end)
+	}
+	if b == nil {
+		return -1
+	}
+
+	// Try numeric comparison first
+	if numA, okA := toFloat64(a); okA {
+		if numB, okB := toFloat64(b); okB {
+			if numA < numB {
+				return -1
+			} else if numA > numB {
+				return 1
+			}
+			return 0
+		}
+	}
+
+	// Fall back to string comparison
+	strA := fmt.Sprint(a)
+	strB := fmt.Sprint(b)
+	return strings.Compare(strA, strB)
+}
+
+// Helper functions
+
+func convertToSlice(v interface{}) ([]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	// Direct slice conversion
+	if slice, ok := v.([]interface{}); ok {
+		return slice, true
+	}
+
+	// Use reflection for other slice types
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array {
+		result := make([]interface{}, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			result[i] = val.Index(i).Interface()
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+func convertToMap(v interface{}) (map[string]interface{}, bool) {
+	if v == nil {
+		return nil, false
+	}
+
+	// Direct map conversion
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	// Try reflection for other map types
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Map {
+		result := make(map[string]interface{})
+		for _, key := range val.MapKeys() {
+			keyStr := fmt.Sprint(key.Interface())
+			result[keyStr] = val.MapIndex(key).Interface()
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+func getFieldByReflection(v interface{}, field string) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	val := reflect.ValueOf(v)
+
+	// Dereference pointers
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// Try to find field (case-insensitive)
+	typ := val.Type()
+	for i := 0; i < val.NumField(); i++ {
+		fieldType := typ.Field(i)
+		if strings.EqualFold(fieldType.Name, field) {
+			fieldVal := val.Field(i)
+			if fieldVal.CanInterface() {
+				return fieldVal.Interface()
+			}
+		}
+	}
+
+	return nil
+}
+
+func getLength(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+
+	if s, ok := v.(string); ok {
+		return len(s)
+	}
+
+	if slice, ok := convertToSlice(v); ok {
+		return len(slice)
+	}
+
+	if m, ok := convertToMap(v); ok {
+		return len(m)
+	}
+
+	// Use reflection as fallback
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array || val.Kind() == reflect.Map {
+		return val.Len()
+	}
+
+	return 0
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case float32:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int8:
+		return float64(val), true
+	case int16:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint:
+		return float64(val), true
+	case uint8:
+		return float64(val), true
+	case uint16:
+		return float64(val), true
+	case uint32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case string:
+		if f, err := strconv.ParseFloat(val, 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+func deepCopyValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	// Use JSON marshal/unmarshal for deep copy
+	// This is simple and handles most cases correctly
+	data, err := json.Marshal(v)
+	if err != nil {
+		// Fallback to shallow copy
+		return v
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		// Fallback to shallow copy
+		return v
+	}
+
+	return result
+}
+
+// isolateContext returns a copy of a rendering context with all nested maps and
+// lists copied. Values of any other type are shared with the original context.
+func isolateContext(context map[string]interface{}) map[string]interface{} {
+	isolated := make(map[string]interface{}, len(context))
+	for key, value := range context {
+		isolated[key] = isolateContextValue(value)
+	}
+	return isolated
+}
+
+// isolateContextValue recursively copies maps and lists of the generic types
+// used in rendering contexts.
+func isolateContextValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return isolateContext(val)
+	case []interface{}:
+		copied := make([]interface{}, len(val))
+		for i, item := range val {
+			copied[i] = isolateContextValue(item)
+		}
+		return copied
+	case map[string]string:
+		copied := make(map[string]string, len(val))
+		for key, item := range val {
+			copied[key] = item
+		}
+		return copied
+	case []string:
+		copied := make([]string, len(val))
+		copy(copied, val)
+		return copied
+	default:
+		return v
+	}
+}
+
+// cloneFilterSet creates a deep copy of a FilterSet to avoid modifying global state.
+// This is necessary because gonja's FilterSet.Update() modifies the filter map in-place,
+// which causes race conditions when multiple engines are created concurrently.
+func cloneFilterSet(original *exec.FilterSet) *exec.FilterSet {
+	// Create empty FilterSet and populate it by updating with original.
+	// This creates a new instance with copied filters.
+	filterMap := make(map[string]exec.FilterFunction)
+	cloned := exec.NewFilterSet(filterMap)
+	cloned.Update(original)
+	return cloned
+}
+
+// wrapCustomFilter wraps a FilterFunc into Gonja's FilterFunction signature.
+// This adapter converts between our simple FilterFunc interface and Gonja's
+// more complex signature that includes the evaluator and typed values.
+//
+// IMPORTANT: This wrapper passes through errors unchanged to preserve proper
+// error messages when templates fail inside include_matching() or other macros.
+func wrapCustomFilter(customFilter FilterFunc) exec.FilterFunction {
+	return func(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+		// Pass through errors unchanged (don't call the filter on errors)
+		// This is critical for proper error propagation from fail() and missing secrets
+		if in.IsError() {
+			return in
+		}
+
+		// Extract the input value as Go interface{}
+		inputValue := in.Interface()
+
+		// Extract arguments from VarArgs as interface{} slice
+		var args []interface{}
+		if params != nil && len(params.Args) > 0 {
+			// Get all positional arguments from Args slice
+			for _, arg := range params.Args {
+				args = append(args, arg.Interface())
+			}
+		}
+
+		// Call the custom filter
+		result, err := customFilter(inputValue, args...)
+		if err != nil {
+			// Return error wrapped in Value
+			return exec.AsValue(err)
+		}
+
+		// Return result as Value
+		return exec.AsValue(result)
+	}
+}
+
+// wrapGlobalFunction wraps a GlobalFunc into a function callable from Gonja templates.
+// This adapter converts between our simple GlobalFunc interface and the signature
+// expected by Gonja's global function system.
+func wrapGlobalFunction(customFunc GlobalFunc) func(_ *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	return func(_ *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+		// Extract arguments from VarArgs as interface{} slice
+		var args []interface{}
+		if params != nil && len(params.Args) > 0 {
+			for _, arg := range params.Args {
+				args = append(args, arg.Interface())
+			}
+		}
+
+		// Call the custom global function
+		result, err := customFunc(args...)
+		if err != nil {
+			// Return error wrapped in Value using ErrInvalidCall
+			// This will cause template rendering to fail with the error message
+			return exec.AsValue(exec.ErrInvalidCall(err))
+		}
+
+		// Return result as Value
+		return exec.AsValue(result)
+	}
+}
+
+// EnvContextKey is the rendering context key holding the environment variables
+// available to the env() global function. Callers inject a map[string]string
+// under this key; the process environment is never read directly.
+const EnvContextKey = "env_vars"
+
+// envFunction implements the env(name, default) global function.
+//
+// It looks up name in the environment variables injected into the rendering
+// context under EnvContextKey and returns default (or "" if omitted) when the
+// variable is not set.
+//
+// Example:
+//
+//	server-state-file /var/lib/haproxy/{{ env("POD_NAME", "haproxy") }}.state
+func envFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params == nil || len(params.Args) < 1 || len(params.Args) > 2 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("env() requires a variable name and an optional default value")))
+	}
+
+	name, ok := params.Args[0].Interface().(string)
+	if !ok {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("env() variable name must be a string, got %T", params.Args[0].Interface())))
+	}
+
+	var defaultValue interface{} = ""
+	if len(params.Args) == 2 {
+		defaultValue = params.Args[1].Interface()
+	}
+
+	if e == nil || e.Environment == nil || e.Environment.Context == nil {
+		return exec.AsValue(defaultValue)
+	}
+
+	vars, ok := e.Environment.Context.Get(EnvContextKey)
+	if !ok {
+		return exec.AsValue(defaultValue)
+	}
+
+	switch v := vars.(type) {
+	case map[string]string:
+		if value, exists := v[name]; exists {
+			return exec.AsValue(value)
+		}
+	case map[string]interface{}:
+		if value, exists := v[name]; exists {
+			return exec.AsValue(value)
+		}
+	}
+
+	return exec.AsValue(defaultValue)
+}
+
+// serverCookieLength is the number of characters in tokens generated by server_cookie.
+// 8 base32 characters carry 40 bits, making collisions within a backend negligible.
+const serverCookieLength = 8
+
+// serverCookieEncoding renders cookie tokens using lowercase letters and digits only.
+var serverCookieEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// serverCookieToken derives a stable cookie value from a server name.
+func serverCookieToken(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return serverCookieEncoding.EncodeToString(sum[:])[:serverCookieLength]
+}
+
+// serverCookieFunction implements the server_cookie(name) global function.
+//
+// It returns a short token derived from the server name that stays the same
+// across renders and controller restarts, for use as the server's cookie value.
+//
+// Example:
+//
+//	server {{ name }} {{ address }}:{{ port }} cookie {{ server_cookie(name) }}
+func serverCookieFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params == nil || len(params.Args) != 1 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("server_cookie() requires exactly one argument (server name)")))
+	}
+
+	return serverCookieValue(params.Args[0])
+}
+
+// serverCookieFilter is the filter form of server_cookie: {{ name | server_cookie }}.
+func serverCookieFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	return serverCookieValue(in)
+}
+
+// serverCookieValue validates the server name and returns its cookie token.
+func serverCookieValue(in *exec.Value) *exec.Value {
+	name, ok := in.Interface().(string)
+	if !ok || name == "" {
+		return exec.AsValue(fmt.Errorf("server_cookie: server name must be a non-empty string, got %v", in.Interface()))
+	}
+
+	return exec.AsValue(serverCookieToken(name))
+}
+
+// stableServerNamePrefix and stableServerNameLength define the names generated
+// by stable_server_name. They must match comparator.StableServerName in
+// pkg/dataplane, which derives the same names when SyncOptions.StableServerNames
+// is set.
+const (
+	stableServerNamePrefix = "srv_"
+	stableServerNameLength = 12
+)
+
+// stableServerNameFilter implements the stable_server_name filter.
+//
+// It derives a server name from the endpoint address and an optional port, or
+// from any other key identifying the server. Unlike names built from a loop
+// index, the name of an endpoint does not change when other endpoints are
+// added, removed or reordered.
+//
+// Example:
+//
+//	{%- for ep in endpoints %}
+//	server {{ ep.address | stable_server_name(ep.port) }} {{ ep.address }}:{{ ep.port }}
+//	{%- endfor %}
+func stableServerNameFilter(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
+	if in.IsError() {
+		return in
+	}
+
+	if len(params.Args) > 1 {
+		return exec.AsValue(fmt.Errorf("stable_server_name: takes at most one argument (port), got %d", len(params.Args)))
+	}
+
+	key := in.String()
+	if in.IsNil() || key == "" {
+		return exec.AsValue(fmt.Errorf("stable_server_name: address must not be empty"))
+	}
+
+	if len(params.Args) == 1 {
+		port, ok := toFloat64(params.Args[0].Interface())
+		if !ok || port != math.Trunc(port) || port < 0 {
+			return exec.AsValue(fmt.Errorf("stable_server_name: port must be a whole number, got %v", params.Args[0].Interface()))
+		}
+		key = fmt.Sprintf("%s:%d", key, int64(port))
+	}
+
+	sum := sha256.Sum256([]byte(key))
+	return exec.AsValue(stableServerNamePrefix + hex.EncodeToString(sum[:])[:stableServerNameLength])
+}
+
+// stableIDLength is the number of hex characters in IDs generated by stable_id.
+// 16 characters carry 64 bits, so IDs of distinct seeds do not collide in practice.
+const stableIDLength = 16
+
+// stableIDFunction implements the stable_id(seed) global function.
+//
+// It returns an ID derived from the seed instead of a loop counter, so an item
+// keeps its ID when other items are added, removed or reordered, and every
+// render of the same item yields the same ID.
+//
+// Example:
+//
+//	{% for route in routes %}
+//	acl route_{{ stable_id(route.namespace ~ "/" ~ route.name) }} path_beg {{ route.path }}
+//	{% endfor %}
+func stableIDFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params == nil || len(params.Args) != 1 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("stable_id() requires exactly one argument (seed)")))
+	}
+
+	seed := params.Args[0]
+	if seed.IsError() {
+		return seed
+	}
+	if seed.IsNil() || seed.String() == "" {
+		return exec.AsValue(fmt.Errorf("stable_id: seed must not be empty"))
+	}
+
+	return exec.AsValue(stableID(seed.String()))
+}
+
+// stableID derives a lowercase hex ID from a seed.
+func stableID(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])[:stableIDLength]
+}
+
+// HAProxyVersionContextKey is the rendering context key holding the version of
+// the HAProxy instances the configuration is rendered for, as reported by
+// "haproxy -v" (e.g. "3.2.9"). It backs the haproxy_version() global function.
+const HAProxyVersionContextKey = "haproxy_target_version"
+
+// HAProxyVersion is an HAProxy version that can be ordered.
+//
+// Its string form is the major.minor release branch, which is what decides the
+// availability of configuration directives. Gonja compares such values with
+// strings lexicographically, which orders "2.10" before "2.9", so templates
+// compare versions numerically with AtLeast:
+//
+//	{% if haproxy_version().AtLeast('2.8') %}
+type HAProxyVersion struct {
+	Major int
+	Minor int
+	Patch int
+	Full  string // Original version string, e.g. "3.2.9-dev1"
+}
+
+// ParseHAProxyVersion parses versions like "3.2", "3.2.9" or "3.2.9-dev1".
+func ParseHAProxyVersion(version string) (HAProxyVersion, error) {
+	full := strings.TrimSpace(version)
+
+	numeric := full
+	if idx := strings.IndexAny(numeric, "-+ "); idx >= 0 {
+		numeric = numeric[:idx]
+	}
+
+	parts := strings.Split(numeric, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return HAProxyVersion{}, fmt.Errorf("invalid HAProxy version %q: expected major.minor[.patch]", version)
+	}
+
+	components := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return HAProxyVersion{}, fmt.Errorf("invalid HAProxy version %q: %q is not a number", version, part)
+		}
+		components[i] = n
+	}
+
+	return HAProxyVersion{Major: components[0], Minor: components[1], Patch: components[2], Full: full}, nil
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or greater than other.
+// Unlike template comparisons, the patch level is taken into account.
+func (v HAProxyVersion) Compare(other HAProxyVersion) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether v is the given version or a later one, comparing
+// major, minor and patch numerically. It is meant to be called from
+// templates, so it accepts the version as a string like "3.1" or "3.1.2".
+func (v HAProxyVersion) AtLeast(version interface{}) (bool, error) {
+	s, ok := version.(string)
+	if !ok {
+		return false, fmt.Errorf("AtLeast() requires a version string, got %T", version)
+	}
+	other, err := ParseHAProxyVersion(s)
+	if err != nil {
+		return false, err
+	}
+	return v.Compare(other) >= 0, nil
+}
+
+// String returns the major.minor release branch, e.g. "3.2".
+func (v HAProxyVersion) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// haproxyVersionFunction implements the haproxy_version() global function.
+//
+// It returns the HAProxyVersion injected into the rendering context under
+// HAProxyVersionContextKey, and fails the render if no version is known so
+// that templates never silently pick directives for the wrong version.
+//
+// Example:
+//
+//	{%- if haproxy_version().AtLeast('3.1') %}
+//	    # directives introduced in HAProxy 3.1
+//	{%- endif %}
+func haproxyVersionFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params != nil && len(params.Args) > 0 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("haproxy_version() takes no arguments")))
+	}
+
+	var raw interface{}
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		raw, _ = e.Environment.Context.Get(HAProxyVersionContextKey)
+	}
+
+	switch v := raw.(type) {
+	case HAProxyVersion:
+		return exec.AsValue(v)
+	case string:
+		if v != "" {
+			version, err := ParseHAProxyVersion(v)
+			if err != nil {
+				return exec.AsValue(exec.ErrInvalidCall(err))
+			}
+			return exec.AsValue(version)
+		}
+	}
+
+	return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("haproxy_version() is not available: the target HAProxy version is unknown")))
+}
+
+// LeaderContextKey is the rendering context key holding whether the controller
+// instance rendering the configuration is the elected leader. It backs the
+// is_leader() global function.
+const LeaderContextKey = "controller_is_leader"
+
+// isLeaderFunction implements the is_leader() global function.
+//
+// It returns the bool injected into the rendering context under
+// LeaderContextKey. Without a value, e.g. in single-instance deployments
+// without leader election, the instance is its own leader and true is returned.
+//
+// Example:
+//
+//	{%- if is_leader() %}
+//	    # only rendered by the leader
+//	{%- endif %}
+func isLeaderFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params != nil && len(params.Args) > 0 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("is_leader() takes no arguments")))
 	}
 
-	// Try reflection for other map types
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Map {
-		result := make(map[string]interface{})
-		for _, key := range val.MapKeys() {
-			keyStr := fmt.Sprint(key.Interface())
-			result[keyStr] = val.MapIndex(key).Interface()
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		if raw, ok := e.Environment.Context.Get(LeaderContextKey); ok {
+			if leader, isBool := raw.(bool); isBool {
+				return exec.AsValue(leader)
+			}
 		}
-		return result, true
 	}
 
-	return nil, false
+	return exec.AsValue(true)
 }
 
-func getFieldByReflection(v interface{}, field string) interface{} {
-	if v == nil {
-		return nil
+// DefaultsContextKey is the rendering context key holding the settings of the
+// defaults section, usually provided through extraContext. Its "timeouts" dict
+// maps timeout names (e.g. "server") to values and backs effective_timeout().
+const DefaultsContextKey = "defaults"
+
+// effectiveTimeoutFunction implements the effective_timeout(section, name) global function.
+//
+// It returns the timeout the section sets in its "timeouts" dict, or the
+// timeout inherited from the defaults in the rendering context under
+// DefaultsContextKey if the section does not set it, mirroring how HAProxy
+// sections inherit timeouts from their defaults section. It returns none if
+// neither sets the timeout.
+//
+// Example:
+//
+//	{%- for backend in backends %}
+//	# {{ backend.name }} waits {{ effective_timeout(backend, "server") }} for responses
+//	{%- endfor %}
+func effectiveTimeoutFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params == nil || len(params.Args) != 2 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("effective_timeout() requires exactly two arguments (section, timeout name)")))
 	}
 
-	val := reflect.ValueOf(v)
+	name, ok := params.Args[1].Interface().(string)
+	if !ok || name == "" {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("effective_timeout() timeout name must be a non-empty string, got %v", params.Args[1].Interface())))
+	}
 
-	// Dereference pointers
-	for val.Kind() == reflect.Ptr {
-		if val.IsNil() {
-			return nil
+	// A missing section inherits every timeout
+	if section := params.Args[0]; !section.IsNil() {
+		settings, ok := convertToDict(section.Interface())
+		if !ok {
+			return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("effective_timeout() section must be a dict, got %T", section.Interface())))
+		}
+		if value, ok := lookupTimeout(settings, name); ok {
+			return exec.AsValue(value)
 		}
-		val = val.Elem()
 	}
 
-	if val.Kind() != reflect.Struct {
-		return nil
+	var raw interface{}
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		raw, _ = e.Environment.Context.Get(DefaultsContextKey)
 	}
-
-	// Try to find field (case-insensitive)
-	typ := val.Type()
-	for i := 0; i < val.NumField(); i++ {
-		fieldType := typ.Field(i)
-		if strings.EqualFold(fieldType.Name, field) {
-			fieldVal := val.Field(i)
-			if fieldVal.CanInterface() {
-				return fieldVal.Interface()
-			}
+	if defaults, ok := convertToDict(raw); ok {
+		if value, ok := lookupTimeout(defaults, name); ok {
+			return exec.AsValue(value)
 		}
 	}
 
-	return nil
+	return exec.AsValue(nil)
 }
 
-func getLength(v interface{}) int {
-	if v == nil {
-		return 0
+// lookupTimeout returns the timeout name from the "timeouts" dict of a section's settings.
+func lookupTimeout(settings map[string]interface{}, name string) (interface{}, bool) {
+	timeouts, ok := convertToDict(unwrapValue(settings["timeouts"]))
+	if !ok {
+		return nil, false
 	}
+	value := unwrapValue(timeouts[name])
+	return value, value != nil
+}
 
-	if s, ok := v.(string); ok {
-		return len(s)
-	}
+// FrontendsContextKey is the rendering context key holding the frontends,
+// usually provided through extraContext. It is a list of frontend dicts, or a
+// dict of them keyed by frontend name, and backs referenced_backends().
+const FrontendsContextKey = "frontends"
 
-	if slice, ok := convertToSlice(v); ok {
-		return len(slice)
+// referencedBackendsFunction implements the referenced_backends() global function.
+//
+// It returns the sorted, unique names of the backends the frontends in the
+// rendering context under FrontendsContextKey route to: their
+// "default_backend", their "use_backend" rules (names, or dicts with a
+// "backend" key), and their "backend_switching_rules" (dicts with a "name"
+// key, as in the Dataplane API). Dynamic names such as "be_%[req.hdr(host)]"
+// are returned as written. Without frontends in the context the list is empty.
+//
+// Example:
+//
+//	{%- set used = referenced_backends() %}
+//	{%- for backend in backends if backend.name in used %}
+//	backend {{ backend.name }}
+//	{%- endfor %}
+func referencedBackendsFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params != nil && len(params.Args) > 0 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("referenced_backends() takes no arguments")))
 	}
 
-	if m, ok := convertToMap(v); ok {
-		return len(m)
+	var raw interface{}
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		raw, _ = e.Environment.Context.Get(FrontendsContextKey)
 	}
 
-	// Use reflection as fallback
-	val := reflect.ValueOf(v)
-	if val.Kind() == reflect.Slice || val.Kind() == reflect.Array || val.Kind() == reflect.Map {
-		return val.Len()
+	var frontends []interface{}
+	if byName, ok := convertToDict(raw); ok {
+		for _, frontend := range byName {
+			frontends = append(frontends, frontend)
+		}
+	} else if list, ok := convertToSlice(raw); ok {
+		frontends = list
 	}
 
-	return 0
-}
+	seen := make(map[string]bool)
+	for _, item := range frontends {
+		frontend, ok := convertToDict(unwrapValue(item))
+		if !ok {
+			return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("referenced_backends() frontends must be dicts, got %T", unwrapValue(item))))
+		}
 
-func toFloat64(v interface{}) (float64, bool) {
-	switch val := v.(type) {
-	case float64:
-		return val, true
-	case float32:
-		return float64(val), true
-	case int:
-		return float64(val), true
-	case int8:
-		return float64(val), true
-	case int16:
-		return float64(val), true
-	case int32:
-		return float64(val), true
-	case int64:
-		return float64(val), true
-	case uint:
-		return float64(val), true
-	case uint8:
-		return float64(val), true
-	case uint16:
-		return float64(val), true
-	case uint32:
-		return float64(val), true
-	case uint64:
-		return float64(val), true
-	case string:
-		if f, err := strconv.ParseFloat(val, 64); err == nil {
-			return f, true
+		if name, ok := unwrapValue(frontend["default_backend"]).(string); ok && name != "" {
+			seen[name] = true
+		}
+		for _, name := range ruleBackends(frontend["use_backend"], "backend") {
+			seen[name] = true
+		}
+		for _, name := range ruleBackends(frontend["backend_switching_rules"], "name") {
+			seen[name] = true
 		}
 	}
-	return 0, false
-}
 
-func deepCopyValue(v interface{}) interface{} {
-	if v == nil {
-		return nil
+	backends := make([]string, 0, len(seen))
+	for name := range seen {
+		backends = append(backends, name)
 	}
+	sort.Strings(backends)
 
-	// Use JSON marshal/unmarshal for deep copy
-	// This is simple and handles most cases correctly
-	data, err := json.Marshal(v)
-	if err != nil {
-		// Fallback to shallow copy
-		return v
-	}
+	return exec.AsValue(backends)
+}
 
-	var result interface{}
-	if err := json.Unmarshal(data, &result); err != nil {
-		// Fallback to shallow copy
-		return v
+// ruleBackends returns the backend names of a list of switching rules, each
+// either a name or a dict holding the name under key.
+func ruleBackends(rules interface{}, key string) []string {
+	items, ok := convertToSlice(unwrapValue(rules))
+	if !ok {
+		return nil
 	}
 
-	return result
+	var names []string
+	for _, item := range items {
+		item = unwrapValue(item)
+		if rule, ok := convertToDict(item); ok {
+			item = unwrapValue(rule[key])
+		}
+		if name, ok := item.(string); ok && name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
 }
 
-// cloneFilterSet creates a deep copy of a FilterSet to avoid modifying global state.
-// This is necessary because gonja's FilterSet.Update() modifies the filter map in-place,
-// which causes race conditions when multiple engines are created concurrently.
-func cloneFilterSet(original *exec.FilterSet) *exec.FilterSet {
-	// Create empty FilterSet and populate it by updating with original.
-	// This creates a new instance with copied filters.
-	filterMap := make(map[string]exec.FilterFunction)
-	cloned := exec.NewFilterSet(filterMap)
-	cloned.Update(original)
-	return cloned
+// ResourcesContextKey is the rendering context key holding the watched
+// resources, keyed by resource kind (e.g. "ingresses", "services"). Each value
+// provides a List() method. It backs the list_resources() global function.
+const ResourcesContextKey = "resources"
+
+// resourceLister is implemented by the resource stores in the rendering context.
+type resourceLister interface {
+	List() []interface{}
 }
 
-// wrapCustomFilter wraps a FilterFunc into Gonja's FilterFunction signature.
-// This adapter converts between our simple FilterFunc interface and Gonja's
-// more complex signature that includes the evaluator and typed values.
+// listResourcesFunction implements the list_resources(kind) global function.
 //
-// IMPORTANT: This wrapper passes through errors unchanged to preserve proper
-// error messages when templates fail inside include_matching() or other macros.
-func wrapCustomFilter(customFilter FilterFunc) exec.FilterFunction {
-	return func(e *exec.Evaluator, in *exec.Value, params *exec.VarArgs) *exec.Value {
-		// Pass through errors unchanged (don't call the filter on errors)
-		// This is critical for proper error propagation from fail() and missing secrets
-		if in.IsError() {
-			return in
-		}
+// It returns the resources of the given kind sorted by namespace and name, so
+// the rendered output does not change with the store's iteration order. The
+// render fails for kinds that are not watched.
+//
+// Example:
+//
+//	{%- for service in list_resources("services") %}
+//	# {{ service.metadata.namespace }}/{{ service.metadata.name }}
+//	{%- endfor %}
+func listResourcesFunction(e *exec.Evaluator, params *exec.VarArgs) *exec.Value {
+	if params == nil || len(params.Args) != 1 {
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("list_resources() requires exactly one argument (resource kind)")))
+	}
 
-		// Extract the input value as Go interface{}
-		inputValue := in.Interface()
+	kind := params.Args[0].String()
 
-		// Extract arguments from VarArgs as interface{} slice
-		var args []interface{}
-		if params != nil && len(params.Args) > 0 {
-			// Get all positional arguments from Args slice
-			for _, arg := range params.Args {
-				args = append(args, arg.Interface())
+	var raw interface{}
+	if e != nil && e.Environment != nil && e.Environment.Context != nil {
+		raw, _ = e.Environment.Context.Get(ResourcesContextKey)
+	}
+	resources, _ := raw.(map[string]interface{})
+
+	lister, ok := resources[kind].(resourceLister)
+	if !ok {
+		available := make([]string, 0, len(resources))
+		for name, value := range resources {
+			if _, isLister := value.(resourceLister); isLister {
+				available = append(available, name)
 			}
 		}
+		sort.Strings(available)
+		return exec.AsValue(exec.ErrInvalidCall(fmt.Errorf("list_resources(): unknown resource kind %q (available: %s)", kind, strings.Join(available, ", "))))
+	}
 
-		// Call the custom filter
-		result, err := customFilter(inputValue, args...)
-		if err != nil {
-			// Return error wrapped in Value
-			return exec.AsValue(err)
+	// Copy before sorting, stores may return a cached slice
+	items := append([]interface{}(nil), lister.List()...)
+	sort.SliceStable(items, func(i, j int) bool {
+		nsI, nameI := resourceKey(items[i])
+		nsJ, nameJ := resourceKey(items[j])
+		if nsI != nsJ {
+			return nsI < nsJ
 		}
+		return nameI < nameJ
+	})
 
-		// Return result as Value
-		return exec.AsValue(result)
-	}
+	return exec.AsValue(items)
 }
 
-// wrapGlobalFunction wraps a GlobalFunc into a function callable from Gonja templates.
-// This adapter converts between our simple GlobalFunc interface and the signature
-// expected by Gonja's global function system.
-func wrapGlobalFunction(customFunc GlobalFunc) func(_ *exec.Evaluator, params *exec.VarArgs) *exec.Value {
-	return func(_ *exec.Evaluator, params *exec.VarArgs) *exec.Value {
-		// Extract arguments from VarArgs as interface{} slice
-		var args []interface{}
-		if params != nil && len(params.Args) > 0 {
-			for _, arg := range params.Args {
-				args = append(args, arg.Interface())
-			}
-		}
+// resourceKey returns the metadata namespace and name of an unwrapped resource.
+func resourceKey(resource interface{}) (namespace, name string) {
+	obj, ok := resource.(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	namespace, _ = metadata["namespace"].(string)
+	name, _ = metadata["name"].(string)
+	return namespace, name
+}
 
-		// Call the custom global function
-		result, err := customFunc(args...)
-		if err != nil {
-			// Return error wrapped in Value using ErrInvalidCall
-			// This will cause template rendering to fail with the error message
-			return exec.AsValue(exec.ErrInvalidCall(err))
-		}
+// WithMetrics enables rendering metrics and registers them with registry.
+//
+// Every render then records its duration, errors and output size, and every
+// compute_once block a cache hit or miss, labeled with the name of the
+// rendered template. Templates that do not exist are not recorded, which
+// bounds the label cardinality to the templates of the engine.
+// Returns the engine to allow chaining after New.
+func (e *TemplateEngine) WithMetrics(registry prometheus.Registerer) *TemplateEngine {
+	e.metrics = metrics.New(registry)
+	return e
+}
 
-		// Return result as Value
-		return exec.AsValue(result)
-	}
+// WithStrictUndefined makes rendering fail when a template references an
+// undefined variable, attribute or item, instead of rendering it as empty.
+//
+// The error names the expression and the line it is on. The default filter
+// and the defined test still work on undefined values. Must be called before
+// rendering. Returns the engine to allow chaining after New.
+func (e *TemplateEngine) WithStrictUndefined() *TemplateEngine {
+	e.config.StrictUndefined = true
+	return e
 }
 
 // EnableTracing enables template execution tracing.
@@ -2015,9 +3993,14 @@ func (cs *ComputeOnceControlStructure) Execute(r *exec.Renderer, tag *nodes.Cont
 	// Marker is named "_computed_<varname>" and stored in the context
 	markerName := "_computed_" + cs.varName
 
+	rm := renderMetricsFrom(r.Environment.Context)
+
 	// Check if computation already happened
 	if r.Environment.Context.Has(markerName) {
 		// Marker exists - computation already done, skip body
+		if rm != nil {
+			rm.metrics.RecordCacheHit(rm.template)
+		}
 		return nil
 	}
 
@@ -2028,6 +4011,9 @@ func (cs *ComputeOnceControlStructure) Execute(r *exec.Renderer, tag *nodes.Cont
 	}
 
 	// Variable exists and computation hasn't been done - execute body
+	if rm != nil {
+		rm.metrics.RecordCacheMiss(rm.template)
+	}
 	err := r.ExecuteWrapper(cs.wrapper)
 	if err != nil {
 		return err
@@ -2068,3 +4054,91 @@ func computeOnceParser(p, args *parser.Parser) (nodes.ControlStructure, error) {
 
 	return cs, nil
 }
+
+// ============================================================================
+// Custom Gonja Tag: assert
+// ============================================================================
+
+// AssertControlStructure implements a custom Gonja tag that fails rendering
+// when a condition is falsy.
+//
+// Syntax:
+//
+//	{% assert condition %}
+//	{% assert condition, "message" %}
+//
+// The message is only evaluated when the assertion fails, so a passing
+// assertion costs no more than evaluating its condition. Without a message,
+// the error reports the template position of the tag.
+type AssertControlStructure struct {
+	location  *tokens.Token
+	condition nodes.Expression // Condition that must be truthy
+	message   nodes.Expression // Error message on failure (optional)
+}
+
+// Position returns the token position for error reporting.
+func (cs *AssertControlStructure) Position() *tokens.Token {
+	return cs.location
+}
+
+// String returns a string representation for debugging.
+func (cs *AssertControlStructure) String() string {
+	t := cs.Position()
+	return fmt.Sprintf("AssertControlStructure(Line=%d Col=%d)", t.Line, t.Col)
+}
+
+// Execute evaluates the condition and returns an error if it is falsy.
+func (cs *AssertControlStructure) Execute(r *exec.Renderer, tag *nodes.ControlStructureBlock) error {
+	result := r.Eval(cs.condition)
+	if result.IsError() {
+		return result
+	}
+	if result.IsTrue() {
+		return nil
+	}
+
+	if cs.message == nil {
+		t := cs.Position()
+		return fmt.Errorf("assertion failed (line %d, column %d)", t.Line, t.Col)
+	}
+
+	message := r.Eval(cs.message)
+	if message.IsError() {
+		return message
+	}
+	return fmt.Errorf("assertion failed: %s", message.String())
+}
+
+// assertParser parses the assert tag syntax.
+//
+// Expected syntax: {% assert condition[, message] %}.
+func assertParser(p, args *parser.Parser) (nodes.ControlStructure, error) {
+	cs := &AssertControlStructure{
+		location: p.Current(),
+	}
+
+	if args.Stream().End() {
+		return nil, args.Error("assert requires a condition", nil)
+	}
+
+	condition, err := args.ParseExpression()
+	if err != nil {
+		return nil, err
+	}
+	cs.condition = condition
+
+	if args.Match(tokens.Comma) != nil {
+		message, err := args.ParseExpression()
+		if err != nil {
+			return nil, err
+		}
+		cs.message = message
+	}
+
+	// Ensure no extra arguments
+	if !args.Stream().End() {
+		return nil, args.Error("assert takes a condition and an optional message, no additional arguments", nil)
+	}
+
+	return cs, nil
+}
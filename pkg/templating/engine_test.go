@@ -1,11 +1,15 @@
 package templating
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -60,6 +64,34 @@ func TestNew_CompilationError(t *testing.T) {
 	assert.Equal(t, "invalid", compilationErr.TemplateName)
 }
 
+func TestNew_TemplateCompileErrorLocation(t *testing.T) {
+	templates := map[string]string{
+		"haproxy.cfg": "global\n    daemon\n    maxconn {{ limits | }}\n",
+	}
+
+	_, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.Error(t, err)
+
+	var compileErr *TemplateCompileError
+	require.ErrorAs(t, err, &compileErr)
+	assert.Equal(t, "haproxy.cfg", compileErr.TemplateName)
+	assert.Equal(t, 3, compileErr.Line)
+	assert.Positive(t, compileErr.Column)
+	assert.Equal(t, "    maxconn {{ limits | }}", compileErr.Snippet)
+
+	var compilationErr *CompilationError
+	assert.ErrorAs(t, err, &compilationErr)
+}
+
+func TestNewTemplateCompileError_UnknownLocation(t *testing.T) {
+	err := NewTemplateCompileError("test", "{{ x }", errors.New(`'}}' expected here (Line: 0 Col: 0, near "}")`))
+
+	assert.Zero(t, err.Line)
+	assert.Zero(t, err.Column)
+	assert.Empty(t, err.Snippet)
+	assert.Equal(t, "{{ x }", err.TemplateSnippet)
+}
+
 func TestRender_Success(t *testing.T) {
 	templates := map[string]string{
 		"greeting": "Hello {{ name }}!",
@@ -127,6 +159,54 @@ func TestRender_RenderError(t *testing.T) {
 	assert.Equal(t, "with_error", renderErr.TemplateName)
 }
 
+func TestRender_StrictUndefined(t *testing.T) {
+	templates := map[string]string{
+		"variable":  "backend default\n    server {{ server_name }} 10.0.0.1:80",
+		"attribute": "maxconn {{ settings.maxconn }}",
+		"defaulted": "maxconn {{ settings.maxconn | default(2000) }}",
+		"tested":    "{% if server_name is defined %}{{ server_name }}{% else %}none{% endif %}",
+	}
+	context := map[string]interface{}{
+		"settings": map[string]interface{}{"timeout": "5s"},
+	}
+
+	// Undefined values render as empty by default
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	output, err := engine.Render("variable", context)
+	require.NoError(t, err)
+	assert.Equal(t, "backend default\n    server  10.0.0.1:80", output)
+
+	// Strict mode reports the undefined variable and where it is referenced
+	engine, err = New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+	engine = engine.WithStrictUndefined()
+
+	output, err = engine.Render("variable", context)
+	assert.Empty(t, output)
+	require.Error(t, err)
+
+	var renderErr *RenderError
+	require.ErrorAs(t, err, &renderErr)
+	assert.Equal(t, "variable", renderErr.TemplateName)
+	assert.Contains(t, err.Error(), `"server_name"`)
+	assert.Contains(t, err.Error(), "line 2")
+
+	_, err = engine.Render("attribute", context)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "settings.maxconn")
+
+	// default and defined still handle undefined values
+	output, err = engine.Render("defaulted", context)
+	require.NoError(t, err)
+	assert.Equal(t, "maxconn 2000", output)
+
+	output, err = engine.Render("tested", context)
+	require.NoError(t, err)
+	assert.Equal(t, "none", output)
+}
+
 func TestTemplateNames(t *testing.T) {
 	templates := map[string]string{
 		"template1": "Content 1",
@@ -862,6 +942,55 @@ func TestComputeOnce_Integration_MultipleRenders(t *testing.T) {
 	// This proves compute_once markers are cleared between renders
 }
 
+// ============================================================================
+// assert tag tests
+// ============================================================================
+
+func TestAssert(t *testing.T) {
+	templates := map[string]string{
+		"passing":    `{% assert backends | length > 0, "no backends" %}ok`,
+		"failing":    `{% assert backends | length > 1, "need 2 backends, got " ~ (backends | length) %}ok`,
+		"no_message": `{% assert backends | length > 1 %}ok`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	ctx := map[string]interface{}{"backends": []interface{}{"web"}}
+
+	t.Run("passing assertion renders nothing", func(t *testing.T) {
+		output, err := engine.Render("passing", ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", output)
+	})
+
+	t.Run("failing assertion returns message", func(t *testing.T) {
+		_, err := engine.Render("failing", ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "assertion failed: need 2 backends, got 1")
+	})
+
+	t.Run("failing assertion without message", func(t *testing.T) {
+		_, err := engine.Render("no_message", ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "assertion failed (line 1, column")
+	})
+}
+
+func TestAssert_SyntaxErrors(t *testing.T) {
+	tests := map[string]string{
+		"missing condition": `{% assert %}`,
+		"extra arguments":   `{% assert true, "message" extra %}`,
+	}
+
+	for name, tmpl := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(EngineTypeGonja, map[string]string{"main": tmpl}, nil, nil, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
 func TestTracing_ConcurrentRenders(t *testing.T) {
 	// This test verifies that tracing is thread-safe when multiple goroutines
 	// call Render() concurrently. Run with: go test -race
@@ -1089,3 +1218,1409 @@ func TestAppendTraces(t *testing.T) {
 	trace2 := engine2.GetTraceOutput()
 	assert.Empty(t, trace2)
 }
+
+func TestEnvFunction(t *testing.T) {
+	templates := map[string]string{
+		"with_default":    `{{ env("NODE_NAME", "unknown-node") }}`,
+		"without_default": `[{{ env("NODE_NAME") }}]`,
+		"invalid_call":    `{{ env() }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		template string
+		context  map[string]interface{}
+		want     string
+	}{
+		{
+			name:     "returns injected value",
+			template: "with_default",
+			context:  map[string]interface{}{EnvContextKey: map[string]string{"NODE_NAME": "worker-1"}},
+			want:     "worker-1",
+		},
+		{
+			name:     "falls back to default when variable is unset",
+			template: "with_default",
+			context:  map[string]interface{}{EnvContextKey: map[string]string{"POD_NAME": "controller-0"}},
+			want:     "unknown-node",
+		},
+		{
+			name:     "falls back to default without injected environment",
+			template: "with_default",
+			context:  nil,
+			want:     "unknown-node",
+		},
+		{
+			name:     "empty default when omitted",
+			template: "without_default",
+			context:  map[string]interface{}{},
+			want:     "[]",
+		},
+		{
+			name:     "keeps empty value when variable is set",
+			template: "with_default",
+			context:  map[string]interface{}{EnvContextKey: map[string]string{"NODE_NAME": ""}},
+			want:     "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render(tt.template, tt.context)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	t.Run("requires a variable name", func(t *testing.T) {
+		_, err := engine.Render("invalid_call", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "env() requires a variable name")
+	})
+}
+
+// staticLister serves a fixed list of resources like the renderer's store wrappers.
+type staticLister []interface{}
+
+func (l staticLister) List() []interface{} { return l }
+
+func testResource(namespace, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"metadata": map[string]interface{}{"namespace": namespace, "name": name},
+	}
+}
+
+func TestListResourcesFunction(t *testing.T) {
+	templates := map[string]string{
+		"services": `{% for svc in list_resources("services") %}{{ svc.metadata.namespace }}/{{ svc.metadata.name }};{% endfor %}`,
+		"unknown":  `{{ list_resources("gateways") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	services := staticLister{
+		testResource("prod", "web"),
+		testResource("default", "zeta"),
+		testResource("prod", "api"),
+		testResource("default", "alpha"),
+	}
+	ctx := map[string]interface{}{
+		ResourcesContextKey: map[string]interface{}{
+			"services":  services,
+			"ingresses": staticLister{},
+		},
+	}
+
+	t.Run("sorted by namespace and name", func(t *testing.T) {
+		output, err := engine.Render("services", ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "default/alpha;default/zeta;prod/api;prod/web;", output)
+
+		// The store's own order is left untouched
+		assert.Equal(t, "web", services[0].(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		_, err := engine.Render("unknown", ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown resource kind "gateways" (available: ingresses, services)`)
+	})
+}
+
+func TestReferencedBackendsFunction(t *testing.T) {
+	templates := map[string]string{
+		"list":    `{{ referenced_backends() | join(",") }}`,
+		"filter":  `{% set used = referenced_backends() %}{% for b in backends if b.name in used %}{{ b.name }};{% endfor %}`,
+		"invalid": `{{ referenced_backends("http") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	frontends := []interface{}{
+		map[string]interface{}{
+			"name":            "http",
+			"default_backend": "web",
+			"use_backend": []interface{}{
+				"static",
+				map[string]interface{}{"backend": "api", "cond": "if is_api"},
+			},
+		},
+		map[string]interface{}{
+			"name":            "https",
+			"default_backend": "web",
+			"backend_switching_rules": []interface{}{
+				map[string]interface{}{"name": "admin", "cond": "if is_admin"},
+				map[string]interface{}{"name": "api"},
+			},
+		},
+	}
+
+	t.Run("collects defaults and switching rules", func(t *testing.T) {
+		output, err := engine.Render("list", map[string]interface{}{FrontendsContextKey: frontends})
+		require.NoError(t, err)
+		assert.Equal(t, "admin,api,static,web", output)
+	})
+
+	t.Run("frontends keyed by name", func(t *testing.T) {
+		output, err := engine.Render("list", map[string]interface{}{
+			FrontendsContextKey: map[string]interface{}{
+				"http":  frontends[0],
+				"https": frontends[1],
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "admin,api,static,web", output)
+	})
+
+	t.Run("prunes unreferenced backends", func(t *testing.T) {
+		output, err := engine.Render("filter", map[string]interface{}{
+			FrontendsContextKey: frontends,
+			"backends": []interface{}{
+				map[string]interface{}{"name": "api"},
+				map[string]interface{}{"name": "legacy"},
+				map[string]interface{}{"name": "web"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "api;web;", output)
+	})
+
+	t.Run("empty without frontends", func(t *testing.T) {
+		output, err := engine.Render("list", nil)
+		require.NoError(t, err)
+		assert.Empty(t, output)
+	})
+
+	t.Run("takes no arguments", func(t *testing.T) {
+		_, err := engine.Render("invalid", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "referenced_backends() takes no arguments")
+	})
+}
+
+func TestEffectiveTimeoutFunction(t *testing.T) {
+	templates := map[string]string{
+		"backends": `{% for b in backends %}{{ b.name }}={{ effective_timeout(b, "server") }};{% endfor %}`,
+		"literal":  `{{ effective_timeout({"timeouts": {"connect": "1s"}}, "connect") }}`,
+		"unset":    `{% if effective_timeout(backends[0], "queue") is none %}unset{% endif %}`,
+		"invalid":  `{{ effective_timeout("api", "server") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	ctx := map[string]interface{}{
+		DefaultsContextKey: map[string]interface{}{
+			"timeouts": map[string]interface{}{"connect": "5s", "server": "30s"},
+		},
+		"backends": []interface{}{
+			map[string]interface{}{"name": "web"},
+			map[string]interface{}{"name": "api", "timeouts": map[string]interface{}{"server": "2m"}},
+		},
+	}
+
+	t.Run("inherits from defaults unless set", func(t *testing.T) {
+		output, err := engine.Render("backends", ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "web=30s;api=2m;", output)
+	})
+
+	t.Run("dict literal section", func(t *testing.T) {
+		output, err := engine.Render("literal", ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "1s", output)
+	})
+
+	t.Run("none if neither sets it", func(t *testing.T) {
+		output, err := engine.Render("unset", ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "unset", output)
+	})
+
+	t.Run("no defaults in context", func(t *testing.T) {
+		output, err := engine.Render("backends", map[string]interface{}{"backends": ctx["backends"]})
+		require.NoError(t, err)
+		assert.Equal(t, "web=;api=2m;", output)
+	})
+
+	t.Run("section must be a dict", func(t *testing.T) {
+		_, err := engine.Render("invalid", ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "effective_timeout() section must be a dict")
+	})
+}
+
+func TestHAProxyVersionFunction(t *testing.T) {
+	templates := map[string]string{
+		"compare": `{% if haproxy_version().AtLeast('2.8') %}new{% else %}old{% endif %}` +
+			`{% if not haproxy_version().AtLeast('3.0') %} pre-3{% endif %}`,
+		"print":   `{{ haproxy_version() }} ({{ haproxy_version().Full }})`,
+		"invalid": `{{ haproxy_version("3.2") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "2.6.17", want: "old pre-3"},
+		{version: "2.8.0", want: "new pre-3"},
+		{version: "2.9", want: "new pre-3"},
+		{version: "3.0.5", want: "new"},
+		{version: "3.2.9-dev1", want: "new"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			output, err := engine.Render("compare", map[string]interface{}{HAProxyVersionContextKey: tt.version})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	t.Run("compares two-digit minor versions numerically", func(t *testing.T) {
+		numeric, err := New(EngineTypeGonja, map[string]string{
+			"at_least": `{{ haproxy_version().AtLeast('2.9') }} {{ haproxy_version().AtLeast('2.10') }} {{ haproxy_version().AtLeast('2.10.1') }}`,
+			"invalid":  `{{ haproxy_version().AtLeast('latest') }}`,
+		}, nil, nil, nil)
+		require.NoError(t, err)
+
+		output, err := numeric.Render("at_least", map[string]interface{}{HAProxyVersionContextKey: "2.10.0"})
+		require.NoError(t, err)
+		assert.Equal(t, "True True False", output)
+
+		output, err = numeric.Render("at_least", map[string]interface{}{HAProxyVersionContextKey: "2.9.5"})
+		require.NoError(t, err)
+		assert.Equal(t, "True False False", output)
+
+		_, err = numeric.Render("invalid", map[string]interface{}{HAProxyVersionContextKey: "2.10.0"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid HAProxy version")
+	})
+
+	t.Run("renders the release branch", func(t *testing.T) {
+		output, err := engine.Render("print", map[string]interface{}{HAProxyVersionContextKey: "3.2.9"})
+		require.NoError(t, err)
+		assert.Equal(t, "3.2 (3.2.9)", output)
+	})
+
+	t.Run("fails without a known version", func(t *testing.T) {
+		_, err := engine.Render("compare", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "target HAProxy version is unknown")
+	})
+
+	t.Run("takes no arguments", func(t *testing.T) {
+		_, err := engine.Render("invalid", map[string]interface{}{HAProxyVersionContextKey: "3.2.9"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "haproxy_version() takes no arguments")
+	})
+}
+
+func TestIsLeaderFunction(t *testing.T) {
+	templates := map[string]string{
+		"role":    `{% if is_leader() %}leader{% else %}follower{% endif %}`,
+		"invalid": `{{ is_leader(true) }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		context map[string]interface{}
+		want    string
+	}{
+		{name: "leader", context: map[string]interface{}{LeaderContextKey: true}, want: "leader"},
+		{name: "follower", context: map[string]interface{}{LeaderContextKey: false}, want: "follower"},
+		{name: "defaults to leader", context: nil, want: "leader"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render("role", tt.context)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	t.Run("takes no arguments", func(t *testing.T) {
+		_, err := engine.Render("invalid", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "is_leader() takes no arguments")
+	})
+}
+
+func TestHAProxyVersion_Compare(t *testing.T) {
+	parse := func(s string) HAProxyVersion {
+		v, err := ParseHAProxyVersion(s)
+		require.NoError(t, err)
+		return v
+	}
+
+	assert.Equal(t, -1, parse("2.8").Compare(parse("3.0")))
+	assert.Equal(t, -1, parse("3.2.9").Compare(parse("3.2.10")))
+	assert.Equal(t, 0, parse("3.2").Compare(parse("3.2.0")))
+	assert.Equal(t, 1, parse("3.10.1").Compare(parse("3.9.12")))
+
+	for _, invalid := range []string{"", "3", "3.x", "3.2.1.4"} {
+		_, err := ParseHAProxyVersion(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestServerCookieFunction(t *testing.T) {
+	templates := map[string]string{
+		"function":     `{{ server_cookie(name) }}`,
+		"filter":       `{{ name | server_cookie }}`,
+		"backend":      `{% for s in servers %}{{ s }}={{ server_cookie(s) }};{% endfor %}`,
+		"invalid_call": `{{ server_cookie() }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("stable across renders and forms", func(t *testing.T) {
+		ctx := map[string]interface{}{"name": "SRV_1"}
+
+		first, err := engine.Render("function", ctx)
+		require.NoError(t, err)
+		second, err := engine.Render("function", ctx)
+		require.NoError(t, err)
+		viaFilter, err := engine.Render("filter", ctx)
+		require.NoError(t, err)
+
+		assert.Len(t, first, serverCookieLength)
+		assert.Regexp(t, `^[a-z2-7]+$`, first)
+		assert.Equal(t, first, second)
+		assert.Equal(t, first, viaFilter)
+	})
+
+	t.Run("unique across servers", func(t *testing.T) {
+		servers := make([]string, 0, 1000)
+		for i := 1; i <= 1000; i++ {
+			servers = append(servers, fmt.Sprintf("SRV_%d", i))
+		}
+
+		seen := make(map[string]string, len(servers))
+		for _, name := range servers {
+			token := serverCookieToken(name)
+			if other, exists := seen[token]; exists {
+				t.Fatalf("cookie %q generated for both %s and %s", token, other, name)
+			}
+			seen[token] = name
+		}
+
+		output, err := engine.Render("backend", map[string]interface{}{"servers": servers[:3]})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("SRV_1=%s;SRV_2=%s;SRV_3=%s;",
+			serverCookieToken("SRV_1"), serverCookieToken("SRV_2"), serverCookieToken("SRV_3")), output)
+	})
+
+	t.Run("requires a server name", func(t *testing.T) {
+		_, err := engine.Render("invalid_call", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server_cookie() requires exactly one argument")
+
+		_, err = engine.Render("filter", map[string]interface{}{"name": ""})
+		require.Error(t, err)
+	})
+}
+
+func TestStableIDFunction(t *testing.T) {
+	templates := map[string]string{
+		"loop":         `{% for r in routes %}{{ r }}={{ stable_id(r) }};{% endfor %}`,
+		"single":       `{{ stable_id(seed) }}`,
+		"invalid_call": `{{ stable_id() }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("deterministic across renders", func(t *testing.T) {
+		ctx := map[string]interface{}{"routes": []string{"default/api", "default/web"}}
+
+		first, err := engine.Render("loop", ctx)
+		require.NoError(t, err)
+		second, err := engine.Render("loop", ctx)
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.Equal(t, fmt.Sprintf("default/api=%s;default/web=%s;",
+			stableID("default/api"), stableID("default/web")), first)
+	})
+
+	t.Run("independent of position", func(t *testing.T) {
+		output, err := engine.Render("loop", map[string]interface{}{"routes": []string{"default/web"}})
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("default/web=%s;", stableID("default/web")), output)
+
+		id, err := engine.Render("single", map[string]interface{}{"seed": "default/web"})
+		require.NoError(t, err)
+		assert.Len(t, id, stableIDLength)
+		assert.Regexp(t, `^[0-9a-f]+$`, id)
+		assert.NotEqual(t, stableID("default/api"), id)
+	})
+
+	t.Run("numeric seed", func(t *testing.T) {
+		output, err := engine.Render("single", map[string]interface{}{"seed": 42})
+		require.NoError(t, err)
+		assert.Equal(t, stableID("42"), output)
+	})
+
+	t.Run("requires a seed", func(t *testing.T) {
+		_, err := engine.Render("invalid_call", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stable_id() requires exactly one argument")
+
+		_, err = engine.Render("single", map[string]interface{}{"seed": ""})
+		require.Error(t, err)
+	})
+}
+
+func TestGonjaFilter_WrapComment(t *testing.T) {
+	templates := map[string]string{
+		"wrap":         `{{ text | wrap_comment(80) }}`,
+		"narrow":       `{{ text | wrap_comment(20) }}`,
+		"invalid_arg":  `{{ text | wrap_comment("wide") }}`,
+		"invalid_size": `{{ text | wrap_comment(2) }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("wraps long description at width 80", func(t *testing.T) {
+		text := "This backend serves the public storefront API. Requests are balanced across all " +
+			"ready endpoints and sticky sessions are enabled for checkout flows."
+
+		output, err := engine.Render("wrap", map[string]interface{}{"text": text})
+		require.NoError(t, err)
+
+		assert.Equal(t, "# This backend serves the public storefront API. Requests are balanced across\n"+
+			"# all ready endpoints and sticky sessions are enabled for checkout flows.", output)
+		for _, line := range strings.Split(output, "\n") {
+			assert.LessOrEqual(t, len(line), 80)
+		}
+	})
+
+	t.Run("keeps paragraphs and long words", func(t *testing.T) {
+		text := "short line\n\nhttps://example.com/a/very/long/path follows"
+
+		output, err := engine.Render("narrow", map[string]interface{}{"text": text})
+		require.NoError(t, err)
+
+		assert.Equal(t, "# short line\n#\n# https://example.com/a/very/long/path\n# follows", output)
+	})
+
+	t.Run("rejects invalid width", func(t *testing.T) {
+		_, err := engine.Render("invalid_arg", map[string]interface{}{"text": "x"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wrap_comment: width must be an integer")
+
+		_, err = engine.Render("invalid_size", map[string]interface{}{"text": "x"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wrap_comment: width must be greater than 2")
+	})
+}
+
+func TestGonjaFilter_SortUnique(t *testing.T) {
+	templates := map[string]string{
+		"join": `{{ items | sort_unique | join(",") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		items   []interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "deduplicates CIDRs from several sources",
+			items: []interface{}{"192.168.0.0/16", "10.0.0.0/8", "172.16.0.0/12", "10.0.0.0/8", "192.168.0.0/16"},
+			want:  "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16",
+		},
+		{
+			name:  "sorts numbers numerically",
+			items: []interface{}{443, 80, 8443, 80, 1.5},
+			want:  "1.5,80,443,8443",
+		},
+		{
+			name:  "empty list",
+			items: []interface{}{},
+			want:  "",
+		},
+		{
+			name:    "mixed numbers and strings",
+			items:   []interface{}{"80", 443},
+			wantErr: "sort_unique: cannot sort a list mixing strings and numbers",
+		},
+		{
+			name:    "non-scalar item",
+			items:   []interface{}{"a", []string{"b"}},
+			wantErr: "sort_unique: item 1 must be a string or number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render("join", map[string]interface{}{"items": tt.items})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+}
+
+func TestGonjaFilter_Distribute(t *testing.T) {
+	templates := map[string]string{
+		"list": `{{ total | distribute(weights) | join(",") }}`,
+		"map":  `{% set maxconn = total | distribute(weights) %}http={{ maxconn.http }} https={{ maxconn.https }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		total   interface{}
+		weights interface{}
+		want    string
+		wantErr string
+	}{
+		{name: "proportional", total: 10000, weights: []interface{}{3, 1}, want: "7500,2500"},
+		{name: "rounding goes to largest remainders", total: 10, weights: []interface{}{1, 1, 1}, want: "4,3,3"},
+		{name: "zero weight gets nothing", total: 7, weights: []interface{}{0, 2, 5}, want: "0,2,5"},
+		{name: "fractional weights", total: 100, weights: []interface{}{0.5, 0.25, 0.25}, want: "50,25,25"},
+		{name: "zero total", total: 0, weights: []interface{}{0, 0}, want: "0,0"},
+		{name: "all weights zero", total: 5, weights: []interface{}{0, 0}, wantErr: "distribute: cannot distribute 5 with all weights zero"},
+		{name: "negative weight", total: 5, weights: []interface{}{1, -1}, wantErr: "distribute: weight 1 must be a non-negative number"},
+		{name: "fractional total", total: 2.5, weights: []interface{}{1}, wantErr: "distribute: total must be a non-negative integer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render("list", map[string]interface{}{"total": tt.total, "weights": tt.weights})
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	t.Run("map weights", func(t *testing.T) {
+		output, err := engine.Render("map", map[string]interface{}{
+			"total":   10000,
+			"weights": map[string]interface{}{"http": 1, "https": 3},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "http=2500 https=7500", output)
+	})
+}
+
+func TestDistribute_SumsToTotal(t *testing.T) {
+	weightSets := [][]interface{}{
+		{1, 1, 1},
+		{1, 2, 3, 4, 5, 6, 7},
+		{0.1, 0.2, 0.7},
+		{1.0 / 3, 1.0 / 3, 1.0 / 3},
+		{0, 13, 0, 29, 0.001},
+		{1e9, 1, 1},
+	}
+
+	for _, weights := range weightSets {
+		for total := 0; total <= 1000; total += 37 {
+			parts, err := distribute(total, weights)
+			require.NoError(t, err)
+
+			sum := 0
+			for i, part := range parts {
+				assert.GreaterOrEqual(t, part, 0)
+				if w, _ := toFloat64(weights[i]); w == 0 {
+					assert.Zero(t, part, "zero weight %d of %v got units", i, weights)
+				}
+				sum += part
+			}
+			assert.Equal(t, total, sum, "weights %v", weights)
+		}
+	}
+}
+
+func TestGonjaFilter_DNSSafe(t *testing.T) {
+	templates := map[string]string{
+		"default":     `{{ name | dns_safe }}`,
+		"short":       `{{ name | dns_safe(10) }}`,
+		"invalid_arg": `{{ name | dns_safe("long") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	render := func(t *testing.T, template, name string) string {
+		t.Helper()
+		output, err := engine.Render(template, map[string]interface{}{"name": name})
+		require.NoError(t, err)
+		return output
+	}
+
+	t.Run("replaces invalid characters and collapses dashes", func(t *testing.T) {
+		assert.Equal(t, "team-a-checkout-api", render(t, "default", "team_a/Checkout.API"))
+		assert.Equal(t, "a-b", render(t, "default", "a -- b"))
+		assert.Equal(t, "caf-v2", render(t, "default", "--Café v2!--"))
+	})
+
+	t.Run("inputs differing only in case collide", func(t *testing.T) {
+		assert.Equal(t, render(t, "default", "MyApp"), render(t, "default", "myapp"))
+		assert.Equal(t, "myapp", render(t, "default", "MYAPP"))
+	})
+
+	t.Run("trims to max length without trailing dash", func(t *testing.T) {
+		assert.Equal(t, "abcdefghi", render(t, "short", "abcdefghi-jklmnop"))
+		assert.Len(t, render(t, "default", strings.Repeat("x", 100)), 63)
+	})
+
+	t.Run("is deterministic", func(t *testing.T) {
+		assert.Equal(t, render(t, "short", "Payments/Service_1"), render(t, "short", "Payments/Service_1"))
+	})
+
+	t.Run("rejects names without letters or digits", func(t *testing.T) {
+		_, err := engine.Render("default", map[string]interface{}{"name": "--__--"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dns_safe: \"--__--\" contains no letters or digits")
+	})
+
+	t.Run("rejects invalid max length", func(t *testing.T) {
+		_, err := engine.Render("invalid_arg", map[string]interface{}{"name": "x"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "dns_safe: max length must be a positive integer")
+	})
+}
+
+func TestGonjaFilter_ServerResilience(t *testing.T) {
+	templates := map[string]string{
+		"opts":    `{{ opts | server_resilience }}`,
+		"literal": `server s1 10.0.0.1:80 check {{ {"observe": "layer7", "error_limit": 5, "on_error": "mark-down"} | server_resilience }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	render := func(opts map[string]interface{}) (string, error) {
+		return engine.Render("opts", map[string]interface{}{"opts": opts})
+	}
+
+	t.Run("maps parameters to options in fixed order", func(t *testing.T) {
+		output, err := render(map[string]interface{}{
+			"downinter":      "5s",
+			"fastinter":      500,
+			"on_marked_down": "shutdown-sessions",
+			"on_error":       "fastinter",
+			"error_limit":    3,
+			"observe":        "layer4",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "observe layer4 error-limit 3 on-error fastinter on-marked-down shutdown-sessions fastinter 500 downinter 5s", output)
+	})
+
+	t.Run("works with dict literals", func(t *testing.T) {
+		output, err := engine.Render("literal", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "server s1 10.0.0.1:80 check observe layer7 error-limit 5 on-error mark-down", output)
+	})
+
+	t.Run("omits absent and null parameters", func(t *testing.T) {
+		output, err := render(map[string]interface{}{"observe": "layer7", "on_error": nil})
+		require.NoError(t, err)
+		assert.Equal(t, "observe layer7", output)
+
+		output, err = render(map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Empty(t, output)
+	})
+
+	t.Run("rejects error handling without observe", func(t *testing.T) {
+		_, err := render(map[string]interface{}{"error_limit": 10})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server_resilience: error_limit and on_error require observe")
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for name, opts := range map[string]map[string]interface{}{
+			"observe":     {"observe": "layer3"},
+			"error_limit": {"observe": "layer7", "error_limit": 0},
+			"on_error":    {"observe": "layer7", "on_error": "retry"},
+			"fastinter":   {"fastinter": "1 second"},
+		} {
+			_, err := render(opts)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "server_resilience: invalid "+name, name)
+		}
+	})
+
+	t.Run("rejects unknown parameters", func(t *testing.T) {
+		_, err := render(map[string]interface{}{"retries": 3})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `server_resilience: unknown parameter "retries"`)
+	})
+}
+
+func TestGonjaFilter_ServerOptions(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{"opts": `{{ opts | server_options }}`}, nil, nil, nil)
+	require.NoError(t, err)
+
+	render := func(opts map[string]interface{}) (string, []string, error) {
+		var warnings []string
+		output, err := engine.Render("opts", map[string]interface{}{
+			"opts": opts,
+			WarningsContextKey: WarningFunc(func(message string) {
+				warnings = append(warnings, message)
+			}),
+		})
+		return output, warnings, err
+	}
+
+	t.Run("maps annotations to options in fixed order", func(t *testing.T) {
+		output, warnings, err := render(map[string]interface{}{
+			"send-proxy-v2":                  "true",
+			"weight":                         "50",
+			"backup":                         "false",
+			"slowstart":                      "30s",
+			"maxconn":                        100,
+			"fall":                           3,
+			"inter":                          "2s",
+			"check":                          true,
+			"haproxy.org/unrelated":          "x",
+			"kubectl.kubernetes.io/restarts": "2",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "check inter 2s fall 3 weight 50 maxconn 100 slowstart 30s send-proxy-v2", output)
+		assert.Equal(t, []string{
+			`server_options: ignoring unknown key "haproxy.org/unrelated"`,
+			`server_options: ignoring unknown key "kubectl.kubernetes.io/restarts"`,
+		}, warnings)
+	})
+
+	t.Run("omits null values", func(t *testing.T) {
+		output, warnings, err := render(map[string]interface{}{"check": nil, "weight": 0})
+		require.NoError(t, err)
+		assert.Equal(t, "weight 0", output)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for name, opts := range map[string]map[string]interface{}{
+			"check":  {"check": "yes please"},
+			"weight": {"weight": 300},
+			"inter":  {"inter": "2 seconds"},
+			"verify": {"verify": "optional"},
+		} {
+			_, _, err := render(opts)
+			require.Error(t, err, name)
+			assert.Contains(t, err.Error(), "server_options: invalid "+name, name)
+		}
+	})
+
+	t.Run("rejects keys set twice", func(t *testing.T) {
+		_, _, err := render(map[string]interface{}{"send-proxy": true, "send_proxy": false})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "server_options: send_proxy is set more than once")
+	})
+}
+
+func TestGonjaFilter_HAProxySize(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{"size": `{{ size | haproxy_size }}`}, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		size interface{}
+		want string
+	}{
+		{size: "16KiB", want: "16384"},
+		{size: "16k", want: "16384"},
+		{size: "16 kb", want: "16384"},
+		{size: "1m", want: "1048576"},
+		{size: "1.5MiB", want: "1572864"},
+		{size: "2G", want: "2147483648"},
+		{size: "512", want: "512"},
+		{size: "512b", want: "512"},
+		{size: 4096, want: "4096"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.size), func(t *testing.T) {
+			output, err := engine.Render("size", map[string]interface{}{"size": tt.size})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	for _, invalid := range []interface{}{"16gb-bad", "16tb", "-1k", "0.1k", "k", ""} {
+		t.Run(fmt.Sprintf("rejects %q", invalid), func(t *testing.T) {
+			_, err := engine.Render("size", map[string]interface{}{"size": invalid})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "haproxy_size:")
+		})
+	}
+}
+
+func TestGonjaFilter_Percentile(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{"p": `{{ values | percentile(p) }}`}, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		values []interface{}
+		p      interface{}
+		want   string
+	}{
+		{name: "median interpolates", values: []interface{}{4, 1, 3, 2}, p: 50, want: "2.5"},
+		{name: "p90", values: []interface{}{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, p: 90, want: "91"},
+		{name: "minimum", values: []interface{}{3, 1, 2}, p: 0, want: "1"},
+		{name: "maximum", values: []interface{}{3, 1, 2}, p: 100, want: "3"},
+		{name: "single value", values: []interface{}{7}, p: 75, want: "7"},
+		{name: "floats", values: []interface{}{0.5, 1.5}, p: 25, want: "0.75"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render("p", map[string]interface{}{"values": tt.values, "p": tt.p})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	invalid := map[string]map[string]interface{}{
+		"empty list":  {"values": []interface{}{}, "p": 50},
+		"p above 100": {"values": []interface{}{1, 2}, "p": 101},
+		"negative p":  {"values": []interface{}{1, 2}, "p": -1},
+		"non-numeric": {"values": []interface{}{1, "many"}, "p": 50},
+		"not a list":  {"values": 5, "p": 50},
+	}
+	for name, ctx := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := engine.Render("p", ctx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "percentile:")
+		})
+	}
+}
+
+func TestGonjaFilter_Clamp(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{
+		"clamp":   `{{ value | clamp(10, 100) }}`,
+		"invalid": `{{ value | clamp(100, 10) }}`,
+		"missing": `{{ value | clamp(10) }}`,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: 5, want: "10"},
+		{value: 50, want: "50"},
+		{value: 500, want: "100"},
+		{value: 12.5, want: "12.5"},
+		{value: 99.9999, want: "99.9999"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.value), func(t *testing.T) {
+			output, err := engine.Render("clamp", map[string]interface{}{"value": tt.value})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	for _, name := range []string{"invalid", "missing"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := engine.Render(name, map[string]interface{}{"value": 50})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "clamp:")
+		})
+	}
+
+	t.Run("non-numeric value", func(t *testing.T) {
+		_, err := engine.Render("clamp", map[string]interface{}{"value": "many"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "clamp:")
+	})
+}
+
+func TestGonjaFilter_Flag(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{
+		"flag":    `{{ "option httpchk" | flag(enabled) }}`,
+		"negate":  `{{ "option http-keep-alive" | flag(enabled, negate=true) }}`,
+		"invalid": `{{ "option httpchk" | flag(enabled, invert=true) }}`,
+		"missing": `{{ "option httpchk" | flag }}`,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		template string
+		enabled  interface{}
+		want     string
+	}{
+		{template: "flag", enabled: true, want: "option httpchk"},
+		{template: "flag", enabled: false, want: ""},
+		{template: "flag", enabled: "true", want: "option httpchk"},
+		{template: "flag", enabled: "false", want: ""},
+		{template: "flag", enabled: nil, want: ""},
+		{template: "negate", enabled: true, want: "option http-keep-alive"},
+		{template: "negate", enabled: false, want: "no option http-keep-alive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s/%v", tt.template, tt.enabled), func(t *testing.T) {
+			output, err := engine.Render(tt.template, map[string]interface{}{"enabled": tt.enabled})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	t.Run("undefined", func(t *testing.T) {
+		output, err := engine.Render("flag", map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Equal(t, "", output)
+	})
+
+	for _, name := range []string{"invalid", "missing"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := engine.Render(name, map[string]interface{}{"enabled": true})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "flag:")
+		})
+	}
+
+	t.Run("non-boolean", func(t *testing.T) {
+		_, err := engine.Render("flag", map[string]interface{}{"enabled": "sometimes"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "flag:")
+	})
+}
+
+func TestGonjaFilter_Healthcheck(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{
+		"healthcheck": `{{ slo | healthcheck }}`,
+		"literal":     `server web1 10.0.0.1:80 check {{ {"detection_time": "10s", "tolerance": 2} | healthcheck }}`,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("representative SLO", func(t *testing.T) {
+		output, err := engine.Render("literal", nil)
+		require.NoError(t, err)
+		// fall 3 checks within 10s: 3333ms rounded down to 3300ms
+		assert.Equal(t, "server web1 10.0.0.1:80 check inter 3300ms fall 3 rise 3", output)
+	})
+
+	tests := []struct {
+		name string
+		slo  map[string]interface{}
+		want string
+	}{
+		{name: "default tolerance", slo: map[string]interface{}{"detection_time": "6s"}, want: "inter 2s fall 3 rise 3"},
+		{name: "milliseconds", slo: map[string]interface{}{"detection_time": 900, "tolerance": 0}, want: "inter 900ms fall 1 rise 1"},
+		{name: "sub-second rounding", slo: map[string]interface{}{"detection_time": "1s", "tolerance": 2}, want: "inter 330ms fall 3 rise 3"},
+		{name: "whole seconds", slo: map[string]interface{}{"detection_time": "1m", "tolerance": 3}, want: "inter 15s fall 4 rise 4"},
+		{name: "long interval rounding", slo: map[string]interface{}{"detection_time": "35s", "tolerance": 2}, want: "inter 11s fall 3 rise 3"},
+		{name: "recovery time", slo: map[string]interface{}{"detection_time": "6s", "recovery_time": "20s"}, want: "inter 2s fall 3 rise 10"},
+		{name: "short recovery", slo: map[string]interface{}{"detection_time": "6s", "recovery_time": "500ms"}, want: "inter 2s fall 3 rise 1"},
+		{name: "string tolerance", slo: map[string]interface{}{"detection_time": "4s", "tolerance": "1"}, want: "inter 2s fall 2 rise 2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := engine.Render("healthcheck", map[string]interface{}{"slo": tt.slo})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, output)
+		})
+	}
+
+	invalid := map[string]map[string]interface{}{
+		"missing detection time": {"tolerance": 2},
+		"invalid detection time": {"detection_time": "soon"},
+		"zero detection time":    {"detection_time": 0},
+		"negative tolerance":     {"detection_time": "10s", "tolerance": -1},
+		"fractional tolerance":   {"detection_time": "10s", "tolerance": 1.5},
+		"invalid recovery time":  {"detection_time": "10s", "recovery_time": "1.5s"},
+		"unknown parameter":      {"detection_time": "10s", "interval": "2s"},
+		"interval too short":     {"detection_time": "200ms", "tolerance": 2},
+	}
+	for name, slo := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := engine.Render("healthcheck", map[string]interface{}{"slo": slo})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "healthcheck:")
+		})
+	}
+
+	t.Run("non-dict", func(t *testing.T) {
+		_, err := engine.Render("healthcheck", map[string]interface{}{"slo": "10s"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "healthcheck:")
+	})
+}
+
+func TestGonjaFilter_ServerDNS(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{
+		"server":  `server api {{ host }}:8080 {{ host | server_dns("kube-dns") }}`,
+		"options": `{{ host | server_dns("kube-dns", prefer="ipv6", init_addr="last, 10.0.0.1") }}`,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	t.Run("default options", func(t *testing.T) {
+		output, err := engine.Render("server", map[string]interface{}{"host": "api.default.svc.cluster.local"})
+		require.NoError(t, err)
+		assert.Equal(t, "server api api.default.svc.cluster.local:8080 resolvers kube-dns resolve-prefer ipv4 init-addr last,libc,none", output)
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		output, err := engine.Render("options", map[string]interface{}{"host": "_http._tcp.api.default.svc"})
+		require.NoError(t, err)
+		assert.Equal(t, "resolvers kube-dns resolve-prefer ipv6 init-addr last,10.0.0.1", output)
+	})
+
+	t.Run("IP addresses need no resolution", func(t *testing.T) {
+		for _, host := range []string{"10.0.0.1", "fd00::1"} {
+			output, err := engine.Render("options", map[string]interface{}{"host": host})
+			require.NoError(t, err)
+			assert.Empty(t, output, host)
+		}
+	})
+
+	invalid := map[string]string{
+		"not a hostname":    `{{ "api svc" | server_dns("kube-dns") }}`,
+		"missing resolvers": `{{ "api" | server_dns }}`,
+		"empty resolvers":   `{{ "api" | server_dns("") }}`,
+		"invalid prefer":    `{{ "api" | server_dns("kube-dns", prefer="ipv5") }}`,
+		"invalid init_addr": `{{ "api" | server_dns("kube-dns", init_addr="last,dns") }}`,
+		"unknown argument":  `{{ "api" | server_dns("kube-dns", hold="10s") }}`,
+	}
+	for name, template := range invalid {
+		t.Run(name, func(t *testing.T) {
+			engine, err := New(EngineTypeGonja, map[string]string{"invalid": template}, nil, nil, nil)
+			require.NoError(t, err)
+
+			_, err = engine.Render("invalid", nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "server_dns:")
+		})
+	}
+}
+
+func TestGonjaFilter_StableServerName(t *testing.T) {
+	engine, err := New(EngineTypeGonja, map[string]string{
+		"endpoint": `{{ address | stable_server_name(port) }}`,
+		"key":      `{{ "10.0.0.1:8080" | stable_server_name }}`,
+		"servers": `{% for ep in endpoints %}` +
+			`{{ ep.address | stable_server_name(ep.port) }}={{ ep.address }};` +
+			`{% endfor %}`,
+		"invalid": `{{ address | stable_server_name(port, 1) }}`,
+	}, nil, nil, nil)
+	require.NoError(t, err)
+
+	// Must match comparator.StableServerName("10.0.0.1:8080")
+	const want = "srv_120e9814c44c"
+
+	t.Run("address and port", func(t *testing.T) {
+		output, err := engine.Render("endpoint", map[string]interface{}{"address": "10.0.0.1", "port": 8080})
+		require.NoError(t, err)
+		assert.Equal(t, want, output)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		output, err := engine.Render("key", nil)
+		require.NoError(t, err)
+		assert.Equal(t, want, output)
+	})
+
+	t.Run("reordering keeps names", func(t *testing.T) {
+		a := map[string]interface{}{"address": "10.0.0.1", "port": 8080}
+		b := map[string]interface{}{"address": "10.0.0.2", "port": 8080}
+
+		first, err := engine.Render("servers", map[string]interface{}{"endpoints": []interface{}{a, b}})
+		require.NoError(t, err)
+		second, err := engine.Render("servers", map[string]interface{}{"endpoints": []interface{}{b, a}})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, strings.Split(first, ";"), strings.Split(second, ";"))
+		assert.Contains(t, first, want+"=10.0.0.1")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := engine.Render("invalid", map[string]interface{}{"address": "10.0.0.1", "port": 8080})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stable_server_name:")
+
+		_, err = engine.Render("endpoint", map[string]interface{}{"address": "", "port": 8080})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stable_server_name:")
+
+		_, err = engine.Render("endpoint", map[string]interface{}{"address": "10.0.0.1", "port": "http"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stable_server_name:")
+	})
+}
+
+func TestRender_ContextIsolation(t *testing.T) {
+	templates := map[string]string{
+		"template_a": `{{ mutate(items, settings) }}{{ items | join(",") }} {{ settings.mode }}`,
+		"template_b": `{{ items | join(",") }} {{ settings.mode }} {{ settings.nested.tags | join(",") }}`,
+	}
+
+	// mutate modifies its list and dict arguments in place
+	functions := map[string]GlobalFunc{
+		"mutate": func(args ...interface{}) (interface{}, error) {
+			items := args[0].([]interface{})
+			items[0] = "mutated"
+
+			settings := args[1].(map[string]interface{})
+			settings["mode"] = "tcp"
+			nested := settings["nested"].(map[string]interface{})
+			nested["tags"].([]string)[0] = "mutated"
+
+			return "", nil
+		},
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, functions, nil)
+	require.NoError(t, err)
+
+	context := map[string]interface{}{
+		"items": []interface{}{"a", "b"},
+		"settings": map[string]interface{}{
+			"mode": "http",
+			"nested": map[string]interface{}{
+				"tags": []string{"x", "y"},
+			},
+		},
+	}
+
+	outputA, err := engine.Render("template_a", context)
+	require.NoError(t, err)
+	assert.Equal(t, "mutated,b tcp", outputA, "template A should see its own mutations")
+
+	outputB, err := engine.Render("template_b", context)
+	require.NoError(t, err)
+	assert.Equal(t, "a,b http x,y", outputB, "template B must not see mutations from template A")
+
+	// The caller's context is left untouched
+	assert.Equal(t, []interface{}{"a", "b"}, context["items"])
+	assert.Equal(t, "http", context["settings"].(map[string]interface{})["mode"])
+}
+
+func TestRenderAll(t *testing.T) {
+	templates := map[string]string{
+		"backend_a": "backend a\n    server a {{ servers.a }}\n",
+		"backend_b": "backend b\n    server b {{ servers.b }}\n",
+		"backend_c": "backend c\n    server c {{ servers.c }}\n",
+		"broken":    `{{ fail("broken template") }}`,
+	}
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	context := map[string]interface{}{
+		"servers": map[string]interface{}{"a": "10.0.0.1", "b": "10.0.0.2", "c": "10.0.0.3"},
+	}
+	names := []string{"backend_c", "backend_a", "backend_b"}
+
+	for _, concurrency := range []int{0, 1, 2, 10} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			results := engine.RenderAll(names, context, concurrency)
+			require.Len(t, results, len(names))
+
+			var concatenated strings.Builder
+			for i, result := range results {
+				require.NoError(t, result.Err)
+				assert.Equal(t, names[i], result.TemplateName)
+				concatenated.WriteString(result.Output)
+			}
+
+			var serial strings.Builder
+			for _, name := range names {
+				output, err := engine.Render(name, context)
+				require.NoError(t, err)
+				serial.WriteString(output)
+			}
+			assert.Equal(t, serial.String(), concatenated.String())
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		results := engine.RenderAll([]string{"backend_a", "broken", "missing", "backend_b"}, context, 2)
+		require.Len(t, results, 4)
+
+		assert.NoError(t, results[0].Err)
+		assert.Contains(t, results[0].Output, "server a 10.0.0.1")
+
+		var renderErr *RenderError
+		require.ErrorAs(t, results[1].Err, &renderErr)
+		assert.Equal(t, "broken", renderErr.TemplateName)
+
+		var notFoundErr *TemplateNotFoundError
+		require.ErrorAs(t, results[2].Err, &notFoundErr)
+
+		assert.NoError(t, results[3].Err, "templates after a failure are still rendered")
+		assert.Contains(t, results[3].Output, "server b 10.0.0.2")
+	})
+
+	t.Run("no templates", func(t *testing.T) {
+		assert.Empty(t, engine.RenderAll(nil, context, 4))
+	})
+}
+
+func TestRenderAll_Race(t *testing.T) {
+	// Renders share the compiled templates, the context and the metrics.
+	// Run with: go test -race
+	templates := make(map[string]string)
+	names := make([]string, 0, 50)
+	for i := range 50 {
+		name := fmt.Sprintf("template%d", i)
+		templates[name] = `{{ mutate(settings) }}{{ settings.mode }} {{ items | join(",") }}`
+		names = append(names, name)
+	}
+
+	// mutate modifies its dict argument in place, which must stay local to the render
+	functions := map[string]GlobalFunc{
+		"mutate": func(args ...interface{}) (interface{}, error) {
+			settings := args[0].(map[string]interface{})
+			settings["mode"] = "tcp"
+			return "", nil
+		},
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, functions, nil)
+	require.NoError(t, err)
+	engine = engine.WithMetrics(prometheus.NewRegistry())
+	engine.EnableTracing()
+
+	context := map[string]interface{}{
+		"items":    []interface{}{"a", "b"},
+		"settings": map[string]interface{}{"mode": "http"},
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, result := range engine.RenderAll(names, context, 8) {
+				assert.NoError(t, result.Err)
+				assert.Equal(t, "tcp a,b", result.Output)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "http", context["settings"].(map[string]interface{})["mode"])
+	assert.Equal(t, 200, strings.Count(engine.GetTraceOutput(), "Completed:"))
+}
+
+// benchmarkRenderAll renders 32 templates with the given concurrency.
+func benchmarkRenderAll(b *testing.B, concurrency int) {
+	templates := make(map[string]string)
+	names := make([]string, 0, 32)
+	for i := range 32 {
+		name := fmt.Sprintf("backend%d", i)
+		templates[name] = `backend {{ name }}
+{%- for server in servers %}
+    server {{ server.name }} {{ server.address }}:{{ server.port }} check weight {{ server.weight }}
+{%- endfor %}
+`
+		names = append(names, name)
+	}
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(b, err)
+
+	servers := make([]interface{}, 0, 200)
+	for i := range 200 {
+		servers = append(servers, map[string]interface{}{
+			"name":    fmt.Sprintf("srv%d", i),
+			"address": fmt.Sprintf("10.0.%d.%d", i/256, i%256),
+			"port":    8080,
+			"weight":  i % 256,
+		})
+	}
+	context := map[string]interface{}{"name": "web", "servers": servers}
+
+	b.ResetTimer()
+	for range b.N {
+		for _, result := range engine.RenderAll(names, context, concurrency) {
+			if result.Err != nil {
+				b.Fatal(result.Err)
+			}
+		}
+	}
+}
+
+func BenchmarkRenderAll_Serial(b *testing.B) {
+	benchmarkRenderAll(b, 1)
+}
+
+func BenchmarkRenderAll_Concurrent(b *testing.B) {
+	benchmarkRenderAll(b, 0)
+}
+
+// ============================================================================
+// metrics tests
+// ============================================================================
+
+func TestWithMetrics(t *testing.T) {
+	templates := map[string]string{
+		"main": `
+{%- set analysis = namespace(count=0) %}
+{%- include "analyze" %}
+{%- include "analyze" -%}
+count={{ analysis.count }}`,
+		"analyze": `
+{%- compute_once analysis %}
+  {%- set analysis.count = 42 %}
+{%- endcompute_once %}`,
+		"broken": `{{ fail("broken") }}`,
+	}
+
+	engine, err := New(EngineTypeGonja, templates, nil, nil, nil)
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	engine = engine.WithMetrics(registry)
+
+	output, err := engine.Render("main", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "count=42", output)
+
+	_, err = engine.Render("broken", nil)
+	require.Error(t, err)
+
+	_, err = engine.Render("missing", nil)
+	require.Error(t, err)
+
+	m := engine.metrics
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CacheMisses.WithLabelValues("main")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.CacheHits.WithLabelValues("main")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.RenderErrors.WithLabelValues("broken")))
+
+	// Durations of both renders, but not of the missing template
+	assert.Equal(t, 2, testutil.CollectAndCount(m.RenderDuration))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.OutputSize))
+
+	count, err := testutil.GatherAndCount(registry, "haproxy_ic_template_render_duration_seconds")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
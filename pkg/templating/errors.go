@@ -1,6 +1,11 @@
 package templating
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
 
 // CompilationError represents a template compilation failure.
 // This error occurs during template initialization when the template
@@ -26,6 +31,29 @@ func (e *CompilationError) Unwrap() error {
 	return e.Cause
 }
 
+// TemplateCompileError is a CompilationError with the location of the syntax
+// error parsed from the template engine's message.
+//
+// It is returned by New so callers can report compile errors precisely, e.g.
+// in CRD status conditions. errors.As also matches the wrapped *CompilationError.
+type TemplateCompileError struct {
+	*CompilationError
+
+	// Line is the 1-based line of the syntax error, or 0 if unknown
+	Line int
+
+	// Column is the 1-based column of the syntax error, or 0 if unknown
+	Column int
+
+	// Snippet is the template source line containing the syntax error
+	Snippet string
+}
+
+// Unwrap returns the wrapped CompilationError for error unwrapping.
+func (e *TemplateCompileError) Unwrap() error {
+	return e.CompilationError
+}
+
 // RenderError represents a template rendering failure.
 // This error occurs when a valid template fails during execution,
 // typically due to missing context variables or runtime evaluation errors.
@@ -88,6 +116,37 @@ func NewCompilationError(templateName, templateContent string, cause error) *Com
 	}
 }
 
+// compileErrorLocationPattern matches the location gonja appends to parse errors,
+// e.g. "(Line: 3 Col: 8, near "}}")". Line 0 means gonja did not know the location.
+var compileErrorLocationPattern = regexp.MustCompile(`\(Line: (\d+) Col: (\d+)`)
+
+// NewTemplateCompileError creates a TemplateCompileError, parsing the error
+// location from the compilation error message of the template engine.
+func NewTemplateCompileError(templateName, templateContent string, cause error) *TemplateCompileError {
+	err := &TemplateCompileError{
+		CompilationError: NewCompilationError(templateName, templateContent, cause),
+	}
+
+	match := compileErrorLocationPattern.FindStringSubmatch(cause.Error())
+	if match == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(match[1])
+	column, _ := strconv.Atoi(match[2])
+	if line == 0 {
+		return err
+	}
+
+	err.Line = line
+	err.Column = column
+	if lines := strings.Split(templateContent, "\n"); line <= len(lines) {
+		err.Snippet = lines[line-1]
+	}
+
+	return err
+}
+
 // NewRenderError creates a RenderError for a template rendering failure.
 func NewRenderError(templateName string, cause error) *RenderError {
 	return &RenderError{
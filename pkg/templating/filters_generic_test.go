@@ -167,6 +167,23 @@ b: count={{ grouped["b"] | length }}{%- endif %}`,
 			},
 			want: "a: count=2\nb: count=1",
 		},
+		{
+			name: "group endpoints by zone",
+			template: `{%- set by_zone = endpoints | group_by("zone") -%}
+a:{% for ep in by_zone["eu-1a"] %}{{ ep.ip }};{% endfor %}
+b:{% for ep in by_zone["eu-1b"] %}{{ ep.ip }};{% endfor %}
+none:{% for ep in by_zone[""] %}{{ ep.ip }};{% endfor %}`,
+			context: map[string]interface{}{
+				"endpoints": []map[string]interface{}{
+					{"ip": "10.0.0.3", "zone": "eu-1a"},
+					{"ip": "10.0.1.1", "zone": "eu-1b"},
+					{"ip": "10.0.0.1", "zone": "eu-1a"},
+					{"ip": "10.0.9.9"},
+					{"ip": "10.0.0.2", "zone": "eu-1a"},
+				},
+			},
+			want: "a:10.0.0.3;10.0.0.1;10.0.0.2;\nb:10.0.1.1;\nnone:10.0.9.9;",
+		},
 		{
 			name:     "empty list",
 			template: `{{ items | group_by("$.key") | length }}`,
@@ -442,13 +459,13 @@ func TestGonjaFilter_EdgeCases(t *testing.T) {
 		},
 		{
 			name:     "group_by with missing field",
-			template: `{{ items | group_by("$.missing") | length }}`,
+			template: `{%- set grouped = items | group_by("$.missing") -%}{{ grouped | length }} {{ grouped[""] | length }}`,
 			context: map[string]interface{}{
 				"items": []map[string]interface{}{
 					{"name": "test"},
 				},
 			},
-			want: "1", // Should group under empty string key
+			want: "1 1", // Grouped under the empty string key
 		},
 		{
 			name: "sort_by with mixed types",
@@ -482,3 +499,191 @@ func TestGonjaFilter_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestGonjaFilter_ToMapfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		context  map[string]interface{}
+		want     string
+		wantErr  string
+	}{
+		{
+			name:     "dict sorted by key",
+			template: `{{ hosts | to_mapfile }}`,
+			context: map[string]interface{}{
+				"hosts": map[string]interface{}{
+					"www.example.com": "be_www",
+					"api.example.com": "be_api",
+					"example.com":     "be_root",
+				},
+			},
+			want: "api.example.com be_api\nexample.com be_root\nwww.example.com be_www\n",
+		},
+		{
+			name:     "list of pairs",
+			template: `{{ pairs | to_mapfile }}`,
+			context: map[string]interface{}{
+				"pairs": []interface{}{
+					[]interface{}{"/b", 2},
+					[]interface{}{"/a", 1},
+				},
+			},
+			want: "/a 1\n/b 2\n",
+		},
+		{
+			name:     "pairs built in template",
+			template: `{{ [["z", "last"], ["a", "first"]] | to_mapfile }}`,
+			want:     "a first\nz last\n",
+		},
+		{
+			name:     "keeps value spaces and key backslashes",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]string{
+					"key":     "value with spaces",
+					`back\sl`: "y",
+				},
+			},
+			want: "back\\sl y\nkey value with spaces\n",
+		},
+		{
+			name:     "key with space rejected",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]string{"my key": "x"},
+			},
+			wantErr: "contains whitespace",
+		},
+		{
+			name:     "key with tab rejected",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]string{"my\tkey": "x"},
+			},
+			wantErr: "contains whitespace",
+		},
+		{
+			name:     "key starting with hash rejected",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]string{"#comment": "x"},
+			},
+			wantErr: "would be read as a comment",
+		},
+		{
+			name:     "empty dict",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]interface{}{},
+			},
+			want: "",
+		},
+		{
+			name:     "line break rejected",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": map[string]interface{}{"key": "multi\nline"},
+			},
+			wantErr: "contains a line break",
+		},
+		{
+			name:     "invalid pair rejected",
+			template: `{{ entries | to_mapfile }}`,
+			context: map[string]interface{}{
+				"entries": []interface{}{[]interface{}{"only-key"}},
+			},
+			wantErr: "is not a [key, value] pair",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := New(EngineTypeGonja, map[string]string{"test": tt.template}, nil, nil, nil)
+			require.NoError(t, err)
+
+			got, err := engine.Render("test", tt.context)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGonjaFilter_ACLConditions(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		context  map[string]interface{}
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "acl_or joins three names",
+			template: `{{ ["is_api", "is_admin", "!is_internal"] | acl_or }}`,
+			want:     "is_api || is_admin || !is_internal",
+		},
+		{
+			name:     "acl_and joins three names",
+			template: `{{ ["is_api", "is_admin", "!is_internal"] | acl_and }}`,
+			want:     "is_api is_admin !is_internal",
+		},
+		{
+			name:     "acl_and distributes over or-ed operands",
+			template: `{{ [["is_api", "is_admin"] | acl_or, "is_get", "!is_internal"] | acl_and }}`,
+			want:     "is_api is_get !is_internal || is_admin is_get !is_internal",
+		},
+		{
+			name:     "acl_or of and-ed operands",
+			template: `{{ [["is_api", "is_get"] | acl_and, "is_admin"] | acl_or }}`,
+			want:     "is_api is_get || is_admin",
+		},
+		{
+			name:     "anonymous ACLs kept intact",
+			template: `{{ names | acl_and }}`,
+			context: map[string]interface{}{
+				"names": []string{"is_api", "{ src 10.0.0.0/8 }"},
+			},
+			want: "is_api { src 10.0.0.0/8 }",
+		},
+		{
+			name:     "single name",
+			template: `{{ ["is_api"] | acl_or }}`,
+			want:     "is_api",
+		},
+		{
+			name:     "empty list rejected",
+			template: `{{ names | acl_or }}`,
+			context: map[string]interface{}{
+				"names": []string{},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "non-list rejected",
+			template: `{{ "is_api" | acl_and }}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := New(EngineTypeGonja, map[string]string{"test": tt.template}, nil, nil, nil)
+			require.NoError(t, err)
+
+			got, err := engine.Render("test", tt.context)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
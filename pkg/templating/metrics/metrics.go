@@ -0,0 +1,122 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics provides Prometheus metrics for template rendering.
+//
+// All metrics carry a single "template" label holding the name of the rendered
+// template, so their cardinality is bounded by the number of templates the
+// engine was created with.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	pkgmetrics "haproxy-template-ic/pkg/metrics"
+)
+
+// templateLabel is the label holding the rendered template name.
+const templateLabel = "template"
+
+// Metrics holds the template rendering metrics.
+//
+// IMPORTANT: Create one instance per application iteration, like the
+// controller metrics. Metrics are scoped to the registry they were created with.
+type Metrics struct {
+	RenderDuration *prometheus.HistogramVec
+	RenderErrors   *prometheus.CounterVec
+	CacheHits      *prometheus.CounterVec
+	CacheMisses    *prometheus.CounterVec
+	OutputSize     *prometheus.HistogramVec
+}
+
+// New creates all template rendering metrics and registers them with the
+// provided registry.
+//
+// IMPORTANT: Pass an instance-based registry (prometheus.NewRegistry()), NOT
+// prometheus.DefaultRegisterer.
+func New(registry prometheus.Registerer) *Metrics {
+	labels := []string{templateLabel}
+
+	return &Metrics{
+		RenderDuration: pkgmetrics.NewHistogramVec(
+			registry,
+			"haproxy_ic_template_render_duration_seconds",
+			"Time spent rendering templates",
+			labels,
+			pkgmetrics.DurationBuckets(),
+		),
+		RenderErrors: pkgmetrics.NewCounterVec(
+			registry,
+			"haproxy_ic_template_render_errors_total",
+			"Total number of failed template renders",
+			labels,
+		),
+		CacheHits: pkgmetrics.NewCounterVec(
+			registry,
+			"haproxy_ic_template_cache_hits_total",
+			"Total number of compute_once blocks that reused an earlier result",
+			labels,
+		),
+		CacheMisses: pkgmetrics.NewCounterVec(
+			registry,
+			"haproxy_ic_template_cache_misses_total",
+			"Total number of compute_once blocks that executed their body",
+			labels,
+		),
+		OutputSize: pkgmetrics.NewHistogramVec(
+			registry,
+			"haproxy_ic_template_output_size_bytes",
+			"Size of rendered template output",
+			labels,
+			SizeBuckets(),
+		),
+	}
+}
+
+// RecordRender records a completed render of a template.
+//
+// Parameters:
+//   - template: Name of the rendered template
+//   - duration: Time spent rendering
+//   - outputSize: Length of the rendered output in bytes (ignored on failure)
+//   - success: Whether the render completed successfully
+func (m *Metrics) RecordRender(template string, duration time.Duration, outputSize int, success bool) {
+	m.RenderDuration.WithLabelValues(template).Observe(duration.Seconds())
+	if !success {
+		m.RenderErrors.WithLabelValues(template).Inc()
+		return
+	}
+	m.OutputSize.WithLabelValues(template).Observe(float64(outputSize))
+}
+
+// RecordCacheHit records a compute_once block that reused an earlier result
+// while rendering template.
+func (m *Metrics) RecordCacheHit(template string) {
+	m.CacheHits.WithLabelValues(template).Inc()
+}
+
+// RecordCacheMiss records a compute_once block that executed its body while
+// rendering template.
+func (m *Metrics) RecordCacheMiss(template string) {
+	m.CacheMisses.WithLabelValues(template).Inc()
+}
+
+// SizeBuckets returns histogram buckets for rendered output sizes in bytes.
+//
+// Buckets: 256 B to 4 MiB in powers of four.
+func SizeBuckets() []float64 {
+	return prometheus.ExponentialBuckets(256, 4, 8)
+}
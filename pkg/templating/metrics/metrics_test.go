@@ -0,0 +1,57 @@
+// Copyright 2025 Philipp Hossner
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_RecordRender(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := New(registry)
+
+	metrics.RecordRender("haproxy.cfg", 50*time.Millisecond, 1024, true)
+	metrics.RecordRender("haproxy.cfg", 20*time.Millisecond, 0, false)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.RenderDuration))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.RenderErrors.WithLabelValues("haproxy.cfg")))
+	// Failed renders have no output to measure
+	assert.Equal(t, 1, testutil.CollectAndCount(metrics.OutputSize))
+}
+
+func TestMetrics_RecordCache(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics := New(registry)
+
+	metrics.RecordCacheMiss("haproxy.cfg")
+	metrics.RecordCacheHit("haproxy.cfg")
+	metrics.RecordCacheHit("haproxy.cfg")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.CacheHits.WithLabelValues("haproxy.cfg")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.CacheMisses.WithLabelValues("haproxy.cfg")))
+}
+
+func TestSizeBuckets(t *testing.T) {
+	buckets := SizeBuckets()
+
+	assert.Len(t, buckets, 8)
+	assert.Equal(t, 256.0, buckets[0])
+	assert.Equal(t, 4194304.0, buckets[len(buckets)-1])
+}
@@ -34,7 +34,7 @@ func ValidateTemplate(templateStr string, engineType EngineType) error {
 	_, err := gonja.FromString(templateStr)
 	if err != nil {
 		// Use a generic name for validation-only compilation errors
-		return NewCompilationError("template", templateStr, err)
+		return NewTemplateCompileError("template", templateStr, err)
 	}
 
 	return nil